@@ -0,0 +1,12 @@
+// The putbackcheck command runs the putbackcheck analyzer:
+//
+//	go run github.com/quic-go/quic-go/analysis/putbackcheck/cmd/putbackcheck@latest ./...
+package main
+
+import (
+	"github.com/quic-go/quic-go/analysis/putbackcheck"
+
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() { singlechecker.Main(putbackcheck.Analyzer) }
@@ -0,0 +1,254 @@
+// Package putbackcheck defines an analyzer that reports lifetime bugs when using frames returned by the wire.FrameParser.
+//
+// The FrameParser avoids allocations by reusing memory:
+//   - STREAM frames are taken from a pool, and must not be used after calling PutBack.
+//   - The ACK frame is reused for the next ACK frame, so it must be cloned (using Clone) if it is retained.
+//
+// The analysis is intraprocedural and heuristic: it doesn't follow control flow,
+// and it only reports the most common mistakes.
+package putbackcheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const wirePkgPath = "github.com/quic-go/quic-go/internal/wire"
+
+// Analyzer reports uses of STREAM frames after PutBack, and retained ACK frames that weren't cloned.
+var Analyzer = &analysis.Analyzer{
+	Name:     "putbackcheck",
+	Doc:      "report STREAM frames used after PutBack, and ACK frames retained without Clone",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.BlockStmt)(nil)}, func(n ast.Node) {
+		checkUseAfterPutBack(pass, n.(*ast.BlockStmt))
+	})
+	insp.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			body = fn.Body
+		case *ast.FuncLit:
+			body = fn.Body
+		}
+		if body != nil {
+			checkRetainedAckFrames(pass, body)
+		}
+	})
+	return nil, nil
+}
+
+// isWireType says if t is a pointer to the named type of the wire package.
+func isWireType(t types.Type, name string) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == wirePkgPath && obj.Name() == name
+}
+
+// checkUseAfterPutBack reports uses of a STREAM frame in the statements following a call to its PutBack method,
+// within the same block.
+func checkUseAfterPutBack(pass *analysis.Pass, block *ast.BlockStmt) {
+	for i, stmt := range block.List {
+		obj := putBackReceiver(pass, stmt)
+		if obj == nil {
+			continue
+		}
+		for _, next := range block.List[i+1:] {
+			if reassigns(pass, next, obj) {
+				break
+			}
+			var found bool
+			ast.Inspect(next, func(n ast.Node) bool {
+				if found {
+					return false
+				}
+				if id, ok := n.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == obj {
+					pass.Reportf(id.Pos(), "%s used after PutBack", id.Name)
+					found = true
+				}
+				return true
+			})
+			if found {
+				break
+			}
+		}
+	}
+}
+
+// putBackReceiver returns the variable the PutBack method is called on, if stmt is a call to (*wire.StreamFrame).PutBack.
+func putBackReceiver(pass *analysis.Pass, stmt ast.Stmt) types.Object {
+	exprStmt, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return nil
+	}
+	call, ok := exprStmt.X.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "PutBack" {
+		return nil
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok || !isWireType(pass.TypesInfo.TypeOf(id), "StreamFrame") {
+		return nil
+	}
+	return pass.TypesInfo.Uses[id]
+}
+
+// reassigns says if stmt assigns a new value to obj.
+func reassigns(pass *analysis.Pass, stmt ast.Stmt, obj types.Object) bool {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok {
+		return false
+	}
+	for _, lhs := range assign.Lhs {
+		if id, ok := lhs.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == obj {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRetainedAckFrames reports ACK frames returned by the FrameParser that are retained without being cloned,
+// i.e. stored in a struct field, a map, a slice or sent on a channel.
+func checkRetainedAckFrames(pass *analysis.Pass, body *ast.BlockStmt) {
+	// parsed contains the variables holding a frame returned by ParseNext
+	parsed := make(map[types.Object]bool)
+	// acks contains the variables holding the ACK frame returned by ParseNext
+	acks := make(map[types.Object]bool)
+
+	isParsed := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		return ok && parsed[pass.TypesInfo.Uses[id]]
+	}
+	isAck := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		return ok && acks[pass.TypesInfo.Uses[id]]
+	}
+	report := func(e ast.Expr) {
+		pass.Reportf(e.Pos(), "ACK frame returned by the FrameParser is retained without Clone")
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// checked separately
+			return false
+		case *ast.AssignStmt:
+			// _, frame, err := parser.ParseNext(...)
+			if len(n.Rhs) == 1 && len(n.Lhs) == 3 && isParseNext(pass, n.Rhs[0]) {
+				if obj := lhsObject(pass, n.Lhs[1]); obj != nil {
+					parsed[obj] = true
+				}
+				return true
+			}
+			// ack := frame.(*wire.AckFrame)
+			if len(n.Rhs) == 1 {
+				if ta, ok := ast.Unparen(n.Rhs[0]).(*ast.TypeAssertExpr); ok && ta.Type != nil && isParsed(ta.X) &&
+					isWireType(pass.TypesInfo.TypeOf(ta.Type), "AckFrame") {
+					if obj := lhsObject(pass, n.Lhs[0]); obj != nil {
+						acks[obj] = true
+					}
+					return true
+				}
+			}
+			for i, rhs := range n.Rhs {
+				if i < len(n.Lhs) && isAck(rhs) {
+					if _, isIdent := n.Lhs[i].(*ast.Ident); !isIdent {
+						report(rhs)
+					}
+				}
+			}
+		case *ast.TypeSwitchStmt:
+			// switch ack := frame.(type) { case *wire.AckFrame: ... }
+			assign, ok := n.Assign.(*ast.AssignStmt)
+			if !ok || len(assign.Rhs) != 1 {
+				return true
+			}
+			ta, ok := assign.Rhs[0].(*ast.TypeAssertExpr)
+			if !ok || !isParsed(ta.X) {
+				return true
+			}
+			for _, stmt := range n.Body.List {
+				clause := stmt.(*ast.CaseClause)
+				if obj := pass.TypesInfo.Implicits[clause]; obj != nil && isWireType(obj.Type(), "AckFrame") {
+					acks[obj] = true
+				}
+			}
+		case *ast.CallExpr:
+			// append(s, ack)
+			if id, ok := n.Fun.(*ast.Ident); ok && id.Name == "append" {
+				if _, isBuiltin := pass.TypesInfo.Uses[id].(*types.Builtin); isBuiltin {
+					for _, arg := range n.Args[1:] {
+						if isAck(arg) {
+							report(arg)
+						}
+					}
+				}
+			}
+		case *ast.SendStmt:
+			if isAck(n.Value) {
+				report(n.Value)
+			}
+		case *ast.CompositeLit:
+			for _, elt := range n.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				if isAck(elt) {
+					report(elt)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// isParseNext says if e is a call to (*wire.FrameParser).ParseNext.
+func isParseNext(pass *analysis.Pass, e ast.Expr) bool {
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "ParseNext" {
+		return false
+	}
+	selection := pass.TypesInfo.Selections[sel]
+	if selection == nil {
+		return false
+	}
+	recv := selection.Recv()
+	if _, ok := recv.(*types.Pointer); !ok {
+		recv = types.NewPointer(recv)
+	}
+	return isWireType(recv, "FrameParser")
+}
+
+func lhsObject(pass *analysis.Pass, e ast.Expr) types.Object {
+	id, ok := e.(*ast.Ident)
+	if !ok || id.Name == "_" {
+		return nil
+	}
+	if obj := pass.TypesInfo.Defs[id]; obj != nil {
+		return obj
+	}
+	return pass.TypesInfo.Uses[id]
+}
@@ -0,0 +1,56 @@
+package a
+
+import "github.com/quic-go/quic-go/internal/wire"
+
+func consume(*wire.StreamFrame) {}
+
+func useAfterPutBack(f *wire.StreamFrame) {
+	f.PutBack()
+	consume(f) // want "f used after PutBack"
+}
+
+func useBeforePutBack(f *wire.StreamFrame) {
+	consume(f)
+	f.PutBack()
+}
+
+func putBackInBranch(f *wire.StreamFrame, cond bool) {
+	if cond {
+		f.PutBack()
+		return
+	}
+	consume(f)
+}
+
+func reassignedAfterPutBack(f *wire.StreamFrame) {
+	f.PutBack()
+	f = &wire.StreamFrame{}
+	consume(f)
+}
+
+type state struct {
+	ack  *wire.AckFrame
+	acks []*wire.AckFrame
+}
+
+func retainAck(p *wire.FrameParser, s *state, ch chan *wire.AckFrame) {
+	_, frame, _ := p.ParseNext(nil)
+	ack := frame.(*wire.AckFrame)
+	s.ack = ack                  // want "ACK frame returned by the FrameParser is retained without Clone"
+	s.acks = append(s.acks, ack) // want "ACK frame returned by the FrameParser is retained without Clone"
+	ch <- ack                    // want "ACK frame returned by the FrameParser is retained without Clone"
+	_ = state{ack: ack}          // want "ACK frame returned by the FrameParser is retained without Clone"
+	s.ack = ack.Clone()
+	local := ack
+	_ = local
+}
+
+func retainAckTypeSwitch(p *wire.FrameParser, s *state) {
+	_, frame, _ := p.ParseNext(nil)
+	switch f := frame.(type) {
+	case *wire.AckFrame:
+		s.ack = f // want "ACK frame returned by the FrameParser is retained without Clone"
+	case *wire.StreamFrame:
+		f.PutBack()
+	}
+}
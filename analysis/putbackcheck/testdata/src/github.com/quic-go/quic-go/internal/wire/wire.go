@@ -0,0 +1,15 @@
+package wire
+
+type Frame interface{}
+
+type StreamFrame struct{ Data []byte }
+
+func (f *StreamFrame) PutBack() {}
+
+type AckFrame struct{}
+
+func (f *AckFrame) Clone() *AckFrame { return &AckFrame{} }
+
+type FrameParser struct{}
+
+func (p *FrameParser) ParseNext(data []byte) (int, Frame, error) { return 0, nil, nil }
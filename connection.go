@@ -9,7 +9,6 @@ import (
 	"io"
 	"net"
 	"reflect"
-	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -114,6 +113,8 @@ type Conn struct {
 	perspective protocol.Perspective
 	version     protocol.Version
 	config      *Config
+	// The source of randomness used when composing frames, see Transport.Rand.
+	random io.Reader
 
 	conn      sendConn
 	sendQueue sender
@@ -233,6 +234,7 @@ var newConnection = func(
 	srcConnID protocol.ConnectionID,
 	connIDGenerator ConnectionIDGenerator,
 	statelessResetter *statelessResetter,
+	random io.Reader,
 	conf *Config,
 	tlsConf *tls.Config,
 	tokenGenerator *handshake.TokenGenerator,
@@ -247,6 +249,7 @@ var newConnection = func(
 		ctxCancel:           ctxCancel,
 		conn:                conn,
 		config:              conf,
+		random:              random,
 		handshakeDestConnID: destConnID,
 		srcConnIDLen:        srcConnID.Len(),
 		tokenGenerator:      tokenGenerator,
@@ -330,6 +333,7 @@ var newConnection = func(
 		conn.RemoteAddr(),
 		params,
 		tlsConf,
+		random,
 		conf.Allow0RTT,
 		s.rttStats,
 		tracer,
@@ -352,6 +356,7 @@ var newClientConnection = func(
 	srcConnID protocol.ConnectionID,
 	connIDGenerator ConnectionIDGenerator,
 	statelessResetter *statelessResetter,
+	random io.Reader,
 	conf *Config,
 	tlsConf *tls.Config,
 	initialPacketNumber protocol.PacketNumber,
@@ -364,6 +369,7 @@ var newClientConnection = func(
 	s := &Conn{
 		conn:                conn,
 		config:              conf,
+		random:              random,
 		origDestConnID:      destConnID,
 		handshakeDestConnID: destConnID,
 		srcConnIDLen:        srcConnID.Len(),
@@ -438,6 +444,7 @@ var newClientConnection = func(
 		destConnID,
 		params,
 		tlsConf,
+		random,
 		enable0RTT,
 		s.rttStats,
 		tracer,
@@ -469,6 +476,7 @@ func (c *Conn) preSetup() {
 	c.sendQueue = newSendQueue(c.conn)
 	c.retransmissionQueue = newRetransmissionQueue()
 	c.frameParser = *wire.NewFrameParser(c.config.EnableDatagrams, false)
+	c.frameParser.SetPerspective(c.perspective)
 	c.rttStats = &utils.RTTStats{}
 	c.connFlowController = flowcontrol.NewConnectionFlowController(
 		protocol.ByteCount(c.config.InitialConnectionReceiveWindow),
@@ -1072,6 +1080,7 @@ func (c *Conn) handleShortHeaderPacket(p receivedPacket, isCoalesced bool) (wasP
 		c.pathManager = newPathManager(
 			c.connIDManager.GetConnIDForPath,
 			c.connIDManager.RetireConnIDForPath,
+			c.random,
 			c.logger,
 		)
 	}
@@ -1282,7 +1291,8 @@ func (c *Conn) handleVersionNegotiationPacket(p receivedPacket) {
 		return
 	}
 
-	if slices.Contains(supportedVersions, c.version) {
+	vnErr := wire.ValidateVersionNegotiation(supportedVersions, c.version, c.config.Versions)
+	if errors.Is(vnErr, wire.ErrVersionNegotiationOfferedVersion) {
 		if c.tracer != nil && c.tracer.DroppedPacket != nil {
 			c.tracer.DroppedPacket(logging.PacketTypeVersionNegotiation, protocol.InvalidPacketNumber, p.Size(), logging.PacketDropUnexpectedVersion)
 		}
@@ -1295,8 +1305,7 @@ func (c *Conn) handleVersionNegotiationPacket(p receivedPacket) {
 	if c.tracer != nil && c.tracer.ReceivedVersionNegotiationPacket != nil {
 		c.tracer.ReceivedVersionNegotiationPacket(dest, src, supportedVersions)
 	}
-	newVersion, ok := protocol.ChooseSupportedVersion(c.config.Versions, supportedVersions)
-	if !ok {
+	if vnErr != nil {
 		c.destroyImpl(&VersionNegotiationError{
 			Ours:   c.config.Versions,
 			Theirs: supportedVersions,
@@ -1304,6 +1313,7 @@ func (c *Conn) handleVersionNegotiationPacket(p receivedPacket) {
 		c.logger.Infof("No compatible QUIC version found.")
 		return
 	}
+	newVersion, _ := protocol.ChooseSupportedVersion(c.config.Versions, supportedVersions)
 	if c.tracer != nil && c.tracer.NegotiatedVersion != nil {
 		c.tracer.NegotiatedVersion(newVersion, c.config.Versions, supportedVersions)
 	}
@@ -1431,14 +1441,32 @@ func (c *Conn) handleFrames(
 	}
 	handshakeWasComplete := c.handshakeComplete
 	var handleErr error
+	c.frameParser.StartPayload()
+	payload := data
+	var offset int
 	for len(data) > 0 {
 		l, frame, err := c.frameParser.ParseNext(data, encLevel, c.version)
 		if err != nil {
+			// make the offset relative to the start of the packet payload
+			if parseErr, ok := err.(*wire.FrameParsingError); ok {
+				parseErr.Offset += offset
+			}
+			traceDebug := c.tracer != nil && c.tracer.Debug != nil
+			if c.logger.Debug() || traceDebug {
+				if a, ok := c.frameParser.AttributeError(payload, encLevel, c.version, err); ok {
+					c.logger.Debugf("Failed to parse %s", a)
+					if traceDebug {
+						c.tracer.Debug("frame_parsing_error", a.String())
+					}
+				}
+			}
 			return false, false, nil, err
 		}
 		data = data[l:]
+		offset += l
+		// The frame parser might return before the end of the data when skipping PADDING.
 		if frame == nil {
-			break
+			continue
 		}
 		if ackhandler.IsFrameAckEliciting(frame) {
 			isAckEliciting = true
@@ -1510,7 +1538,7 @@ func (c *Conn) handleFrame(
 	case *wire.MaxStreamDataFrame:
 		err = c.streamsMap.HandleMaxStreamDataFrame(frame)
 	case *wire.MaxStreamsFrame:
-		c.streamsMap.HandleMaxStreamsFrame(frame)
+		err = c.streamsMap.HandleMaxStreamsFrame(frame)
 	case *wire.DataBlockedFrame:
 	case *wire.StreamDataBlockedFrame:
 		err = c.streamsMap.HandleStreamDataBlockedFrame(frame)
@@ -1561,19 +1589,7 @@ func (c *Conn) handlePacket(p receivedPacket) {
 }
 
 func (c *Conn) handleConnectionCloseFrame(frame *wire.ConnectionCloseFrame) error {
-	if frame.IsApplicationError {
-		return &qerr.ApplicationError{
-			Remote:       true,
-			ErrorCode:    qerr.ApplicationErrorCode(frame.ErrorCode),
-			ErrorMessage: frame.ReasonPhrase,
-		}
-	}
-	return &qerr.TransportError{
-		Remote:       true,
-		ErrorCode:    qerr.TransportErrorCode(frame.ErrorCode),
-		FrameType:    frame.FrameType,
-		ErrorMessage: frame.ReasonPhrase,
-	}
+	return frame.ToError(true)
 }
 
 func (c *Conn) handleCryptoFrame(frame *wire.CryptoFrame, encLevel protocol.EncryptionLevel, rcvTime time.Time) error {
@@ -1965,6 +1981,7 @@ func (c *Conn) applyTransportParameters() {
 	c.frameParser.SetAckDelayExponent(params.AckDelayExponent)
 	c.connFlowController.UpdateSendWindow(params.InitialMaxData)
 	c.rttStats.SetMaxAckDelay(params.MaxAckDelay)
+	c.frameParser.SetMaxAckDelay(params.MaxAckDelay)
 	c.connIDGenerator.SetMaxActiveConnIDs(params.ActiveConnectionIDLimit)
 	if params.StatelessResetToken != nil {
 		c.connIDManager.SetStatelessResetToken(*params.StatelessResetToken)
@@ -2587,6 +2604,7 @@ func (c *Conn) getPathManager() *pathManagerOutgoing {
 				c.connIDManager.GetConnIDForPath,
 				c.connIDManager.RetireConnIDForPath,
 				c.scheduleSending,
+				c.random,
 			)
 		}(),
 	)
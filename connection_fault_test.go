@@ -0,0 +1,34 @@
+//go:build quicdebug
+
+package quic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/internal/wire"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionFrameParsingFaultPropagation(t *testing.T) {
+	tc := newServerTestConnection(t, nil, nil, false)
+	tc.conn.frameParser.SetFaultInjector(&wire.FaultInjector{EveryNth: 2})
+
+	b, err := (&wire.PingFrame{}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	b, err = (&wire.PingFrame{}).Append(b, protocol.Version1)
+	require.NoError(t, err)
+	isAckEliciting, _, _, err := tc.conn.handleFrames(b, protocol.ConnectionID{}, protocol.Encryption1RTT, nil, time.Now())
+	require.False(t, isAckEliciting)
+	require.Equal(t, &wire.FrameParsingError{
+		Err: &qerr.TransportError{
+			FrameType:    0x1,
+			ErrorCode:    qerr.FrameEncodingError,
+			ErrorMessage: wire.ErrInjectedFault.Error(),
+		},
+		Offset: 1,
+	}, err)
+}
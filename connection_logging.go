@@ -41,11 +41,14 @@ func toLoggingFrame(frame wire.Frame) logging.Frame {
 
 func toLoggingAckFrame(f *wire.AckFrame) *logging.AckFrame {
 	ack := &logging.AckFrame{
-		AckRanges: slices.Clone(f.AckRanges),
-		DelayTime: f.DelayTime,
-		ECNCE:     f.ECNCE,
-		ECT0:      f.ECT0,
-		ECT1:      f.ECT1,
+		AckRanges:  slices.Clone(f.AckRanges),
+		DelayTime:  f.DelayTime,
+		ECNPresent: f.ECNPresent,
+		ECNCE:      f.ECNCE,
+		ECT0:       f.ECT0,
+		ECT1:       f.ECT1,
+
+		DelayExceedsMaxAckDelay: f.DelayExceedsMaxAckDelay,
 	}
 	return ack
 }
@@ -124,6 +124,7 @@ func newServerTestConnection(
 		srcConnID,
 		&protocol.DefaultConnectionIDGenerator{},
 		newStatelessResetter(nil),
+		rand.Reader,
 		populateConfig(config),
 		&tls.Config{},
 		handshake.NewTokenGenerator(handshake.TokenProtectorKey{}),
@@ -183,6 +184,7 @@ func newClientTestConnection(
 		srcConnID,
 		&protocol.DefaultConnectionIDGenerator{},
 		newStatelessResetter(nil),
+		rand.Reader,
 		populateConfig(config),
 		&tls.Config{ServerName: "quic-go.net"},
 		0,
@@ -266,6 +268,20 @@ func TestConnectionServerInvalidFrames(t *testing.T) {
 	}
 }
 
+func TestConnectionFrameParsingErrorTracing(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	tr, tracer := mocklogging.NewMockConnectionTracer(mockCtrl)
+	tc := newServerTestConnection(t, mockCtrl, nil, false, connectionOptTracer(tr))
+
+	b, err := (&wire.PingFrame{}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	// a MAX_STREAM_DATA frame that's missing the maximum stream data
+	b = append(b, 0x11, 0x5)
+	tracer.EXPECT().Debug("frame_parsing_error", "MAX_STREAM_DATA frame at offset 1 (fields: [5]): 1105")
+	_, _, _, err = tc.conn.handleFrames(b, protocol.ConnectionID{}, protocol.Encryption1RTT, nil, time.Now())
+	require.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.FrameEncodingError, FrameType: 0x11})
+}
+
 func TestConnectionTransportError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	tr, tracer := mocklogging.NewMockConnectionTracer(mockCtrl)
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/tls"
 	"log"
 	"net"
@@ -28,6 +29,7 @@ func main() {
 			RootCAs:            testdata.GetRootCA(),
 			ClientSessionCache: tls.NewLRUClientSessionCache(1),
 		},
+		rand.Reader,
 		false,
 		&utils.RTTStats{},
 		nil,
@@ -43,6 +45,7 @@ func main() {
 		&net.UDPAddr{IP: net.IPv6loopback, Port: 4321},
 		&wire.TransportParameters{ActiveConnectionIDLimit: 2},
 		config,
+		rand.Reader,
 		false,
 		&utils.RTTStats{},
 		nil,
@@ -281,6 +281,7 @@ func runHandshake(runConfig [confLen]byte, messageConfig uint8, clientConf *tls.
 		protocol.ConnectionID{},
 		clientTP,
 		clientConf,
+		rand.Reader,
 		enable0RTTClient,
 		&utils.RTTStats{},
 		nil,
@@ -298,6 +299,7 @@ func runHandshake(runConfig [confLen]byte, messageConfig uint8, clientConf *tls.
 		&net.UDPAddr{IP: net.IPv6loopback, Port: 4321},
 		serverTP,
 		serverConf,
+		rand.Reader,
 		enable0RTTServer,
 		&utils.RTTStats{},
 		nil,
@@ -1,6 +1,9 @@
 package ackhandler
 
 import (
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/wire"
 )
 
@@ -19,3 +22,18 @@ type StreamFrame struct {
 	Frame   *wire.StreamFrame
 	Handler FrameHandler
 }
+
+// setProvenance records the packet that STREAM and CRYPTO frames are first sent in.
+// Frames that are retransmitted keep the provenance of their original transmission.
+func setProvenance(pn protocol.PacketNumber, t time.Time, streamFrames []StreamFrame, frames []Frame) {
+	for _, f := range streamFrames {
+		if !f.Frame.Provenance.IsSet() {
+			f.Frame.Provenance = wire.Provenance{PacketNumber: pn, EnqueueTime: t}
+		}
+	}
+	for _, f := range frames {
+		if cf, ok := f.Frame.(*wire.CryptoFrame); ok && !cf.Provenance.IsSet() {
+			cf.Provenance = wire.Provenance{PacketNumber: pn, EnqueueTime: t}
+		}
+	}
+}
@@ -70,11 +70,9 @@ func (h *receivedPacketTracker) IsPotentiallyDuplicate(pn protocol.PacketNumber)
 	return h.packetHistory.IsPotentiallyDuplicate(pn)
 }
 
-// number of ack-eliciting packets received before sending an ACK
-const packetsBeforeAck = 2
-
 // The appDataReceivedPacketTracker tracks packets received in the Application Data packet number space.
-// It waits until at least 2 packets were received before queueing an ACK, or until the max_ack_delay was reached.
+// It uses a wire.AckPolicy to decide when to send an ACK: it waits until at least 2 packets were received
+// before queueing an ACK, or until the max_ack_delay was reached.
 type appDataReceivedPacketTracker struct {
 	receivedPacketTracker
 
@@ -83,11 +81,7 @@ type appDataReceivedPacketTracker struct {
 	largestObserved protocol.PacketNumber
 	ignoreBelow     protocol.PacketNumber
 
-	maxAckDelay time.Duration
-	ackQueued   bool // true if we need send a new ACK
-
-	ackElicitingPacketsReceivedSinceLastAck int
-	ackAlarm                                time.Time
+	ackPolicy *wire.AckPolicy
 
 	logger utils.Logger
 }
@@ -95,7 +89,7 @@ type appDataReceivedPacketTracker struct {
 func newAppDataReceivedPacketTracker(logger utils.Logger) *appDataReceivedPacketTracker {
 	h := &appDataReceivedPacketTracker{
 		receivedPacketTracker: *newReceivedPacketTracker(),
-		maxAckDelay:           protocol.MaxAckDelay,
+		ackPolicy:             wire.NewAckPolicy(0, protocol.MaxAckDelay),
 		logger:                logger,
 	}
 	return h
@@ -112,17 +106,18 @@ func (h *appDataReceivedPacketTracker) ReceivedPacket(pn protocol.PacketNumber,
 	if !ackEliciting {
 		return nil
 	}
-	h.ackElicitingPacketsReceivedSinceLastAck++
-	isMissing := h.isMissing(pn)
-	if !h.ackQueued && h.shouldQueueACK(pn, ecn, isMissing) {
-		h.ackQueued = true
-		h.ackAlarm = time.Time{} // cancel the ack alarm
-	}
-	if !h.ackQueued {
+	// Send an ACK if this packet was reported missing in an ACK sent before,
+	// or if there are new missing packets to report.
+	reordered := h.isMissing(pn) || h.hasNewMissingPackets()
+	switch h.ackPolicy.ReceivedPacket(rcvTime, ackEliciting, reordered, ecn) {
+	case wire.AckDecisionNow:
+		if h.logger.Debug() {
+			h.logger.Debugf("\tQueueing ACK for packet %d.", pn)
+		}
+	case wire.AckDecisionDelay:
 		// No ACK queued, but we'll need to acknowledge the packet after max_ack_delay.
-		h.ackAlarm = rcvTime.Add(h.maxAckDelay)
 		if h.logger.Debug() {
-			h.logger.Debugf("\tSetting ACK timer to max ack delay: %s", h.maxAckDelay)
+			h.logger.Debugf("\tSetting ACK timer to max ack delay: %s", protocol.MaxAckDelay)
 		}
 	}
 	return nil
@@ -157,63 +152,17 @@ func (h *appDataReceivedPacketTracker) hasNewMissingPackets() bool {
 	return highestRange.Smallest > h.lastAck.LargestAcked()+1 && highestRange.Len() == 1
 }
 
-func (h *appDataReceivedPacketTracker) shouldQueueACK(pn protocol.PacketNumber, ecn protocol.ECN, wasMissing bool) bool {
-	// always acknowledge the first packet
-	if h.lastAck == nil {
-		h.logger.Debugf("\tQueueing ACK because the first packet should be acknowledged.")
-		return true
-	}
-
-	// Send an ACK if this packet was reported missing in an ACK sent before.
-	// Ack decimation with reordering relies on the timer to send an ACK, but if
-	// missing packets we reported in the previous ACK, send an ACK immediately.
-	if wasMissing {
-		if h.logger.Debug() {
-			h.logger.Debugf("\tQueueing ACK because packet %d was missing before.", pn)
-		}
-		return true
-	}
-
-	// send an ACK every 2 ack-eliciting packets
-	if h.ackElicitingPacketsReceivedSinceLastAck >= packetsBeforeAck {
-		if h.logger.Debug() {
-			h.logger.Debugf("\tQueueing ACK because packet %d packets were received after the last ACK (using initial threshold: %d).", h.ackElicitingPacketsReceivedSinceLastAck, packetsBeforeAck)
-		}
-		return true
-	}
-
-	// queue an ACK if there are new missing packets to report
-	if h.hasNewMissingPackets() {
-		h.logger.Debugf("\tQueuing ACK because there's a new missing packet to report.")
-		return true
-	}
-
-	// queue an ACK if the packet was ECN-CE marked
-	if ecn == protocol.ECNCE {
-		h.logger.Debugf("\tQueuing ACK because the packet was ECN-CE marked.")
-		return true
-	}
-	return false
-}
-
 func (h *appDataReceivedPacketTracker) GetAckFrame(now time.Time, onlyIfQueued bool) *wire.AckFrame {
-	if onlyIfQueued && !h.ackQueued {
-		if h.ackAlarm.IsZero() || h.ackAlarm.After(now) {
-			return nil
-		}
-		if h.logger.Debug() && !h.ackAlarm.IsZero() {
-			h.logger.Debugf("Sending ACK because the ACK timer expired.")
-		}
+	if onlyIfQueued && !h.ackPolicy.ShouldSendAck(now) {
+		return nil
 	}
 	ack := h.receivedPacketTracker.GetAckFrame()
 	if ack == nil {
 		return nil
 	}
 	ack.DelayTime = max(0, now.Sub(h.largestObservedRcvdTime))
-	h.ackQueued = false
-	h.ackAlarm = time.Time{}
-	h.ackElicitingPacketsReceivedSinceLastAck = 0
+	h.ackPolicy.SentAck()
 	return ack
 }
 
-func (h *appDataReceivedPacketTracker) GetAlarmTimeout() time.Time { return h.ackAlarm }
+func (h *appDataReceivedPacketTracker) GetAlarmTimeout() time.Time { return h.ackPolicy.AlarmTimeout() }
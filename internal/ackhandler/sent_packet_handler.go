@@ -282,6 +282,7 @@ func (h *sentPacketHandler) SentPacket(
 		if h.numProbesToSend > 0 {
 			h.numProbesToSend--
 		}
+		setProvenance(pn, t, streamFrames, frames)
 	}
 	h.congestion.OnPacketSent(t, h.bytesInFlight, pn, size, isAckEliciting)
 
@@ -360,6 +361,9 @@ func (h *sentPacketHandler) ReceivedAck(ack *wire.AckFrame, encLevel protocol.En
 			var ackDelay time.Duration
 			if encLevel == protocol.Encryption1RTT {
 				ackDelay = min(ack.DelayTime, h.rttStats.MaxAckDelay())
+				if ack.DelayExceedsMaxAckDelay && h.logger.Debug() {
+					h.logger.Debugf("\tACK delay (%s) exceeds the peer's max_ack_delay (%s)", ack.DelayTime, h.rttStats.MaxAckDelay())
+				}
 			}
 			h.rttStats.UpdateRTT(rcvTime.Sub(p.SendTime), ackDelay)
 			if h.logger.Debug() {
@@ -155,6 +155,24 @@ func testSentPacketHandlerSendAndAcknowledge(t *testing.T, encLevel protocol.Enc
 	require.ErrorContains(t, err, "received ACK for an unsent packet")
 }
 
+func TestSentPacketHandlerSetsProvenance(t *testing.T) {
+	sph := newSentPacketHandler(0, 1200, &utils.RTTStats{}, false, false, protocol.PerspectiveClient, nil, utils.DefaultLogger)
+
+	sf := &wire.StreamFrame{StreamID: 4, Data: []byte("foobar")}
+	cf := &wire.CryptoFrame{Data: []byte("foobar")}
+	now := time.Now()
+	pn := sph.PopPacketNumber(protocol.Encryption1RTT)
+	sph.SentPacket(now, pn, protocol.InvalidPacketNumber, []StreamFrame{{Frame: sf}}, []Frame{{Frame: cf}}, protocol.Encryption1RTT, protocol.ECNNon, 1200, false, false)
+	require.Equal(t, wire.Provenance{PacketNumber: pn, EnqueueTime: now}, sf.Provenance)
+	require.Equal(t, wire.Provenance{PacketNumber: pn, EnqueueTime: now}, cf.Provenance)
+
+	// retransmissions keep the provenance of the original transmission
+	pn2 := sph.PopPacketNumber(protocol.Encryption1RTT)
+	sph.SentPacket(now.Add(time.Second), pn2, protocol.InvalidPacketNumber, []StreamFrame{{Frame: sf}}, []Frame{{Frame: cf}}, protocol.Encryption1RTT, protocol.ECNNon, 1200, false, false)
+	require.Equal(t, wire.Provenance{PacketNumber: pn, EnqueueTime: now}, sf.Provenance)
+	require.Equal(t, wire.Provenance{PacketNumber: pn, EnqueueTime: now}, cf.Provenance)
+}
+
 func TestSentPacketHandlerAcknowledgeSkippedPacket(t *testing.T) {
 	sph := newSentPacketHandler(
 		0,
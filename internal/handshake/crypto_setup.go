@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"strings"
 	"sync/atomic"
@@ -34,6 +35,8 @@ type cryptoSetup struct {
 
 	ourParams  *wire.TransportParameters
 	peerParams *wire.TransportParameters
+	// The source of randomness for the greased transport parameter.
+	rand io.Reader
 
 	zeroRTTParameters *wire.TransportParameters
 	allow0RTT         bool
@@ -70,6 +73,7 @@ func NewCryptoSetupClient(
 	connID protocol.ConnectionID,
 	tp *wire.TransportParameters,
 	tlsConf *tls.Config,
+	rand io.Reader,
 	enable0RTT bool,
 	rttStats *utils.RTTStats,
 	tracer *logging.ConnectionTracer,
@@ -79,6 +83,7 @@ func NewCryptoSetupClient(
 	cs := newCryptoSetup(
 		connID,
 		tp,
+		rand,
 		rttStats,
 		tracer,
 		logger,
@@ -95,8 +100,6 @@ func NewCryptoSetupClient(
 		TLSConfig:           tlsConf,
 		EnableSessionEvents: true,
 	})
-	cs.conn.SetTransportParameters(cs.ourParams.Marshal(protocol.PerspectiveClient))
-
 	return cs
 }
 
@@ -106,6 +109,7 @@ func NewCryptoSetupServer(
 	localAddr, remoteAddr net.Addr,
 	tp *wire.TransportParameters,
 	tlsConf *tls.Config,
+	rand io.Reader,
 	allow0RTT bool,
 	rttStats *utils.RTTStats,
 	tracer *logging.ConnectionTracer,
@@ -115,6 +119,7 @@ func NewCryptoSetupServer(
 	cs := newCryptoSetup(
 		connID,
 		tp,
+		rand,
 		rttStats,
 		tracer,
 		logger,
@@ -136,6 +141,7 @@ func NewCryptoSetupServer(
 func newCryptoSetup(
 	connID protocol.ConnectionID,
 	tp *wire.TransportParameters,
+	rand io.Reader,
 	rttStats *utils.RTTStats,
 	tracer *logging.ConnectionTracer,
 	logger utils.Logger,
@@ -153,6 +159,7 @@ func newCryptoSetup(
 		aead:          newUpdatableAEAD(rttStats, tracer, logger, version),
 		events:        make([]Event, 0, 16),
 		ourParams:     tp,
+		rand:          rand,
 		rttStats:      rttStats,
 		tracer:        tracer,
 		logger:        logger,
@@ -176,6 +183,14 @@ func (h *cryptoSetup) SetLargest1RTTAcked(pn protocol.PacketNumber) error {
 }
 
 func (h *cryptoSetup) StartHandshake(ctx context.Context) error {
+	// The client's transport parameters are sent in the ClientHello.
+	if h.perspective == protocol.PerspectiveClient {
+		params, err := h.ourParams.MarshalWithRand(protocol.PerspectiveClient, h.rand)
+		if err != nil {
+			return err
+		}
+		h.conn.SetTransportParameters(params)
+	}
 	err := h.conn.Start(context.WithValue(ctx, QUICVersionContextKey, h.version))
 	if err != nil {
 		return wrapError(err)
@@ -243,7 +258,11 @@ func (h *cryptoSetup) handleEvent(ev tls.QUICEvent) (err error) {
 	case tls.QUICTransportParameters:
 		return h.handleTransportParameters(ev.Data)
 	case tls.QUICTransportParametersRequired:
-		h.conn.SetTransportParameters(h.ourParams.Marshal(h.perspective))
+		params, err := h.ourParams.MarshalWithRand(h.perspective, h.rand)
+		if err != nil {
+			return err
+		}
+		h.conn.SetTransportParameters(params)
 		return nil
 	case tls.QUICRejectedEarlyData:
 		h.rejected0RTT()
@@ -69,6 +69,7 @@ func TestErrorBeforeClientHelloGeneration(t *testing.T) {
 		protocol.ConnectionID{},
 		&wire.TransportParameters{},
 		tlsConf,
+		rand.Reader,
 		false,
 		&utils.RTTStats{},
 		nil,
@@ -91,6 +92,7 @@ func TestMessageReceivedAtWrongEncryptionLevel(t *testing.T) {
 		&net.UDPAddr{IP: net.IPv6loopback, Port: 4321},
 		&wire.TransportParameters{StatelessResetToken: &token},
 		testdata.GetTLSConfig(),
+		rand.Reader,
 		false,
 		&utils.RTTStats{},
 		nil,
@@ -189,6 +191,7 @@ func handshakeWithTLSConf(
 		protocol.ConnectionID{},
 		clientTransportParameters,
 		clientConf,
+		rand.Reader,
 		enable0RTT,
 		clientRTTStats,
 		nil,
@@ -206,6 +209,7 @@ func handshakeWithTLSConf(
 		&net.UDPAddr{IP: net.IPv6loopback, Port: 4321},
 		serverTransportParameters,
 		serverConf,
+		rand.Reader,
 		enable0RTT,
 		serverRTTStats,
 		nil,
@@ -282,6 +286,7 @@ func TestTransportParameters(t *testing.T) {
 		protocol.ConnectionID{},
 		cTransportParameters,
 		clientConf,
+		rand.Reader,
 		false,
 		&utils.RTTStats{},
 		nil,
@@ -301,6 +306,7 @@ func TestTransportParameters(t *testing.T) {
 		&net.UDPAddr{IP: net.IPv6loopback, Port: 4321},
 		sTransportParameters,
 		serverConf,
+		rand.Reader,
 		false,
 		&utils.RTTStats{},
 		nil,
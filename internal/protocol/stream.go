@@ -1,6 +1,10 @@
 package protocol
 
-import "github.com/quic-go/quic-go/quicvarint"
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/quicvarint"
+)
 
 // StreamType encodes if this is a unidirectional or bidirectional stream
 type StreamType uint8
@@ -100,3 +104,36 @@ func (s StreamID) Type() StreamType {
 func (s StreamID) StreamNum() StreamNum {
 	return StreamNum(s/4) + 1
 }
+
+// ValidateStreamCount checks that count is a valid stream count (as sent in MAX_STREAMS and STREAMS_BLOCKED frames,
+// and in the transport parameters) for streams of the given type.
+// It returns an error if the stream type is invalid, or if the count is negative or larger than MaxStreamCount.
+func ValidateStreamCount(count StreamNum, stype StreamType) error {
+	if stype != StreamTypeBidi && stype != StreamTypeUni {
+		return fmt.Errorf("invalid stream type: %d", stype)
+	}
+	if count < 0 || count > MaxStreamCount {
+		return fmt.Errorf("invalid stream count: %d (maximum %d)", count, MaxStreamCount)
+	}
+	return nil
+}
+
+// MaxStreamIDForCount converts a stream count (as sent in MAX_STREAMS frames and in the transport parameters)
+// into the largest stream ID of the given type that the endpoint pers is allowed to open.
+// It returns InvalidStreamID if the count is 0, and an error if the count is invalid (see ValidateStreamCount).
+func MaxStreamIDForCount(count StreamNum, stype StreamType, pers Perspective) (StreamID, error) {
+	if err := ValidateStreamCount(count, stype); err != nil {
+		return InvalidStreamID, err
+	}
+	return count.StreamID(stype, pers), nil
+}
+
+// StreamCountForMaxStreamID is the inverse of MaxStreamIDForCount:
+// It returns the number of streams of the type and initiator of id, up to and including id.
+// It returns 0 for InvalidStreamID (and any other negative stream ID).
+func StreamCountForMaxStreamID(id StreamID) StreamNum {
+	if id < 0 {
+		return 0
+	}
+	return id.StreamNum()
+}
@@ -64,3 +64,31 @@ func TestMaxStreamCountValue(t *testing.T) {
 		}
 	}
 }
+
+func TestMaxStreamIDForCount(t *testing.T) {
+	id, err := MaxStreamIDForCount(100, StreamTypeUni, PerspectiveServer)
+	require.NoError(t, err)
+	require.Equal(t, StreamID(399), id)
+	require.Equal(t, StreamNum(100), StreamCountForMaxStreamID(id))
+
+	id, err = MaxStreamIDForCount(0, StreamTypeBidi, PerspectiveClient)
+	require.NoError(t, err)
+	require.Equal(t, InvalidStreamID, id)
+	require.Zero(t, StreamCountForMaxStreamID(id))
+
+	for _, dir := range []StreamType{StreamTypeUni, StreamTypeBidi} {
+		for _, pers := range []Perspective{PerspectiveClient, PerspectiveServer} {
+			id, err := MaxStreamIDForCount(MaxStreamCount, dir, pers)
+			require.NoError(t, err)
+			require.LessOrEqual(t, id, MaxStreamID)
+			require.Equal(t, MaxStreamCount, StreamCountForMaxStreamID(id))
+		}
+	}
+
+	_, err = MaxStreamIDForCount(MaxStreamCount+1, StreamTypeBidi, PerspectiveClient)
+	require.EqualError(t, err, "invalid stream count: 1152921504606846977 (maximum 1152921504606846976)")
+	_, err = MaxStreamIDForCount(-1, StreamTypeBidi, PerspectiveClient)
+	require.EqualError(t, err, "invalid stream count: -1 (maximum 1152921504606846976)")
+	_, err = MaxStreamIDForCount(1, 42, PerspectiveClient)
+	require.EqualError(t, err, "invalid stream type: 42")
+}
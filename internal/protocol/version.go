@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
 	mrand "math/rand/v2"
 	"slices"
@@ -101,15 +102,42 @@ func generateReservedVersion() Version {
 	return Version((binary.BigEndian.Uint32(b[:]) | 0x0a0a0a0a) & 0xfafafafa)
 }
 
+// IsReservedVersion says if the version is a reserved version.
+// Reserved versions are used to exercise version negotiation (see section 15 of RFC 9000).
+func IsReservedVersion(v Version) bool {
+	return v&0x0f0f0f0f == 0x0a0a0a0a
+}
+
+// GetReservedVersion returns a random reserved version.
+func GetReservedVersion() Version {
+	versionNegotiationMx.Lock()
+	defer versionNegotiationMx.Unlock()
+	return generateReservedVersion()
+}
+
 // GetGreasedVersions adds one reserved version number to a slice of version numbers, at a random position.
 // It doesn't modify the supported slice.
+// If reading from crypto/rand fails, no reserved version number is added.
 func GetGreasedVersions(supported []Version) []Version {
-	versionNegotiationMx.Lock()
-	defer versionNegotiationMx.Unlock()
-	randPos := versionNegotiationRand.IntN(len(supported) + 1)
+	greased, err := GetGreasedVersionsWithRand(supported, rand.Reader)
+	if err != nil {
+		return slices.Clone(supported)
+	}
+	return greased
+}
+
+// GetGreasedVersionsWithRand is like GetGreasedVersions, but uses r as the source of randomness.
+// This allows tests to be deterministic, and deployments to supply an approved random number generator.
+// It returns an error if reading from r fails.
+func GetGreasedVersionsWithRand(supported []Version, r io.Reader) ([]Version, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+	randPos := int(binary.BigEndian.Uint32(b[:4]) % uint32(len(supported)+1))
 	greased := make([]Version, len(supported)+1)
 	copy(greased, supported[:randPos])
-	greased[randPos] = generateReservedVersion()
+	greased[randPos] = Version((binary.BigEndian.Uint32(b[4:]) | 0x0a0a0a0a) & 0xfafafafa)
 	copy(greased[randPos+1:], supported[randPos:])
-	return greased
+	return greased, nil
 }
@@ -1,6 +1,8 @@
 package protocol
 
 import (
+	"bytes"
+	"io"
 	"slices"
 	"testing"
 
@@ -94,13 +96,21 @@ func TestVersionSelection(t *testing.T) {
 	}
 }
 
-func isReservedVersion(v Version) bool { return v&0x0f0f0f0f == 0x0a0a0a0a }
+func TestReservedVersions(t *testing.T) {
+	require.True(t, IsReservedVersion(0x0a0a0a0a))
+	require.True(t, IsReservedVersion(0x1a2a3a4a))
+	require.False(t, IsReservedVersion(Version1))
+	require.False(t, IsReservedVersion(Version2))
+	for range 10 {
+		require.True(t, IsReservedVersion(GetReservedVersion()))
+	}
+}
 
 func TestVersionGreasing(t *testing.T) {
 	// adding to an empty slice
 	greased := GetGreasedVersions([]Version{})
 	require.Len(t, greased, 1)
-	require.True(t, isReservedVersion(greased[0]))
+	require.True(t, IsReservedVersion(greased[0]))
 
 	// make sure that the greased versions are distinct,
 	// allowing for a small number of duplicates
@@ -111,7 +121,7 @@ func TestVersionGreasing(t *testing.T) {
 	slices.Sort(versions)
 	var numDuplicates int
 	for i, v := range versions {
-		require.True(t, isReservedVersion(v))
+		require.True(t, IsReservedVersion(v))
 		if i > 0 && versions[i-1] == v {
 			numDuplicates++
 		}
@@ -121,7 +131,7 @@ func TestVersionGreasing(t *testing.T) {
 	// adding it somewhere in a slice of supported versions
 	supported := []Version{10, 18, 29}
 	for _, v := range supported {
-		require.False(t, isReservedVersion(v))
+		require.False(t, IsReservedVersion(v))
 	}
 
 	var greasedVersionFirst, greasedVersionLast, greasedVersionMiddle int
@@ -131,7 +141,7 @@ func TestVersionGreasing(t *testing.T) {
 
 		var j int
 		for i, v := range greased {
-			if isReservedVersion(v) {
+			if IsReservedVersion(v) {
 				if i == 0 {
 					greasedVersionFirst++
 				}
@@ -149,3 +159,14 @@ func TestVersionGreasing(t *testing.T) {
 	require.NotZero(t, greasedVersionLast)
 	require.NotZero(t, greasedVersionMiddle)
 }
+
+func TestVersionGreasingWithRand(t *testing.T) {
+	supported := []Version{10, 18, 29}
+	r := bytes.NewReader([]byte{0, 0, 0, 5, 0x1a, 0x2a, 0x3a, 0x4a})
+	greased, err := GetGreasedVersionsWithRand(supported, r)
+	require.NoError(t, err)
+	require.Equal(t, []Version{10, 0x1a2a3a4a, 18, 29}, greased)
+
+	_, err = GetGreasedVersionsWithRand(supported, bytes.NewReader([]byte{1, 2, 3}))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -3,7 +3,10 @@ package wire
 import (
 	"errors"
 	"math"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/quic-go/quic-go/internal/protocol"
@@ -17,45 +20,44 @@ type AckFrame struct {
 	AckRanges []AckRange // has to be ordered. The highest ACK range goes first, the lowest ACK range goes last
 	DelayTime time.Duration
 
+	// ECNPresent is true if the frame carries ECN counts, i.e. if it is an ACK_ECN frame.
+	// When parsing, it is set according to the frame type.
+	// When serializing, an ACK_ECN frame is written if it is set, or if any of the counts is non-zero.
+	ECNPresent        bool
 	ECT0, ECT1, ECNCE uint64
+
+	// DelayExceedsMaxAckDelay is set by the FrameParser if the ACK Delay is larger than
+	// the max_ack_delay advertised by the peer. It is not serialized.
+	DelayExceedsMaxAckDelay bool
 }
 
 // parseAckFrame reads an ACK frame
 func parseAckFrame(frame *AckFrame, b []byte, typ uint64, ackDelayExponent uint8, _ protocol.Version) (int, error) {
-	startLen := len(b)
+	c := newCursor(b)
 	ecn := typ == ackECNFrameType
 
-	la, l, err := quicvarint.Parse(b)
+	la, err := c.readVarInt()
 	if err != nil {
-		return 0, replaceUnexpectedEOF(err)
+		return 0, err
 	}
-	b = b[l:]
 	largestAcked := protocol.PacketNumber(la)
-	delay, l, err := quicvarint.Parse(b)
+	delay, err := c.readVarInt()
 	if err != nil {
-		return 0, replaceUnexpectedEOF(err)
+		return 0, err
 	}
-	b = b[l:]
 
-	delayTime := time.Duration(delay*1<<ackDelayExponent) * time.Microsecond
-	if delayTime < 0 {
-		// If the delay time overflows, set it to the maximum encode-able value.
-		delayTime = time.Duration(math.MaxInt64)
-	}
-	frame.DelayTime = delayTime
+	frame.DelayTime = DecodeAckDelay(delay, ackDelayExponent)
 
-	numBlocks, l, err := quicvarint.Parse(b)
+	numBlocks, err := c.readVarInt()
 	if err != nil {
-		return 0, replaceUnexpectedEOF(err)
+		return 0, err
 	}
-	b = b[l:]
 
 	// read the first ACK range
-	ab, l, err := quicvarint.Parse(b)
+	ab, err := c.readVarInt()
 	if err != nil {
-		return 0, replaceUnexpectedEOF(err)
+		return 0, err
 	}
-	b = b[l:]
 	ackBlock := protocol.PacketNumber(ab)
 	if ackBlock > largestAcked {
 		return 0, errors.New("invalid first ACK range")
@@ -65,22 +67,20 @@ func parseAckFrame(frame *AckFrame, b []byte, typ uint64, ackDelayExponent uint8
 
 	// read all the other ACK ranges
 	for i := uint64(0); i < numBlocks; i++ {
-		g, l, err := quicvarint.Parse(b)
+		g, err := c.readVarInt()
 		if err != nil {
-			return 0, replaceUnexpectedEOF(err)
+			return 0, err
 		}
-		b = b[l:]
 		gap := protocol.PacketNumber(g)
 		if smallest < gap+2 {
 			return 0, errInvalidAckRanges
 		}
 		largest := smallest - gap - 2
 
-		ab, l, err := quicvarint.Parse(b)
+		ab, err := c.readVarInt()
 		if err != nil {
-			return 0, replaceUnexpectedEOF(err)
+			return 0, err
 		}
-		b = b[l:]
 		ackBlock := protocol.PacketNumber(ab)
 
 		if ackBlock > largest {
@@ -94,40 +94,38 @@ func parseAckFrame(frame *AckFrame, b []byte, typ uint64, ackDelayExponent uint8
 		return 0, errInvalidAckRanges
 	}
 
+	frame.ECNPresent = ecn
 	if ecn {
-		ect0, l, err := quicvarint.Parse(b)
+		ect0, err := c.readVarInt()
 		if err != nil {
-			return 0, replaceUnexpectedEOF(err)
+			return 0, err
 		}
-		b = b[l:]
 		frame.ECT0 = ect0
-		ect1, l, err := quicvarint.Parse(b)
+		ect1, err := c.readVarInt()
 		if err != nil {
-			return 0, replaceUnexpectedEOF(err)
+			return 0, err
 		}
-		b = b[l:]
 		frame.ECT1 = ect1
-		ecnce, l, err := quicvarint.Parse(b)
+		ecnce, err := c.readVarInt()
 		if err != nil {
-			return 0, replaceUnexpectedEOF(err)
+			return 0, err
 		}
-		b = b[l:]
 		frame.ECNCE = ecnce
 	}
 
-	return startLen - len(b), nil
+	return c.consumed(), nil
 }
 
 // Append appends an ACK frame.
 func (f *AckFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
-	hasECN := f.ECT0 > 0 || f.ECT1 > 0 || f.ECNCE > 0
+	hasECN := f.hasECN()
 	if hasECN {
 		b = append(b, ackECNFrameType)
 	} else {
 		b = append(b, ackFrameType)
 	}
 	b = quicvarint.Append(b, uint64(f.LargestAcked()))
-	b = quicvarint.Append(b, encodeAckDelay(f.DelayTime))
+	b = quicvarint.Append(b, EncodeAckDelay(f.DelayTime, protocol.AckDelayExponent))
 
 	numRanges := f.numEncodableAckRanges()
 	b = quicvarint.Append(b, uint64(numRanges-1))
@@ -153,10 +151,45 @@ func (f *AckFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
 
 // Length of a written frame
 func (f *AckFrame) Length(_ protocol.Version) protocol.ByteCount {
-	largestAcked := f.AckRanges[0].Largest
+	return f.lengthWithRanges(f.numEncodableAckRanges())
+}
+
+// LengthCapped returns the length of the frame if at most maxRanges ACK ranges are encoded.
+// maxRanges must be at least 1.
+func (f *AckFrame) LengthCapped(maxRanges int, _ protocol.Version) protocol.ByteCount {
+	return f.lengthWithRanges(min(maxRanges, f.numEncodableAckRanges()))
+}
+
+// AdditionalRangeLength returns by how many bytes the frame grows if the ACK range with index i
+// is encoded in addition to all ACK ranges before it.
+// i must be at least 1, since the first ACK range is always encoded.
+func (f *AckFrame) AdditionalRangeLength(i int) protocol.ByteCount {
+	gap, len := f.encodeAckRange(i)
+	// the number of ACK ranges might require a longer varint encoding
+	numRangesDiff := quicvarint.Len(uint64(i)) - quicvarint.Len(uint64(i-1))
+	return protocol.ByteCount(quicvarint.Len(gap) + quicvarint.Len(len) + numRangesDiff)
+}
+
+// NumRangesFitting returns the number of ACK ranges that can be encoded into maxSize bytes.
+// It returns 0 if not even a frame with a single ACK range fits.
+func (f *AckFrame) NumRangesFitting(maxSize protocol.ByteCount, v protocol.Version) int {
+	length := f.LengthCapped(1, v)
+	if length > maxSize {
+		return 0
+	}
 	numRanges := f.numEncodableAckRanges()
+	for i := 1; i < numRanges; i++ {
+		length += f.AdditionalRangeLength(i)
+		if length > maxSize {
+			return i
+		}
+	}
+	return numRanges
+}
 
-	length := 1 + quicvarint.Len(uint64(largestAcked)) + quicvarint.Len(encodeAckDelay(f.DelayTime))
+func (f *AckFrame) lengthWithRanges(numRanges int) protocol.ByteCount {
+	largestAcked := f.AckRanges[0].Largest
+	length := 1 + quicvarint.Len(uint64(largestAcked)) + quicvarint.Len(EncodeAckDelay(f.DelayTime, protocol.AckDelayExponent))
 
 	length += quicvarint.Len(uint64(numRanges - 1))
 	lowestInFirstRange := f.AckRanges[0].Smallest
@@ -167,7 +200,7 @@ func (f *AckFrame) Length(_ protocol.Version) protocol.ByteCount {
 		length += quicvarint.Len(gap)
 		length += quicvarint.Len(len)
 	}
-	if f.ECT0 > 0 || f.ECT1 > 0 || f.ECNCE > 0 {
+	if f.hasECN() {
 		length += quicvarint.Len(f.ECT0)
 		length += quicvarint.Len(f.ECT1)
 		length += quicvarint.Len(f.ECNCE)
@@ -175,10 +208,24 @@ func (f *AckFrame) Length(_ protocol.Version) protocol.ByteCount {
 	return protocol.ByteCount(length)
 }
 
+func (f *AckFrame) hasECN() bool {
+	return f.ECNPresent || f.ECT0 > 0 || f.ECT1 > 0 || f.ECNCE > 0
+}
+
+// NewlyReportedECNMarks returns the increase of the ECN counts compared to the counts
+// reported in a previously received ACK frame.
+// The ECN counts are cumulative, so ok is false if any of the counts decreased (see section 13.4.2.1 of RFC 9000).
+func (f *AckFrame) NewlyReportedECNMarks(prevECT0, prevECT1, prevECNCE uint64) (ect0, ect1, ecnce uint64, ok bool) {
+	if f.ECT0 < prevECT0 || f.ECT1 < prevECT1 || f.ECNCE < prevECNCE {
+		return 0, 0, 0, false
+	}
+	return f.ECT0 - prevECT0, f.ECT1 - prevECT1, f.ECNCE - prevECNCE, true
+}
+
 // gets the number of ACK ranges that can be encoded
 // such that the resulting frame is smaller than the maximum ACK frame size
 func (f *AckFrame) numEncodableAckRanges() int {
-	length := 1 + quicvarint.Len(uint64(f.LargestAcked())) + quicvarint.Len(encodeAckDelay(f.DelayTime))
+	length := 1 + quicvarint.Len(uint64(f.LargestAcked())) + quicvarint.Len(EncodeAckDelay(f.DelayTime, protocol.AckDelayExponent))
 	length += 2 // assume that the number of ranges will consume 2 bytes
 	for i := 1; i < len(f.AckRanges); i++ {
 		gap, len := f.encodeAckRange(i)
@@ -258,8 +305,44 @@ func (f *AckFrame) AcksPacket(p protocol.PacketNumber) bool {
 	return p <= f.AckRanges[i].Largest
 }
 
+// RangesString returns a compact representation of the acknowledged packets, in ascending order,
+// followed by the sizes of the gaps between the ranges, e.g. "0-4199,5000-5200 (gap 800)".
+// Ranges containing a single packet are printed as a single packet number.
+func (f *AckFrame) RangesString() string {
+	var sb strings.Builder
+	for i := len(f.AckRanges) - 1; i >= 0; i-- {
+		r := f.AckRanges[i]
+		if i < len(f.AckRanges)-1 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatInt(int64(r.Smallest), 10))
+		if r.Largest != r.Smallest {
+			sb.WriteByte('-')
+			sb.WriteString(strconv.FormatInt(int64(r.Largest), 10))
+		}
+	}
+	if len(f.AckRanges) < 2 {
+		return sb.String()
+	}
+	if len(f.AckRanges) == 2 {
+		sb.WriteString(" (gap ")
+	} else {
+		sb.WriteString(" (gaps ")
+	}
+	for i := len(f.AckRanges) - 1; i > 0; i-- {
+		if i < len(f.AckRanges)-1 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(strconv.FormatInt(int64(f.AckRanges[i-1].Smallest-f.AckRanges[i].Largest-1), 10))
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
 func (f *AckFrame) Reset() {
 	f.DelayTime = 0
+	f.DelayExceedsMaxAckDelay = false
+	f.ECNPresent = false
 	f.ECT0 = 0
 	f.ECT1 = 0
 	f.ECNCE = 0
@@ -270,6 +353,30 @@ func (f *AckFrame) Reset() {
 	f.AckRanges = f.AckRanges[:0]
 }
 
-func encodeAckDelay(delay time.Duration) uint64 {
-	return uint64(delay.Nanoseconds() / (1000 * (1 << protocol.AckDelayExponent)))
+// Clone returns a deep copy of the ACK frame.
+// The ACK frame returned by the FrameParser is reused for the next ACK frame,
+// so it must be cloned if it is retained beyond the next call to ParseNext.
+func (f *AckFrame) Clone() *AckFrame {
+	c := *f
+	c.AckRanges = slices.Clone(f.AckRanges)
+	return &c
+}
+
+// EncodeAckDelay encodes an ACK delay for the ACK Delay field of an ACK frame,
+// in units of 2^exponent microseconds (see section 19.3 of RFC 9000).
+// The delay is rounded down, and negative delays are encoded as 0.
+func EncodeAckDelay(delay time.Duration, exponent uint8) uint64 {
+	if delay <= 0 {
+		return 0
+	}
+	return uint64(delay.Microseconds()) >> exponent
+}
+
+// DecodeAckDelay decodes the ACK Delay field of an ACK frame.
+// If the delay overflows a time.Duration, the maximum time.Duration is returned.
+func DecodeAckDelay(delay uint64, exponent uint8) time.Duration {
+	if delay > uint64(math.MaxInt64/int64(time.Microsecond))>>exponent {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay<<exponent) * time.Microsecond
 }
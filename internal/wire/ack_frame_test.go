@@ -138,6 +138,30 @@ func TestParseACKHandleDelayTimeOverflow(t *testing.T) {
 	require.InDelta(t, 292*365*24, frame.DelayTime.Hours(), 365*24)
 }
 
+func TestAckDelayEncoding(t *testing.T) {
+	require.Zero(t, EncodeAckDelay(-time.Second, 3))
+	require.Zero(t, EncodeAckDelay(7*time.Microsecond, 3))
+	require.Equal(t, uint64(1), EncodeAckDelay(8*time.Microsecond, 3))
+	// rounded down
+	require.Equal(t, uint64(1), EncodeAckDelay(15*time.Microsecond, 3))
+	require.Equal(t, uint64(1000), EncodeAckDelay(time.Millisecond, 0))
+	require.Equal(t, uint64(math.MaxInt64/1000), EncodeAckDelay(math.MaxInt64, 0))
+
+	require.Equal(t, 8*time.Microsecond, DecodeAckDelay(1, 3))
+	require.Equal(t, time.Millisecond, DecodeAckDelay(1000, 0))
+	require.Equal(t, time.Duration(math.MaxInt64), DecodeAckDelay(math.MaxInt64/1000+1, 0))
+	require.Equal(t, time.Duration(math.MaxInt64), DecodeAckDelay(quicvarint.Max, protocol.MaxAckDelayExponent))
+	require.Less(t, DecodeAckDelay(math.MaxInt64/1000>>3, 3), time.Duration(math.MaxInt64))
+
+	for _, d := range []time.Duration{0, time.Microsecond, 1337 * time.Microsecond, time.Second, time.Hour} {
+		for exp := uint8(0); exp <= protocol.MaxAckDelayExponent; exp++ {
+			decoded := DecodeAckDelay(EncodeAckDelay(d, exp), exp)
+			require.LessOrEqual(t, decoded, d)
+			require.Less(t, d-decoded, time.Duration(1<<exp)*time.Microsecond)
+		}
+	}
+}
+
 func TestParseACKErrorOnEOF(t *testing.T) {
 	data := encodeVarInt(1000)                // largest acked
 	data = append(data, encodeVarInt(0)...)   // delay
@@ -151,7 +175,7 @@ func TestParseACKErrorOnEOF(t *testing.T) {
 	for i := range data {
 		var frame AckFrame
 		_, err := parseAckFrame(&frame, data[:i], ackFrameType, protocol.AckDelayExponent, protocol.Version1)
-		require.Equal(t, io.EOF, err)
+		require.Equal(t, io.ErrUnexpectedEOF, err)
 	}
 }
 
@@ -170,6 +194,7 @@ func TestParseACKECN(t *testing.T) {
 	require.Equal(t, protocol.PacketNumber(100), frame.LargestAcked())
 	require.Equal(t, protocol.PacketNumber(90), frame.LowestAcked())
 	require.False(t, frame.HasMissingRanges())
+	require.True(t, frame.ECNPresent)
 	require.Equal(t, uint64(0x42), frame.ECT0)
 	require.Equal(t, uint64(0x12345), frame.ECT1)
 	require.Equal(t, uint64(0x12345678), frame.ECNCE)
@@ -192,7 +217,7 @@ func TestParseACKECNErrorOnEOF(t *testing.T) {
 	for i := range data {
 		var frame AckFrame
 		_, err := parseAckFrame(&frame, data[:i], ackECNFrameType, protocol.AckDelayExponent, protocol.Version1)
-		require.Equal(t, io.EOF, err)
+		require.Equal(t, io.ErrUnexpectedEOF, err)
 	}
 }
 
@@ -458,19 +483,132 @@ func TestAckFrameAcksPacketWithMultipleRanges(t *testing.T) {
 	require.False(t, f.AcksPacket(21))
 }
 
+func TestWriteACKECNFrameWithZeroCounts(t *testing.T) {
+	f := &AckFrame{
+		AckRanges:  []AckRange{{Smallest: 10, Largest: 2000}},
+		ECNPresent: true,
+	}
+	b, err := f.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	require.Len(t, b, int(f.Length(protocol.Version1)))
+	require.Equal(t, byte(ackECNFrameType), b[0])
+	require.Equal(t, []byte{0, 0, 0}, b[len(b)-3:])
+
+	var parsed AckFrame
+	_, err = parseAckFrame(&parsed, b[1:], ackECNFrameType, protocol.AckDelayExponent, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, f, &parsed)
+}
+
+func TestParseACKWithAndWithoutECNWithoutAllocations(t *testing.T) {
+	for _, ack := range []*AckFrame{
+		{AckRanges: []AckRange{{Smallest: 300, Largest: 1000}, {Smallest: 10, Largest: 200}}},
+		{AckRanges: []AckRange{{Smallest: 300, Largest: 1000}, {Smallest: 10, Largest: 200}}, ECT0: 1, ECT1: 2, ECNCE: 3},
+	} {
+		b, err := ack.Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		parser := NewFrameParser(false, false)
+		allocs := testing.AllocsPerRun(100, func() {
+			_, _, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+		require.Zero(t, allocs)
+	}
+}
+
+func TestACKNewlyReportedECNMarks(t *testing.T) {
+	f := &AckFrame{
+		AckRanges:  []AckRange{{Smallest: 1, Largest: 10}},
+		ECNPresent: true,
+		ECT0:       10,
+		ECT1:       5,
+		ECNCE:      2,
+	}
+	ect0, ect1, ecnce, ok := f.NewlyReportedECNMarks(7, 5, 1)
+	require.True(t, ok)
+	require.Equal(t, uint64(3), ect0)
+	require.Zero(t, ect1)
+	require.Equal(t, uint64(1), ecnce)
+
+	_, _, _, ok = f.NewlyReportedECNMarks(7, 6, 1)
+	require.False(t, ok)
+}
+
 func TestAckFrameReset(t *testing.T) {
 	f := &AckFrame{
-		DelayTime: time.Second,
-		AckRanges: []AckRange{{Smallest: 1, Largest: 3}},
-		ECT0:      1,
-		ECT1:      2,
-		ECNCE:     3,
+		DelayTime:  time.Second,
+		AckRanges:  []AckRange{{Smallest: 1, Largest: 3}},
+		ECNPresent: true,
+		ECT0:       1,
+		ECT1:       2,
+		ECNCE:      3,
 	}
 	f.Reset()
 	require.Empty(t, f.AckRanges)
 	require.Equal(t, 1, cap(f.AckRanges))
 	require.Zero(t, f.DelayTime)
+	require.False(t, f.ECNPresent)
 	require.Zero(t, f.ECT0)
 	require.Zero(t, f.ECT1)
 	require.Zero(t, f.ECNCE)
 }
+
+func TestAckFrameLengthCapped(t *testing.T) {
+	var ranges []AckRange
+	// use large gaps, such that the number of ranges requires a 2 byte varint
+	for i := 100; i > 0; i-- {
+		ranges = append(ranges, AckRange{Smallest: protocol.PacketNumber(i * 1000), Largest: protocol.PacketNumber(i*1000 + i)})
+	}
+	f := &AckFrame{AckRanges: ranges, ECT0: 1}
+	require.Equal(t, f.Length(protocol.Version1), f.LengthCapped(len(ranges), protocol.Version1))
+	require.Equal(t, f.Length(protocol.Version1), f.LengthCapped(1000, protocol.Version1))
+
+	for n := 1; n <= len(ranges); n++ {
+		capped := &AckFrame{AckRanges: ranges[:n], ECT0: 1}
+		b, err := capped.Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		require.Len(t, b, int(f.LengthCapped(n, protocol.Version1)))
+		if n > 1 {
+			require.Equal(t,
+				f.LengthCapped(n, protocol.Version1)-f.LengthCapped(n-1, protocol.Version1),
+				f.AdditionalRangeLength(n-1),
+			)
+		}
+	}
+}
+
+func TestAckFrameNumRangesFitting(t *testing.T) {
+	f := &AckFrame{AckRanges: []AckRange{
+		{Smallest: 1000, Largest: 2000},
+		{Smallest: 500, Largest: 600},
+		{Smallest: 100, Largest: 200},
+	}}
+	require.Zero(t, f.NumRangesFitting(f.LengthCapped(1, protocol.Version1)-1, protocol.Version1))
+	require.Equal(t, 1, f.NumRangesFitting(f.LengthCapped(1, protocol.Version1), protocol.Version1))
+	require.Equal(t, 1, f.NumRangesFitting(f.LengthCapped(2, protocol.Version1)-1, protocol.Version1))
+	require.Equal(t, 2, f.NumRangesFitting(f.LengthCapped(2, protocol.Version1), protocol.Version1))
+	require.Equal(t, 3, f.NumRangesFitting(f.Length(protocol.Version1), protocol.Version1))
+	require.Equal(t, 3, f.NumRangesFitting(1000, protocol.Version1))
+}
+
+func TestAckFrameRangesString(t *testing.T) {
+	for _, tc := range []struct {
+		ranges   []AckRange
+		expected string
+	}{
+		{ranges: []AckRange{{Smallest: 0, Largest: 4199}}, expected: "0-4199"},
+		{ranges: []AckRange{{Smallest: 7, Largest: 7}}, expected: "7"},
+		{
+			ranges:   []AckRange{{Smallest: 5000, Largest: 5200}, {Smallest: 0, Largest: 4199}},
+			expected: "0-4199,5000-5200 (gap 800)",
+		},
+		{
+			ranges:   []AckRange{{Smallest: 20, Largest: 25}, {Smallest: 10, Largest: 10}, {Smallest: 1, Largest: 8}},
+			expected: "1-8,10,20-25 (gaps 1, 9)",
+		},
+	} {
+		require.Equal(t, tc.expected, (&AckFrame{AckRanges: tc.ranges}).RangesString())
+	}
+}
@@ -0,0 +1,98 @@
+package wire
+
+import (
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// An AckDecision is the decision made by the AckPolicy when a packet is received.
+type AckDecision uint8
+
+const (
+	// AckDecisionNone means that no ACK needs to be sent for the packet.
+	AckDecisionNone AckDecision = iota
+	// AckDecisionDelay means that an ACK needs to be sent when the ACK timer expires.
+	AckDecisionDelay
+	// AckDecisionNow means that an ACK should be sent immediately.
+	AckDecisionNow
+)
+
+func (d AckDecision) String() string {
+	switch d {
+	case AckDecisionNone:
+		return "none"
+	case AckDecisionDelay:
+		return "delay"
+	case AckDecisionNow:
+		return "now"
+	default:
+		return "unknown ack decision"
+	}
+}
+
+// An AckPolicy decides when to send ACK frames for the Application Data packet number space.
+// Following section 13.2.1 of RFC 9000, an ACK is sent immediately for packets that were received out of order,
+// and for packets that were ECN-CE marked. Otherwise, an ACK is sent at most max_ack_delay after receiving
+// an ack-eliciting packet.
+// Following section 13.2.2 of RFC 9000, an ACK is sent after PacketThreshold ack-eliciting packets were received.
+// In addition, the first ack-eliciting packet is acknowledged immediately, to speed up the handshake.
+// Non-ack-eliciting packets never trigger an ACK.
+type AckPolicy struct {
+	packetThreshold int
+	maxAckDelay     time.Duration
+
+	sentAck              bool
+	ackQueued            bool
+	ackElicitingSinceAck int
+	ackAlarm             time.Time
+}
+
+// NewAckPolicy creates a new AckPolicy.
+// If packetThreshold is 0, an ACK is sent every 2 ack-eliciting packets, as recommended by RFC 9000.
+func NewAckPolicy(packetThreshold int, maxAckDelay time.Duration) *AckPolicy {
+	if packetThreshold <= 0 {
+		packetThreshold = 2
+	}
+	return &AckPolicy{packetThreshold: packetThreshold, maxAckDelay: maxAckDelay}
+}
+
+// ReceivedPacket is called for every packet received.
+// reordered says if the packet was received out of order,
+// i.e. if it was reported missing in an ACK sent before, or if it creates a new gap in the received packet numbers.
+func (p *AckPolicy) ReceivedPacket(rcvTime time.Time, ackEliciting, reordered bool, ecn protocol.ECN) AckDecision {
+	if !ackEliciting {
+		return AckDecisionNone
+	}
+	p.ackElicitingSinceAck++
+	if p.ackQueued {
+		return AckDecisionNow
+	}
+	if !p.sentAck || reordered || ecn == protocol.ECNCE || p.ackElicitingSinceAck >= p.packetThreshold {
+		p.ackQueued = true
+		p.ackAlarm = time.Time{}
+		return AckDecisionNow
+	}
+	if p.ackAlarm.IsZero() {
+		p.ackAlarm = rcvTime.Add(p.maxAckDelay)
+	}
+	return AckDecisionDelay
+}
+
+// ShouldSendAck says if an ACK should be sent now.
+// This is the case if an ACK was queued, or if the ACK timer expired.
+func (p *AckPolicy) ShouldSendAck(now time.Time) bool {
+	return p.ackQueued || (!p.ackAlarm.IsZero() && !p.ackAlarm.After(now))
+}
+
+// AlarmTimeout returns the time when the ACK timer expires.
+// It returns the zero value if the timer is not set.
+func (p *AckPolicy) AlarmTimeout() time.Time { return p.ackAlarm }
+
+// SentAck is called when an ACK frame is sent.
+func (p *AckPolicy) SentAck() {
+	p.sentAck = true
+	p.ackQueued = false
+	p.ackAlarm = time.Time{}
+	p.ackElicitingSinceAck = 0
+}
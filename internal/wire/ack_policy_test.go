@@ -0,0 +1,78 @@
+package wire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckPolicyPacketThreshold(t *testing.T) {
+	now := time.Now()
+	p := NewAckPolicy(0, 25*time.Millisecond)
+	require.False(t, p.ShouldSendAck(now))
+
+	// the first ack-eliciting packet is acknowledged immediately
+	require.Equal(t, AckDecisionNow, p.ReceivedPacket(now, true, false, protocol.ECNNon))
+	require.True(t, p.ShouldSendAck(now))
+	p.SentAck()
+	require.False(t, p.ShouldSendAck(now))
+
+	require.Equal(t, AckDecisionNone, p.ReceivedPacket(now, false, false, protocol.ECNNon))
+	require.Zero(t, p.AlarmTimeout())
+	require.Equal(t, AckDecisionDelay, p.ReceivedPacket(now, true, false, protocol.ECNNon))
+	require.Equal(t, now.Add(25*time.Millisecond), p.AlarmTimeout())
+	require.Equal(t, AckDecisionNow, p.ReceivedPacket(now, true, false, protocol.ECNNon))
+	require.Zero(t, p.AlarmTimeout())
+	require.True(t, p.ShouldSendAck(now))
+	p.SentAck()
+
+	p = NewAckPolicy(10, 25*time.Millisecond)
+	require.Equal(t, AckDecisionNow, p.ReceivedPacket(now, true, false, protocol.ECNNon))
+	p.SentAck()
+	for range 9 {
+		require.Equal(t, AckDecisionDelay, p.ReceivedPacket(now, true, false, protocol.ECNNon))
+	}
+	require.Equal(t, AckDecisionNow, p.ReceivedPacket(now, true, false, protocol.ECNNon))
+}
+
+func TestAckPolicyMaxAckDelay(t *testing.T) {
+	now := time.Now()
+	p := NewAckPolicy(10, 25*time.Millisecond)
+	require.Equal(t, AckDecisionNow, p.ReceivedPacket(now, true, false, protocol.ECNNon))
+	p.SentAck()
+
+	require.Equal(t, AckDecisionDelay, p.ReceivedPacket(now, true, false, protocol.ECNNon))
+	// the timer is not pushed back by later packets
+	require.Equal(t, AckDecisionDelay, p.ReceivedPacket(now.Add(10*time.Millisecond), true, false, protocol.ECNNon))
+	require.Equal(t, now.Add(25*time.Millisecond), p.AlarmTimeout())
+	require.False(t, p.ShouldSendAck(now.Add(24*time.Millisecond)))
+	require.True(t, p.ShouldSendAck(now.Add(25*time.Millisecond)))
+	p.SentAck()
+	require.Zero(t, p.AlarmTimeout())
+	require.False(t, p.ShouldSendAck(now.Add(time.Hour)))
+}
+
+func TestAckPolicyImmediateAck(t *testing.T) {
+	now := time.Now()
+	p := NewAckPolicy(10, 25*time.Millisecond)
+	require.Equal(t, AckDecisionNow, p.ReceivedPacket(now, true, false, protocol.ECNNon))
+	p.SentAck()
+
+	t.Run("reordering", func(t *testing.T) {
+		require.Equal(t, AckDecisionNow, p.ReceivedPacket(now, true, true, protocol.ECNNon))
+		p.SentAck()
+	})
+
+	t.Run("ECN-CE", func(t *testing.T) {
+		require.Equal(t, AckDecisionNow, p.ReceivedPacket(now, true, false, protocol.ECNCE))
+		p.SentAck()
+	})
+
+	t.Run("non-ack-eliciting", func(t *testing.T) {
+		require.Equal(t, AckDecisionNone, p.ReceivedPacket(now, false, true, protocol.ECNCE))
+		require.False(t, p.ShouldSendAck(now))
+	})
+}
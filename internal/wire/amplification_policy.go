@@ -0,0 +1,74 @@
+package wire
+
+// An AmplificationResponse is the response that a payload requires.
+type AmplificationResponse uint8
+
+const (
+	// AmplificationResponseNone means that the payload doesn't require a response,
+	// e.g. because it only contains ACK, PADDING or CONNECTION_CLOSE frames.
+	AmplificationResponseNone AmplificationResponse = iota
+	// AmplificationResponseAck means that the payload only needs to be acknowledged.
+	AmplificationResponseAck
+	// AmplificationResponseAckEliciting means that the payload requires an ack-eliciting response,
+	// e.g. CRYPTO frames that advance the handshake.
+	AmplificationResponseAckEliciting
+)
+
+func (r AmplificationResponse) String() string {
+	switch r {
+	case AmplificationResponseNone:
+		return "none"
+	case AmplificationResponseAck:
+		return "ACK"
+	case AmplificationResponseAckEliciting:
+		return "ack-eliciting"
+	default:
+		return "invalid amplification response"
+	}
+}
+
+// An AmplificationPolicy is used by servers before the client's address is validated.
+// During that time, a server must not send more than 3 times the number of bytes it received
+// (see section 8.1 of RFC 9000). The policy determines which frames warrant spending that budget
+// on an ack-eliciting response. CRYPTO frames always do, since the handshake can't progress otherwise.
+// Any other ack-eliciting frame only needs to be acknowledged, unless the policy allows a response.
+// The zero value only allows responses to CRYPTO frames.
+type AmplificationPolicy struct {
+	// RespondToStreamData allows responses to STREAM frames received in 0-RTT packets (i.e. sending 0.5-RTT data).
+	RespondToStreamData bool
+	// RespondToPathChallenge allows responding to PATH_CHALLENGE frames with a PATH_RESPONSE frame.
+	RespondToPathChallenge bool
+}
+
+// ClassifyFrame returns the response that a frame requires.
+func (p AmplificationPolicy) ClassifyFrame(f Frame) AmplificationResponse {
+	switch f.(type) {
+	case *CryptoFrame:
+		return AmplificationResponseAckEliciting
+	case *StreamFrame:
+		if p.RespondToStreamData {
+			return AmplificationResponseAckEliciting
+		}
+	case *PathChallengeFrame:
+		if p.RespondToPathChallenge {
+			return AmplificationResponseAckEliciting
+		}
+	}
+	if IsAckElicitingFrame(f) {
+		return AmplificationResponseAck
+	}
+	return AmplificationResponseNone
+}
+
+// Classify returns the response that a payload requires, based on the frames it contains.
+// If the payload doesn't require a response, it can be ignored without spending the amplification budget.
+func (p AmplificationPolicy) Classify(frames []Frame) AmplificationResponse {
+	var resp AmplificationResponse
+	for _, f := range frames {
+		resp = max(resp, p.ClassifyFrame(f))
+		if resp == AmplificationResponseAckEliciting {
+			break
+		}
+	}
+	return resp
+}
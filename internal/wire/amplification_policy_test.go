@@ -0,0 +1,37 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmplificationPolicyClassifyFrame(t *testing.T) {
+	var p AmplificationPolicy
+	require.Equal(t, AmplificationResponseAckEliciting, p.ClassifyFrame(&CryptoFrame{Data: []byte("foobar")}))
+	require.Equal(t, AmplificationResponseAck, p.ClassifyFrame(&PingFrame{}))
+	require.Equal(t, AmplificationResponseAck, p.ClassifyFrame(&StreamFrame{StreamID: 4, Data: []byte("foobar")}))
+	require.Equal(t, AmplificationResponseAck, p.ClassifyFrame(&PathChallengeFrame{}))
+	require.Equal(t, AmplificationResponseNone, p.ClassifyFrame(&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 1}}}))
+	require.Equal(t, AmplificationResponseNone, p.ClassifyFrame(&ConnectionCloseFrame{}))
+
+	p = AmplificationPolicy{RespondToStreamData: true, RespondToPathChallenge: true}
+	require.Equal(t, AmplificationResponseAckEliciting, p.ClassifyFrame(&StreamFrame{StreamID: 4, Data: []byte("foobar")}))
+	require.Equal(t, AmplificationResponseAckEliciting, p.ClassifyFrame(&PathChallengeFrame{}))
+}
+
+func TestAmplificationPolicyClassify(t *testing.T) {
+	var p AmplificationPolicy
+	ack := &AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 1}}}
+	require.Equal(t, AmplificationResponseNone, p.Classify(nil))
+	require.Equal(t, AmplificationResponseNone, p.Classify([]Frame{ack, &ConnectionCloseFrame{}}))
+	require.Equal(t, AmplificationResponseAck, p.Classify([]Frame{ack, &PingFrame{}}))
+	require.Equal(t, AmplificationResponseAckEliciting, p.Classify([]Frame{ack, &PingFrame{}, &CryptoFrame{Data: []byte("foobar")}}))
+}
+
+func TestAmplificationResponseStringer(t *testing.T) {
+	require.Equal(t, "none", AmplificationResponseNone.String())
+	require.Equal(t, "ACK", AmplificationResponseAck.String())
+	require.Equal(t, "ack-eliciting", AmplificationResponseAckEliciting.String())
+	require.Equal(t, "invalid amplification response", AmplificationResponse(42).String())
+}
@@ -0,0 +1,58 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// An ApplicationErrorCodeSpace names the error codes defined by an application protocol,
+// e.g. the HTTP/3 error codes defined in section 8.1 of RFC 9114.
+// These error codes are carried in STOP_SENDING and RESET_STREAM frames,
+// as well as in CONNECTION_CLOSE frames of type 0x1d.
+type ApplicationErrorCodeSpace map[qerr.StreamErrorCode]string
+
+// Name returns the name of the error code.
+// Error codes not contained in the space are formatted as a hexadecimal number.
+func (s ApplicationErrorCodeSpace) Name(code qerr.StreamErrorCode) string {
+	if name, ok := s[code]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", uint64(code))
+}
+
+// validateApplicationErrorCode checks that the error code can be encoded as a varint.
+func validateApplicationErrorCode(code uint64) error {
+	if code > quicvarint.Max {
+		return fmt.Errorf("application error code %#x exceeds the maximum value %#x", code, uint64(quicvarint.Max))
+	}
+	return nil
+}
+
+// NewStopSendingFrame creates a STOP_SENDING frame for an application error.
+func NewStopSendingFrame(id protocol.StreamID, e *qerr.ApplicationError) (*StopSendingFrame, error) {
+	if err := validateApplicationErrorCode(uint64(e.ErrorCode)); err != nil {
+		return nil, err
+	}
+	return &StopSendingFrame{StreamID: id, ErrorCode: qerr.StreamErrorCode(e.ErrorCode)}, nil
+}
+
+// ApplicationError converts the error code to an application error.
+func (f *StopSendingFrame) ApplicationError(remote bool) *qerr.ApplicationError {
+	return &qerr.ApplicationError{Remote: remote, ErrorCode: qerr.ApplicationErrorCode(f.ErrorCode)}
+}
+
+// NewResetStreamFrame creates a RESET_STREAM frame for an application error.
+func NewResetStreamFrame(id protocol.StreamID, finalSize protocol.ByteCount, e *qerr.ApplicationError) (*ResetStreamFrame, error) {
+	if err := validateApplicationErrorCode(uint64(e.ErrorCode)); err != nil {
+		return nil, err
+	}
+	return &ResetStreamFrame{StreamID: id, ErrorCode: qerr.StreamErrorCode(e.ErrorCode), FinalSize: finalSize}, nil
+}
+
+// ApplicationError converts the error code to an application error.
+func (f *ResetStreamFrame) ApplicationError(remote bool) *qerr.ApplicationError {
+	return &qerr.ApplicationError{Remote: remote, ErrorCode: qerr.ApplicationErrorCode(f.ErrorCode)}
+}
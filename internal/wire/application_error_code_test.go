@@ -0,0 +1,39 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplicationErrorCodeSpaceNames(t *testing.T) {
+	space := ApplicationErrorCodeSpace{0x100: "H3_NO_ERROR", 0x10c: "H3_REQUEST_CANCELLED"}
+	require.Equal(t, "H3_REQUEST_CANCELLED", space.Name(0x10c))
+	require.Equal(t, "0x1337", space.Name(0x1337))
+}
+
+func TestStopSendingFrameApplicationError(t *testing.T) {
+	f, err := NewStopSendingFrame(4, &qerr.ApplicationError{ErrorCode: 0x10c})
+	require.NoError(t, err)
+	require.Equal(t, &StopSendingFrame{StreamID: 4, ErrorCode: 0x10c}, f)
+	require.Equal(t, &qerr.ApplicationError{Remote: true, ErrorCode: 0x10c}, f.ApplicationError(true))
+
+	_, err = NewStopSendingFrame(4, &qerr.ApplicationError{ErrorCode: quicvarint.Max + 1})
+	require.EqualError(t, err, "application error code 0x4000000000000000 exceeds the maximum value 0x3fffffffffffffff")
+}
+
+func TestResetStreamFrameApplicationError(t *testing.T) {
+	f, err := NewResetStreamFrame(8, 1337, &qerr.ApplicationError{ErrorCode: 0x42})
+	require.NoError(t, err)
+	require.Equal(t, &ResetStreamFrame{StreamID: 8, FinalSize: 1337, ErrorCode: 0x42}, f)
+	require.Equal(t, &qerr.ApplicationError{ErrorCode: 0x42}, f.ApplicationError(false))
+	_, err = f.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+
+	_, err = NewResetStreamFrame(8, 1337, &qerr.ApplicationError{ErrorCode: quicvarint.Max + 1})
+	require.Error(t, err)
+}
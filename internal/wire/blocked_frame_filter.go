@@ -0,0 +1,63 @@
+package wire
+
+import "github.com/quic-go/quic-go/internal/protocol"
+
+// A BlockedFrameFilter suppresses DATA_BLOCKED, STREAM_DATA_BLOCKED and STREAMS_BLOCKED frames
+// that were already sent for the same limit.
+// A sender only needs to signal once that it is blocked at a certain limit (see section 4.1 of RFC 9000),
+// so blocked frames don't need to be sent in every packet.
+// Retransmissions of lost blocked frames are not handled by the BlockedFrameFilter.
+// The zero value is ready to use.
+type BlockedFrameFilter struct {
+	dataBlockedSent bool
+	dataBlockedAt   protocol.ByteCount
+
+	streamDataBlockedAt map[protocol.StreamID]protocol.ByteCount
+
+	streamsBlockedSent [2]bool // indexed by protocol.StreamType
+	streamsBlockedAt   [2]protocol.StreamNum
+}
+
+// ShouldSend says if the frame should be sent.
+// For blocked frames, it returns false if a blocked frame for the same (or a higher) limit was sent before,
+// and it records the limit otherwise.
+// It returns true for all other frames.
+func (b *BlockedFrameFilter) ShouldSend(f Frame) bool {
+	switch f := f.(type) {
+	case *DataBlockedFrame:
+		if b.dataBlockedSent && f.MaximumData <= b.dataBlockedAt {
+			return false
+		}
+		b.dataBlockedSent = true
+		b.dataBlockedAt = f.MaximumData
+	case *StreamDataBlockedFrame:
+		if limit, ok := b.streamDataBlockedAt[f.StreamID]; ok && f.MaximumStreamData <= limit {
+			return false
+		}
+		if b.streamDataBlockedAt == nil {
+			b.streamDataBlockedAt = make(map[protocol.StreamID]protocol.ByteCount)
+		}
+		b.streamDataBlockedAt[f.StreamID] = f.MaximumStreamData
+	case *StreamsBlockedFrame:
+		i := streamTypeIndex(f.Type)
+		if b.streamsBlockedSent[i] && f.StreamLimit <= b.streamsBlockedAt[i] {
+			return false
+		}
+		b.streamsBlockedSent[i] = true
+		b.streamsBlockedAt[i] = f.StreamLimit
+	}
+	return true
+}
+
+// ForgetStream removes the state kept for a stream.
+// It should be called once the stream is completed.
+func (b *BlockedFrameFilter) ForgetStream(id protocol.StreamID) {
+	delete(b.streamDataBlockedAt, id)
+}
+
+func streamTypeIndex(t protocol.StreamType) int {
+	if t == protocol.StreamTypeUni {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,47 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockedFrameFilterDataBlocked(t *testing.T) {
+	var f BlockedFrameFilter
+	require.True(t, f.ShouldSend(&DataBlockedFrame{MaximumData: 0}))
+	require.False(t, f.ShouldSend(&DataBlockedFrame{MaximumData: 0}))
+	require.True(t, f.ShouldSend(&DataBlockedFrame{MaximumData: 100}))
+	require.False(t, f.ShouldSend(&DataBlockedFrame{MaximumData: 100}))
+	// a lower limit is stale
+	require.False(t, f.ShouldSend(&DataBlockedFrame{MaximumData: 50}))
+}
+
+func TestBlockedFrameFilterStreamDataBlocked(t *testing.T) {
+	var f BlockedFrameFilter
+	require.True(t, f.ShouldSend(&StreamDataBlockedFrame{StreamID: 4, MaximumStreamData: 100}))
+	require.False(t, f.ShouldSend(&StreamDataBlockedFrame{StreamID: 4, MaximumStreamData: 100}))
+	require.True(t, f.ShouldSend(&StreamDataBlockedFrame{StreamID: 8, MaximumStreamData: 100}))
+	require.True(t, f.ShouldSend(&StreamDataBlockedFrame{StreamID: 4, MaximumStreamData: 200}))
+	require.False(t, f.ShouldSend(&StreamDataBlockedFrame{StreamID: 4, MaximumStreamData: 150}))
+
+	f.ForgetStream(4)
+	require.True(t, f.ShouldSend(&StreamDataBlockedFrame{StreamID: 4, MaximumStreamData: 100}))
+}
+
+func TestBlockedFrameFilterStreamsBlocked(t *testing.T) {
+	var f BlockedFrameFilter
+	require.True(t, f.ShouldSend(&StreamsBlockedFrame{Type: protocol.StreamTypeBidi, StreamLimit: 10}))
+	require.False(t, f.ShouldSend(&StreamsBlockedFrame{Type: protocol.StreamTypeBidi, StreamLimit: 10}))
+	require.True(t, f.ShouldSend(&StreamsBlockedFrame{Type: protocol.StreamTypeUni, StreamLimit: 10}))
+	require.True(t, f.ShouldSend(&StreamsBlockedFrame{Type: protocol.StreamTypeBidi, StreamLimit: 20}))
+}
+
+func TestBlockedFrameFilterOtherFrames(t *testing.T) {
+	var f BlockedFrameFilter
+	require.True(t, f.ShouldSend(&PingFrame{}))
+	require.True(t, f.ShouldSend(&PingFrame{}))
+	require.True(t, f.ShouldSend(&MaxDataFrame{MaximumData: 100}))
+	require.True(t, f.ShouldSend(&MaxDataFrame{MaximumData: 100}))
+}
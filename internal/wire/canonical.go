@@ -0,0 +1,59 @@
+package wire
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// CanonicalizePayload parses the payload and serializes the frames in a canonical form,
+// such that two payloads containing the same frames can be compared byte by byte.
+// PADDING frames are removed, all varints use the minimal encoding, STREAM and DATAGRAM frames
+// always carry a Length field, and STREAM frames with offset 0 omit the Offset field.
+// The frames are sorted by their serialization.
+// Frames are parsed regardless of the encryption level they are allowed at.
+// It is meant for differential testing, and must not be used on the hot path.
+func CanonicalizePayload(b []byte, v protocol.Version) ([]byte, error) {
+	parser := NewFrameParser(true, true)
+	parser.SetStrictnessProfile(ProfileAnalyzer)
+	// ACK frames are serialized using our ACK delay exponent
+	parser.SetAckDelayExponent(protocol.AckDelayExponent)
+
+	var frames [][]byte
+	var length int
+	for len(b) > 0 {
+		if b[0] == 0x0 { // skip PADDING frames
+			b = b[1:]
+			continue
+		}
+		frame, l, err := parser.parseNext(b, protocol.Encryption1RTT, v)
+		if err != nil {
+			return nil, err
+		}
+		switch f := frame.(type) {
+		case *StreamFrame:
+			f.DataLenPresent = true
+			f.OffsetPresent = false
+		case *DatagramFrame:
+			f.DataLenPresent = true
+		}
+		serialized, err := frame.Append(nil, v)
+		if sf, ok := frame.(*StreamFrame); ok {
+			sf.PutBack()
+		}
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, serialized)
+		length += len(serialized)
+		b = b[l:]
+	}
+	slices.SortStableFunc(frames, bytes.Compare)
+
+	canonical := make([]byte, 0, length)
+	for _, f := range frames {
+		canonical = append(canonical, f...)
+	}
+	return canonical, nil
+}
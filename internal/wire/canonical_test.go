@@ -0,0 +1,56 @@
+package wire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalizePayload(t *testing.T) {
+	ack := &AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 5}}, DelayTime: time.Millisecond}
+	a := appendFrames(t, &PingFrame{})
+	a = append(a, make([]byte, 10)...) // PADDING
+	a = append(a, maxDataFrameType)
+	a = quicvarint.AppendWithLen(a, 1337, 4)
+	a = append(a, appendFrames(t,
+		ack,
+		&StreamFrame{StreamID: 4, Data: []byte("foo")}, // no Length field
+	)...)
+
+	b := appendFrames(t,
+		ack,
+		&MaxDataFrame{MaximumData: 1337},
+		&StreamFrame{StreamID: 4, Data: []byte("foo"), DataLenPresent: true, OffsetPresent: true},
+		&PingFrame{},
+	)
+	require.NotEqual(t, a, b)
+
+	canonicalA, err := CanonicalizePayload(a, protocol.Version1)
+	require.NoError(t, err)
+	canonicalB, err := CanonicalizePayload(b, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, canonicalA, canonicalB)
+	require.Len(t, canonicalA, len(b)-1) // the explicit zero Offset field was removed
+	// the canonical form is stable
+	canonical, err := CanonicalizePayload(canonicalA, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, canonicalA, canonical)
+}
+
+func TestCanonicalizePayloadDifferentFrames(t *testing.T) {
+	a, err := CanonicalizePayload(appendFrames(t, &MaxDataFrame{MaximumData: 1}), protocol.Version1)
+	require.NoError(t, err)
+	b, err := CanonicalizePayload(appendFrames(t, &MaxDataFrame{MaximumData: 2}), protocol.Version1)
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+}
+
+func TestCanonicalizePayloadErrors(t *testing.T) {
+	_, err := CanonicalizePayload([]byte{maxDataFrameType}, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: maxDataFrameType, ErrorCode: qerr.FrameEncodingError})
+}
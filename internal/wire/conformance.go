@@ -0,0 +1,263 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+)
+
+// A ConformanceRequirement is a requirement of an RFC concerning the parsing of frames.
+// It consists of an input payload, and the expected outcome of parsing it.
+type ConformanceRequirement struct {
+	// Source is the section of the RFC defining the requirement.
+	Source      string
+	Description string
+	EncLevel    protocol.EncryptionLevel
+	// Perspective is the perspective of the endpoint receiving the payload.
+	// If unset, the perspective of the FrameParser is not set.
+	Perspective protocol.Perspective
+	Payload     []byte
+	// Accept says if the payload must be parsed successfully.
+	// If false, parsing must fail with a transport error using one of the ErrorCodes.
+	Accept     bool
+	ErrorCodes []qerr.TransportErrorCode
+}
+
+var (
+	// the varint encoding of 2^62-1, the largest value that can be encoded as a varint
+	conformanceMaxVarInt = []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	// the varint encoding of 2^60+1
+	conformanceStreamCountTooLarge = []byte{0xd0, 0, 0, 0, 0, 0, 0, 1}
+	conformanceStatelessResetToken = make([]byte, 16)
+)
+
+// ConformanceRequirements are the requirements of RFC 9000 concerning the parsing of frames.
+// Most of them are MUSTs for rejecting invalid frames.
+var ConformanceRequirements = []ConformanceRequirement{
+	{
+		Source:      "RFC 9000, Section 12.4",
+		Description: "PING frames are allowed in Initial packets",
+		EncLevel:    protocol.EncryptionInitial,
+		Payload:     []byte{pingFrameType},
+		Accept:      true,
+	},
+	{
+		Source:      "RFC 9000, Section 12.4",
+		Description: "ACK frames are allowed in Handshake packets",
+		EncLevel:    protocol.EncryptionHandshake,
+		Payload:     []byte{ackFrameType, 0, 0, 0, 0},
+		Accept:      true,
+	},
+	{
+		Source:      "RFC 9000, Section 12.4",
+		Description: "CONNECTION_CLOSE frames of type 0x1c are allowed in Initial packets",
+		EncLevel:    protocol.EncryptionInitial,
+		Payload:     []byte{connectionCloseFrameType, 0, 0, 0},
+		Accept:      true,
+	},
+	{
+		Source:      "RFC 9000, Section 19.8",
+		Description: "STREAM frames without a Length field extend to the end of the packet",
+		EncLevel:    protocol.Encryption1RTT,
+		Payload:     []byte{0x8, 4, 'f', 'o', 'o', 'b', 'a', 'r'},
+		Accept:      true,
+	},
+	{
+		Source:      "RFC 9000, Section 12.4",
+		Description: "MAX_DATA frames in Initial packets are a PROTOCOL_VIOLATION",
+		EncLevel:    protocol.EncryptionInitial,
+		Payload:     []byte{maxDataFrameType, 1},
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.ProtocolViolation},
+	},
+	{
+		Source:      "RFC 9000, Section 12.4",
+		Description: "STREAM frames in Handshake packets are a PROTOCOL_VIOLATION",
+		EncLevel:    protocol.EncryptionHandshake,
+		Payload:     []byte{0x8, 4, 'f', 'o', 'o'},
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.ProtocolViolation},
+	},
+	{
+		Source:      "RFC 9000, Section 12.4",
+		Description: "ACK frames in 0-RTT packets are a PROTOCOL_VIOLATION",
+		EncLevel:    protocol.Encryption0RTT,
+		Payload:     []byte{ackFrameType, 0, 0, 0, 0},
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.ProtocolViolation},
+	},
+	{
+		Source:      "RFC 9000, Section 12.4",
+		Description: "CRYPTO frames in 0-RTT packets are a PROTOCOL_VIOLATION",
+		EncLevel:    protocol.Encryption0RTT,
+		Payload:     []byte{cryptoFrameType, 0, 3, 'f', 'o', 'o'},
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.ProtocolViolation},
+	},
+	{
+		Source:      "RFC 9000, Section 12.4",
+		Description: "CONNECTION_CLOSE frames of type 0x1d in Initial packets are a PROTOCOL_VIOLATION",
+		EncLevel:    protocol.EncryptionInitial,
+		Payload:     []byte{applicationCloseFrameType, 0, 0},
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.ProtocolViolation},
+	},
+	{
+		Source:      "RFC 9000, Section 12.4",
+		Description: "frames of unknown types are a FRAME_ENCODING_ERROR",
+		EncLevel:    protocol.Encryption1RTT,
+		Payload:     []byte{0x21},
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.FrameEncodingError},
+	},
+	{
+		Source:      "RFC 9000, Section 12.4",
+		Description: "truncated frames are a FRAME_ENCODING_ERROR",
+		EncLevel:    protocol.Encryption1RTT,
+		Payload:     []byte{maxDataFrameType},
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.FrameEncodingError},
+	},
+	{
+		Source:      "RFC 9000, Section 19.6",
+		Description: "CRYPTO frames exceeding the maximum offset are a FRAME_ENCODING_ERROR or CRYPTO_BUFFER_EXCEEDED",
+		EncLevel:    protocol.Encryption1RTT,
+		Payload:     slices.Concat([]byte{cryptoFrameType}, conformanceMaxVarInt, []byte{1, 'f'}),
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.FrameEncodingError, qerr.CryptoBufferExceeded},
+	},
+	{
+		Source:      "RFC 9000, Section 19.7",
+		Description: "NEW_TOKEN frames with an empty token are a FRAME_ENCODING_ERROR",
+		EncLevel:    protocol.Encryption1RTT,
+		Payload:     []byte{newTokenFrameType, 0},
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.FrameEncodingError},
+	},
+	{
+		Source:      "RFC 9000, Section 19.7",
+		Description: "NEW_TOKEN frames received by a server are a PROTOCOL_VIOLATION",
+		EncLevel:    protocol.Encryption1RTT,
+		Perspective: protocol.PerspectiveServer,
+		Payload:     []byte{newTokenFrameType, 3, 'f', 'o', 'o'},
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.ProtocolViolation},
+	},
+	{
+		Source:      "RFC 9000, Section 19.8",
+		Description: "STREAM frames exceeding the maximum offset are a FRAME_ENCODING_ERROR",
+		EncLevel:    protocol.Encryption1RTT,
+		Payload:     slices.Concat([]byte{0x8 | 0x4 | 0x2, 4}, conformanceMaxVarInt, []byte{1, 'f'}),
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.FrameEncodingError},
+	},
+	{
+		Source:      "RFC 9000, Section 19.11",
+		Description: "MAX_STREAMS frames with a stream count larger than 2^60 are a FRAME_ENCODING_ERROR",
+		EncLevel:    protocol.Encryption1RTT,
+		Payload:     slices.Concat([]byte{bidiMaxStreamsFrameType}, conformanceStreamCountTooLarge),
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.FrameEncodingError},
+	},
+	{
+		Source:      "RFC 9000, Section 19.14",
+		Description: "STREAMS_BLOCKED frames with a stream count larger than 2^60 are a STREAM_LIMIT_ERROR or FRAME_ENCODING_ERROR",
+		EncLevel:    protocol.Encryption1RTT,
+		Payload:     slices.Concat([]byte{uniStreamBlockedFrameType}, conformanceStreamCountTooLarge),
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.StreamLimitError, qerr.FrameEncodingError},
+	},
+	{
+		Source:      "RFC 9000, Section 19.15",
+		Description: "NEW_CONNECTION_ID frames with Retire Prior To larger than the Sequence Number are a FRAME_ENCODING_ERROR",
+		EncLevel:    protocol.Encryption1RTT,
+		Payload:     slices.Concat([]byte{newConnectionIDFrameType, 1, 2, 4, 0xde, 0xad, 0xbe, 0xef}, conformanceStatelessResetToken),
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.FrameEncodingError},
+	},
+	{
+		Source:      "RFC 9000, Section 19.15",
+		Description: "NEW_CONNECTION_ID frames with a zero-length connection ID are a FRAME_ENCODING_ERROR",
+		EncLevel:    protocol.Encryption1RTT,
+		Payload:     slices.Concat([]byte{newConnectionIDFrameType, 1, 0, 0}, conformanceStatelessResetToken),
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.FrameEncodingError},
+	},
+	{
+		Source:      "RFC 9000, Section 19.20",
+		Description: "HANDSHAKE_DONE frames received by a server are a PROTOCOL_VIOLATION",
+		EncLevel:    protocol.Encryption1RTT,
+		Perspective: protocol.PerspectiveServer,
+		Payload:     []byte{handshakeDoneFrameType},
+		ErrorCodes:  []qerr.TransportErrorCode{qerr.ProtocolViolation},
+	},
+}
+
+// A ConformanceResult is the result of checking a single ConformanceRequirement.
+type ConformanceResult struct {
+	Requirement ConformanceRequirement
+	Passed      bool
+	// Err is the error returned by the FrameParser, if any.
+	Err error
+}
+
+func (r ConformanceResult) String() string {
+	status := "PASS"
+	if !r.Passed {
+		status = "FAIL"
+	}
+	s := fmt.Sprintf("%s %s: %s", status, r.Requirement.Source, r.Requirement.Description)
+	if !r.Passed {
+		if r.Err == nil {
+			s += " (payload accepted)"
+		} else {
+			s += fmt.Sprintf(" (%s)", r.Err)
+		}
+	}
+	return s
+}
+
+// A ConformanceReport is the result of checking a list of ConformanceRequirements.
+type ConformanceReport struct {
+	Profile StrictnessProfile
+	Results []ConformanceResult
+}
+
+// Failed returns the results of the requirements that weren't met.
+func (r *ConformanceReport) Failed() []ConformanceResult {
+	var failed []ConformanceResult
+	for _, res := range r.Results {
+		if !res.Passed {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+func (r *ConformanceReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "conformance report (profile: %s): %d of %d requirements met\n", r.Profile, len(r.Results)-len(r.Failed()), len(r.Results))
+	for _, res := range r.Results {
+		sb.WriteString(res.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// RunConformance checks the requirements against a FrameParser using the strictness profile.
+// A new FrameParser is used for every requirement.
+// Use ConformanceRequirements to check the requirements of RFC 9000.
+func RunConformance(profile StrictnessProfile, reqs []ConformanceRequirement) *ConformanceReport {
+	report := &ConformanceReport{Profile: profile, Results: make([]ConformanceResult, 0, len(reqs))}
+	for _, req := range reqs {
+		parser := NewFrameParser(true, true)
+		parser.SetStrictnessProfile(profile)
+		if req.Perspective != 0 {
+			parser.SetPerspective(req.Perspective)
+		}
+		var err error
+		for _, err = range parser.ParseAll(req.Payload, req.EncLevel, protocol.Version1) {
+			if err != nil {
+				break
+			}
+		}
+		res := ConformanceResult{Requirement: req, Err: err}
+		if req.Accept {
+			res.Passed = err == nil
+		} else {
+			var transportErr *qerr.TransportError
+			res.Passed = errors.As(err, &transportErr) && slices.Contains(req.ErrorCodes, transportErr.ErrorCode)
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
@@ -0,0 +1,94 @@
+package wire
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func failedConformanceRequirements(r *ConformanceReport) []string {
+	var failed []string
+	for _, res := range r.Failed() {
+		failed = append(failed, res.Requirement.Description)
+	}
+	return failed
+}
+
+func TestConformance(t *testing.T) {
+	// Frames sent at the wrong encryption level are rejected with a FRAME_ENCODING_ERROR instead of a PROTOCOL_VIOLATION,
+	// and some checks aren't implemented by the parser.
+	knownGaps := []string{
+		"MAX_DATA frames in Initial packets are a PROTOCOL_VIOLATION",
+		"STREAM frames in Handshake packets are a PROTOCOL_VIOLATION",
+		"ACK frames in 0-RTT packets are a PROTOCOL_VIOLATION",
+		"CRYPTO frames in 0-RTT packets are a PROTOCOL_VIOLATION",
+		"CONNECTION_CLOSE frames of type 0x1d in Initial packets are a PROTOCOL_VIOLATION",
+		"NEW_TOKEN frames received by a server are a PROTOCOL_VIOLATION",
+	}
+
+	t.Run("RFC strict", func(t *testing.T) {
+		report := RunConformance(ProfileRFCStrict, ConformanceRequirements)
+		require.Len(t, report.Results, len(ConformanceRequirements))
+		require.Equal(t, knownGaps, failedConformanceRequirements(report))
+	})
+
+	t.Run("interop", func(t *testing.T) {
+		// the maximum CRYPTO offset is only enforced by the RFC strict profile
+		report := RunConformance(ProfileInterop, ConformanceRequirements)
+		require.ElementsMatch(t,
+			append(knownGaps, "CRYPTO frames exceeding the maximum offset are a FRAME_ENCODING_ERROR or CRYPTO_BUFFER_EXCEEDED"),
+			failedConformanceRequirements(report),
+		)
+	})
+
+	t.Run("analyzer", func(t *testing.T) {
+		report := RunConformance(ProfileAnalyzer, ConformanceRequirements)
+		for _, res := range report.Results {
+			// the analyzer profile accepts every syntactically valid frame
+			if res.Requirement.Accept {
+				require.True(t, res.Passed, res.Requirement.Description)
+			}
+		}
+		require.Contains(t, failedConformanceRequirements(report), "HANDSHAKE_DONE frames received by a server are a PROTOCOL_VIOLATION")
+	})
+}
+
+func TestConformanceReport(t *testing.T) {
+	reqs := []ConformanceRequirement{
+		{
+			Source:      "RFC 9000, Section 12.4",
+			Description: "PING frames are allowed in Initial packets",
+			EncLevel:    protocol.EncryptionInitial,
+			Payload:     []byte{pingFrameType},
+			Accept:      true,
+		},
+		{
+			Source:      "RFC 9000, Section 19.2",
+			Description: "PING frames are rejected",
+			EncLevel:    protocol.Encryption1RTT,
+			Payload:     []byte{pingFrameType},
+			ErrorCodes:  []qerr.TransportErrorCode{qerr.ProtocolViolation},
+		},
+		{
+			Source:      "RFC 9000, Section 19.9",
+			Description: "truncated MAX_DATA frames are a PROTOCOL_VIOLATION",
+			EncLevel:    protocol.Encryption1RTT,
+			Payload:     []byte{maxDataFrameType},
+			ErrorCodes:  []qerr.TransportErrorCode{qerr.ProtocolViolation},
+		},
+	}
+	report := RunConformance(ProfileInterop, reqs)
+	require.Len(t, report.Failed(), 2)
+	require.Error(t, report.Results[2].Err)
+	lines := strings.Split(strings.TrimSpace(report.String()), "\n")
+	require.Equal(t, []string{
+		"conformance report (profile: interop): 1 of 3 requirements met",
+		"PASS RFC 9000, Section 12.4: PING frames are allowed in Initial packets",
+		"FAIL RFC 9000, Section 19.2: PING frames are rejected (payload accepted)",
+		"FAIL RFC 9000, Section 19.9: truncated MAX_DATA frames are a PROTOCOL_VIOLATION (" + report.Results[2].Err.Error() + ")",
+	}, lines)
+}
@@ -0,0 +1,99 @@
+package wire
+
+import (
+	"errors"
+
+	"github.com/quic-go/quic-go/internal/qerr"
+)
+
+// ConnectionCloseFromError returns the CONNECTION_CLOSE frame used to close the connection with the error.
+// The error must be a *qerr.TransportError or a *qerr.ApplicationError, otherwise false is returned.
+// Details of crypto errors are not sent to the peer, so the reason phrase is empty for those errors.
+func ConnectionCloseFromError(err error) (*ConnectionCloseFrame, bool) {
+	var transportErr *qerr.TransportError
+	var applicationErr *qerr.ApplicationError
+	switch {
+	case errors.As(err, &transportErr):
+		var reason string
+		// don't send details of crypto errors
+		if !transportErr.ErrorCode.IsCryptoError() {
+			reason = transportErr.ErrorMessage
+		}
+		return NewTransportClose(transportErr.ErrorCode, transportErr.FrameType, reason), true
+	case errors.As(err, &applicationErr):
+		return NewApplicationClose(applicationErr.ErrorCode, applicationErr.ErrorMessage), true
+	default:
+		return nil, false
+	}
+}
+
+// ToError converts the frame to the error used to close the connection.
+// The error is a *qerr.TransportError or a *qerr.ApplicationError.
+// Remote is set if the frame was received from the peer.
+func (f *ConnectionCloseFrame) ToError(remote bool) error {
+	if f.IsApplicationError {
+		return &qerr.ApplicationError{
+			Remote:       remote,
+			ErrorCode:    qerr.ApplicationErrorCode(f.ErrorCode),
+			ErrorMessage: f.ReasonPhrase,
+		}
+	}
+	return &qerr.TransportError{
+		Remote:       remote,
+		ErrorCode:    qerr.TransportErrorCode(f.ErrorCode),
+		FrameType:    f.FrameType,
+		ErrorMessage: f.ReasonPhrase,
+	}
+}
+
+// ErrorCodeName returns the qlog name of the error code (e.g. "flow_control_error").
+// It returns an empty string for application errors and for unknown transport error codes.
+func (f *ConnectionCloseFrame) ErrorCodeName() string {
+	if f.IsApplicationError {
+		return ""
+	}
+	return TransportErrorName(qerr.TransportErrorCode(f.ErrorCode))
+}
+
+// TransportErrorName returns the qlog name of a transport error code (e.g. "flow_control_error").
+// It returns an empty string for unknown error codes.
+func TransportErrorName(code qerr.TransportErrorCode) string {
+	switch code {
+	case qerr.NoError:
+		return "no_error"
+	case qerr.InternalError:
+		return "internal_error"
+	case qerr.ConnectionRefused:
+		return "connection_refused"
+	case qerr.FlowControlError:
+		return "flow_control_error"
+	case qerr.StreamLimitError:
+		return "stream_limit_error"
+	case qerr.StreamStateError:
+		return "stream_state_error"
+	case qerr.FinalSizeError:
+		return "final_size_error"
+	case qerr.FrameEncodingError:
+		return "frame_encoding_error"
+	case qerr.TransportParameterError:
+		return "transport_parameter_error"
+	case qerr.ConnectionIDLimitError:
+		return "connection_id_limit_error"
+	case qerr.ProtocolViolation:
+		return "protocol_violation"
+	case qerr.InvalidToken:
+		return "invalid_token"
+	case qerr.ApplicationErrorErrorCode:
+		return "application_error"
+	case qerr.CryptoBufferExceeded:
+		return "crypto_buffer_exceeded"
+	case qerr.KeyUpdateError:
+		return "key_update_error"
+	case qerr.AEADLimitReached:
+		return "aead_limit_reached"
+	case qerr.NoViablePathError:
+		return "no_viable_path"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,65 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionCloseFromTransportError(t *testing.T) {
+	f, ok := ConnectionCloseFromError(&qerr.TransportError{
+		ErrorCode:    qerr.FlowControlError,
+		FrameType:    maxDataFrameType,
+		ErrorMessage: "foobar",
+	})
+	require.True(t, ok)
+	require.Equal(t, NewTransportClose(qerr.FlowControlError, maxDataFrameType, "foobar"), f)
+	require.Equal(t, &qerr.TransportError{
+		Remote:       true,
+		ErrorCode:    qerr.FlowControlError,
+		FrameType:    maxDataFrameType,
+		ErrorMessage: "foobar",
+	}, f.ToError(true))
+	require.Equal(t, "flow_control_error", f.ErrorCodeName())
+}
+
+func TestConnectionCloseFromCryptoError(t *testing.T) {
+	f, ok := ConnectionCloseFromError(&qerr.TransportError{
+		ErrorCode:    0x100 + 0x2a,
+		ErrorMessage: "bad certificate",
+	})
+	require.True(t, ok)
+	// details of crypto errors are not sent
+	require.Empty(t, f.ReasonPhrase)
+	require.Equal(t, uint64(0x100+0x2a), f.ErrorCode)
+	require.Empty(t, f.ErrorCodeName())
+}
+
+func TestConnectionCloseFromApplicationError(t *testing.T) {
+	f, ok := ConnectionCloseFromError(fmt.Errorf("wrapped: %w", &qerr.ApplicationError{
+		ErrorCode:    0x1,
+		ErrorMessage: "foobar",
+	}))
+	require.True(t, ok)
+	require.Equal(t, NewApplicationClose(0x1, "foobar"), f)
+	require.Equal(t, &qerr.ApplicationError{ErrorCode: 0x1, ErrorMessage: "foobar"}, f.ToError(false))
+	// application error codes don't have a name, even if they collide with a transport error code
+	require.Empty(t, f.ErrorCodeName())
+}
+
+func TestConnectionCloseFromOtherError(t *testing.T) {
+	_, ok := ConnectionCloseFromError(errors.New("foobar"))
+	require.False(t, ok)
+	_, ok = ConnectionCloseFromError(&qerr.IdleTimeoutError{})
+	require.False(t, ok)
+}
+
+func TestTransportErrorName(t *testing.T) {
+	require.Equal(t, "no_error", TransportErrorName(qerr.NoError))
+	require.Equal(t, "aead_limit_reached", TransportErrorName(qerr.AEADLimitReached))
+	require.Empty(t, TransportErrorName(1337))
+}
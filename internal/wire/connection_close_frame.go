@@ -1,9 +1,11 @@
 package wire
 
 import (
-	"io"
+	"errors"
+	"unicode/utf8"
 
 	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
 	"github.com/quic-go/quic-go/quicvarint"
 )
 
@@ -15,38 +17,51 @@ type ConnectionCloseFrame struct {
 	ReasonPhrase       string
 }
 
+// NewTransportClose creates a CONNECTION_CLOSE frame of type 0x1c, signaling a transport error.
+// The frame type is the type of the frame that triggered the error, or 0 if unknown.
+func NewTransportClose(code qerr.TransportErrorCode, frameType uint64, reason string) *ConnectionCloseFrame {
+	return &ConnectionCloseFrame{
+		ErrorCode:    uint64(code),
+		FrameType:    frameType,
+		ReasonPhrase: reason,
+	}
+}
+
+// NewApplicationClose creates a CONNECTION_CLOSE frame of type 0x1d, signaling an application error.
+func NewApplicationClose(code qerr.ApplicationErrorCode, reason string) *ConnectionCloseFrame {
+	return &ConnectionCloseFrame{
+		IsApplicationError: true,
+		ErrorCode:          uint64(code),
+		ReasonPhrase:       reason,
+	}
+}
+
 func parseConnectionCloseFrame(b []byte, typ uint64, _ protocol.Version) (*ConnectionCloseFrame, int, error) {
-	startLen := len(b)
+	c := newCursor(b)
 	f := &ConnectionCloseFrame{IsApplicationError: typ == applicationCloseFrameType}
-	ec, l, err := quicvarint.Parse(b)
+	ec, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
 	f.ErrorCode = ec
 	// read the Frame Type, if this is not an application error
 	if !f.IsApplicationError {
-		ft, l, err := quicvarint.Parse(b)
+		ft, err := c.readVarInt()
 		if err != nil {
-			return nil, 0, replaceUnexpectedEOF(err)
+			return nil, 0, err
 		}
-		b = b[l:]
 		f.FrameType = ft
 	}
-	var reasonPhraseLen uint64
-	reasonPhraseLen, l, err = quicvarint.Parse(b)
+	reasonPhraseLen, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
-	if int(reasonPhraseLen) > len(b) {
-		return nil, 0, io.EOF
+	reasonPhrase, err := c.readBytes(reasonPhraseLen)
+	if err != nil {
+		return nil, 0, err
 	}
-
-	reasonPhrase := make([]byte, reasonPhraseLen)
-	copy(reasonPhrase, b)
 	f.ReasonPhrase = string(reasonPhrase)
-	return f, startLen - len(b) + int(reasonPhraseLen), nil
+	return f, c.consumed(), nil
 }
 
 // Length of a written frame
@@ -59,6 +74,10 @@ func (f *ConnectionCloseFrame) Length(protocol.Version) protocol.ByteCount {
 }
 
 func (f *ConnectionCloseFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
+	// the frame type field only exists for transport errors
+	if f.IsApplicationError && f.FrameType != 0 {
+		return nil, errors.New("ConnectionCloseFrame: application error with a frame type")
+	}
 	if f.IsApplicationError {
 		b = append(b, applicationCloseFrameType)
 	} else {
@@ -73,3 +92,34 @@ func (f *ConnectionCloseFrame) Append(b []byte, _ protocol.Version) ([]byte, err
 	b = append(b, []byte(f.ReasonPhrase)...)
 	return b, nil
 }
+
+// ComposeConnectionClose returns a copy of the frame that is at most maxLen bytes long.
+// If necessary, the reason phrase is truncated (at a UTF-8 character boundary), or dropped entirely.
+// It also returns the number of PADDING bytes needed for the frame to fill minLen bytes,
+// e.g. to make the packet large enough for header protection sampling.
+// It returns false if the frame doesn't fit into maxLen bytes even without a reason phrase.
+func ComposeConnectionClose(f *ConnectionCloseFrame, minLen, maxLen protocol.ByteCount, v protocol.Version) (_ *ConnectionCloseFrame, padding protocol.ByteCount, ok bool) {
+	frame := *f
+	frame.ReasonPhrase = ""
+	// the length of the frame with an empty reason phrase, without the reason phrase length
+	fixedLen := frame.Length(v) - 1
+	if fixedLen+1 > maxLen {
+		return nil, 0, false
+	}
+	avail := int(min(maxLen-fixedLen, protocol.ByteCount(len(f.ReasonPhrase)+8)))
+	reasonLen := min(len(f.ReasonPhrase), avail-1)
+	// this loop runs at most 7 times, since that's the maximum difference in varint lengths
+	for reasonLen > 0 && quicvarint.Len(uint64(reasonLen))+reasonLen > avail {
+		reasonLen--
+	}
+	if reasonLen < len(f.ReasonPhrase) {
+		for reasonLen > 0 && !utf8.RuneStart(f.ReasonPhrase[reasonLen]) {
+			reasonLen--
+		}
+	}
+	frame.ReasonPhrase = f.ReasonPhrase[:reasonLen]
+	if l := frame.Length(v); l < minLen {
+		padding = minLen - l
+	}
+	return &frame, padding, true
+}
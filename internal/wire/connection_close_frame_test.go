@@ -2,9 +2,12 @@ package wire
 
 import (
 	"io"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
 
 	"github.com/stretchr/testify/require"
 )
@@ -42,7 +45,7 @@ func TestParseConnectionCloseLongReasonPhrase(t *testing.T) {
 	data = append(data, encodeVarInt(0x42)...)   // frame type
 	data = append(data, encodeVarInt(0xffff)...) // reason phrase length
 	_, _, err := parseConnectionCloseFrame(data, connectionCloseFrameType, protocol.Version1)
-	require.Equal(t, io.EOF, err)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
 }
 
 func TestParseConnectionCloseErrorsOnEOFs(t *testing.T) {
@@ -56,7 +59,7 @@ func TestParseConnectionCloseErrorsOnEOFs(t *testing.T) {
 	require.NoError(t, err)
 	for i := range data {
 		_, _, err = parseConnectionCloseFrame(data[:i], connectionCloseFrameType, protocol.Version1)
-		require.Equal(t, io.EOF, err)
+		require.Equal(t, io.ErrUnexpectedEOF, err)
 	}
 }
 
@@ -135,3 +138,89 @@ func TestWriteConnectionCloseLength(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, b, int(f.Length(protocol.Version1)))
 }
+
+func TestComposeConnectionClose(t *testing.T) {
+	f := &ConnectionCloseFrame{
+		ErrorCode:    0x1337,
+		FrameType:    0x42,
+		ReasonPhrase: strings.Repeat("a", 100),
+	}
+	fixedLen := (&ConnectionCloseFrame{ErrorCode: 0x1337, FrameType: 0x42}).Length(protocol.Version1)
+
+	t.Run("fitting", func(t *testing.T) {
+		frame, padding, ok := ComposeConnectionClose(f, 0, f.Length(protocol.Version1), protocol.Version1)
+		require.True(t, ok)
+		require.Equal(t, f, frame)
+		require.NotSame(t, f, frame)
+		require.Zero(t, padding)
+	})
+
+	t.Run("truncating the reason phrase", func(t *testing.T) {
+		frame, _, ok := ComposeConnectionClose(f, 0, f.Length(protocol.Version1)-1, protocol.Version1)
+		require.True(t, ok)
+		require.Equal(t, f.Length(protocol.Version1)-1, frame.Length(protocol.Version1))
+		require.Equal(t, strings.Repeat("a", 99), frame.ReasonPhrase)
+
+		frame, _, ok = ComposeConnectionClose(f, 0, fixedLen+10, protocol.Version1)
+		require.True(t, ok)
+		require.Equal(t, fixedLen+10, frame.Length(protocol.Version1))
+		require.Equal(t, strings.Repeat("a", 10), frame.ReasonPhrase)
+		require.Equal(t, strings.Repeat("a", 100), f.ReasonPhrase) // the original frame is not modified
+	})
+
+	t.Run("dropping the reason phrase", func(t *testing.T) {
+		frame, _, ok := ComposeConnectionClose(f, 0, fixedLen, protocol.Version1)
+		require.True(t, ok)
+		require.Empty(t, frame.ReasonPhrase)
+		require.Equal(t, fixedLen, frame.Length(protocol.Version1))
+	})
+
+	t.Run("not fitting", func(t *testing.T) {
+		_, _, ok := ComposeConnectionClose(f, 0, fixedLen-1, protocol.Version1)
+		require.False(t, ok)
+	})
+
+	t.Run("padding", func(t *testing.T) {
+		frame, padding, ok := ComposeConnectionClose(&ConnectionCloseFrame{ErrorCode: 1}, 20, 100, protocol.Version1)
+		require.True(t, ok)
+		require.Equal(t, protocol.ByteCount(20), frame.Length(protocol.Version1)+padding)
+	})
+}
+
+func TestComposeConnectionCloseUTF8(t *testing.T) {
+	f := &ConnectionCloseFrame{IsApplicationError: true, ReasonPhrase: "äöü"} // 2 bytes per character
+	fixedLen := (&ConnectionCloseFrame{IsApplicationError: true}).Length(protocol.Version1)
+	frame, _, ok := ComposeConnectionClose(f, 0, fixedLen+3, protocol.Version1)
+	require.True(t, ok)
+	require.Equal(t, "ä", frame.ReasonPhrase)
+	require.True(t, utf8.ValidString(frame.ReasonPhrase))
+}
+
+func TestConnectionCloseConstructors(t *testing.T) {
+	f := NewTransportClose(qerr.FlowControlError, maxDataFrameType, "foobar")
+	require.Equal(t, &ConnectionCloseFrame{
+		ErrorCode:    uint64(qerr.FlowControlError),
+		FrameType:    maxDataFrameType,
+		ReasonPhrase: "foobar",
+	}, f)
+	b, err := f.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, byte(connectionCloseFrameType), b[0])
+
+	f = NewApplicationClose(0x1337, "foobar")
+	require.Equal(t, &ConnectionCloseFrame{
+		IsApplicationError: true,
+		ErrorCode:          0x1337,
+		ReasonPhrase:       "foobar",
+	}, f)
+	b, err = f.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, byte(applicationCloseFrameType), b[0])
+}
+
+func TestWriteConnectionCloseApplicationErrorWithFrameType(t *testing.T) {
+	f := NewApplicationClose(0x1337, "foobar")
+	f.FrameType = 0x42
+	_, err := f.Append(nil, protocol.Version1)
+	require.EqualError(t, err, "ConnectionCloseFrame: application error with a frame type")
+}
@@ -1,8 +1,6 @@
 package wire
 
 import (
-	"io"
-
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/quicvarint"
 )
@@ -11,30 +9,31 @@ import (
 type CryptoFrame struct {
 	Offset protocol.ByteCount
 	Data   []byte
+	// Provenance records where the data was originally sent.
+	Provenance Provenance
 }
 
 func parseCryptoFrame(b []byte, _ protocol.Version) (*CryptoFrame, int, error) {
-	startLen := len(b)
+	c := newCursor(b)
 	frame := &CryptoFrame{}
-	offset, l, err := quicvarint.Parse(b)
+	offset, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
 	frame.Offset = protocol.ByteCount(offset)
-	dataLen, l, err := quicvarint.Parse(b)
+	dataLen, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
-	if dataLen > uint64(len(b)) {
-		return nil, 0, io.EOF
+	data, err := c.readBytes(dataLen)
+	if err != nil {
+		return nil, 0, err
 	}
 	if dataLen != 0 {
 		frame.Data = make([]byte, dataLen)
-		copy(frame.Data, b)
+		copy(frame.Data, data)
 	}
-	return frame, startLen - len(b) + int(dataLen), nil
+	return frame, c.consumed(), nil
 }
 
 func (f *CryptoFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
@@ -84,6 +83,7 @@ func (f *CryptoFrame) MaybeSplitOffFrame(maxSize protocol.ByteCount, version pro
 
 	new := &CryptoFrame{}
 	new.Offset = f.Offset
+	new.Provenance = f.Provenance
 	new.Data = make([]byte, newLen)
 
 	// swap the data slices
@@ -3,6 +3,7 @@ package wire
 import (
 	"io"
 	"testing"
+	"time"
 
 	"github.com/quic-go/quic-go/internal/protocol"
 
@@ -29,7 +30,7 @@ func TestParseCryptoFrameErrorsOnEOFs(t *testing.T) {
 	require.Equal(t, len(data), l)
 	for i := range data {
 		_, _, err := parseCryptoFrame(data[:i], protocol.Version1)
-		require.Equal(t, io.EOF, err)
+		require.Equal(t, io.ErrUnexpectedEOF, err)
 	}
 }
 
@@ -96,6 +97,15 @@ func TestCryptoFrameSplitting(t *testing.T) {
 	require.Equal(t, protocol.ByteCount(0x1337+3), f.Offset)
 }
 
+func TestCryptoFrameSplittingPreservesProvenance(t *testing.T) {
+	prov := Provenance{PacketNumber: 42, EnqueueTime: time.Now()}
+	f := &CryptoFrame{Offset: 0x1337, Data: []byte("foobar"), Provenance: prov}
+	new, needsSplit := f.MaybeSplitOffFrame(f.Length(protocol.Version1)-3, protocol.Version1)
+	require.True(t, needsSplit)
+	require.Equal(t, prov, new.Provenance)
+	require.Equal(t, prov, f.Provenance)
+}
+
 func TestCryptoFrameNoSplitWhenEnoughSpace(t *testing.T) {
 	f := &CryptoFrame{
 		Offset: 0x1337,
@@ -0,0 +1,50 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+)
+
+// A CryptoReassemblyLimiter limits the amount of CRYPTO data that is buffered out of order, per encryption level.
+// Data received out of order can't be passed to TLS until the gaps are filled,
+// so a peer could otherwise force the endpoint to buffer an arbitrary amount of handshake data.
+// See section 7.5 of RFC 9000.
+type CryptoReassemblyLimiter struct {
+	maxOutOfOrder protocol.ByteCount
+	ranges        [protocol.Encryption1RTT + 1]ReceivedRanges
+}
+
+// NewCryptoReassemblyLimiter creates a new CryptoReassemblyLimiter,
+// allowing up to maxOutOfOrder bytes to be buffered out of order at every encryption level.
+func NewCryptoReassemblyLimiter(maxOutOfOrder protocol.ByteCount) *CryptoReassemblyLimiter {
+	return &CryptoReassemblyLimiter{maxOutOfOrder: maxOutOfOrder}
+}
+
+// HandleCryptoFrame records the receipt of a CRYPTO frame.
+// It returns a CRYPTO_BUFFER_EXCEEDED error if the amount of data buffered out of order exceeds the limit.
+func (l *CryptoReassemblyLimiter) HandleCryptoFrame(encLevel protocol.EncryptionLevel, f *CryptoFrame) error {
+	r := &l.ranges[encLevel]
+	if err := r.Insert(f.Offset, protocol.ByteCount(len(f.Data)), false); err != nil {
+		return err
+	}
+	if outOfOrder := r.OutOfOrderLen(); outOfOrder > l.maxOutOfOrder {
+		return &qerr.TransportError{
+			ErrorCode:    qerr.CryptoBufferExceeded,
+			ErrorMessage: fmt.Sprintf("%d bytes of CRYPTO data buffered out of order at encryption level %s, maximum allowed %d", outOfOrder, encLevel, l.maxOutOfOrder),
+		}
+	}
+	return nil
+}
+
+// OutOfOrderLen returns the number of bytes buffered out of order at an encryption level.
+func (l *CryptoReassemblyLimiter) OutOfOrderLen(encLevel protocol.EncryptionLevel) protocol.ByteCount {
+	return l.ranges[encLevel].OutOfOrderLen()
+}
+
+// ContiguousLen returns the number of bytes received without gaps at an encryption level.
+// This is the data that can be passed to TLS.
+func (l *CryptoReassemblyLimiter) ContiguousLen(encLevel protocol.EncryptionLevel) protocol.ByteCount {
+	return l.ranges[encLevel].ContiguousLen()
+}
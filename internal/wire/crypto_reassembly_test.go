@@ -0,0 +1,40 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCryptoReassemblyLimiter(t *testing.T) {
+	l := NewCryptoReassemblyLimiter(100)
+
+	// in-order data is not limited
+	require.NoError(t, l.HandleCryptoFrame(protocol.EncryptionInitial, &CryptoFrame{Data: make([]byte, 1000)}))
+	require.Equal(t, protocol.ByteCount(1000), l.ContiguousLen(protocol.EncryptionInitial))
+	require.Zero(t, l.OutOfOrderLen(protocol.EncryptionInitial))
+
+	require.NoError(t, l.HandleCryptoFrame(protocol.EncryptionInitial, &CryptoFrame{Offset: 1100, Data: make([]byte, 60)}))
+	require.NoError(t, l.HandleCryptoFrame(protocol.EncryptionInitial, &CryptoFrame{Offset: 1200, Data: make([]byte, 40)}))
+	require.Equal(t, protocol.ByteCount(100), l.OutOfOrderLen(protocol.EncryptionInitial))
+	// retransmissions of data that was already received don't count
+	require.NoError(t, l.HandleCryptoFrame(protocol.EncryptionInitial, &CryptoFrame{Offset: 1100, Data: make([]byte, 60)}))
+
+	// the limit applies per encryption level
+	require.NoError(t, l.HandleCryptoFrame(protocol.EncryptionHandshake, &CryptoFrame{Offset: 500, Data: make([]byte, 100)}))
+	require.Equal(t, protocol.ByteCount(100), l.OutOfOrderLen(protocol.EncryptionHandshake))
+
+	err := l.HandleCryptoFrame(protocol.EncryptionInitial, &CryptoFrame{Offset: 1300, Data: []byte{0}})
+	require.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.CryptoBufferExceeded})
+	require.ErrorContains(t, err, "101 bytes of CRYPTO data buffered out of order at encryption level Initial, maximum allowed 100")
+
+	// filling the gap frees the budget
+	l = NewCryptoReassemblyLimiter(100)
+	require.NoError(t, l.HandleCryptoFrame(protocol.EncryptionHandshake, &CryptoFrame{Offset: 50, Data: make([]byte, 100)}))
+	require.NoError(t, l.HandleCryptoFrame(protocol.EncryptionHandshake, &CryptoFrame{Data: make([]byte, 50)}))
+	require.Zero(t, l.OutOfOrderLen(protocol.EncryptionHandshake))
+	require.NoError(t, l.HandleCryptoFrame(protocol.EncryptionHandshake, &CryptoFrame{Offset: 200, Data: make([]byte, 100)}))
+}
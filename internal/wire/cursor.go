@@ -0,0 +1,60 @@
+package wire
+
+import (
+	"io"
+
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// A cursor reads QUIC wire encodings from a byte slice.
+// All read methods return io.ErrUnexpectedEOF if the slice doesn't contain enough data,
+// in which case the cursor is not advanced.
+type cursor struct {
+	b   []byte
+	pos int
+}
+
+func newCursor(b []byte) cursor {
+	return cursor{b: b}
+}
+
+// readVarInt reads a varint.
+func (c *cursor) readVarInt() (uint64, error) {
+	v, l, err := quicvarint.Parse(c.b[c.pos:])
+	if err != nil {
+		return 0, io.ErrUnexpectedEOF
+	}
+	c.pos += l
+	return v, nil
+}
+
+// readByte reads a single byte.
+func (c *cursor) readByte() (byte, error) {
+	if c.pos >= len(c.b) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b := c.b[c.pos]
+	c.pos++
+	return b, nil
+}
+
+// readBytes reads n bytes.
+// The returned slice references the underlying byte slice, it is not a copy.
+func (c *cursor) readBytes(n uint64) ([]byte, error) {
+	if n > uint64(c.remaining()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := c.b[c.pos : c.pos+int(n)]
+	c.pos += int(n)
+	return b, nil
+}
+
+// remaining returns the number of bytes that haven't been read yet.
+func (c *cursor) remaining() int {
+	return len(c.b) - c.pos
+}
+
+// consumed returns the number of bytes read.
+func (c *cursor) consumed() int {
+	return c.pos
+}
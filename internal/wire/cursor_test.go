@@ -0,0 +1,53 @@
+package wire
+
+import (
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorReadVarInt(t *testing.T) {
+	b := quicvarint.Append(nil, 1337)
+	b = quicvarint.Append(b, 42)
+	c := newCursor(b)
+	v, err := c.readVarInt()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1337), v)
+	require.Equal(t, 2, c.consumed())
+	require.Equal(t, 1, c.remaining())
+	v, err = c.readVarInt()
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), v)
+	_, err = c.readVarInt()
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+
+	// a truncated varint
+	c = newCursor(quicvarint.Append(nil, 1337)[:1])
+	_, err = c.readVarInt()
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+	require.Zero(t, c.consumed())
+}
+
+func TestCursorReadBytes(t *testing.T) {
+	c := newCursor([]byte("foobar"))
+	b, err := c.readByte()
+	require.NoError(t, err)
+	require.Equal(t, byte('f'), b)
+	data, err := c.readBytes(3)
+	require.NoError(t, err)
+	require.Equal(t, []byte("oob"), data)
+	_, err = c.readBytes(3)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+	_, err = c.readBytes(1 << 63)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+	require.Equal(t, 4, c.consumed())
+	data, err = c.readBytes(2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("ar"), data)
+	_, err = c.readByte()
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+	require.Zero(t, c.remaining())
+}
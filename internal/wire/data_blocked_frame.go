@@ -11,11 +11,12 @@ type DataBlockedFrame struct {
 }
 
 func parseDataBlockedFrame(b []byte, _ protocol.Version) (*DataBlockedFrame, int, error) {
-	offset, l, err := quicvarint.Parse(b)
+	c := newCursor(b)
+	offset, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	return &DataBlockedFrame{MaximumData: protocol.ByteCount(offset)}, l, nil
+	return &DataBlockedFrame{MaximumData: protocol.ByteCount(offset)}, c.consumed(), nil
 }
 
 func (f *DataBlockedFrame) Append(b []byte, version protocol.Version) ([]byte, error) {
@@ -25,7 +25,7 @@ func TestParseDataBlockedErrorsOnEOFs(t *testing.T) {
 	require.Equal(t, len(data), l)
 	for i := range data {
 		_, _, err := parseDataBlockedFrame(data[:i], protocol.Version1)
-		require.Equal(t, io.EOF, err)
+		require.Equal(t, io.ErrUnexpectedEOF, err)
 	}
 }
 
@@ -0,0 +1,46 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// A DatagramCodec transforms the payload of DATAGRAM frames, e.g. to compress it.
+// It is experimental, and only meant for prototyping payload compression schemes:
+// nothing in the handshake negotiates its use, so both endpoints need to be configured with the same codec.
+type DatagramCodec interface {
+	// Encode appends the encoded data to dst.
+	Encode(dst, data []byte) []byte
+	// Decode appends the decoded data to dst.
+	// Implementations should stop decoding once the result exceeds MaxDatagramSize.
+	Decode(dst, data []byte) ([]byte, error)
+}
+
+// NewEncodedDatagramFrame creates a DATAGRAM frame carrying the data encoded using the codec.
+// The frame can then be sent like any other DATAGRAM frame.
+func NewEncodedDatagramFrame(data []byte, codec DatagramCodec, dataLenPresent bool) *DatagramFrame {
+	return &DatagramFrame{
+		DataLenPresent: dataLenPresent,
+		Data:           codec.Encode(nil, data),
+	}
+}
+
+// SetDatagramCodec sets the codec used to decode the payload of DATAGRAM frames.
+// If decoding fails, or the decoded data is larger than MaxDatagramSize, parsing fails with a FRAME_ENCODING_ERROR.
+// If nil, the payload is not transformed.
+func (p *FrameParser) SetDatagramCodec(codec DatagramCodec) {
+	p.datagramCodec = codec
+}
+
+func (p *FrameParser) decodeDatagram(f *DatagramFrame) error {
+	data, err := p.datagramCodec.Decode(nil, f.Data)
+	if err != nil {
+		return fmt.Errorf("decoding DATAGRAM payload failed: %w", err)
+	}
+	if protocol.ByteCount(len(data)) > MaxDatagramSize {
+		return fmt.Errorf("decoded DATAGRAM payload too large: %d bytes", len(data))
+	}
+	f.Data = data
+	return nil
+}
@@ -0,0 +1,77 @@
+package wire
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+type flateDatagramCodec struct{}
+
+func (flateDatagramCodec) Encode(dst, data []byte) []byte {
+	buf := bytes.NewBuffer(dst)
+	w, _ := flate.NewWriter(buf, flate.BestCompression)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func (flateDatagramCodec) Decode(dst, data []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	// read one byte more than allowed, so the parser can reject oversized payloads
+	_, err := io.Copy(buf, io.LimitReader(flate.NewReader(bytes.NewReader(data)), int64(MaxDatagramSize)+1))
+	return buf.Bytes(), err
+}
+
+func TestDatagramCodec(t *testing.T) {
+	data := bytes.Repeat([]byte("foobar"), 100)
+	f := NewEncodedDatagramFrame(data, flateDatagramCodec{}, true)
+	require.Less(t, len(f.Data), len(data))
+	b, err := f.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+
+	p := NewFrameParser(true, false)
+	p.SetDatagramCodec(flateDatagramCodec{})
+	l, frame, err := p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(b), l)
+	require.Equal(t, &DatagramFrame{DataLenPresent: true, Data: data}, frame)
+
+	// the codec is copied by Clone
+	_, frame, err = p.Clone().ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, data, frame.(*DatagramFrame).Data)
+
+	// without a codec, the encoded data is returned
+	_, frame, err = NewFrameParser(true, false).ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, f.Data, frame.(*DatagramFrame).Data)
+}
+
+func TestDatagramCodecErrors(t *testing.T) {
+	p := NewFrameParser(true, false)
+	p.SetDatagramCodec(flateDatagramCodec{})
+
+	t.Run("invalid data", func(t *testing.T) {
+		b, err := (&DatagramFrame{Data: []byte("foobar")}).Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		_, _, err = p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+		require.ErrorIs(t, err, &qerr.TransportError{FrameType: 0x30, ErrorCode: qerr.FrameEncodingError})
+		require.ErrorContains(t, err, "decoding DATAGRAM payload failed")
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		f := NewEncodedDatagramFrame(make([]byte, MaxDatagramSize+1), flateDatagramCodec{}, false)
+		b, err := f.Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		_, _, err = p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+		require.ErrorIs(t, err, &qerr.TransportError{FrameType: 0x30, ErrorCode: qerr.FrameEncodingError})
+		require.ErrorContains(t, err, "decoded DATAGRAM payload too large")
+	})
+}
@@ -1,8 +1,6 @@
 package wire
 
 import (
-	"io"
-
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/quicvarint"
 )
@@ -20,28 +18,25 @@ type DatagramFrame struct {
 }
 
 func parseDatagramFrame(b []byte, typ uint64, _ protocol.Version) (*DatagramFrame, int, error) {
-	startLen := len(b)
+	c := newCursor(b)
 	f := &DatagramFrame{}
 	f.DataLenPresent = typ&0x1 > 0
 
-	var length uint64
+	length := uint64(c.remaining())
 	if f.DataLenPresent {
 		var err error
-		var l int
-		length, l, err = quicvarint.Parse(b)
+		length, err = c.readVarInt()
 		if err != nil {
-			return nil, 0, replaceUnexpectedEOF(err)
-		}
-		b = b[l:]
-		if length > uint64(len(b)) {
-			return nil, 0, io.EOF
+			return nil, 0, err
 		}
-	} else {
-		length = uint64(len(b))
+	}
+	data, err := c.readBytes(length)
+	if err != nil {
+		return nil, 0, err
 	}
 	f.Data = make([]byte, length)
-	copy(f.Data, b)
-	return f, startLen - len(b) + int(length), nil
+	copy(f.Data, data)
+	return f, c.consumed(), nil
 }
 
 func (f *DatagramFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
@@ -57,6 +52,12 @@ func (f *DatagramFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
 	return b, nil
 }
 
+// ExtendsToEndOfPacket returns true if the frame doesn't have a Length field,
+// and therefore consumes the rest of the packet.
+func (f *DatagramFrame) ExtendsToEndOfPacket() bool {
+	return !f.DataLenPresent
+}
+
 // MaxDataLen returns the maximum data length
 func (f *DatagramFrame) MaxDataLen(maxSize protocol.ByteCount, version protocol.Version) protocol.ByteCount {
 	headerLen := protocol.ByteCount(1)
@@ -83,3 +84,58 @@ func (f *DatagramFrame) Length(_ protocol.Version) protocol.ByteCount {
 	}
 	return length
 }
+
+// AppendDatagramFrames appends DATAGRAM frames for as many datagrams as fit into maxSize bytes,
+// preserving the order of the datagrams.
+// All frames except for the last one have a Length field, the last frame extends to the end of the packet.
+// It returns the number of datagrams that were appended.
+func AppendDatagramFrames(b []byte, datagrams [][]byte, maxSize protocol.ByteCount, v protocol.Version) ([]byte, int) {
+	var n int
+	var length protocol.ByteCount
+	for _, data := range datagrams {
+		// the datagram needs to fit, at least as the last frame
+		if length+1+protocol.ByteCount(len(data)) > maxSize {
+			break
+		}
+		n++
+		// if another datagram is added, this frame needs a Length field
+		length += 1 + protocol.ByteCount(quicvarint.Len(uint64(len(data)))+len(data))
+	}
+	for i, data := range datagrams[:n] {
+		f := &DatagramFrame{Data: data, DataLenPresent: i < n-1}
+		b, _ = f.Append(b, v)
+	}
+	return b, n
+}
+
+// A DatagramEncoding describes how a DATAGRAM frame is encoded.
+type DatagramEncoding struct {
+	// DataLenPresent says if the frame carries a Length field.
+	DataLenPresent bool
+	// HeaderLen is the length of the frame header, i.e. the frame type and the Length field (if present).
+	HeaderLen protocol.ByteCount
+}
+
+// MustBeLast says if the frame must be the last frame in the packet.
+// This is the case for frames without a Length field, since they extend to the end of the packet.
+func (e DatagramEncoding) MustBeLast() bool { return !e.DataLenPresent }
+
+// ChooseDatagramEncoding chooses the encoding of a DATAGRAM frame carrying dataLen bytes,
+// such that the frame fits into remainingSpace bytes.
+// If the frame fits with a Length field, it is encoded with a Length field, allowing more frames to follow it.
+// Otherwise, the frame is encoded without a Length field, and must be the last frame in the packet.
+// It returns false if the frame doesn't fit in either encoding.
+func ChooseDatagramEncoding(dataLen, remainingSpace protocol.ByteCount) (DatagramEncoding, bool) {
+	withLen := DatagramEncoding{
+		DataLenPresent: true,
+		HeaderLen:      1 + protocol.ByteCount(quicvarint.Len(uint64(dataLen))),
+	}
+	if withLen.HeaderLen+dataLen <= remainingSpace {
+		return withLen, true
+	}
+	withoutLen := DatagramEncoding{HeaderLen: 1}
+	if withoutLen.HeaderLen+dataLen <= remainingSpace {
+		return withoutLen, true
+	}
+	return DatagramEncoding{}, false
+}
@@ -32,7 +32,7 @@ func TestParseDatagramFrameErrorsOnLengthLongerThanFrame(t *testing.T) {
 	data := encodeVarInt(0x6) // length
 	data = append(data, []byte("fooba")...)
 	_, _, err := parseDatagramFrame(data, 0x30^0x1, protocol.Version1)
-	require.Equal(t, io.EOF, err)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
 }
 
 func TestParseDatagramFrameErrorsOnEOFs(t *testing.T) {
@@ -44,7 +44,7 @@ func TestParseDatagramFrameErrorsOnEOFs(t *testing.T) {
 	require.Equal(t, len(data), l)
 	for i := range data {
 		_, _, err = parseDatagramFrame(data[0:i], typ, protocol.Version1)
-		require.Equal(t, io.EOF, err)
+		require.Equal(t, io.ErrUnexpectedEOF, err)
 	}
 }
 
@@ -124,3 +124,67 @@ func TestMaxDatagramLenWithDataLenPresent(t *testing.T) {
 	}
 	require.Equal(t, 1, frameOneByteTooSmallCounter)
 }
+
+func TestAppendDatagramFrames(t *testing.T) {
+	datagrams := [][]byte{[]byte("foo"), []byte("bar"), make([]byte, 100), []byte("baz")}
+	// the first two frames need 5 bytes each, the third one needs 101 bytes without a Length field
+	for _, tc := range []struct {
+		maxSize  protocol.ByteCount
+		expected int
+	}{
+		{maxSize: 3, expected: 0},
+		{maxSize: 4, expected: 1},
+		{maxSize: 8, expected: 1},
+		{maxSize: 9, expected: 2},
+		{maxSize: 110, expected: 2},
+		{maxSize: 111, expected: 3},
+		{maxSize: 1000, expected: 4},
+	} {
+		b, n := AppendDatagramFrames([]byte{0x42}, datagrams, tc.maxSize, protocol.Version1)
+		require.Equal(t, tc.expected, n, "max size %d", tc.maxSize)
+		require.Equal(t, byte(0x42), b[0])
+		require.LessOrEqual(t, len(b)-1, int(tc.maxSize))
+
+		parser := NewFrameParser(true, false)
+		data := b[1:]
+		for i := range n {
+			l, f, err := parser.ParseNext(data, protocol.Encryption1RTT, protocol.Version1)
+			require.NoError(t, err)
+			require.Equal(t, datagrams[i], f.(*DatagramFrame).Data)
+			require.Equal(t, i == n-1, ExtendsToEndOfPacket(f))
+			data = data[l:]
+		}
+		require.Empty(t, data)
+	}
+}
+
+func TestChooseDatagramEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		dataLen        protocol.ByteCount
+		remainingSpace protocol.ByteCount
+		encoding       DatagramEncoding
+		ok             bool
+	}{
+		{name: "with length", dataLen: 10, remainingSpace: 100, encoding: DatagramEncoding{DataLenPresent: true, HeaderLen: 2}, ok: true},
+		{name: "with length, exact fit", dataLen: 10, remainingSpace: 12, encoding: DatagramEncoding{DataLenPresent: true, HeaderLen: 2}, ok: true},
+		{name: "without length", dataLen: 10, remainingSpace: 11, encoding: DatagramEncoding{HeaderLen: 1}, ok: true},
+		{name: "2 byte length", dataLen: 100, remainingSpace: 103, encoding: DatagramEncoding{DataLenPresent: true, HeaderLen: 3}, ok: true},
+		{name: "2 byte length doesn't fit", dataLen: 100, remainingSpace: 102, encoding: DatagramEncoding{HeaderLen: 1}, ok: true},
+		{name: "too large", dataLen: 100, remainingSpace: 100, ok: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e, ok := ChooseDatagramEncoding(tc.dataLen, tc.remainingSpace)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.encoding, e)
+			if !ok {
+				return
+			}
+			require.Equal(t, !tc.encoding.DataLenPresent, e.MustBeLast())
+			// the header length is exact
+			f := &DatagramFrame{Data: make([]byte, tc.dataLen), DataLenPresent: e.DataLenPresent}
+			require.Equal(t, e.HeaderLen+tc.dataLen, f.Length(protocol.Version1))
+			require.LessOrEqual(t, f.Length(protocol.Version1), tc.remainingSpace)
+		})
+	}
+}
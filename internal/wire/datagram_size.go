@@ -0,0 +1,30 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// CheckDatagramSize checks the size of a composed datagram, including all packet headers and the AEAD overhead.
+// The datagram must not exceed maxSize, which is derived from the peer's max_udp_payload_size.
+// If requiresMinSize is set, the datagram must be padded to at least 1200 bytes,
+// as required for datagrams carrying Initial packets (see section 14.1 of RFC 9000).
+// Violating these limits means that the datagram would be dropped, either on the path or by the peer.
+// The check is only performed when building with the quicdebug build tag, otherwise nil is returned.
+func CheckDatagramSize(size, maxSize protocol.ByteCount, requiresMinSize bool) error {
+	if !debugChecks {
+		return nil
+	}
+	return checkDatagramSize(size, maxSize, requiresMinSize)
+}
+
+func checkDatagramSize(size, maxSize protocol.ByteCount, requiresMinSize bool) error {
+	if size > maxSize {
+		return fmt.Errorf("BUG: datagram too large (%d bytes, maximum: %d bytes)", size, maxSize)
+	}
+	if requiresMinSize && size < protocol.MinInitialPacketSize {
+		return fmt.Errorf("BUG: datagram containing an Initial packet too small (%d bytes, minimum: %d bytes)", size, protocol.MinInitialPacketSize)
+	}
+	return nil
+}
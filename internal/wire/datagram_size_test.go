@@ -0,0 +1,28 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDatagramSize(t *testing.T) {
+	require.NoError(t, checkDatagramSize(1200, 1200, true))
+	require.NoError(t, checkDatagramSize(100, 1200, false))
+	require.EqualError(t,
+		checkDatagramSize(1201, 1200, false),
+		"BUG: datagram too large (1201 bytes, maximum: 1200 bytes)",
+	)
+	require.EqualError(t,
+		checkDatagramSize(1199, 1452, true),
+		"BUG: datagram containing an Initial packet too small (1199 bytes, minimum: 1200 bytes)",
+	)
+
+	if !debugChecks {
+		require.NoError(t, CheckDatagramSize(2000, protocol.MinInitialPacketSize, true))
+	} else {
+		require.Error(t, CheckDatagramSize(2000, protocol.MinInitialPacketSize, true))
+	}
+}
@@ -0,0 +1,7 @@
+//go:build quicdebug
+
+package wire
+
+// debugChecks enables additional consistency checks.
+// They are enabled by building with the quicdebug build tag.
+const debugChecks = true
@@ -0,0 +1,136 @@
+package wire
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// DescribeFrame renders a frame in a multi-line, human-readable form,
+// using the field names of RFC 9000 (and RFC 9221 for DATAGRAM frames).
+// Computed values, like the packet numbers covered by the ACK ranges, are shown next to the raw values.
+// It is meant for debugging and analysis tools, and for test failure messages.
+func DescribeFrame(f Frame, v protocol.Version) string {
+	b, err := f.Append(nil, v)
+	if err != nil {
+		return fmt.Sprintf("%T (failed to serialize: %s)", f, err)
+	}
+	typ, _, err := quicvarint.Parse(b)
+	if err != nil {
+		return fmt.Sprintf("%T (failed to serialize: %s)", f, err)
+	}
+
+	d := frameDescriber{}
+	d.sb.WriteString(fmt.Sprintf("%s (%#x), %d bytes\n", FrameType(typ), typ, len(b)))
+	switch f := f.(type) {
+	case *PingFrame, *HandshakeDoneFrame:
+	case *AckFrame:
+		d.field("Largest Acknowledged", "%d", f.LargestAcked())
+		d.field("ACK Delay", "%d (%s, ack_delay_exponent %d)", EncodeAckDelay(f.DelayTime, protocol.AckDelayExponent), f.DelayTime, protocol.AckDelayExponent)
+		numRanges := f.numEncodableAckRanges()
+		d.field("ACK Range Count", "%d", numRanges-1)
+		for i := range numRanges {
+			gap, length := f.encodeAckRange(i)
+			r := f.AckRanges[i]
+			if i == 0 {
+				d.field("First ACK Range", "%d (packets %d-%d)", length, r.Smallest, r.Largest)
+				continue
+			}
+			d.field(fmt.Sprintf("ACK Range %d", i), "Gap %d, ACK Range Length %d (packets %d-%d)", gap, length, r.Smallest, r.Largest)
+		}
+		if numRanges < len(f.AckRanges) {
+			d.field("Omitted ACK Ranges", "%d", len(f.AckRanges)-numRanges)
+		}
+		if typ == ackECNFrameType {
+			d.field("ECT0 Count", "%d", f.ECT0)
+			d.field("ECT1 Count", "%d", f.ECT1)
+			d.field("ECN-CE Count", "%d", f.ECNCE)
+		}
+	case *ResetStreamFrame:
+		d.field("Stream ID", "%d", f.StreamID)
+		d.field("Application Protocol Error Code", "%#x", uint64(f.ErrorCode))
+		d.field("Final Size", "%d", f.FinalSize)
+		if typ == resetStreamAtFrameType {
+			d.field("Reliable Size", "%d", f.ReliableSize)
+		}
+	case *StopSendingFrame:
+		d.field("Stream ID", "%d", f.StreamID)
+		d.field("Application Protocol Error Code", "%#x", uint64(f.ErrorCode))
+	case *CryptoFrame:
+		d.field("Offset", "%d", f.Offset)
+		d.field("Length", "%d (end offset %d)", len(f.Data), f.Offset+protocol.ByteCount(len(f.Data)))
+	case *NewTokenFrame:
+		d.field("Token Length", "%d", len(f.Token))
+		d.field("Token", "%x", f.Token)
+	case *StreamFrame:
+		d.field("Type Bits", "OFF=%d LEN=%d FIN=%d", (typ>>2)&1, (typ>>1)&1, typ&1)
+		d.field("Stream ID", "%d", f.StreamID)
+		d.field("Offset", "%d", f.Offset)
+		if f.DataLenPresent {
+			d.field("Length", "%d (end offset %d)", f.DataLen(), f.Offset+f.DataLen())
+		} else {
+			d.field("Length", "%d, extends to the end of the packet (end offset %d)", f.DataLen(), f.Offset+f.DataLen())
+		}
+	case *MaxDataFrame:
+		d.field("Maximum Data", "%d", f.MaximumData)
+	case *MaxStreamDataFrame:
+		d.field("Stream ID", "%d", f.StreamID)
+		d.field("Maximum Stream Data", "%d", f.MaximumStreamData)
+	case *MaxStreamsFrame:
+		d.field("Maximum Streams", "%d (%s)", f.MaxStreamNum, streamTypeName(f.Type))
+	case *DataBlockedFrame:
+		d.field("Maximum Data", "%d", f.MaximumData)
+	case *StreamDataBlockedFrame:
+		d.field("Stream ID", "%d", f.StreamID)
+		d.field("Maximum Stream Data", "%d", f.MaximumStreamData)
+	case *StreamsBlockedFrame:
+		d.field("Maximum Streams", "%d (%s)", f.StreamLimit, streamTypeName(f.Type))
+	case *NewConnectionIDFrame:
+		d.field("Sequence Number", "%d", f.SequenceNumber)
+		d.field("Retire Prior To", "%d", f.RetirePriorTo)
+		d.field("Length", "%d", f.ConnectionID.Len())
+		d.field("Connection ID", "%s", f.ConnectionID)
+		d.field("Stateless Reset Token", "%x", f.StatelessResetToken)
+	case *RetireConnectionIDFrame:
+		d.field("Sequence Number", "%d", f.SequenceNumber)
+	case *PathChallengeFrame:
+		d.field("Data", "%x", f.Data)
+	case *PathResponseFrame:
+		d.field("Data", "%x", f.Data)
+	case *ConnectionCloseFrame:
+		d.field("Error Code", "%#x", f.ErrorCode)
+		if !f.IsApplicationError {
+			d.field("Frame Type", "%#x (%s)", f.FrameType, FrameType(f.FrameType))
+		}
+		d.field("Reason Phrase Length", "%d", len(f.ReasonPhrase))
+		d.field("Reason Phrase", "%q", f.ReasonPhrase)
+	case *DatagramFrame:
+		if f.DataLenPresent {
+			d.field("Length", "%d", len(f.Data))
+		} else {
+			d.field("Length", "%d, extends to the end of the packet", len(f.Data))
+		}
+	}
+	return strings.TrimSuffix(d.sb.String(), "\n")
+}
+
+type frameDescriber struct {
+	sb strings.Builder
+}
+
+func (d *frameDescriber) field(name, format string, args ...any) {
+	d.sb.WriteString("  ")
+	d.sb.WriteString(name)
+	d.sb.WriteString(": ")
+	d.sb.WriteString(fmt.Sprintf(format, args...))
+	d.sb.WriteByte('\n')
+}
+
+func streamTypeName(t protocol.StreamType) string {
+	if t == protocol.StreamTypeUni {
+		return "unidirectional"
+	}
+	return "bidirectional"
+}
@@ -0,0 +1,76 @@
+package wire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeAckFrame(t *testing.T) {
+	f := &AckFrame{
+		AckRanges: []AckRange{{Smallest: 10, Largest: 20}, {Smallest: 1, Largest: 5}},
+		DelayTime: time.Millisecond,
+		ECT0:      1,
+		ECT1:      2,
+		ECNCE:     3,
+	}
+	require.Equal(t, `ACK_ECN (0x3), 11 bytes
+  Largest Acknowledged: 20
+  ACK Delay: 125 (1ms, ack_delay_exponent 3)
+  ACK Range Count: 1
+  First ACK Range: 10 (packets 10-20)
+  ACK Range 1: Gap 3, ACK Range Length 4 (packets 1-5)
+  ECT0 Count: 1
+  ECT1 Count: 2
+  ECN-CE Count: 3`, DescribeFrame(f, protocol.Version1))
+}
+
+func TestDescribeStreamFrame(t *testing.T) {
+	f := &StreamFrame{StreamID: 4, Offset: 10, Data: []byte("foobar"), Fin: true}
+	require.Equal(t, `STREAM (0xd), 9 bytes
+  Type Bits: OFF=1 LEN=0 FIN=1
+  Stream ID: 4
+  Offset: 10
+  Length: 6, extends to the end of the packet (end offset 16)`, DescribeFrame(f, protocol.Version1))
+}
+
+func TestDescribeConnectionCloseFrame(t *testing.T) {
+	f := &ConnectionCloseFrame{ErrorCode: 0x7, FrameType: 0x8, ReasonPhrase: "foo"}
+	require.Equal(t, `CONNECTION_CLOSE (0x1c), 7 bytes
+  Error Code: 0x7
+  Frame Type: 0x8 (STREAM)
+  Reason Phrase Length: 3
+  Reason Phrase: "foo"`, DescribeFrame(f, protocol.Version1))
+}
+
+func TestDescribeAllFrames(t *testing.T) {
+	frames := []Frame{
+		&PingFrame{},
+		&HandshakeDoneFrame{},
+		&ResetStreamFrame{StreamID: 4, ErrorCode: 1, FinalSize: 100, ReliableSize: 50},
+		&StopSendingFrame{StreamID: 4, ErrorCode: 1},
+		&CryptoFrame{Offset: 10, Data: []byte("crypto")},
+		&NewTokenFrame{Token: []byte("token")},
+		&MaxDataFrame{MaximumData: 1000},
+		&MaxStreamDataFrame{StreamID: 4, MaximumStreamData: 1000},
+		&MaxStreamsFrame{Type: protocol.StreamTypeUni, MaxStreamNum: 10},
+		&DataBlockedFrame{MaximumData: 1000},
+		&StreamDataBlockedFrame{StreamID: 4, MaximumStreamData: 1000},
+		&StreamsBlockedFrame{Type: protocol.StreamTypeBidi, StreamLimit: 10},
+		&NewConnectionIDFrame{SequenceNumber: 2, ConnectionID: protocol.ParseConnectionID([]byte{1, 2, 3, 4})},
+		&RetireConnectionIDFrame{SequenceNumber: 1},
+		&PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		&PathResponseFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		&DatagramFrame{Data: []byte("foo")},
+	}
+	for _, f := range frames {
+		desc := DescribeFrame(f, protocol.Version1)
+		require.NotContains(t, desc, "unknown", "%T", f)
+		require.NotContains(t, desc, "failed", "%T", f)
+	}
+	require.Contains(t, DescribeFrame(&ResetStreamFrame{ReliableSize: 50}, protocol.Version1), "Reliable Size: 50")
+	require.Contains(t, DescribeFrame(&MaxStreamsFrame{Type: protocol.StreamTypeUni}, protocol.Version1), "unidirectional")
+}
@@ -0,0 +1,59 @@
+package wire
+
+import (
+	"hash/fnv"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// A FingerprintConfig configures which fields are included in a frame fingerprint.
+// By default, fields that change between retransmissions of the same frame are excluded.
+type FingerprintConfig struct {
+	// IncludeAckDelay includes the ACK Delay of ACK frames.
+	// It depends on the time the ACK frame is sent at, not on its contents.
+	IncludeAckDelay bool
+	// IncludeEncoding includes encoding choices that don't change the meaning of a frame:
+	// whether STREAM and DATAGRAM frames carry a Length field,
+	// and whether STREAM frames with offset 0 carry an Offset field.
+	IncludeEncoding bool
+}
+
+// FrameFingerprint returns a 64-bit fingerprint of a frame, using the default FingerprintConfig.
+func FrameFingerprint(f Frame, v protocol.Version) uint64 {
+	return FingerprintConfig{}.Fingerprint(f, v)
+}
+
+// Fingerprint returns a 64-bit fingerprint of a frame.
+// Identical frames have the same fingerprint, and the fingerprint is stable across processes,
+// so it can be used to deduplicate frames in retransmission queues and analysis tools.
+// Different frames can collide, so it's not suitable when an adversary chooses the frames.
+// It returns 0 if the frame can't be serialized.
+func (c FingerprintConfig) Fingerprint(f Frame, v protocol.Version) uint64 {
+	switch frame := f.(type) {
+	case *AckFrame:
+		if !c.IncludeAckDelay && frame.DelayTime != 0 {
+			ack := *frame
+			ack.DelayTime = 0
+			f = &ack
+		}
+	case *StreamFrame:
+		if !c.IncludeEncoding && (!frame.DataLenPresent || frame.OffsetPresent) {
+			sf := *frame
+			sf.DataLenPresent = true
+			sf.OffsetPresent = false
+			sf.fromPool = false
+			f = &sf
+		}
+	case *DatagramFrame:
+		if !c.IncludeEncoding && !frame.DataLenPresent {
+			f = &DatagramFrame{DataLenPresent: true, Data: frame.Data}
+		}
+	}
+	b, err := f.Append(make([]byte, 0, f.Length(v)), v)
+	if err != nil {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
@@ -0,0 +1,60 @@
+package wire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameFingerprint(t *testing.T) {
+	frames := []Frame{
+		&PingFrame{},
+		&MaxDataFrame{MaximumData: 1337},
+		&MaxDataFrame{MaximumData: 1338},
+		&MaxStreamDataFrame{StreamID: 4, MaximumStreamData: 1337},
+		&StreamFrame{StreamID: 4, Data: []byte("foo"), DataLenPresent: true},
+		&StreamFrame{StreamID: 4, Data: []byte("bar"), DataLenPresent: true},
+		&StreamFrame{StreamID: 4, Offset: 3, Data: []byte("foo"), DataLenPresent: true},
+		&StreamFrame{StreamID: 4, Data: []byte("foo"), Fin: true, DataLenPresent: true},
+		&CryptoFrame{Data: []byte("foo")},
+		&DatagramFrame{Data: []byte("foo"), DataLenPresent: true},
+		&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}},
+	}
+	seen := make(map[uint64]Frame)
+	for _, f := range frames {
+		fp := FrameFingerprint(f, protocol.Version1)
+		require.NotZero(t, fp)
+		require.NotContains(t, seen, fp, "%#v collides with %#v", f, seen[fp])
+		seen[fp] = f
+	}
+
+	// the fingerprint is the FNV-1a hash of the serialized frame
+	require.Equal(t, uint64(0x64ad0d18bb391765), FrameFingerprint(&MaxDataFrame{MaximumData: 1337}, protocol.Version1))
+	// frames that can't be serialized
+	require.Zero(t, FrameFingerprint(&StreamFrame{StreamID: 4}, protocol.Version1))
+}
+
+func TestFrameFingerprintVolatileFields(t *testing.T) {
+	ack1 := &AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}, DelayTime: time.Millisecond}
+	ack2 := &AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}, DelayTime: 10 * time.Millisecond}
+	require.Equal(t, FrameFingerprint(ack1, protocol.Version1), FrameFingerprint(ack2, protocol.Version1))
+	// the frame is not modified
+	require.Equal(t, time.Millisecond, ack1.DelayTime)
+
+	stream1 := &StreamFrame{StreamID: 4, Data: []byte("foo"), DataLenPresent: true}
+	stream2 := &StreamFrame{StreamID: 4, Data: []byte("foo"), OffsetPresent: true}
+	require.Equal(t, FrameFingerprint(stream1, protocol.Version1), FrameFingerprint(stream2, protocol.Version1))
+	require.False(t, stream2.DataLenPresent)
+
+	datagram1 := &DatagramFrame{Data: []byte("foo"), DataLenPresent: true}
+	datagram2 := &DatagramFrame{Data: []byte("foo")}
+	require.Equal(t, FrameFingerprint(datagram1, protocol.Version1), FrameFingerprint(datagram2, protocol.Version1))
+
+	conf := FingerprintConfig{IncludeAckDelay: true, IncludeEncoding: true}
+	require.NotEqual(t, conf.Fingerprint(ack1, protocol.Version1), conf.Fingerprint(ack2, protocol.Version1))
+	require.NotEqual(t, conf.Fingerprint(stream1, protocol.Version1), conf.Fingerprint(stream2, protocol.Version1))
+	require.NotEqual(t, conf.Fingerprint(datagram1, protocol.Version1), conf.Fingerprint(datagram2, protocol.Version1))
+}
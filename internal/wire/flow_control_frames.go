@@ -0,0 +1,81 @@
+package wire
+
+import "github.com/quic-go/quic-go/internal/protocol"
+
+// StreamFlowControlState is the flow control state of a single stream.
+type StreamFlowControlState struct {
+	StreamID protocol.StreamID
+	// WindowUpdate is the receive window offset that should be advertised to the peer.
+	// If 0, no MAX_STREAM_DATA frame is generated.
+	WindowUpdate protocol.ByteCount
+	// Blocked is set if sending is blocked by the peer's flow control limit.
+	Blocked bool
+	// SendLimit is the peer's flow control limit.
+	// It is only used if Blocked is set.
+	SendLimit protocol.ByteCount
+}
+
+// StreamLimitState is the stream limit state for one stream type.
+type StreamLimitState struct {
+	// MaxStreams is the stream limit that should be advertised to the peer.
+	// If 0, no MAX_STREAMS frame is generated.
+	MaxStreams protocol.StreamNum
+	// Blocked is set if opening a new stream is blocked by the peer's stream limit.
+	Blocked bool
+	// Limit is the peer's stream limit.
+	// It is only used if Blocked is set.
+	Limit protocol.StreamNum
+}
+
+// FlowControlState is a snapshot of the flow control state of a connection.
+type FlowControlState struct {
+	// ConnectionWindowUpdate is the receive window offset that should be advertised to the peer.
+	// If 0, no MAX_DATA frame is generated.
+	ConnectionWindowUpdate protocol.ByteCount
+	// ConnectionBlocked is set if sending is blocked by the peer's connection-level flow control limit.
+	ConnectionBlocked bool
+	// ConnectionSendLimit is the peer's connection-level flow control limit.
+	// It is only used if ConnectionBlocked is set.
+	ConnectionSendLimit protocol.ByteCount
+
+	Streams []StreamFlowControlState
+
+	BidiStreams StreamLimitState
+	UniStreams  StreamLimitState
+}
+
+// AppendFlowControlFrames appends the MAX_DATA, MAX_STREAM_DATA, MAX_STREAMS, DATA_BLOCKED,
+// STREAM_DATA_BLOCKED and STREAMS_BLOCKED frames needed to communicate the flow control state to the peer.
+// Window updates are appended before the BLOCKED frames.
+func AppendFlowControlFrames(frames []Frame, s *FlowControlState) []Frame {
+	if s.ConnectionWindowUpdate > 0 {
+		frames = append(frames, &MaxDataFrame{MaximumData: s.ConnectionWindowUpdate})
+	}
+	for _, str := range s.Streams {
+		if str.WindowUpdate > 0 {
+			frames = append(frames, &MaxStreamDataFrame{StreamID: str.StreamID, MaximumStreamData: str.WindowUpdate})
+		}
+	}
+	if s.BidiStreams.MaxStreams > 0 {
+		frames = append(frames, &MaxStreamsFrame{Type: protocol.StreamTypeBidi, MaxStreamNum: s.BidiStreams.MaxStreams})
+	}
+	if s.UniStreams.MaxStreams > 0 {
+		frames = append(frames, &MaxStreamsFrame{Type: protocol.StreamTypeUni, MaxStreamNum: s.UniStreams.MaxStreams})
+	}
+
+	if s.ConnectionBlocked {
+		frames = append(frames, &DataBlockedFrame{MaximumData: s.ConnectionSendLimit})
+	}
+	for _, str := range s.Streams {
+		if str.Blocked {
+			frames = append(frames, &StreamDataBlockedFrame{StreamID: str.StreamID, MaximumStreamData: str.SendLimit})
+		}
+	}
+	if s.BidiStreams.Blocked {
+		frames = append(frames, &StreamsBlockedFrame{Type: protocol.StreamTypeBidi, StreamLimit: s.BidiStreams.Limit})
+	}
+	if s.UniStreams.Blocked {
+		frames = append(frames, &StreamsBlockedFrame{Type: protocol.StreamTypeUni, StreamLimit: s.UniStreams.Limit})
+	}
+	return frames
+}
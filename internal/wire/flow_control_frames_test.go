@@ -0,0 +1,40 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendFlowControlFrames(t *testing.T) {
+	frames := AppendFlowControlFrames(nil, &FlowControlState{
+		ConnectionWindowUpdate: 1000,
+		ConnectionBlocked:      true,
+		ConnectionSendLimit:    0,
+		Streams: []StreamFlowControlState{
+			{StreamID: 4, WindowUpdate: 100},
+			{StreamID: 8, Blocked: true, SendLimit: 200},
+			{StreamID: 12},
+		},
+		BidiStreams: StreamLimitState{MaxStreams: 10},
+		UniStreams:  StreamLimitState{MaxStreams: 5, Blocked: true, Limit: 3},
+	})
+	require.Equal(t, []Frame{
+		&MaxDataFrame{MaximumData: 1000},
+		&MaxStreamDataFrame{StreamID: 4, MaximumStreamData: 100},
+		&MaxStreamsFrame{Type: protocol.StreamTypeBidi, MaxStreamNum: 10},
+		&MaxStreamsFrame{Type: protocol.StreamTypeUni, MaxStreamNum: 5},
+		&DataBlockedFrame{MaximumData: 0},
+		&StreamDataBlockedFrame{StreamID: 8, MaximumStreamData: 200},
+		&StreamsBlockedFrame{Type: protocol.StreamTypeUni, StreamLimit: 3},
+	}, frames)
+}
+
+func TestAppendFlowControlFramesNothingToSend(t *testing.T) {
+	frames := AppendFlowControlFrames([]Frame{&PingFrame{}}, &FlowControlState{
+		Streams: []StreamFlowControlState{{StreamID: 4}},
+	})
+	require.Equal(t, []Frame{&PingFrame{}}, frames)
+}
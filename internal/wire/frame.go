@@ -1,6 +1,8 @@
 package wire
 
 import (
+	"fmt"
+
 	"github.com/quic-go/quic-go/internal/protocol"
 )
 
@@ -10,6 +12,30 @@ type Frame interface {
 	Length(version protocol.Version) protocol.ByteCount
 }
 
+// AppendN appends a frame, and returns the number of bytes appended.
+// When built with the quicdebug build tag, it checks that this number matches the length reported by the frame.
+func AppendN(b []byte, f Frame, v protocol.Version) ([]byte, int, error) {
+	startLen := len(b)
+	b, err := f.Append(b, v)
+	if err != nil {
+		return b, 0, err
+	}
+	n := len(b) - startLen
+	if debugChecks {
+		if err := checkAppendedLength(f, n, v); err != nil {
+			return b, n, err
+		}
+	}
+	return b, n, nil
+}
+
+func checkAppendedLength(f Frame, n int, v protocol.Version) error {
+	if l := f.Length(v); protocol.ByteCount(n) != l {
+		return fmt.Errorf("BUG: %T appended %d bytes, but its length is %d bytes", f, n, l)
+	}
+	return nil
+}
+
 // IsProbingFrame returns true if the frame is a probing frame.
 // See section 9.1 of RFC 9000.
 func IsProbingFrame(f Frame) bool {
@@ -19,3 +45,16 @@ func IsProbingFrame(f Frame) bool {
 	}
 	return false
 }
+
+// ExtendsToEndOfPacket returns true if the frame extends to the end of the packet.
+// This is the case for STREAM and DATAGRAM frames that don't have a Length field.
+// No other frame can follow such a frame in the same packet.
+func ExtendsToEndOfPacket(f Frame) bool {
+	switch f := f.(type) {
+	case *StreamFrame:
+		return f.ExtendsToEndOfPacket()
+	case *DatagramFrame:
+		return f.ExtendsToEndOfPacket()
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package wire
+
+import "github.com/quic-go/quic-go/internal/protocol"
+
+// IsAckElicitingFrame says if the frame is ack-eliciting.
+// All frames other than ACK, PADDING and CONNECTION_CLOSE are ack-eliciting (see section 2 of RFC 9002).
+func IsAckElicitingFrame(f Frame) bool {
+	switch f.(type) {
+	case *AckFrame, *ConnectionCloseFrame:
+		return false
+	default:
+		return true
+	}
+}
+
+// FrameAccounting describes the frames packed into a packet, as needed for loss recovery and congestion control.
+type FrameAccounting struct {
+	// Bytes is the number of bytes of all frames, including PADDING.
+	Bytes protocol.ByteCount
+	// AckElicitingBytes is the number of bytes of all ack-eliciting frames.
+	AckElicitingBytes protocol.ByteCount
+	// AckEliciting is true if the packet contains at least one ack-eliciting frame.
+	AckEliciting bool
+	// InFlight is true if the packet counts towards the bytes in flight,
+	// i.e. if it is ack-eliciting or contains PADDING (see section 2 of RFC 9002).
+	InFlight bool
+}
+
+// AccountFrames computes the FrameAccounting for the frames of a packet.
+// padding is the number of PADDING bytes added to the packet.
+func AccountFrames(frames []Frame, padding protocol.ByteCount, v protocol.Version) FrameAccounting {
+	a := FrameAccounting{Bytes: padding}
+	for _, f := range frames {
+		l := f.Length(v)
+		a.Bytes += l
+		if IsAckElicitingFrame(f) {
+			a.AckEliciting = true
+			a.AckElicitingBytes += l
+		}
+	}
+	a.InFlight = a.AckEliciting || padding > 0
+	return a
+}
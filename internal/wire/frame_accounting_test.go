@@ -0,0 +1,52 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAckElicitingFrame(t *testing.T) {
+	require.False(t, IsAckElicitingFrame(&AckFrame{}))
+	require.False(t, IsAckElicitingFrame(&ConnectionCloseFrame{}))
+	require.True(t, IsAckElicitingFrame(&PingFrame{}))
+	require.True(t, IsAckElicitingFrame(&StreamFrame{}))
+	require.True(t, IsAckElicitingFrame(&DataBlockedFrame{}))
+}
+
+func TestAccountFrames(t *testing.T) {
+	ack := &AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}}
+	ping := &PingFrame{}
+	stream := &StreamFrame{StreamID: 4, Data: []byte("foobar")}
+	ackLen := ack.Length(protocol.Version1)
+	pingLen := ping.Length(protocol.Version1)
+	streamLen := stream.Length(protocol.Version1)
+
+	t.Run("ACK-only", func(t *testing.T) {
+		a := AccountFrames([]Frame{ack}, 0, protocol.Version1)
+		require.Equal(t, FrameAccounting{Bytes: ackLen}, a)
+	})
+
+	t.Run("ACK and PADDING", func(t *testing.T) {
+		a := AccountFrames([]Frame{ack}, 10, protocol.Version1)
+		require.Equal(t, FrameAccounting{Bytes: ackLen + 10, InFlight: true}, a)
+	})
+
+	t.Run("ack-eliciting", func(t *testing.T) {
+		a := AccountFrames([]Frame{ack, ping, stream}, 0, protocol.Version1)
+		require.Equal(t, FrameAccounting{
+			Bytes:             ackLen + pingLen + streamLen,
+			AckElicitingBytes: pingLen + streamLen,
+			AckEliciting:      true,
+			InFlight:          true,
+		}, a)
+	})
+
+	t.Run("CONNECTION_CLOSE", func(t *testing.T) {
+		a := AccountFrames([]Frame{&ConnectionCloseFrame{}}, 0, protocol.Version1)
+		require.False(t, a.AckEliciting)
+		require.False(t, a.InFlight)
+	})
+}
@@ -0,0 +1,142 @@
+package wire
+
+import (
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// A FrameHandler handles the frames parsed by FrameParser.ParseWithHandler.
+// Every frame type is passed to a dedicated method, so the caller doesn't need to use a type switch.
+// If a method returns an error, parsing is aborted, and the error is returned by ParseWithHandler.
+type FrameHandler interface {
+	OnPing(*PingFrame) error
+	// OnAck is called for ACK frames. The ACK frame is only valid until the method returns.
+	OnAck(*AckFrame) error
+	// OnResetStream is called for RESET_STREAM and RESET_STREAM_AT frames.
+	OnResetStream(*ResetStreamFrame) error
+	OnStopSending(*StopSendingFrame) error
+	OnCrypto(*CryptoFrame) error
+	OnNewToken(*NewTokenFrame) error
+	// OnStream is called for STREAM frames.
+	// The handler takes ownership of the frame, and should return it to the pool using PutBack.
+	OnStream(*StreamFrame) error
+	OnMaxData(*MaxDataFrame) error
+	OnMaxStreamData(*MaxStreamDataFrame) error
+	OnMaxStreams(*MaxStreamsFrame) error
+	OnDataBlocked(*DataBlockedFrame) error
+	OnStreamDataBlocked(*StreamDataBlockedFrame) error
+	OnStreamsBlocked(*StreamsBlockedFrame) error
+	OnNewConnectionID(*NewConnectionIDFrame) error
+	OnRetireConnectionID(*RetireConnectionIDFrame) error
+	OnPathChallenge(*PathChallengeFrame) error
+	OnPathResponse(*PathResponseFrame) error
+	OnConnectionClose(*ConnectionCloseFrame) error
+	OnHandshakeDone(*HandshakeDoneFrame) error
+	OnDatagram(*DatagramFrame) error
+	// OnOther is called for frames not defined by this package,
+	// i.e. for frames registered using RegisterFrameType and for UnknownFrames.
+	OnOther(Frame) error
+}
+
+// ParseWithHandler parses all frames in a packet payload, and passes them to the handler.
+// It calls StartPayload, and skips PADDING frames.
+// Frames are validated in the same way as by ParseNext.
+// Unless middlewares are used, frames are passed to the handler without boxing them in the Frame interface.
+func (p *FrameParser) ParseWithHandler(data []byte, encLevel protocol.EncryptionLevel, v protocol.Version, h FrameHandler) error {
+	p.StartPayload()
+	for len(data) > 0 {
+		l, err := p.parseNextTo(data, encLevel, v, h)
+		if err != nil {
+			return err
+		}
+		data = data[l:]
+	}
+	return nil
+}
+
+// A handlerError wraps an error returned by a FrameHandler,
+// such that it is returned as is, instead of being converted into a FRAME_ENCODING_ERROR.
+type handlerError struct{ err error }
+
+func (e *handlerError) Error() string { return e.err.Error() }
+
+func handled(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &handlerError{err: err}
+}
+
+// A handledFrame is a frame defined by this package, which knows which method of the FrameHandler to call.
+type handledFrame interface {
+	Frame
+	handle(FrameHandler) error
+}
+
+// dispatchFrame passes a frame returned by a middleware to the handler.
+func dispatchFrame(f Frame, h FrameHandler) error {
+	if hf, ok := f.(handledFrame); ok {
+		return hf.handle(h)
+	}
+	return h.OnOther(f)
+}
+
+func (f *PingFrame) handle(h FrameHandler) error               { return h.OnPing(f) }
+func (f *AckFrame) handle(h FrameHandler) error                { return h.OnAck(f) }
+func (f *ResetStreamFrame) handle(h FrameHandler) error        { return h.OnResetStream(f) }
+func (f *StopSendingFrame) handle(h FrameHandler) error        { return h.OnStopSending(f) }
+func (f *CryptoFrame) handle(h FrameHandler) error             { return h.OnCrypto(f) }
+func (f *NewTokenFrame) handle(h FrameHandler) error           { return h.OnNewToken(f) }
+func (f *StreamFrame) handle(h FrameHandler) error             { return h.OnStream(f) }
+func (f *MaxDataFrame) handle(h FrameHandler) error            { return h.OnMaxData(f) }
+func (f *MaxStreamDataFrame) handle(h FrameHandler) error      { return h.OnMaxStreamData(f) }
+func (f *MaxStreamsFrame) handle(h FrameHandler) error         { return h.OnMaxStreams(f) }
+func (f *DataBlockedFrame) handle(h FrameHandler) error        { return h.OnDataBlocked(f) }
+func (f *StreamDataBlockedFrame) handle(h FrameHandler) error  { return h.OnStreamDataBlocked(f) }
+func (f *StreamsBlockedFrame) handle(h FrameHandler) error     { return h.OnStreamsBlocked(f) }
+func (f *NewConnectionIDFrame) handle(h FrameHandler) error    { return h.OnNewConnectionID(f) }
+func (f *RetireConnectionIDFrame) handle(h FrameHandler) error { return h.OnRetireConnectionID(f) }
+func (f *PathChallengeFrame) handle(h FrameHandler) error      { return h.OnPathChallenge(f) }
+func (f *PathResponseFrame) handle(h FrameHandler) error       { return h.OnPathResponse(f) }
+func (f *ConnectionCloseFrame) handle(h FrameHandler) error    { return h.OnConnectionClose(f) }
+func (f *HandshakeDoneFrame) handle(h FrameHandler) error      { return h.OnHandshakeDone(f) }
+func (f *DatagramFrame) handle(h FrameHandler) error           { return h.OnDatagram(f) }
+
+// frameBox is the FrameHandler used by ParseNext.
+// It boxes the frame in the Frame interface.
+type frameBox struct{ frame Frame }
+
+var _ FrameHandler = &frameBox{}
+
+// take returns the frame, and resets the frameBox.
+func (b *frameBox) take() Frame {
+	f := b.frame
+	b.frame = nil
+	return f
+}
+
+func (b *frameBox) set(f Frame) error {
+	b.frame = f
+	return nil
+}
+
+func (b *frameBox) OnPing(f *PingFrame) error                             { return b.set(f) }
+func (b *frameBox) OnAck(f *AckFrame) error                               { return b.set(f) }
+func (b *frameBox) OnResetStream(f *ResetStreamFrame) error               { return b.set(f) }
+func (b *frameBox) OnStopSending(f *StopSendingFrame) error               { return b.set(f) }
+func (b *frameBox) OnCrypto(f *CryptoFrame) error                         { return b.set(f) }
+func (b *frameBox) OnNewToken(f *NewTokenFrame) error                     { return b.set(f) }
+func (b *frameBox) OnStream(f *StreamFrame) error                         { return b.set(f) }
+func (b *frameBox) OnMaxData(f *MaxDataFrame) error                       { return b.set(f) }
+func (b *frameBox) OnMaxStreamData(f *MaxStreamDataFrame) error           { return b.set(f) }
+func (b *frameBox) OnMaxStreams(f *MaxStreamsFrame) error                 { return b.set(f) }
+func (b *frameBox) OnDataBlocked(f *DataBlockedFrame) error               { return b.set(f) }
+func (b *frameBox) OnStreamDataBlocked(f *StreamDataBlockedFrame) error   { return b.set(f) }
+func (b *frameBox) OnStreamsBlocked(f *StreamsBlockedFrame) error         { return b.set(f) }
+func (b *frameBox) OnNewConnectionID(f *NewConnectionIDFrame) error       { return b.set(f) }
+func (b *frameBox) OnRetireConnectionID(f *RetireConnectionIDFrame) error { return b.set(f) }
+func (b *frameBox) OnPathChallenge(f *PathChallengeFrame) error           { return b.set(f) }
+func (b *frameBox) OnPathResponse(f *PathResponseFrame) error             { return b.set(f) }
+func (b *frameBox) OnConnectionClose(f *ConnectionCloseFrame) error       { return b.set(f) }
+func (b *frameBox) OnHandshakeDone(f *HandshakeDoneFrame) error           { return b.set(f) }
+func (b *frameBox) OnDatagram(f *DatagramFrame) error                     { return b.set(f) }
+func (b *frameBox) OnOther(f Frame) error                                 { return b.set(f) }
@@ -0,0 +1,175 @@
+package wire
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+// frameRecorder records the frames passed to it.
+type frameRecorder struct {
+	frames []Frame
+	err    error
+}
+
+var _ FrameHandler = &frameRecorder{}
+
+func (h *frameRecorder) record(f Frame) error {
+	if ack, ok := f.(*AckFrame); ok {
+		f = ack.Clone()
+	}
+	h.frames = append(h.frames, f)
+	return h.err
+}
+
+func (h *frameRecorder) OnPing(f *PingFrame) error                             { return h.record(f) }
+func (h *frameRecorder) OnAck(f *AckFrame) error                               { return h.record(f) }
+func (h *frameRecorder) OnResetStream(f *ResetStreamFrame) error               { return h.record(f) }
+func (h *frameRecorder) OnStopSending(f *StopSendingFrame) error               { return h.record(f) }
+func (h *frameRecorder) OnCrypto(f *CryptoFrame) error                         { return h.record(f) }
+func (h *frameRecorder) OnNewToken(f *NewTokenFrame) error                     { return h.record(f) }
+func (h *frameRecorder) OnStream(f *StreamFrame) error                         { return h.record(f) }
+func (h *frameRecorder) OnMaxData(f *MaxDataFrame) error                       { return h.record(f) }
+func (h *frameRecorder) OnMaxStreamData(f *MaxStreamDataFrame) error           { return h.record(f) }
+func (h *frameRecorder) OnMaxStreams(f *MaxStreamsFrame) error                 { return h.record(f) }
+func (h *frameRecorder) OnDataBlocked(f *DataBlockedFrame) error               { return h.record(f) }
+func (h *frameRecorder) OnStreamDataBlocked(f *StreamDataBlockedFrame) error   { return h.record(f) }
+func (h *frameRecorder) OnStreamsBlocked(f *StreamsBlockedFrame) error         { return h.record(f) }
+func (h *frameRecorder) OnNewConnectionID(f *NewConnectionIDFrame) error       { return h.record(f) }
+func (h *frameRecorder) OnRetireConnectionID(f *RetireConnectionIDFrame) error { return h.record(f) }
+func (h *frameRecorder) OnPathChallenge(f *PathChallengeFrame) error           { return h.record(f) }
+func (h *frameRecorder) OnPathResponse(f *PathResponseFrame) error             { return h.record(f) }
+func (h *frameRecorder) OnConnectionClose(f *ConnectionCloseFrame) error       { return h.record(f) }
+func (h *frameRecorder) OnHandshakeDone(f *HandshakeDoneFrame) error           { return h.record(f) }
+func (h *frameRecorder) OnDatagram(f *DatagramFrame) error                     { return h.record(f) }
+func (h *frameRecorder) OnOther(f Frame) error                                 { return h.record(f) }
+
+func TestFrameParserParseWithHandler(t *testing.T) {
+	frames := []Frame{
+		&PingFrame{},
+		&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}},
+		&ResetStreamFrame{StreamID: 4, ErrorCode: 42, FinalSize: 1337},
+		&StopSendingFrame{StreamID: 4, ErrorCode: 42},
+		&CryptoFrame{Offset: 10, Data: []byte("foobar")},
+		&NewTokenFrame{Token: []byte("token")},
+		&MaxDataFrame{MaximumData: 1337},
+		&MaxStreamDataFrame{StreamID: 4, MaximumStreamData: 1337},
+		&MaxStreamsFrame{Type: protocol.StreamTypeBidi, MaxStreamNum: 10},
+		&DataBlockedFrame{MaximumData: 1337},
+		&StreamDataBlockedFrame{StreamID: 4, MaximumStreamData: 1337},
+		&StreamsBlockedFrame{Type: protocol.StreamTypeUni, StreamLimit: 10},
+		&NewConnectionIDFrame{SequenceNumber: 1, ConnectionID: protocol.ParseConnectionID([]byte{1, 2, 3, 4})},
+		&RetireConnectionIDFrame{SequenceNumber: 1},
+		&PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		&PathResponseFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		&HandshakeDoneFrame{},
+		&DatagramFrame{Data: []byte("foobar"), DataLenPresent: true},
+		&ConnectionCloseFrame{IsApplicationError: true, ErrorCode: 42, ReasonPhrase: "foo"},
+		&StreamFrame{StreamID: 4, Data: []byte("foobar")},
+	}
+	b := appendFrames(t, frames...)
+
+	parser := NewFrameParser(true, true)
+	var h frameRecorder
+	require.NoError(t, parser.ParseWithHandler(b, protocol.Encryption1RTT, protocol.Version1, &h))
+	require.Len(t, h.frames, len(frames))
+	for i, f := range h.frames {
+		require.IsType(t, frames[i], f)
+		require.Equal(t, appendFrames(t, frames[i]), appendFrames(t, f))
+	}
+}
+
+func TestFrameParserParseWithHandlerMiddleware(t *testing.T) {
+	frames := []Frame{
+		&PingFrame{},
+		&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}},
+		&MaxDataFrame{MaximumData: 1337},
+		&StreamFrame{StreamID: 4, Data: []byte("foobar")},
+	}
+	b := appendFrames(t, frames...)
+
+	parser := NewFrameParser(true, true)
+	var parsed []uint64
+	parser.Use(func(next ParseFunc) ParseFunc {
+		return func(b []byte, typ uint64, encLevel protocol.EncryptionLevel, v protocol.Version) (Frame, int, error) {
+			parsed = append(parsed, typ)
+			return next(b, typ, encLevel, v)
+		}
+	})
+	var h frameRecorder
+	require.NoError(t, parser.ParseWithHandler(b, protocol.Encryption1RTT, protocol.Version1, &h))
+	require.Equal(t, []uint64{pingFrameType, ackFrameType, maxDataFrameType, 0x8}, parsed)
+	require.Len(t, h.frames, len(frames))
+	for i, f := range h.frames {
+		require.IsType(t, frames[i], f)
+		require.Equal(t, appendFrames(t, frames[i]), appendFrames(t, f))
+	}
+}
+
+func TestFrameParserParseWithHandlerOther(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	parser.RegisterFrameType(testExtensionFrameType, parseTestExtensionFrame)
+	parser.SetUnknownFrameLength(UnknownFrameExtendsToEnd)
+	b := appendFrames(t, &testExtensionFrame{Value: 1337})
+	b = append(b, quicvarint.Append(nil, 0x4343)...)
+	b = append(b, []byte("foo")...)
+
+	var h frameRecorder
+	require.NoError(t, parser.ParseWithHandler(b, protocol.Encryption1RTT, protocol.Version1, &h))
+	require.Len(t, h.frames, 2)
+	require.Equal(t, &testExtensionFrame{Value: 1337}, h.frames[0])
+	require.Equal(t, &UnknownFrame{FrameType: 0x4343, Data: []byte("foo")}, h.frames[1])
+}
+
+func TestFrameParserParseWithHandlerAllocations(t *testing.T) {
+	b := appendFrames(t,
+		&PingFrame{},
+		&AckFrame{AckRanges: []AckRange{{Smallest: 5, Largest: 10}, {Smallest: 1, Largest: 2}}},
+	)
+	parser := NewFrameParser(true, true)
+	var h countingHandler
+	// warm up the ACK frame's ACK range slice
+	require.NoError(t, parser.ParseWithHandler(b, protocol.Encryption1RTT, protocol.Version1, &h))
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := parser.ParseWithHandler(b, protocol.Encryption1RTT, protocol.Version1, &h); err != nil {
+			t.Fatal(err)
+		}
+	})
+	require.Zero(t, allocs)
+	require.NotZero(t, h.pings)
+	require.Equal(t, h.pings, h.acks)
+}
+
+// countingHandler counts PING and ACK frames, and rejects all other frames.
+type countingHandler struct {
+	frameRecorder
+	pings, acks int
+}
+
+func (h *countingHandler) OnPing(*PingFrame) error { h.pings++; return nil }
+func (h *countingHandler) OnAck(*AckFrame) error   { h.acks++; return nil }
+
+func TestFrameParserParseWithHandlerErrors(t *testing.T) {
+	parser := NewFrameParser(true, true)
+
+	t.Run("parsing error", func(t *testing.T) {
+		b := appendFrames(t, &PingFrame{})
+		b = append(b, maxDataFrameType)
+		var h frameRecorder
+		err := parser.ParseWithHandler(b, protocol.Encryption1RTT, protocol.Version1, &h)
+		require.ErrorIs(t, err, &qerr.TransportError{FrameType: maxDataFrameType, ErrorCode: qerr.FrameEncodingError})
+		require.Equal(t, []Frame{&PingFrame{}}, h.frames)
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		b := appendFrames(t, &PingFrame{}, &PingFrame{})
+		h := frameRecorder{err: errors.New("test error")}
+		require.EqualError(t, parser.ParseWithHandler(b, protocol.Encryption1RTT, protocol.Version1, &h), "test error")
+		require.Len(t, h.frames, 1)
+	})
+}
@@ -0,0 +1,195 @@
+package wire
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+// randVarInt returns a random value that fits into a varint.
+// Values close to the boundaries of the varint encodings are favored.
+func randVarInt(r *rand.Rand, max uint64) uint64 {
+	var v uint64
+	switch r.IntN(6) {
+	case 0:
+		v = r.Uint64N(64)
+	case 1:
+		v = []uint64{63, 64, 16383, 16384, 1<<30 - 1, 1 << 30, quicvarint.Max}[r.IntN(7)]
+	case 2:
+		v = r.Uint64N(16384)
+	case 3:
+		v = r.Uint64N(1 << 30)
+	default:
+		v = r.Uint64N(quicvarint.Max + 1)
+	}
+	return min(v, max)
+}
+
+func randBytes(r *rand.Rand, maxLen int) []byte {
+	b := make([]byte, r.IntN(maxLen+1))
+	for i := range b {
+		b[i] = byte(r.Uint32())
+	}
+	return b
+}
+
+func randStreamType(r *rand.Rand) protocol.StreamType {
+	if r.IntN(2) == 0 {
+		return protocol.StreamTypeUni
+	}
+	return protocol.StreamTypeBidi
+}
+
+func randAckFrame(r *rand.Rand) *AckFrame {
+	f := &AckFrame{DelayTime: time.Duration(randVarInt(r, 1<<40)) * time.Microsecond}
+	largest := randVarInt(r, quicvarint.Max)
+	numRanges := 1 + r.IntN(10)
+	for range numRanges {
+		smallest := largest - randVarInt(r, largest)
+		f.AckRanges = append(f.AckRanges, AckRange{Smallest: protocol.PacketNumber(smallest), Largest: protocol.PacketNumber(largest)})
+		if smallest < 2 {
+			break
+		}
+		// leave a gap of at least 1 packet
+		largest = smallest - 2 - randVarInt(r, smallest-2)
+	}
+	if r.IntN(2) == 0 {
+		f.ECT0, f.ECT1, f.ECNCE = randVarInt(r, quicvarint.Max), randVarInt(r, quicvarint.Max), randVarInt(r, quicvarint.Max)
+	}
+	return f
+}
+
+func randFrame(r *rand.Rand) Frame {
+	switch r.IntN(22) {
+	case 0:
+		return &PingFrame{}
+	case 1:
+		return randAckFrame(r)
+	case 2:
+		return &ResetStreamFrame{
+			StreamID:  protocol.StreamID(randVarInt(r, quicvarint.Max)),
+			ErrorCode: qerr.StreamErrorCode(randVarInt(r, quicvarint.Max)),
+			FinalSize: protocol.ByteCount(randVarInt(r, quicvarint.Max)),
+		}
+	case 3:
+		finalSize := 1 + randVarInt(r, quicvarint.Max-1)
+		return &ResetStreamFrame{
+			StreamID:     protocol.StreamID(randVarInt(r, quicvarint.Max)),
+			ErrorCode:    qerr.StreamErrorCode(randVarInt(r, quicvarint.Max)),
+			FinalSize:    protocol.ByteCount(finalSize),
+			ReliableSize: protocol.ByteCount(1 + randVarInt(r, finalSize-1)),
+		}
+	case 4:
+		return &StopSendingFrame{
+			StreamID:  protocol.StreamID(randVarInt(r, quicvarint.Max)),
+			ErrorCode: qerr.StreamErrorCode(randVarInt(r, quicvarint.Max)),
+		}
+	case 5:
+		return &CryptoFrame{Offset: protocol.ByteCount(randVarInt(r, quicvarint.Max)), Data: randBytes(r, 20000)}
+	case 6:
+		return &NewTokenFrame{Token: append(randBytes(r, 200), 0)}
+	case 7:
+		data := randBytes(r, 20000)
+		f := &StreamFrame{
+			StreamID:       protocol.StreamID(randVarInt(r, quicvarint.Max)),
+			Offset:         protocol.ByteCount(randVarInt(r, quicvarint.Max-uint64(len(data)))),
+			Data:           data,
+			Fin:            r.IntN(2) == 0,
+			DataLenPresent: r.IntN(2) == 0,
+			OffsetPresent:  r.IntN(4) == 0,
+		}
+		if len(data) == 0 {
+			f.Fin = true
+		}
+		return f
+	case 8:
+		return &MaxDataFrame{MaximumData: protocol.ByteCount(randVarInt(r, quicvarint.Max))}
+	case 9:
+		return &MaxStreamDataFrame{
+			StreamID:          protocol.StreamID(randVarInt(r, quicvarint.Max)),
+			MaximumStreamData: protocol.ByteCount(randVarInt(r, quicvarint.Max)),
+		}
+	case 10:
+		return &MaxStreamsFrame{Type: randStreamType(r), MaxStreamNum: protocol.StreamNum(randVarInt(r, uint64(protocol.MaxStreamCount)))}
+	case 11:
+		return &DataBlockedFrame{MaximumData: protocol.ByteCount(randVarInt(r, quicvarint.Max))}
+	case 12:
+		return &StreamDataBlockedFrame{
+			StreamID:          protocol.StreamID(randVarInt(r, quicvarint.Max)),
+			MaximumStreamData: protocol.ByteCount(randVarInt(r, quicvarint.Max)),
+		}
+	case 13:
+		return &StreamsBlockedFrame{Type: randStreamType(r), StreamLimit: protocol.StreamNum(randVarInt(r, uint64(protocol.MaxStreamCount)))}
+	case 14:
+		seq := randVarInt(r, quicvarint.Max)
+		connID := make([]byte, 1+r.IntN(protocol.MaxConnIDLen))
+		for i := range connID {
+			connID[i] = byte(r.Uint32())
+		}
+		return &NewConnectionIDFrame{
+			SequenceNumber: seq,
+			RetirePriorTo:  randVarInt(r, seq),
+			ConnectionID:   protocol.ParseConnectionID(connID),
+		}
+	case 15:
+		return &RetireConnectionIDFrame{SequenceNumber: randVarInt(r, quicvarint.Max)}
+	case 16:
+		var data [8]byte
+		for i := range data {
+			data[i] = byte(r.Uint32())
+		}
+		return &PathChallengeFrame{Data: data}
+	case 17:
+		var data [8]byte
+		for i := range data {
+			data[i] = byte(r.Uint32())
+		}
+		return &PathResponseFrame{Data: data}
+	case 18:
+		return &ConnectionCloseFrame{
+			ErrorCode:    randVarInt(r, quicvarint.Max),
+			FrameType:    randVarInt(r, quicvarint.Max),
+			ReasonPhrase: string(randBytes(r, 1000)),
+		}
+	case 19:
+		return &ConnectionCloseFrame{
+			IsApplicationError: true,
+			ErrorCode:          randVarInt(r, quicvarint.Max),
+			ReasonPhrase:       string(randBytes(r, 1000)),
+		}
+	case 20:
+		return &HandshakeDoneFrame{}
+	default:
+		return &DatagramFrame{Data: randBytes(r, 1200), DataLenPresent: r.IntN(2) == 0}
+	}
+}
+
+func TestFrameLengthMatchesAppend(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	t.Logf("seed: %d", seed)
+	r := rand.New(rand.NewPCG(seed, seed))
+
+	seen := make(map[string]bool)
+	for range 10000 {
+		f := randFrame(r)
+		b, n, err := AppendN([]byte("foo"), f, protocol.Version1)
+		require.NoError(t, err, "%#v", f)
+		require.NoError(t, checkAppendedLength(f, n, protocol.Version1), DescribeFrame(f, protocol.Version1))
+		require.Equal(t, len(b)-3, n)
+		typ, _, err := quicvarint.Parse(b[3:])
+		require.NoError(t, err)
+		seen[FrameType(typ).String()] = true
+	}
+	for _, name := range frameTypeNames {
+		if name == "" || name == "PADDING" {
+			continue
+		}
+		require.True(t, seen[name], "no %s frame generated", name)
+	}
+}
@@ -0,0 +1,43 @@
+package wire
+
+import "github.com/quic-go/quic-go/internal/protocol"
+
+// maxVarIntLen is the length of the longest varint encoding.
+const maxVarIntLen = 8
+
+// The maximum number of bytes a frame adds on top of its variable-length payload,
+// assuming that every varint field is encoded using the longest encoding.
+// These bounds are asserted against the encoding used by Append in the tests.
+const (
+	// MaxStreamFrameHeaderLen is the maximum length of a STREAM frame, excluding the Stream Data.
+	// It consists of the frame type, the Stream ID, the Offset and the Length.
+	MaxStreamFrameHeaderLen = 1 + 3*maxVarIntLen
+	// MaxCryptoFrameHeaderLen is the maximum length of a CRYPTO frame, excluding the Crypto Data.
+	MaxCryptoFrameHeaderLen = 1 + 2*maxVarIntLen
+	// MaxDatagramFrameHeaderLen is the maximum length of a DATAGRAM frame, excluding the Datagram Data.
+	MaxDatagramFrameHeaderLen = 1 + maxVarIntLen
+	// MaxNewTokenFrameHeaderLen is the maximum length of a NEW_TOKEN frame, excluding the Token.
+	MaxNewTokenFrameHeaderLen = 1 + maxVarIntLen
+	// MaxConnectionCloseFrameHeaderLen is the maximum length of a CONNECTION_CLOSE frame, excluding the Reason Phrase.
+	MaxConnectionCloseFrameHeaderLen = 1 + 3*maxVarIntLen
+	// MaxAckFrameHeaderLen is the maximum length of an ACK frame with a single ACK range.
+	// It consists of the frame type, the Largest Acknowledged, the ACK Delay, the ACK Range Count and the First ACK Range.
+	MaxAckFrameHeaderLen = 1 + 4*maxVarIntLen
+	// MaxAckFrameHeaderLenPerRange is the maximum length of every additional ACK range (Gap and ACK Range Length).
+	MaxAckFrameHeaderLenPerRange = 2 * maxVarIntLen
+	// MaxAckFrameECNCountsLen is the maximum length of the ECN counts of an ACK_ECN frame.
+	MaxAckFrameECNCountsLen = 3 * maxVarIntLen
+	// MaxResetStreamFrameLen is the maximum length of a RESET_STREAM or RESET_STREAM_AT frame.
+	MaxResetStreamFrameLen = 1 + 4*maxVarIntLen
+	// MaxNewConnectionIDFrameLen is the maximum length of a NEW_CONNECTION_ID frame.
+	MaxNewConnectionIDFrameLen = 1 + 2*maxVarIntLen + 1 + protocol.MaxConnIDLen + len(protocol.StatelessResetToken{})
+)
+
+// MaxAckFrameLen returns the maximum length of an ACK frame with numRanges ACK ranges.
+func MaxAckFrameLen(numRanges int, ecn bool) int {
+	l := MaxAckFrameHeaderLen + (numRanges-1)*MaxAckFrameHeaderLenPerRange
+	if ecn {
+		l += MaxAckFrameECNCountsLen
+	}
+	return l
+}
@@ -0,0 +1,72 @@
+package wire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameOverheadBounds(t *testing.T) {
+	require.Equal(t, maxVarIntLen, quicvarint.Len(quicvarint.Max))
+
+	const maxVal = quicvarint.Max
+	data := []byte("foobar")
+	appendLen := func(f Frame) int {
+		t.Helper()
+		b, err := f.Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		require.Len(t, b, int(f.Length(protocol.Version1)))
+		return len(b)
+	}
+
+	require.Equal(t, MaxStreamFrameHeaderLen, appendLen(&StreamFrame{
+		StreamID:       maxVal,
+		Offset:         maxVal - protocol.ByteCount(len(data)) - (1 << 61),
+		Data:           make([]byte, 1<<14),
+		DataLenPresent: true,
+	})-(1<<14)+(maxVarIntLen-4)) // the Length field uses 4 bytes
+	require.Equal(t, MaxCryptoFrameHeaderLen, appendLen(&CryptoFrame{Offset: maxVal, Data: data})-len(data)+(maxVarIntLen-1))
+	require.Equal(t, MaxDatagramFrameHeaderLen, appendLen(&DatagramFrame{Data: data, DataLenPresent: true})-len(data)+(maxVarIntLen-1))
+	require.Equal(t, MaxNewTokenFrameHeaderLen, appendLen(&NewTokenFrame{Token: data})-len(data)+(maxVarIntLen-1))
+	require.Equal(t, MaxConnectionCloseFrameHeaderLen, appendLen(&ConnectionCloseFrame{
+		ErrorCode:    maxVal,
+		FrameType:    maxVal,
+		ReasonPhrase: string(data),
+	})-len(data)+(maxVarIntLen-1))
+	require.Equal(t, MaxResetStreamFrameLen, appendLen(&ResetStreamFrame{
+		StreamID:     maxVal,
+		ErrorCode:    maxVal,
+		FinalSize:    maxVal,
+		ReliableSize: maxVal,
+	}))
+	require.Equal(t, MaxNewConnectionIDFrameLen, appendLen(&NewConnectionIDFrame{
+		SequenceNumber: maxVal,
+		RetirePriorTo:  maxVal,
+		ConnectionID:   protocol.ParseConnectionID(make([]byte, protocol.MaxConnIDLen)),
+	}))
+}
+
+func TestMaxAckFrameLen(t *testing.T) {
+	const maxVal = quicvarint.Max
+	// Every field is encoded using 8 bytes, apart from the ACK Range Count.
+	// The ACK Delay is encoded using 8 bytes for delays above ~2^30 * 8µs.
+	f := &AckFrame{
+		AckRanges: []AckRange{
+			{Smallest: 1 << 61, Largest: maxVal},
+			{Smallest: 0, Largest: 1<<60 - 1},
+		},
+		DelayTime: 1 << 40 * time.Microsecond,
+	}
+	b, err := f.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, MaxAckFrameLen(2, false), len(b)+(maxVarIntLen-1))
+
+	f.ECT0, f.ECT1, f.ECNCE = maxVal, maxVal, maxVal
+	b, err = f.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, MaxAckFrameLen(2, true), len(b)+(maxVarIntLen-1))
+}
@@ -3,8 +3,8 @@ package wire
 import (
 	"errors"
 	"fmt"
-	"io"
-	"reflect"
+	"maps"
+	"time"
 
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/qerr"
@@ -41,13 +41,59 @@ var errUnknownFrameType = errors.New("unknown frame type")
 
 // The FrameParser parses QUIC frames, one by one.
 type FrameParser struct {
-	ackDelayExponent      uint8
+	ackDelayExponent uint8
+	// The perspective of the endpoint receiving the frames.
+	// If not set, frames are not checked for the direction they're sent in.
+	perspective           protocol.Perspective
 	supportsDatagrams     bool
 	supportsResetStreamAt bool
+	// The peer's max_ack_delay (sent in the transport parameters).
+	// If 0, ACK delays are not checked.
+	maxAckDelay time.Duration
+	// The maximum number of PADDING bytes skipped by a single call to ParseNext.
+	// If 0, there's no limit.
+	maxPaddingScan int
+	// Returns the largest packet number sent in the packet number space of the encryption level.
+	// If nil, ACK frames are not checked for acknowledging packets that were never sent.
+	largestSent func(protocol.EncryptionLevel) protocol.PacketNumber
+	// Tracks the largest packet numbers sent and acknowledged, per packet number space.
+	// If nil, ACK frames are not checked against it.
+	pnSpaces *PacketNumberSpaces
+	// The maximum offset of CRYPTO data, per encryption level.
+	// If not set for an encryption level, CRYPTO frames are not checked.
+	maxCryptoOffsets map[protocol.EncryptionLevel]protocol.ByteCount
+	newTokenBudget   newTokenBudget
+	// The maximum number of PATH_CHALLENGE and PATH_RESPONSE frames per payload.
+	// If 0, the number of frames is not limited.
+	maxPathFramesPerPayload int
+	// The number of PATH_CHALLENGE and PATH_RESPONSE frames parsed since the last call to StartPayload.
+	pathFrames int
+	// If set, frames are not checked for being allowed at the encryption level,
+	// or for being sent in the right direction.
+	lenient bool
+	// Chooses the buffers for received STREAM frames, based on the frame sizes on this connection.
+	streamBuffers streamBufferPolicy
+	// If set, the payload of DATAGRAM frames is decoded using this codec.
+	datagramCodec DatagramCodec
+	// Parse functions for frame types registered using RegisterFrameType.
+	registeredFrames map[uint64]func([]byte, protocol.Version) (Frame, int, error)
+	// If set, frames of unknown types are returned as UnknownFrames.
+	unknownFrameLength UnknownFrameLengthFunc
+	// Called for every successfully parsed frame.
+	onFrameParsed func(FrameType, int)
+	// Only available when building with the quicdebug build tag.
+	faultInjection
+	// The middlewares added using Use, and the resulting parse function.
+	// If no middleware was added, parse is nil.
+	middlewares []func(ParseFunc) ParseFunc
+	parse       ParseFunc
 
 	// To avoid allocating when parsing, keep a single ACK frame struct.
 	// It is used over and over again.
 	ackFrame *AckFrame
+	// The FrameHandlers used by ParseNext and ParseNextTyped.
+	box   frameBox
+	typed typedFrameBuilder
 }
 
 // NewFrameParser creates a new frame parser.
@@ -59,128 +105,357 @@ func NewFrameParser(supportsDatagrams, supportsResetStreamAt bool) *FrameParser
 	}
 }
 
+// Clone creates a new frame parser with the same configuration.
+// State accumulated while parsing (the per-payload and NEW_TOKEN frame counters, the ACK frame) is not copied.
+// This is useful when different paths need separate parsers.
+func (p *FrameParser) Clone() *FrameParser {
+	c := &FrameParser{
+		ackDelayExponent:        p.ackDelayExponent,
+		perspective:             p.perspective,
+		supportsDatagrams:       p.supportsDatagrams,
+		supportsResetStreamAt:   p.supportsResetStreamAt,
+		maxAckDelay:             p.maxAckDelay,
+		maxPaddingScan:          p.maxPaddingScan,
+		largestSent:             p.largestSent,
+		pnSpaces:                p.pnSpaces,
+		maxPathFramesPerPayload: p.maxPathFramesPerPayload,
+		lenient:                 p.lenient,
+		datagramCodec:           p.datagramCodec,
+		registeredFrames:        maps.Clone(p.registeredFrames),
+		unknownFrameLength:      p.unknownFrameLength,
+		onFrameParsed:           p.onFrameParsed,
+		ackFrame:                &AckFrame{},
+	}
+	c.SetNewTokenLimits(p.newTokenBudget.maxFrames, p.newTokenBudget.maxBytes)
+	for encLevel, maxOffset := range p.maxCryptoOffsets {
+		c.SetMaxCryptoOffset(encLevel, maxOffset)
+	}
+	for _, m := range p.middlewares {
+		c.Use(m)
+	}
+	return c
+}
+
 // ParseNext parses the next frame.
 // It skips PADDING frames.
+// If a limit was set using SetMaxPaddingScan, it might return a nil frame before the end of the data is reached,
+// in which case the caller is expected to continue parsing the remaining data.
+// Parsing errors are of type *FrameParsingError, with the offset of the frame in data.
 func (p *FrameParser) ParseNext(data []byte, encLevel protocol.EncryptionLevel, v protocol.Version) (int, Frame, error) {
 	frame, l, err := p.parseNext(data, encLevel, v)
 	return l, frame, err
 }
 
 func (p *FrameParser) parseNext(b []byte, encLevel protocol.EncryptionLevel, v protocol.Version) (Frame, int, error) {
+	l, err := p.parseNextTo(b, encLevel, v, &p.box)
+	f := p.box.take()
+	if err != nil {
+		return nil, l, err
+	}
+	return f, l, nil
+}
+
+// parseNextTo parses the next frame, and passes it to the handler.
+// It skips PADDING frames. If no frame was parsed, the handler is not called.
+// Errors returned by the handler are returned as is.
+func (p *FrameParser) parseNextTo(b []byte, encLevel protocol.EncryptionLevel, v protocol.Version, h FrameHandler) (int, error) {
 	var parsed int
 	for len(b) != 0 {
+		if p.maxPaddingScan > 0 && parsed >= p.maxPaddingScan {
+			return parsed, nil
+		}
+		offset := parsed
 		typ, l, err := quicvarint.Parse(b)
 		parsed += l
 		if err != nil {
-			return nil, parsed, &qerr.TransportError{
-				ErrorCode:    qerr.FrameEncodingError,
-				ErrorMessage: err.Error(),
+			return parsed, &FrameParsingError{
+				Err: &qerr.TransportError{
+					ErrorCode:    qerr.FrameEncodingError,
+					ErrorMessage: err.Error(),
+				},
+				Offset: offset,
 			}
 		}
 		b = b[l:]
 		if typ == 0x0 { // skip PADDING frames
 			continue
 		}
+		typLen := l
 
-		f, l, err := p.parseFrame(b, typ, encLevel, v)
-		parsed += l
+		err = p.injectFault(typ)
+		if err == nil {
+			if p.parse != nil {
+				// Middlewares operate on the Frame interface.
+				var f Frame
+				f, l, err = p.parse(b, typ, encLevel, v)
+				if err == nil {
+					err = handled(dispatchFrame(f, h))
+				}
+			} else {
+				l, err = p.parseFrameTo(b, typ, encLevel, v, h)
+			}
+			parsed += l
+		}
 		if err != nil {
-			return nil, parsed, &qerr.TransportError{
-				FrameType:    typ,
-				ErrorCode:    qerr.FrameEncodingError,
-				ErrorMessage: err.Error(),
+			if herr, ok := err.(*handlerError); ok {
+				return parsed, herr.err
+			}
+			var transportErr *qerr.TransportError
+			if errors.As(err, &transportErr) {
+				transportErr.FrameType = typ
+			} else {
+				transportErr = &qerr.TransportError{
+					FrameType:    typ,
+					ErrorCode:    qerr.FrameEncodingError,
+					ErrorMessage: err.Error(),
+				}
 			}
+			return parsed, &FrameParsingError{Err: transportErr, Offset: offset}
 		}
-		return f, parsed, nil
+		if p.onFrameParsed != nil {
+			p.onFrameParsed(FrameType(typ), typLen+l)
+		}
+		return parsed, nil
 	}
-	return nil, parsed, nil
+	return parsed, nil
 }
 
+// parseFrame parses a single frame, and returns it boxed in the Frame interface.
+// It is the innermost ParseFunc when middlewares are used.
 func (p *FrameParser) parseFrame(b []byte, typ uint64, encLevel protocol.EncryptionLevel, v protocol.Version) (Frame, int, error) {
-	var frame Frame
-	var err error
-	var l int
+	l, err := p.parseFrameTo(b, typ, encLevel, v, &p.box)
+	f := p.box.take()
+	if err != nil {
+		return nil, l, err
+	}
+	return f, l, nil
+}
+
+// parseFrameTo parses a single frame, and passes it to the method of the handler for this frame type.
+// Errors returned by the handler are wrapped in a handlerError.
+func (p *FrameParser) parseFrameTo(b []byte, typ uint64, encLevel protocol.EncryptionLevel, v protocol.Version, h FrameHandler) (int, error) {
+	// Checking the encryption level before parsing the frame makes sure that frames that are not allowed
+	// don't have any side effects (e.g. on the packet number spaces or on the NEW_TOKEN budget).
+	if !p.lenient && !isAllowedAtEncLevel(typ, encLevel) {
+		return 0, fmt.Errorf("%s frame not allowed at encryption level %s", FrameType(typ), encLevel)
+	}
 	if typ&0xf8 == 0x8 {
-		frame, l, err = parseStreamFrame(b, typ, v)
-	} else {
-		switch typ {
-		case pingFrameType:
-			frame = &PingFrame{}
-		case ackFrameType, ackECNFrameType:
-			ackDelayExponent := p.ackDelayExponent
-			if encLevel != protocol.Encryption1RTT {
-				ackDelayExponent = protocol.DefaultAckDelayExponent
+		f, l, err := parseStreamFrameWithPolicy(b, typ, v, &p.streamBuffers)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnStream(f))
+	}
+	switch typ {
+	case pingFrameType:
+		return 0, handled(h.OnPing(&PingFrame{}))
+	case ackFrameType, ackECNFrameType:
+		ackDelayExponent := p.ackDelayExponent
+		if encLevel != protocol.Encryption1RTT {
+			ackDelayExponent = protocol.DefaultAckDelayExponent
+		}
+		p.ackFrame.Reset()
+		l, err := parseAckFrame(p.ackFrame, b, typ, ackDelayExponent, v)
+		if err != nil {
+			return 0, err
+		}
+		// ACK delays are only meaningful for the Application Data packet number space (see section 13.2.5 of RFC 9000).
+		if encLevel == protocol.Encryption1RTT && p.maxAckDelay > 0 && p.ackFrame.DelayTime > p.maxAckDelay {
+			p.ackFrame.DelayExceedsMaxAckDelay = true
+		}
+		if p.largestSent != nil {
+			if largestSent := p.largestSent(encLevel); p.ackFrame.LargestAcked() > largestSent {
+				return l, &qerr.TransportError{
+					ErrorCode:    qerr.ProtocolViolation,
+					ErrorMessage: "received ACK for an unsent packet",
+				}
 			}
-			p.ackFrame.Reset()
-			l, err = parseAckFrame(p.ackFrame, b, typ, ackDelayExponent, v)
-			frame = p.ackFrame
-		case resetStreamFrameType:
-			frame, l, err = parseResetStreamFrame(b, false, v)
-		case stopSendingFrameType:
-			frame, l, err = parseStopSendingFrame(b, v)
-		case cryptoFrameType:
-			frame, l, err = parseCryptoFrame(b, v)
-		case newTokenFrameType:
-			frame, l, err = parseNewTokenFrame(b, v)
-		case maxDataFrameType:
-			frame, l, err = parseMaxDataFrame(b, v)
-		case maxStreamDataFrameType:
-			frame, l, err = parseMaxStreamDataFrame(b, v)
-		case bidiMaxStreamsFrameType, uniMaxStreamsFrameType:
-			frame, l, err = parseMaxStreamsFrame(b, typ, v)
-		case dataBlockedFrameType:
-			frame, l, err = parseDataBlockedFrame(b, v)
-		case streamDataBlockedFrameType:
-			frame, l, err = parseStreamDataBlockedFrame(b, v)
-		case bidiStreamBlockedFrameType, uniStreamBlockedFrameType:
-			frame, l, err = parseStreamsBlockedFrame(b, typ, v)
-		case newConnectionIDFrameType:
-			frame, l, err = parseNewConnectionIDFrame(b, v)
-		case retireConnectionIDFrameType:
-			frame, l, err = parseRetireConnectionIDFrame(b, v)
-		case pathChallengeFrameType:
-			frame, l, err = parsePathChallengeFrame(b, v)
-		case pathResponseFrameType:
-			frame, l, err = parsePathResponseFrame(b, v)
-		case connectionCloseFrameType, applicationCloseFrameType:
-			frame, l, err = parseConnectionCloseFrame(b, typ, v)
-		case handshakeDoneFrameType:
-			frame = &HandshakeDoneFrame{}
-		case 0x30, 0x31:
-			if !p.supportsDatagrams {
-				return nil, 0, errUnknownFrameType
+		}
+		if p.pnSpaces != nil {
+			if err := p.pnSpaces.ReceivedAck(PacketNumberSpaceFromEncryptionLevel(encLevel), p.ackFrame); err != nil {
+				return l, err
 			}
-			frame, l, err = parseDatagramFrame(b, typ, v)
-		case resetStreamAtFrameType:
-			if !p.supportsResetStreamAt {
-				return nil, 0, errUnknownFrameType
+		}
+		return l, handled(h.OnAck(p.ackFrame))
+	case resetStreamFrameType:
+		f, l, err := parseResetStreamFrame(b, false, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnResetStream(f))
+	case stopSendingFrameType:
+		f, l, err := parseStopSendingFrame(b, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnStopSending(f))
+	case cryptoFrameType:
+		f, l, err := parseCryptoFrame(b, v)
+		if err != nil {
+			return 0, err
+		}
+		if maxOffset, ok := p.maxCryptoOffsets[encLevel]; ok && f.Offset+protocol.ByteCount(len(f.Data)) > maxOffset {
+			return l, &qerr.TransportError{
+				ErrorCode:    qerr.CryptoBufferExceeded,
+				ErrorMessage: fmt.Sprintf("received CRYPTO data beyond the limit of %d bytes at encryption level %s", maxOffset, encLevel),
 			}
-			frame, l, err = parseResetStreamFrame(b, true, v)
-		default:
+		}
+		return l, handled(h.OnCrypto(f))
+	case newTokenFrameType:
+		f, l, err := parseNewTokenFrame(b, v)
+		if err != nil {
+			return 0, err
+		}
+		if err := p.newTokenBudget.consume(f); err != nil {
+			return l, err
+		}
+		return l, handled(h.OnNewToken(f))
+	case maxDataFrameType:
+		f, l, err := parseMaxDataFrame(b, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnMaxData(f))
+	case maxStreamDataFrameType:
+		f, l, err := parseMaxStreamDataFrame(b, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnMaxStreamData(f))
+	case bidiMaxStreamsFrameType, uniMaxStreamsFrameType:
+		f, l, err := parseMaxStreamsFrame(b, typ, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnMaxStreams(f))
+	case dataBlockedFrameType:
+		f, l, err := parseDataBlockedFrame(b, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnDataBlocked(f))
+	case streamDataBlockedFrameType:
+		f, l, err := parseStreamDataBlockedFrame(b, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnStreamDataBlocked(f))
+	case bidiStreamBlockedFrameType, uniStreamBlockedFrameType:
+		f, l, err := parseStreamsBlockedFrame(b, typ, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnStreamsBlocked(f))
+	case newConnectionIDFrameType:
+		f, l, err := parseNewConnectionIDFrame(b, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnNewConnectionID(f))
+	case retireConnectionIDFrameType:
+		f, l, err := parseRetireConnectionIDFrame(b, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnRetireConnectionID(f))
+	case pathChallengeFrameType:
+		f, l, err := parsePathChallengeFrame(b, v)
+		if err != nil {
+			return 0, err
+		}
+		if err := p.countPathFrame(); err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnPathChallenge(f))
+	case pathResponseFrameType:
+		f, l, err := parsePathResponseFrame(b, v)
+		if err != nil {
+			return 0, err
+		}
+		if err := p.countPathFrame(); err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnPathResponse(f))
+	case connectionCloseFrameType, applicationCloseFrameType:
+		f, l, err := parseConnectionCloseFrame(b, typ, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnConnectionClose(f))
+	case handshakeDoneFrameType:
+		// HANDSHAKE_DONE frames are only sent by servers (see section 19.20 of RFC 9000).
+		if !p.lenient && p.perspective == protocol.PerspectiveServer {
+			return 0, &qerr.TransportError{
+				ErrorCode:    qerr.ProtocolViolation,
+				ErrorMessage: "received a HANDSHAKE_DONE frame",
+			}
+		}
+		return 0, handled(h.OnHandshakeDone(&HandshakeDoneFrame{}))
+	case 0x30, 0x31:
+		if !p.supportsDatagrams {
+			return 0, errUnknownFrameType
+		}
+		f, l, err := parseDatagramFrame(b, typ, v)
+		if err != nil {
+			return 0, err
+		}
+		if p.datagramCodec != nil {
+			if err := p.decodeDatagram(f); err != nil {
+				return 0, err
+			}
+		}
+		return l, handled(h.OnDatagram(f))
+	case resetStreamAtFrameType:
+		if !p.supportsResetStreamAt {
+			return 0, errUnknownFrameType
+		}
+		f, l, err := parseResetStreamFrame(b, true, v)
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnResetStream(f))
+	default:
+		var f Frame
+		var l int
+		var err error
+		if parse, ok := p.registeredFrames[typ]; ok {
+			f, l, err = parseRegisteredFrame(parse, b, v)
+		} else if p.unknownFrameLength != nil {
+			f, l, err = p.parseUnknownFrame(b, typ)
+		} else {
 			err = errUnknownFrameType
 		}
+		if err != nil {
+			return 0, err
+		}
+		return l, handled(h.OnOther(f))
 	}
-	if err != nil {
-		return nil, 0, err
-	}
-	if !p.isAllowedAtEncLevel(frame, encLevel) {
-		return nil, l, fmt.Errorf("%s not allowed at encryption level %s", reflect.TypeOf(frame).Elem().Name(), encLevel)
+}
+
+func (p *FrameParser) countPathFrame() error {
+	p.pathFrames++
+	if p.maxPathFramesPerPayload > 0 && p.pathFrames > p.maxPathFramesPerPayload {
+		return &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			ErrorMessage: fmt.Sprintf("received more than %d PATH_CHALLENGE / PATH_RESPONSE frames in a packet", p.maxPathFramesPerPayload),
+		}
 	}
-	return frame, l, nil
+	return nil
 }
 
-func (p *FrameParser) isAllowedAtEncLevel(f Frame, encLevel protocol.EncryptionLevel) bool {
+func isAllowedAtEncLevel(typ uint64, encLevel protocol.EncryptionLevel) bool {
 	switch encLevel {
 	case protocol.EncryptionInitial, protocol.EncryptionHandshake:
-		switch f.(type) {
-		case *CryptoFrame, *AckFrame, *ConnectionCloseFrame, *PingFrame:
+		switch typ {
+		case cryptoFrameType, ackFrameType, ackECNFrameType, connectionCloseFrameType, applicationCloseFrameType, pingFrameType:
 			return true
 		default:
 			return false
 		}
 	case protocol.Encryption0RTT:
-		switch f.(type) {
-		case *CryptoFrame, *AckFrame, *ConnectionCloseFrame, *NewTokenFrame, *PathResponseFrame, *RetireConnectionIDFrame:
+		switch typ {
+		case cryptoFrameType, ackFrameType, ackECNFrameType, connectionCloseFrameType, applicationCloseFrameType,
+			newTokenFrameType, pathResponseFrameType, retireConnectionIDFrameType:
 			return false
 		default:
 			return true
@@ -198,9 +473,93 @@ func (p *FrameParser) SetAckDelayExponent(exp uint8) {
 	p.ackDelayExponent = exp
 }
 
-func replaceUnexpectedEOF(e error) error {
-	if e == io.ErrUnexpectedEOF {
-		return io.EOF
+// SetPerspective sets the perspective of the endpoint receiving the frames.
+// This is used to reject frames that must only be sent in one direction.
+func (p *FrameParser) SetPerspective(pers protocol.Perspective) {
+	p.perspective = pers
+}
+
+// SetSupportsDatagrams sets if DATAGRAM frames are accepted (see RFC 9221).
+// This allows creating the parser before the handshake completes.
+func (p *FrameParser) SetSupportsDatagrams(supported bool) {
+	p.supportsDatagrams = supported
+}
+
+// SetSupportsResetStreamAt sets if RESET_STREAM_AT frames are accepted.
+// This allows creating the parser before the handshake completes.
+func (p *FrameParser) SetSupportsResetStreamAt(supported bool) {
+	p.supportsResetStreamAt = supported
+}
+
+// SetOnFrameParsed sets a callback that is called for every successfully parsed frame (PADDING frames excluded),
+// with the frame type and the length of the frame, including the frame type.
+// This allows collecting metrics without wrapping every call site of the parser.
+// If nil, no callback is called.
+func (p *FrameParser) SetOnFrameParsed(f func(typ FrameType, length int)) {
+	p.onFrameParsed = f
+}
+
+// SetMaxPaddingScan limits the number of PADDING bytes skipped by a single call to ParseNext.
+// This allows the caller to regain control when processing large payloads consisting mostly of PADDING,
+// e.g. when processing GSO super-packets.
+// If 0, there's no limit.
+func (p *FrameParser) SetMaxPaddingScan(n int) {
+	p.maxPaddingScan = n
+}
+
+// SetMaxAckDelay sets the peer's max_ack_delay (sent in the transport parameters).
+// ACK frames received at the 1-RTT encryption level with an ACK Delay larger than this value
+// are flagged, such that the RTT estimator can ignore the implausible delay (see section 5.3 of RFC 9002).
+func (p *FrameParser) SetMaxAckDelay(d time.Duration) {
+	p.maxAckDelay = d
+}
+
+// SetLargestSentPacketNumberFunc sets a callback that returns the largest packet number sent
+// in the packet number space of the given encryption level (protocol.InvalidPacketNumber if no packet was sent yet).
+// ACK frames acknowledging a packet number larger than that are rejected with a PROTOCOL_VIOLATION
+// (see section 13.1 of RFC 9000).
+func (p *FrameParser) SetLargestSentPacketNumberFunc(f func(protocol.EncryptionLevel) protocol.PacketNumber) {
+	p.largestSent = f
+}
+
+// SetPacketNumberSpaces sets the PacketNumberSpaces used to validate ACK frames.
+// ACK frames are checked against the packet number space of the encryption level they're received at,
+// and the largest acknowledged packet number of that packet number space is updated.
+func (p *FrameParser) SetPacketNumberSpaces(s *PacketNumberSpaces) {
+	p.pnSpaces = s
+}
+
+// SetMaxCryptoOffset limits the offset up to which CRYPTO data is accepted at the given encryption level.
+// CRYPTO frames that carry data beyond this offset are rejected with a CRYPTO_BUFFER_EXCEEDED error.
+// Since the amount of handshake data is bounded in practice, this allows servers to reject
+// excessive handshake data early.
+func (p *FrameParser) SetMaxCryptoOffset(encLevel protocol.EncryptionLevel, maxOffset protocol.ByteCount) {
+	if p.maxCryptoOffsets == nil {
+		p.maxCryptoOffsets = make(map[protocol.EncryptionLevel]protocol.ByteCount)
 	}
-	return e
+	p.maxCryptoOffsets[encLevel] = maxOffset
+}
+
+// SetNewTokenLimits limits the number of NEW_TOKEN frames (maxFrames) and the total number of token bytes (maxBytes)
+// accepted by this parser.
+// Once a limit is exceeded, parsing a NEW_TOKEN frame fails with a PROTOCOL_VIOLATION.
+// If a limit is 0, the respective quantity is not limited.
+func (p *FrameParser) SetNewTokenLimits(maxFrames, maxBytes int) {
+	p.newTokenBudget.maxFrames = maxFrames
+	p.newTokenBudget.maxBytes = maxBytes
+}
+
+// StartPayload must be called before parsing the frames of a new packet payload.
+// It resets the per-payload frame counters.
+func (p *FrameParser) StartPayload() {
+	p.pathFrames = 0
+}
+
+// SetMaxPathFramesPerPayload limits the number of PATH_CHALLENGE and PATH_RESPONSE frames in a single packet payload.
+// Exceeding this limit is treated as a PROTOCOL_VIOLATION.
+// Since only a single PATH_RESPONSE is sent in response to the PATH_CHALLENGE frames in a packet,
+// there's no reason for a peer to send a large number of these frames.
+// If 0, the number of frames is not limited.
+func (p *FrameParser) SetMaxPathFramesPerPayload(n int) {
+	p.maxPathFramesPerPayload = n
 }
@@ -0,0 +1,94 @@
+package wire
+
+import (
+	"flag"
+	"runtime"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// maxParseAllocOverhead is the number of bytes a single ParseNext call may allocate
+// on top of the number of bytes it consumed.
+// This covers the frame struct itself, as well as a (re)allocation of a pooled STREAM frame buffer.
+var maxParseAllocOverhead = flag.Int(
+	"wire.max-parse-alloc-overhead",
+	int(protocol.MaxPacketBufferSize)+1024,
+	"maximum number of bytes a single frame parse may allocate in addition to the bytes consumed",
+)
+
+func allocatedBytes() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.TotalAlloc
+}
+
+// FuzzFrameParserAllocations makes sure that the amount of memory allocated when parsing a frame
+// is bounded by the size of that frame, i.e. that a peer can't make us allocate large buffers
+// by sending a frame that declares a huge length.
+func FuzzFrameParserAllocations(f *testing.F) {
+	for _, frame := range []Frame{
+		&PingFrame{},
+		&NewTokenFrame{Token: []byte("foobar")},
+		&CryptoFrame{Offset: 1337, Data: []byte("lorem ipsum")},
+		&StreamFrame{StreamID: 4, Offset: 42, Data: []byte("foobar"), DataLenPresent: true},
+		&DatagramFrame{Data: []byte("foobar"), DataLenPresent: true},
+		&ConnectionCloseFrame{ErrorCode: 1, ReasonPhrase: "foobar"},
+		&AckFrame{AckRanges: []AckRange{{Smallest: 15, Largest: 20}, {Smallest: 1, Largest: 10}}},
+	} {
+		b, err := frame.Append(nil, protocol.Version1)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(uint8(protocol.Encryption1RTT), b)
+	}
+	// frames that declare a length much larger than the data that follows
+	for _, typ := range []uint64{newTokenFrameType, cryptoFrameType, 0x31, connectionCloseFrameType} {
+		b := quicvarint.Append(nil, typ)
+		switch typ {
+		case cryptoFrameType:
+			b = quicvarint.Append(b, 0) // offset
+		case connectionCloseFrameType:
+			b = quicvarint.Append(b, 0) // error code
+			b = quicvarint.Append(b, 0) // frame type
+		}
+		b = quicvarint.Append(b, quicvarint.Max)
+		f.Add(uint8(protocol.Encryption1RTT), append(b, "foobar"...))
+	}
+
+	f.Fuzz(func(t *testing.T, level uint8, data []byte) {
+		var encLevel protocol.EncryptionLevel
+		switch level % 4 {
+		case 0:
+			encLevel = protocol.EncryptionInitial
+		case 1:
+			encLevel = protocol.EncryptionHandshake
+		case 2:
+			encLevel = protocol.Encryption0RTT
+		case 3:
+			encLevel = protocol.Encryption1RTT
+		}
+		parser := NewFrameParser(true, true)
+		for len(data) > 0 {
+			before := allocatedBytes()
+			l, frame, err := parser.ParseNext(data, encLevel, protocol.Version1)
+			allocated := allocatedBytes() - before
+			consumed := l
+			if err != nil {
+				// a failing parse might have read all the remaining data
+				consumed = len(data)
+			}
+			if limit := uint64(consumed + *maxParseAllocOverhead); allocated > limit {
+				t.Fatalf("parsing %d bytes allocated %d bytes (limit: %d): %#v (error: %v)", consumed, allocated, limit, frame, err)
+			}
+			if err != nil {
+				return
+			}
+			if sf, ok := frame.(*StreamFrame); ok {
+				sf.PutBack()
+			}
+			data = data[l:]
+		}
+	})
+}
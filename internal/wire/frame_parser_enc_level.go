@@ -0,0 +1,56 @@
+package wire
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// An EncLevelFrameParser is a FrameParser bound to a single encryption level.
+// Since the encryption level can't be passed on every call, it's not possible
+// to accidentally parse a packet using the rules of a different encryption level.
+type EncLevelFrameParser struct {
+	parser   *FrameParser
+	encLevel protocol.EncryptionLevel
+}
+
+// WithEncLevel binds the FrameParser to an encryption level.
+// The returned parser shares its state with the FrameParser.
+func (p *FrameParser) WithEncLevel(encLevel protocol.EncryptionLevel) EncLevelFrameParser {
+	switch encLevel {
+	case protocol.EncryptionInitial, protocol.EncryptionHandshake, protocol.Encryption0RTT, protocol.Encryption1RTT:
+	default:
+		panic(fmt.Sprintf("unknown encryption level: %d", encLevel))
+	}
+	return EncLevelFrameParser{parser: p, encLevel: encLevel}
+}
+
+// EncryptionLevel returns the encryption level.
+func (p EncLevelFrameParser) EncryptionLevel() protocol.EncryptionLevel {
+	return p.encLevel
+}
+
+// StartPayload must be called before parsing the frames of a new packet payload.
+// See FrameParser.StartPayload for details.
+func (p EncLevelFrameParser) StartPayload() {
+	p.parser.StartPayload()
+}
+
+// ParseNext parses the next frame.
+// See FrameParser.ParseNext for details.
+func (p EncLevelFrameParser) ParseNext(data []byte, v protocol.Version) (int, Frame, error) {
+	return p.parser.ParseNext(data, p.encLevel, v)
+}
+
+// ParseDiagnostic parses the whole payload.
+// See FrameParser.ParseDiagnostic for details.
+func (p EncLevelFrameParser) ParseDiagnostic(payload []byte, v protocol.Version) *ParseDiagnostic {
+	return p.parser.ParseDiagnostic(payload, p.encLevel, v)
+}
+
+// ParseDiagnosticContext parses the whole payload, respecting the context and the budget.
+// See FrameParser.ParseDiagnosticContext for details.
+func (p EncLevelFrameParser) ParseDiagnosticContext(ctx context.Context, payload []byte, v protocol.Version, budget ParseBudget) *ParseDiagnostic {
+	return p.parser.ParseDiagnosticContext(ctx, payload, p.encLevel, v, budget)
+}
@@ -0,0 +1,44 @@
+package wire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncLevelFrameParser(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	b, err := (&MaxDataFrame{MaximumData: 1337}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+
+	initial := parser.WithEncLevel(protocol.EncryptionInitial)
+	require.Equal(t, protocol.EncryptionInitial, initial.EncryptionLevel())
+	_, _, err = initial.ParseNext(b, protocol.Version1)
+	require.Error(t, err)
+	require.Error(t, initial.ParseDiagnostic(b, protocol.Version1).Err)
+
+	oneRTT := parser.WithEncLevel(protocol.Encryption1RTT)
+	l, f, err := oneRTT.ParseNext(b, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &MaxDataFrame{MaximumData: 1337}, f)
+	require.Equal(t, len(b), l)
+	require.Equal(t, []Frame{f}, oneRTT.ParseDiagnostic(b, protocol.Version1).Frames)
+}
+
+func TestEncLevelFrameParserSharesState(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	oneRTT := parser.WithEncLevel(protocol.Encryption1RTT)
+	parser.SetAckDelayExponent(protocol.AckDelayExponent + 1)
+	b, err := (&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 1}}, DelayTime: 80 * time.Microsecond}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	_, f, err := oneRTT.ParseNext(b, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, 160*time.Microsecond, f.(*AckFrame).DelayTime)
+}
+
+func TestEncLevelFrameParserInvalidEncLevel(t *testing.T) {
+	require.Panics(t, func() { NewFrameParser(true, true).WithEncLevel(42) })
+}
@@ -0,0 +1,58 @@
+//go:build quicdebug
+
+package wire
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrInjectedFault is the error returned for frames failed by a FaultInjector, unless a different error is configured.
+var ErrInjectedFault = errors.New("injected fault")
+
+// A FaultInjector makes the FrameParser fail deterministically.
+// It must only be used in tests, to check that errors reported by the FrameParser
+// are correctly propagated by the code using it.
+// It is only available when building with the quicdebug build tag,
+// such that release builds don't pay for checking for it when parsing frames.
+type FaultInjector struct {
+	// EveryNth fails every Nth frame (not counting PADDING frames). If 0, no frames are failed based on their position.
+	EveryNth int
+	// FrameTypes fails all frames of these types.
+	FrameTypes []FrameType
+	// Err is the error returned.
+	// If nil, ErrInjectedFault is returned, wrapped in a FRAME_ENCODING_ERROR.
+	// Like for any other error, the FrameType of a *qerr.TransportError is set to the type of the failed frame.
+	Err error
+
+	frames int
+}
+
+func (f *FaultInjector) inject(typ uint64) error {
+	f.frames++
+	if (f.EveryNth > 0 && f.frames%f.EveryNth == 0) || slices.Contains(f.FrameTypes, FrameType(typ)) {
+		if f.Err != nil {
+			return f.Err
+		}
+		return ErrInjectedFault
+	}
+	return nil
+}
+
+type faultInjection struct {
+	faultInjector *FaultInjector
+}
+
+func (f *faultInjection) injectFault(typ uint64) error {
+	if f.faultInjector == nil {
+		return nil
+	}
+	return f.faultInjector.inject(typ)
+}
+
+// SetFaultInjector sets a FaultInjector, which is consulted before parsing every frame.
+// It must only be used in tests. If nil, no faults are injected.
+// The FaultInjector is not copied by Clone.
+func (p *FrameParser) SetFaultInjector(f *FaultInjector) {
+	p.faultInjector = f
+}
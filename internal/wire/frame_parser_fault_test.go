@@ -0,0 +1,55 @@
+//go:build quicdebug
+
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameParserFaultInjectionEveryNth(t *testing.T) {
+	p := NewFrameParser(false, false)
+	p.SetFaultInjector(&FaultInjector{EveryNth: 3})
+	b := append([]byte{0, 0}, appendFrames(t, &PingFrame{})...) // PADDING frames are not counted
+
+	for i := 1; i <= 6; i++ {
+		_, _, err := p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+		if i%3 == 0 {
+			require.Equal(t, &FrameParsingError{
+				Err: &qerr.TransportError{
+					FrameType:    pingFrameType,
+					ErrorCode:    qerr.FrameEncodingError,
+					ErrorMessage: ErrInjectedFault.Error(),
+				},
+				Offset: 2,
+			}, err)
+		} else {
+			require.NoError(t, err)
+		}
+	}
+
+	p.SetFaultInjector(nil)
+	for range 3 {
+		_, _, err := p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+		require.NoError(t, err)
+	}
+}
+
+func TestFrameParserFaultInjectionFrameTypes(t *testing.T) {
+	p := NewFrameParser(false, false)
+	p.SetFaultInjector(&FaultInjector{
+		FrameTypes: []FrameType{maxDataFrameType},
+		Err:        &qerr.TransportError{ErrorCode: qerr.FlowControlError},
+	})
+
+	_, _, err := p.ParseNext(appendFrames(t, &PingFrame{}), protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	_, _, err = p.ParseNext(appendFrames(t, &MaxDataFrame{MaximumData: 1000}), protocol.Encryption1RTT, protocol.Version1)
+	require.Equal(t, &FrameParsingError{Err: &qerr.TransportError{FrameType: maxDataFrameType, ErrorCode: qerr.FlowControlError}}, err)
+
+	require.Nil(t, p.Clone().faultInjector)
+}
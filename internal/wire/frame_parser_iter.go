@@ -0,0 +1,38 @@
+package wire
+
+import (
+	"iter"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// ParseAll returns an iterator over all frames in a packet payload.
+// It calls StartPayload, and skips PADDING frames.
+// If parsing fails, the error is yielded (with a nil frame), and iteration stops.
+// The Offset of a *FrameParsingError is the offset in the payload.
+// As with ParseNext, the ACK frame is reused: it is only valid until the next iteration.
+// STREAM frames can be returned to the pool using PutBack, once the caller is done with them.
+func (p *FrameParser) ParseAll(data []byte, encLevel protocol.EncryptionLevel, v protocol.Version) iter.Seq2[Frame, error] {
+	return func(yield func(Frame, error) bool) {
+		p.StartPayload()
+		var offset int
+		for offset < len(data) {
+			frame, l, err := p.parseNext(data[offset:], encLevel, v)
+			if err != nil {
+				if parseErr, ok := err.(*FrameParsingError); ok {
+					parseErr.Offset += offset
+				}
+				yield(nil, err)
+				return
+			}
+			offset += l
+			// parseNext might return before the end of the data when skipping PADDING
+			if frame == nil {
+				continue
+			}
+			if !yield(frame, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameParserParseAll(t *testing.T) {
+	b := appendFrames(t,
+		&PingFrame{},
+		&MaxDataFrame{MaximumData: 1337},
+	)
+	b = append(b, make([]byte, 10)...) // PADDING
+	b = append(b, appendFrames(t, &StreamFrame{StreamID: 4, Data: []byte("foobar"), DataLenPresent: true})...)
+	b = append(b, appendFrames(t, &StreamFrame{StreamID: 8, Data: []byte("foobar")})...)
+
+	parser := NewFrameParser(true, true)
+	parser.SetMaxPaddingScan(4)
+	var frames []Frame
+	for f, err := range parser.ParseAll(b, protocol.Encryption1RTT, protocol.Version1) {
+		require.NoError(t, err)
+		frames = append(frames, f)
+	}
+	require.Len(t, frames, 4)
+	require.Equal(t, &PingFrame{}, frames[0])
+	require.Equal(t, &MaxDataFrame{MaximumData: 1337}, frames[1])
+	require.Equal(t, protocol.StreamID(4), frames[2].(*StreamFrame).StreamID)
+	require.Equal(t, protocol.StreamID(8), frames[3].(*StreamFrame).StreamID)
+}
+
+func TestFrameParserParseAllBreak(t *testing.T) {
+	b := appendFrames(t, &PingFrame{}, &PingFrame{}, &PingFrame{})
+	parser := NewFrameParser(true, true)
+	var n int
+	for range parser.ParseAll(b, protocol.Encryption1RTT, protocol.Version1) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	require.Equal(t, 2, n)
+}
+
+func TestFrameParserParseAllError(t *testing.T) {
+	b := appendFrames(t, &PingFrame{}, &PingFrame{})
+	b = append(b, maxDataFrameType)
+	parser := NewFrameParser(true, true)
+	var frames []Frame
+	var errs []error
+	for f, err := range parser.ParseAll(b, protocol.Encryption1RTT, protocol.Version1) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		frames = append(frames, f)
+	}
+	require.Equal(t, []Frame{&PingFrame{}, &PingFrame{}}, frames)
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], &qerr.TransportError{FrameType: maxDataFrameType, ErrorCode: qerr.FrameEncodingError})
+	var parseErr *FrameParsingError
+	require.ErrorAs(t, errs[0], &parseErr)
+	require.Equal(t, 2, parseErr.Offset)
+}
+
+func TestFrameParserParseAllIterateTwice(t *testing.T) {
+	b := appendFrames(t, &PingFrame{}, &MaxDataFrame{MaximumData: 1337})
+	parser := NewFrameParser(true, true)
+	frames := parser.ParseAll(b, protocol.Encryption1RTT, protocol.Version1)
+	for range 2 {
+		var n int
+		for _, err := range frames {
+			require.NoError(t, err)
+			n++
+		}
+		require.Equal(t, 2, n)
+	}
+}
+
+func TestFrameParserParseAllStartsPayload(t *testing.T) {
+	b := appendFrames(t, &PathChallengeFrame{}, &PathChallengeFrame{})
+	parser := NewFrameParser(true, true)
+	parser.SetMaxPathFramesPerPayload(2)
+	for range 3 {
+		for _, err := range parser.ParseAll(b, protocol.Encryption1RTT, protocol.Version1) {
+			require.NoError(t, err)
+		}
+	}
+}
+
+func BenchmarkFrameParserParseAll(b *testing.B) {
+	payload := appendFrames(b,
+		&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}},
+		&StreamFrame{StreamID: 4, Data: make([]byte, 100), DataLenPresent: true},
+	)
+	parser := NewFrameParser(true, true)
+	b.ReportAllocs()
+	for range b.N {
+		for f, err := range parser.ParseAll(payload, protocol.Encryption1RTT, protocol.Version1) {
+			if err != nil {
+				b.Fatal(err)
+			}
+			if sf, ok := f.(*StreamFrame); ok {
+				sf.PutBack()
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package wire
+
+import "github.com/quic-go/quic-go/internal/protocol"
+
+// A ParseFunc parses a single frame of type typ.
+// b starts right after the frame type.
+// It returns the frame and the number of bytes consumed, not including the frame type.
+type ParseFunc func(b []byte, typ uint64, encLevel protocol.EncryptionLevel, v protocol.Version) (Frame, int, error)
+
+// Use adds a middleware to the FrameParser.
+// Middlewares wrap the parsing of every frame (PADDING frames excluded),
+// and can be used to layer additional validations, collect statistics or trace frames.
+// They are applied in the order they are added, i.e. the middleware added first is called first.
+// Errors returned by a middleware are handled like any other parsing error:
+// unless it already is a *qerr.TransportError, it is converted into a FRAME_ENCODING_ERROR.
+// Middlewares are copied by Clone. Any state held by a middleware is therefore shared with the clone.
+func (p *FrameParser) Use(middleware func(next ParseFunc) ParseFunc) {
+	p.middlewares = append(p.middlewares, middleware)
+	p.parse = p.parseFrame
+	for i := len(p.middlewares) - 1; i >= 0; i-- {
+		p.parse = p.middlewares[i](p.parse)
+	}
+}
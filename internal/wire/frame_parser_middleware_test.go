@@ -0,0 +1,86 @@
+package wire
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameParserMiddlewareOrder(t *testing.T) {
+	var calls []string
+	tracer := func(name string) func(ParseFunc) ParseFunc {
+		return func(next ParseFunc) ParseFunc {
+			return func(b []byte, typ uint64, encLevel protocol.EncryptionLevel, v protocol.Version) (Frame, int, error) {
+				calls = append(calls, name+" before "+FrameType(typ).String())
+				f, l, err := next(b, typ, encLevel, v)
+				calls = append(calls, name+" after "+FrameType(typ).String())
+				return f, l, err
+			}
+		}
+	}
+
+	p := NewFrameParser(true, true)
+	p.Use(tracer("first"))
+	p.Use(tracer("second"))
+	b := appendFrames(t, &PingFrame{}, &MaxDataFrame{MaximumData: 1337})
+	b = append(b, 0, 0, 0) // PADDING
+	var frames []Frame
+	for f, err := range p.ParseAll(b, protocol.Encryption1RTT, protocol.Version1) {
+		require.NoError(t, err)
+		frames = append(frames, f)
+	}
+	require.Equal(t, []Frame{&PingFrame{}, &MaxDataFrame{MaximumData: 1337}}, frames)
+	require.Equal(t,
+		[]string{
+			"first before PING", "second before PING", "second after PING", "first after PING",
+			"first before MAX_DATA", "second before MAX_DATA", "second after MAX_DATA", "first after MAX_DATA",
+		},
+		calls,
+	)
+}
+
+func TestFrameParserMiddlewareErrors(t *testing.T) {
+	errNoMaxData := errors.New("MAX_DATA frames not allowed")
+	p := NewFrameParser(true, true)
+	p.Use(func(next ParseFunc) ParseFunc {
+		return func(b []byte, typ uint64, encLevel protocol.EncryptionLevel, v protocol.Version) (Frame, int, error) {
+			if typ == maxDataFrameType {
+				return nil, 0, errNoMaxData
+			}
+			return next(b, typ, encLevel, v)
+		}
+	})
+	p.Use(func(next ParseFunc) ParseFunc {
+		return func(b []byte, typ uint64, encLevel protocol.EncryptionLevel, v protocol.Version) (Frame, int, error) {
+			f, l, err := next(b, typ, encLevel, v)
+			if err == nil && typ == pingFrameType && encLevel == protocol.Encryption0RTT {
+				return nil, l, &qerr.TransportError{ErrorCode: qerr.ProtocolViolation, ErrorMessage: "no PINGs in 0-RTT"}
+			}
+			return f, l, err
+		}
+	})
+
+	_, f, err := p.ParseNext([]byte{pingFrameType}, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &PingFrame{}, f)
+
+	b := appendFrames(t, &MaxDataFrame{MaximumData: 1337})
+	_, _, err = p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{
+		FrameType:    maxDataFrameType,
+		ErrorCode:    qerr.FrameEncodingError,
+		ErrorMessage: errNoMaxData.Error(),
+	})
+
+	// the middlewares are copied by Clone
+	_, _, err = p.Clone().ParseNext([]byte{pingFrameType}, protocol.Encryption0RTT, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{
+		FrameType:    pingFrameType,
+		ErrorCode:    qerr.ProtocolViolation,
+		ErrorMessage: "no PINGs in 0-RTT",
+	})
+}
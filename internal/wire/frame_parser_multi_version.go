@@ -0,0 +1,51 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// A MultiVersionFrameParser holds a FrameParser for every QUIC version,
+// and dispatches every call to the parser of the version passed.
+// This is useful during compatible version negotiation (RFC 9368),
+// where extensions might be negotiated differently for different versions.
+type MultiVersionFrameParser struct {
+	parsers map[protocol.Version]*FrameParser
+}
+
+// NewMultiVersionFrameParser creates a new MultiVersionFrameParser.
+// Parsers need to be added for every supported version using SetParser.
+func NewMultiVersionFrameParser() *MultiVersionFrameParser {
+	return &MultiVersionFrameParser{parsers: make(map[protocol.Version]*FrameParser)}
+}
+
+// SetParser sets the parser used for a version, replacing the parser previously set (if any).
+func (m *MultiVersionFrameParser) SetParser(v protocol.Version, p *FrameParser) {
+	m.parsers[v] = p
+}
+
+// Parser returns the parser used for a version.
+// It can be used to configure the parser after the version-specific transport parameters were received.
+func (m *MultiVersionFrameParser) Parser(v protocol.Version) (*FrameParser, bool) {
+	p, ok := m.parsers[v]
+	return p, ok
+}
+
+// StartPayload must be called before parsing the frames of a new packet payload.
+// It resets the per-payload frame counters of all parsers.
+func (m *MultiVersionFrameParser) StartPayload() {
+	for _, p := range m.parsers {
+		p.StartPayload()
+	}
+}
+
+// ParseNext parses the next frame using the parser of the version.
+// It returns an error if no parser was set for the version.
+func (m *MultiVersionFrameParser) ParseNext(data []byte, encLevel protocol.EncryptionLevel, v protocol.Version) (int, Frame, error) {
+	p, ok := m.parsers[v]
+	if !ok {
+		return 0, nil, fmt.Errorf("no frame parser for version %s", v)
+	}
+	return p.ParseNext(data, encLevel, v)
+}
@@ -0,0 +1,36 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiVersionFrameParser(t *testing.T) {
+	m := NewMultiVersionFrameParser()
+	// DATAGRAM support was only negotiated for QUIC v2
+	v1Parser := NewFrameParser(false, false)
+	m.SetParser(protocol.Version1, v1Parser)
+	m.SetParser(protocol.Version2, NewFrameParser(true, false))
+
+	datagram, err := (&DatagramFrame{Data: []byte("foobar")}).Append(nil, protocol.Version2)
+	require.NoError(t, err)
+	m.StartPayload()
+	l, f, err := m.ParseNext(datagram, protocol.Encryption1RTT, protocol.Version2)
+	require.NoError(t, err)
+	require.Equal(t, len(datagram), l)
+	require.Equal(t, &DatagramFrame{Data: []byte("foobar")}, f)
+
+	_, _, err = m.ParseNext(datagram, protocol.Encryption1RTT, protocol.Version1)
+	require.Error(t, err)
+
+	p, ok := m.Parser(protocol.Version1)
+	require.True(t, ok)
+	require.Same(t, v1Parser, p)
+	_, ok = m.Parser(protocol.VersionUnknown)
+	require.False(t, ok)
+	_, _, err = m.ParseNext(datagram, protocol.Encryption1RTT, 0x1337)
+	require.EqualError(t, err, "no frame parser for version 0x1337")
+}
@@ -0,0 +1,8 @@
+//go:build !quicdebug
+
+package wire
+
+// Fault injection is only available when building with the quicdebug build tag.
+type faultInjection struct{}
+
+func (faultInjection) injectFault(uint64) error { return nil }
@@ -0,0 +1,67 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// A StrictnessProfile bundles the parser's policy options into a named configuration.
+type StrictnessProfile uint8
+
+const (
+	// ProfileRFCStrict enforces all checks required by RFC 9000,
+	// and additionally applies tight resource limits to frames that a well-behaved peer only sends in small numbers.
+	ProfileRFCStrict StrictnessProfile = 1 + iota
+	// ProfileInterop enforces all checks required by RFC 9000,
+	// but doesn't apply any resource limits beyond that.
+	// It is the most tolerant profile that still protects the connection state.
+	ProfileInterop
+	// ProfileAnalyzer parses every syntactically valid frame,
+	// ignoring which encryption level it was received at and which endpoint sent it.
+	// It is meant for offline analysis of packet captures, and must not be used for live connections.
+	ProfileAnalyzer
+)
+
+const (
+	strictMaxNewTokenFrames       = 16
+	strictMaxNewTokenBytes        = 16 * (1 << 10)
+	strictMaxPathFramesPerPayload = 8
+)
+
+func (p StrictnessProfile) String() string {
+	switch p {
+	case ProfileRFCStrict:
+		return "RFC strict"
+	case ProfileInterop:
+		return "interop"
+	case ProfileAnalyzer:
+		return "analyzer"
+	default:
+		return fmt.Sprintf("unknown strictness profile (%d)", uint8(p))
+	}
+}
+
+// SetStrictnessProfile configures the parser's policy options according to the profile.
+// It overwrites the values set by SetMaxCryptoOffset, SetNewTokenLimits and SetMaxPathFramesPerPayload.
+// Options depending on the connection state (e.g. the perspective, the peer's max_ack_delay) are not changed.
+func (p *FrameParser) SetStrictnessProfile(profile StrictnessProfile) {
+	p.lenient = false
+	p.maxCryptoOffsets = nil
+	p.SetNewTokenLimits(0, 0)
+	p.SetMaxPathFramesPerPayload(0)
+
+	switch profile {
+	case ProfileRFCStrict:
+		for _, encLevel := range []protocol.EncryptionLevel{protocol.EncryptionInitial, protocol.EncryptionHandshake, protocol.Encryption1RTT} {
+			p.SetMaxCryptoOffset(encLevel, protocol.MaxCryptoStreamOffset)
+		}
+		p.SetNewTokenLimits(strictMaxNewTokenFrames, strictMaxNewTokenBytes)
+		p.SetMaxPathFramesPerPayload(strictMaxPathFramesPerPayload)
+	case ProfileInterop:
+	case ProfileAnalyzer:
+		p.lenient = true
+	default:
+		panic(fmt.Sprintf("unknown strictness profile: %d", profile))
+	}
+}
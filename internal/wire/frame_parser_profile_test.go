@@ -0,0 +1,72 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameParserStrictnessProfiles(t *testing.T) {
+	pathChallenges := appendFrames(t,
+		&PathChallengeFrame{}, &PathChallengeFrame{}, &PathChallengeFrame{}, &PathChallengeFrame{},
+		&PathChallengeFrame{}, &PathChallengeFrame{}, &PathChallengeFrame{}, &PathChallengeFrame{},
+		&PathChallengeFrame{},
+	)
+	crypto := appendFrames(t, &CryptoFrame{Offset: protocol.MaxCryptoStreamOffset, Data: []byte("foo")})
+	handshakeDone := appendFrames(t, &HandshakeDoneFrame{})
+	stream := appendFrames(t, &StreamFrame{StreamID: 4, Data: []byte("foo")})
+
+	parseAll := func(p *FrameParser, b []byte, encLevel protocol.EncryptionLevel) error {
+		p.StartPayload()
+		for len(b) > 0 {
+			l, _, err := p.ParseNext(b, encLevel, protocol.Version1)
+			if err != nil {
+				return err
+			}
+			b = b[l:]
+		}
+		return nil
+	}
+
+	t.Run("RFC strict", func(t *testing.T) {
+		p := NewFrameParser(false, false)
+		p.SetPerspective(protocol.PerspectiveServer)
+		p.SetStrictnessProfile(ProfileRFCStrict)
+		require.ErrorIs(t, parseAll(p, pathChallenges, protocol.Encryption1RTT), &qerr.TransportError{
+			FrameType: pathChallengeFrameType,
+			ErrorCode: qerr.ProtocolViolation,
+		})
+		require.ErrorIs(t, parseAll(p, crypto, protocol.EncryptionHandshake), &qerr.TransportError{
+			FrameType: cryptoFrameType,
+			ErrorCode: qerr.CryptoBufferExceeded,
+		})
+		require.Error(t, parseAll(p, handshakeDone, protocol.Encryption1RTT))
+		require.Error(t, parseAll(p, stream, protocol.EncryptionInitial))
+	})
+
+	t.Run("interop", func(t *testing.T) {
+		p := NewFrameParser(false, false)
+		p.SetPerspective(protocol.PerspectiveServer)
+		p.SetStrictnessProfile(ProfileRFCStrict)
+		p.SetStrictnessProfile(ProfileInterop)
+		require.NoError(t, parseAll(p, pathChallenges, protocol.Encryption1RTT))
+		require.NoError(t, parseAll(p, crypto, protocol.EncryptionHandshake))
+		require.Error(t, parseAll(p, handshakeDone, protocol.Encryption1RTT))
+		require.Error(t, parseAll(p, stream, protocol.EncryptionInitial))
+	})
+
+	t.Run("analyzer", func(t *testing.T) {
+		p := NewFrameParser(false, false)
+		p.SetPerspective(protocol.PerspectiveServer)
+		p.SetStrictnessProfile(ProfileAnalyzer)
+		require.NoError(t, parseAll(p, pathChallenges, protocol.Encryption1RTT))
+		require.NoError(t, parseAll(p, crypto, protocol.EncryptionHandshake))
+		require.NoError(t, parseAll(p, handshakeDone, protocol.Encryption1RTT))
+		require.NoError(t, parseAll(p, stream, protocol.EncryptionInitial))
+	})
+
+	require.Panics(t, func() { NewFrameParser(false, false).SetStrictnessProfile(42) })
+}
@@ -0,0 +1,53 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// RegisterFrameType registers a parse function for a frame type not implemented by this package.
+// This allows parsing frames defined by experimental or private extensions.
+// The parse function is passed the frame, starting right after the frame type,
+// and returns the frame and the number of bytes consumed.
+// Registered frames are not allowed at the Initial and Handshake encryption level.
+// It panics if the frame type is implemented by this package.
+func (p *FrameParser) RegisterFrameType(typ uint64, parse func([]byte, protocol.Version) (Frame, int, error)) {
+	if _, class, _ := ClassifyFrameType(quicvarint.Append(nil, typ)); class != FrameTypeUnknown {
+		panic(fmt.Sprintf("can't register frame type %#x: already implemented", typ))
+	}
+	if p.registeredFrames == nil {
+		p.registeredFrames = make(map[uint64]func([]byte, protocol.Version) (Frame, int, error))
+	}
+	p.registeredFrames[typ] = parse
+}
+
+// ClassifyFrameType is like the package-level ClassifyFrameType,
+// but frame types registered using RegisterFrameType are classified as FrameTypeExtension.
+func (p *FrameParser) ClassifyFrameType(b []byte) (FrameType, FrameTypeClass, error) {
+	typ, class, err := ClassifyFrameType(b)
+	if class == FrameTypeUnknown {
+		if _, ok := p.registeredFrames[uint64(typ)]; ok {
+			class = FrameTypeExtension
+		}
+	}
+	return typ, class, err
+}
+
+// parseRegisteredFrame calls a parse function registered using RegisterFrameType,
+// and validates the result, such that a buggy parse function can't make the FrameParser read out of bounds.
+func parseRegisteredFrame(parse func([]byte, protocol.Version) (Frame, int, error), b []byte, v protocol.Version) (Frame, int, error) {
+	f, l, err := parse(b, v)
+	if err != nil {
+		return nil, 0, err
+	}
+	if l < 0 || l > len(b) {
+		return nil, 0, fmt.Errorf("invalid length for registered frame: %d (%d bytes remaining)", l, len(b))
+	}
+	if f == nil {
+		return nil, 0, errors.New("registered frame parser returned no frame")
+	}
+	return f, l, nil
+}
@@ -0,0 +1,108 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testExtensionFrameType = 0x4242
+
+type testExtensionFrame struct{ Value uint64 }
+
+func (f *testExtensionFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
+	b = quicvarint.Append(b, testExtensionFrameType)
+	return quicvarint.Append(b, f.Value), nil
+}
+
+func (f *testExtensionFrame) Length(protocol.Version) protocol.ByteCount {
+	return protocol.ByteCount(quicvarint.Len(testExtensionFrameType) + quicvarint.Len(f.Value))
+}
+
+func parseTestExtensionFrame(b []byte, _ protocol.Version) (Frame, int, error) {
+	val, l, err := quicvarint.Parse(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &testExtensionFrame{Value: val}, l, nil
+}
+
+func TestFrameParserRegisterFrameType(t *testing.T) {
+	b := appendFrames(t, &testExtensionFrame{Value: 1337}, &PingFrame{})
+
+	p := NewFrameParser(true, true)
+	_, _, err := p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	checkFrameUnsupported(t, err, testExtensionFrameType)
+	_, class, err := p.ClassifyFrameType(b)
+	require.NoError(t, err)
+	require.Equal(t, FrameTypeUnknown, class)
+
+	p.RegisterFrameType(testExtensionFrameType, parseTestExtensionFrame)
+	typ, class, err := p.ClassifyFrameType(b)
+	require.NoError(t, err)
+	require.Equal(t, FrameType(testExtensionFrameType), typ)
+	require.Equal(t, FrameTypeExtension, class)
+	_, class, err = p.ClassifyFrameType([]byte{pingFrameType})
+	require.NoError(t, err)
+	require.Equal(t, FrameTypeStandard, class)
+
+	for _, p := range []*FrameParser{p, p.Clone()} {
+		l, f, err := p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, &testExtensionFrame{Value: 1337}, f)
+		_, f, err = p.ParseNext(b[l:], protocol.Encryption1RTT, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, &PingFrame{}, f)
+	}
+
+	// registered frames are not allowed in Initial and Handshake packets
+	_, _, err = p.ParseNext(b, protocol.EncryptionHandshake, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: testExtensionFrameType, ErrorCode: qerr.FrameEncodingError})
+	// errors returned by the parse function are converted to FRAME_ENCODING_ERRORs
+	_, _, err = p.ParseNext(quicvarint.Append(nil, testExtensionFrameType), protocol.Encryption1RTT, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: testExtensionFrameType, ErrorCode: qerr.FrameEncodingError})
+}
+
+func TestFrameParserRegisteredFrameValidation(t *testing.T) {
+	b := append(quicvarint.Append(nil, testExtensionFrameType), "foobar"...)
+	for _, tc := range []struct {
+		name   string
+		parse  func([]byte, protocol.Version) (Frame, int, error)
+		errMsg string
+	}{
+		{
+			name:   "negative length",
+			parse:  func([]byte, protocol.Version) (Frame, int, error) { return &testExtensionFrame{}, -1, nil },
+			errMsg: "invalid length for registered frame: -1 (6 bytes remaining)",
+		},
+		{
+			name:   "length exceeding the data",
+			parse:  func(b []byte, _ protocol.Version) (Frame, int, error) { return &testExtensionFrame{}, len(b) + 1, nil },
+			errMsg: "invalid length for registered frame: 7 (6 bytes remaining)",
+		},
+		{
+			name:   "nil frame",
+			parse:  func(b []byte, _ protocol.Version) (Frame, int, error) { return nil, len(b), nil },
+			errMsg: "registered frame parser returned no frame",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewFrameParser(true, true)
+			p.RegisterFrameType(testExtensionFrameType, tc.parse)
+			_, _, err := p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+			require.ErrorIs(t, err, &qerr.TransportError{FrameType: testExtensionFrameType, ErrorCode: qerr.FrameEncodingError})
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestFrameParserRegisterImplementedFrameType(t *testing.T) {
+	p := NewFrameParser(true, true)
+	require.Panics(t, func() { p.RegisterFrameType(0, parseTestExtensionFrame) })
+	require.Panics(t, func() { p.RegisterFrameType(maxDataFrameType, parseTestExtensionFrame) })
+	require.Panics(t, func() { p.RegisterFrameType(0x30, parseTestExtensionFrame) })
+}
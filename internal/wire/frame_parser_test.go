@@ -3,6 +3,7 @@ package wire
 import (
 	"bytes"
 	"crypto/rand"
+	"io"
 	"testing"
 	"time"
 
@@ -39,6 +40,39 @@ func TestFrameParsingHandlesPaddingAtEnd(t *testing.T) {
 	require.Equal(t, 3, l)
 }
 
+func TestFrameParsingLimitsPaddingScan(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	parser.SetMaxPaddingScan(100)
+	b := make([]byte, 250) // PADDING
+	b, err := (&PingFrame{}).Append(b, protocol.Version1)
+	require.NoError(t, err)
+
+	l, f, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Nil(t, f)
+	require.Equal(t, 100, l)
+	b = b[l:]
+	l, f, err = parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Nil(t, f)
+	require.Equal(t, 100, l)
+	b = b[l:]
+	l, f, err = parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &PingFrame{}, f)
+	require.Equal(t, 51, l)
+
+	// without a limit, all PADDING is skipped in one go
+	parser.SetMaxPaddingScan(0)
+	b = make([]byte, 250)
+	b, err = (&PingFrame{}).Append(b, protocol.Version1)
+	require.NoError(t, err)
+	l, f, err = parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &PingFrame{}, f)
+	require.Equal(t, len(b), l)
+}
+
 func TestFrameParsingParsesSingleFrame(t *testing.T) {
 	parser := NewFrameParser(true, true)
 	var b []byte
@@ -93,6 +127,173 @@ func testFrameParserAckDelay(t *testing.T, encLevel protocol.EncryptionLevel) {
 	}
 }
 
+func TestFrameParserFlagsAckDelayExceedingMaxAckDelay(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	parser.SetAckDelayExponent(protocol.AckDelayExponent)
+	parser.SetMaxAckDelay(25 * time.Millisecond)
+
+	for _, tc := range []struct {
+		delay    time.Duration
+		encLevel protocol.EncryptionLevel
+		exceeds  bool
+	}{
+		{delay: 20 * time.Millisecond, encLevel: protocol.Encryption1RTT, exceeds: false},
+		{delay: 25 * time.Millisecond, encLevel: protocol.Encryption1RTT, exceeds: false},
+		{delay: 30 * time.Millisecond, encLevel: protocol.Encryption1RTT, exceeds: true},
+		// ACK delays are not checked for Initial and Handshake packets
+		{delay: 30 * time.Millisecond, encLevel: protocol.EncryptionHandshake, exceeds: false},
+	} {
+		b, err := (&AckFrame{
+			AckRanges: []AckRange{{Smallest: 1, Largest: 1}},
+			DelayTime: tc.delay,
+		}).Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		_, frame, err := parser.ParseNext(b, tc.encLevel, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, tc.exceeds, frame.(*AckFrame).DelayExceedsMaxAckDelay)
+	}
+}
+
+func TestFrameParserRejectsAcksForUnsentPackets(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	largestSent := map[protocol.EncryptionLevel]protocol.PacketNumber{
+		protocol.EncryptionInitial:   protocol.InvalidPacketNumber,
+		protocol.EncryptionHandshake: 10,
+		protocol.Encryption1RTT:      100,
+	}
+	parser.SetLargestSentPacketNumberFunc(func(encLevel protocol.EncryptionLevel) protocol.PacketNumber {
+		return largestSent[encLevel]
+	})
+
+	for _, tc := range []struct {
+		encLevel     protocol.EncryptionLevel
+		largestAcked protocol.PacketNumber
+		valid        bool
+	}{
+		{encLevel: protocol.EncryptionInitial, largestAcked: 0, valid: false},
+		{encLevel: protocol.EncryptionHandshake, largestAcked: 10, valid: true},
+		{encLevel: protocol.EncryptionHandshake, largestAcked: 11, valid: false},
+		{encLevel: protocol.Encryption1RTT, largestAcked: 100, valid: true},
+		{encLevel: protocol.Encryption1RTT, largestAcked: 101, valid: false},
+	} {
+		b, err := (&AckFrame{
+			AckRanges: []AckRange{{Smallest: tc.largestAcked, Largest: tc.largestAcked}},
+		}).Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		_, frame, err := parser.ParseNext(b, tc.encLevel, protocol.Version1)
+		if tc.valid {
+			require.NoError(t, err)
+			require.Equal(t, tc.largestAcked, frame.(*AckFrame).LargestAcked())
+			continue
+		}
+		require.Equal(t, &FrameParsingError{
+			Err: &qerr.TransportError{
+				ErrorCode:    qerr.ProtocolViolation,
+				FrameType:    ackFrameType,
+				ErrorMessage: "received ACK for an unsent packet",
+			},
+		}, err)
+	}
+}
+
+func TestFrameParserLimitsCryptoOffset(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	parser.SetMaxCryptoOffset(protocol.EncryptionInitial, 100)
+
+	b, err := (&CryptoFrame{Offset: 94, Data: []byte("foobar")}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	_, frame, err := parser.ParseNext(b, protocol.EncryptionInitial, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &CryptoFrame{Offset: 94, Data: []byte("foobar")}, frame)
+
+	b, err = (&CryptoFrame{Offset: 95, Data: []byte("foobar")}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	_, _, err = parser.ParseNext(b, protocol.EncryptionInitial, protocol.Version1)
+	require.Equal(t, &FrameParsingError{
+		Err: &qerr.TransportError{
+			ErrorCode:    qerr.CryptoBufferExceeded,
+			FrameType:    cryptoFrameType,
+			ErrorMessage: "received CRYPTO data beyond the limit of 100 bytes at encryption level Initial",
+		},
+	}, err)
+
+	// no limit was set for the Handshake encryption level
+	_, frame, err = parser.ParseNext(b, protocol.EncryptionHandshake, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &CryptoFrame{Offset: 95, Data: []byte("foobar")}, frame)
+}
+
+func TestFrameParserLimitsNewTokenFrames(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	parser.SetNewTokenLimits(3, 0)
+	b, err := (&NewTokenFrame{Token: []byte("foobar")}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	for range 3 {
+		_, frame, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, &NewTokenFrame{Token: []byte("foobar")}, frame)
+	}
+	_, _, err = parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.Equal(t, &FrameParsingError{
+		Err: &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			FrameType:    newTokenFrameType,
+			ErrorMessage: "received more than 3 NEW_TOKEN frames",
+		},
+	}, err)
+}
+
+func TestFrameParserRejectsHandshakeDoneFramesFromClients(t *testing.T) {
+	b, err := (&HandshakeDoneFrame{}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+
+	parser := NewFrameParser(true, true)
+	parser.SetPerspective(protocol.PerspectiveClient)
+	_, frame, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &HandshakeDoneFrame{}, frame)
+
+	parser.SetPerspective(protocol.PerspectiveServer)
+	_, _, err = parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.Equal(t, &FrameParsingError{
+		Err: &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			FrameType:    handshakeDoneFrameType,
+			ErrorMessage: "received a HANDSHAKE_DONE frame",
+		},
+	}, err)
+}
+
+func TestFrameParserLimitsPathFramesPerPayload(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	parser.SetMaxPathFramesPerPayload(2)
+	b, err := (&PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	b, err = (&PathResponseFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}).Append(b, protocol.Version1)
+	require.NoError(t, err)
+
+	parser.StartPayload()
+	for data := b; len(data) > 0; {
+		l, _, err := parser.ParseNext(data, protocol.Encryption1RTT, protocol.Version1)
+		require.NoError(t, err)
+		data = data[l:]
+	}
+	_, _, err = parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.Equal(t, &FrameParsingError{
+		Err: &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			FrameType:    pathChallengeFrameType,
+			ErrorMessage: "received more than 2 PATH_CHALLENGE / PATH_RESPONSE frames in a packet",
+		},
+	}, err)
+
+	// the limit applies per payload
+	parser.StartPayload()
+	_, frame, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}, frame)
+}
+
 func TestFrameParserStreamFrames(t *testing.T) {
 	parser := NewFrameParser(true, true)
 	f := &StreamFrame{
@@ -238,6 +439,34 @@ func TestFrameParserResetStreamAtUnsupported(t *testing.T) {
 	checkFrameUnsupported(t, err, 0x24)
 }
 
+func TestFrameParserUpdateCapabilities(t *testing.T) {
+	parser := NewFrameParser(false, false)
+	df := &DatagramFrame{Data: []byte("foobar")}
+	b1, err := df.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	rsf := &ResetStreamFrame{StreamID: 0x1337, ReliableSize: 0x42, FinalSize: 0xdeadbeef}
+	b2, err := rsf.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+
+	_, _, err = parser.ParseNext(b1, protocol.Encryption1RTT, protocol.Version1)
+	checkFrameUnsupported(t, err, 0x30)
+	_, _, err = parser.ParseNext(b2, protocol.Encryption1RTT, protocol.Version1)
+	checkFrameUnsupported(t, err, 0x24)
+
+	parser.SetSupportsDatagrams(true)
+	parser.SetSupportsResetStreamAt(true)
+	_, f, err := parser.ParseNext(b1, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, df, f)
+	_, f, err = parser.ParseNext(b2, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, rsf, f)
+
+	parser.SetSupportsDatagrams(false)
+	_, _, err = parser.ParseNext(b1, protocol.Encryption1RTT, protocol.Version1)
+	checkFrameUnsupported(t, err, 0x30)
+}
+
 func TestFrameParserInvalidFrameType(t *testing.T) {
 	parser := NewFrameParser(true, true)
 	_, _, err := parser.ParseNext(encodeVarInt(0x42), protocol.Encryption1RTT, protocol.Version1)
@@ -388,3 +617,116 @@ func BenchmarkParseOtherFrames(b *testing.B) {
 		}
 	}
 }
+
+func TestFrameParserClone(t *testing.T) {
+	p := NewFrameParser(true, false)
+	p.SetAckDelayExponent(5)
+	p.SetPerspective(protocol.PerspectiveServer)
+	p.SetMaxCryptoOffset(protocol.EncryptionHandshake, 10)
+	p.SetNewTokenLimits(1, 0)
+	p.SetMaxPathFramesPerPayload(1)
+
+	newToken, err := (&NewTokenFrame{Token: []byte("token")}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	_, _, err = p.ParseNext(newToken, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+
+	c := p.Clone()
+	// the clone doesn't share the configuration
+	p.SetMaxCryptoOffset(protocol.EncryptionHandshake, 100)
+	// the NEW_TOKEN counter is not copied
+	_, _, err = c.ParseNext(newToken, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	_, _, err = c.ParseNext(newToken, protocol.Encryption1RTT, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: newTokenFrameType, ErrorCode: qerr.ProtocolViolation})
+
+	// DATAGRAM support is copied
+	datagram, err := (&DatagramFrame{Data: []byte("foo")}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	_, _, err = c.ParseNext(datagram, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+
+	crypto, err := (&CryptoFrame{Data: make([]byte, 20)}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	_, _, err = c.ParseNext(crypto, protocol.EncryptionHandshake, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: cryptoFrameType, ErrorCode: qerr.CryptoBufferExceeded})
+
+	handshakeDone, err := (&HandshakeDoneFrame{}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	_, _, err = c.ParseNext(handshakeDone, protocol.Encryption1RTT, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: handshakeDoneFrameType, ErrorCode: qerr.ProtocolViolation})
+
+	// the ACK frame is not shared
+	ack, err := (&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	_, f1, err := p.ParseNext(ack, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	_, f2, err := c.ParseNext(ack, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.NotSame(t, f1, f2)
+	require.Equal(t, p.ackDelayExponent, c.ackDelayExponent)
+
+	// the per-payload counter is not copied
+	pathChallenge, err := (&PathChallengeFrame{}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	_, _, err = p.ParseNext(pathChallenge, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	c = p.Clone()
+	_, _, err = c.ParseNext(pathChallenge, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+}
+
+func TestFrameParserTruncatedFrames(t *testing.T) {
+	frames := []Frame{
+		&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}, ECT0: 1},
+		&CryptoFrame{Offset: 1000, Data: []byte("foobar")},
+		&StreamFrame{StreamID: 4, Offset: 1000, Data: []byte("foobar"), DataLenPresent: true},
+		&NewConnectionIDFrame{SequenceNumber: 1, ConnectionID: protocol.ParseConnectionID([]byte{1, 2, 3, 4})},
+		&ConnectionCloseFrame{ErrorCode: 1, ReasonPhrase: "foobar"},
+		&DatagramFrame{Data: []byte("foobar"), DataLenPresent: true},
+	}
+	for _, f := range frames {
+		b, err := f.Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		typ := uint64(b[0])
+		for i := 1; i < len(b); i++ {
+			parser := NewFrameParser(true, true)
+			_, _, err := parser.ParseNext(b[:i], protocol.Encryption1RTT, protocol.Version1)
+			require.Equal(t, &FrameParsingError{
+				Err: &qerr.TransportError{
+					FrameType:    typ,
+					ErrorCode:    qerr.FrameEncodingError,
+					ErrorMessage: io.ErrUnexpectedEOF.Error(),
+				},
+			}, err, "%T truncated to %d bytes", f, i)
+		}
+	}
+}
+
+func TestFrameParserOnFrameParsed(t *testing.T) {
+	type parsedFrame struct {
+		typ    FrameType
+		length int
+	}
+	var parsed []parsedFrame
+	p := NewFrameParser(true, true)
+	p.SetOnFrameParsed(func(typ FrameType, length int) { parsed = append(parsed, parsedFrame{typ, length}) })
+
+	b := appendFrames(t,
+		&PingFrame{},
+		&StreamFrame{StreamID: 4, Data: []byte("foobar"), DataLenPresent: true},
+		&MaxDataFrame{MaximumData: 1337},
+	)
+	b = append(b, 0, 0, 0)          // PADDING
+	b = append(b, maxDataFrameType) // truncated MAX_DATA frame
+	for _, err := range p.ParseAll(b, protocol.Encryption1RTT, protocol.Version1) {
+		if err != nil {
+			break
+		}
+	}
+	require.Equal(t, []parsedFrame{
+		{typ: pingFrameType, length: 1},
+		{typ: 0xa, length: 9},
+		{typ: maxDataFrameType, length: 3},
+	}, parsed)
+}
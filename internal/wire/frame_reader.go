@@ -0,0 +1,129 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// maxFrameReaderBuffer is the maximum amount of data buffered by the FrameReader to parse a single frame.
+// It is larger than any QUIC packet payload.
+const maxFrameReaderBuffer = 1 << 16
+
+const frameReaderReadSize = 4096
+
+// A FrameReader parses frames from an io.Reader, e.g. a file containing decrypted packet payloads.
+// Frames without a Length field (STREAM and DATAGRAM frames) extend to the end of the data.
+// It is meant for offline analysis tools, and must not be used on the hot path.
+type FrameReader struct {
+	r        io.Reader
+	parser   *FrameParser
+	encLevel protocol.EncryptionLevel
+	version  protocol.Version
+
+	buf []byte // the data read, but not yet parsed
+	eof bool
+}
+
+// NewFrameReader creates a new FrameReader.
+// Frames are parsed using the FrameParser, as if they were received at the encryption level.
+func NewFrameReader(r io.Reader, parser *FrameParser, encLevel protocol.EncryptionLevel, v protocol.Version) *FrameReader {
+	return &FrameReader{
+		r:        r,
+		parser:   parser,
+		encLevel: encLevel,
+		version:  v,
+	}
+}
+
+// ReadFrame reads the next frame. PADDING frames are skipped.
+// It returns io.EOF if there are no more frames.
+// As with FrameParser.ParseNext, the ACK frame is only valid until the next call.
+func (r *FrameReader) ReadFrame() (Frame, error) {
+	for {
+		if len(r.buf) == 0 {
+			if r.eof {
+				return nil, io.EOF
+			}
+			if err := r.fill(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// Parsing a frame has side effects (e.g. middlewares are called, and STREAM frames are taken from the pool),
+		// so the frame is only parsed once it was read completely.
+		if !r.eof && r.needsMoreData() {
+			if len(r.buf) >= maxFrameReaderBuffer {
+				return nil, fmt.Errorf("frame larger than %d bytes", maxFrameReaderBuffer)
+			}
+			if err := r.fill(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		frame, l, err := r.parser.parseNext(r.buf, r.encLevel, r.version)
+		if err != nil {
+			return nil, err
+		}
+		r.buf = r.buf[l:]
+		// parseNext might return before the end of the data when skipping PADDING
+		if frame == nil {
+			continue
+		}
+		return frame, nil
+	}
+}
+
+// needsMoreData says if more data needs to be read before the next frame can be parsed.
+// It only scans the framing, without parsing the frame.
+// Malformed frames are left to the parser, which then returns the error.
+func (r *FrameReader) needsMoreData() bool {
+	c := newCursor(r.buf)
+	for c.remaining() > 0 {
+		typ, err := c.readVarInt()
+		if err != nil {
+			return true
+		}
+		if typ == 0x0 { // PADDING frames are skipped by the parser
+			continue
+		}
+		// Frames without a Length field extend to the end of the data.
+		if (typ&0xf8 == 0x8 && typ&0b10 == 0) || typ == 0x30 {
+			return true
+		}
+		switch err := skipFrame(&c, typ); err {
+		case nil:
+			return false
+		case io.ErrUnexpectedEOF:
+			return true
+		case errUnknownFrameType:
+			// The length of frames not implemented by this package (e.g. registered frame types) is unknown.
+			// Read as much data as possible, before passing them to the parser.
+			return len(r.buf) < maxFrameReaderBuffer
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// fill reads more data from the underlying reader.
+func (r *FrameReader) fill() error {
+	if cap(r.buf)-len(r.buf) < frameReaderReadSize {
+		buf := make([]byte, len(r.buf), len(r.buf)+frameReaderReadSize)
+		copy(buf, r.buf)
+		r.buf = buf
+	}
+	n, err := r.r.Read(r.buf[len(r.buf):cap(r.buf)])
+	r.buf = r.buf[:len(r.buf)+n]
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			r.eof = true
+			return nil
+		}
+		return err
+	}
+	return nil
+}
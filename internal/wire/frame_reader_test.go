@@ -0,0 +1,142 @@
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameReader(t *testing.T) {
+	data := appendFrames(t,
+		&PingFrame{},
+		&CryptoFrame{Offset: 1000, Data: bytes.Repeat([]byte("foobar"), 1000)},
+		&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}},
+	)
+	data = append(data, make([]byte, 5000)...) // PADDING
+	data = append(data, appendFrames(t,
+		&MaxDataFrame{MaximumData: 1337},
+		&StreamFrame{StreamID: 4, Data: []byte("foobar")},
+	)...)
+
+	for _, tc := range []struct {
+		name string
+		r    io.Reader
+	}{
+		{name: "bytes.Reader", r: bytes.NewReader(data)},
+		{name: "one byte at a time", r: iotest.OneByteReader(bytes.NewReader(data))},
+		{name: "EOF with data", r: iotest.DataErrReader(bytes.NewReader(data))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewFrameReader(tc.r, NewFrameParser(true, true), protocol.Encryption1RTT, protocol.Version1)
+			f, err := r.ReadFrame()
+			require.NoError(t, err)
+			require.Equal(t, &PingFrame{}, f)
+			f, err = r.ReadFrame()
+			require.NoError(t, err)
+			require.Equal(t, protocol.ByteCount(1000), f.(*CryptoFrame).Offset)
+			require.Len(t, f.(*CryptoFrame).Data, 6000)
+			f, err = r.ReadFrame()
+			require.NoError(t, err)
+			require.Equal(t, protocol.PacketNumber(10), f.(*AckFrame).LargestAcked())
+			f, err = r.ReadFrame()
+			require.NoError(t, err)
+			require.Equal(t, &MaxDataFrame{MaximumData: 1337}, f)
+			// the STREAM frame without a Length field extends to the end of the data
+			f, err = r.ReadFrame()
+			require.NoError(t, err)
+			require.Equal(t, []byte("foobar"), f.(*StreamFrame).Data)
+			_, err = r.ReadFrame()
+			require.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestFrameReaderParsesFramesOnce(t *testing.T) {
+	data := appendFrames(t,
+		&CryptoFrame{Data: []byte("foobar")},
+		&MaxDataFrame{MaximumData: 1337},
+		&StreamFrame{StreamID: 4, Data: []byte("foobar")},
+	)
+	parser := NewFrameParser(true, true)
+	var parsed []FrameType
+	parser.Use(func(next ParseFunc) ParseFunc {
+		return func(b []byte, typ uint64, encLevel protocol.EncryptionLevel, v protocol.Version) (Frame, int, error) {
+			parsed = append(parsed, FrameType(typ))
+			return next(b, typ, encLevel, v)
+		}
+	})
+	r := NewFrameReader(iotest.OneByteReader(bytes.NewReader(data)), parser, protocol.Encryption1RTT, protocol.Version1)
+	for {
+		_, err := r.ReadFrame()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	require.Equal(t, []FrameType{cryptoFrameType, maxDataFrameType, 0x8}, parsed)
+}
+
+func TestFrameReaderRegisteredFrameType(t *testing.T) {
+	data := append(quicvarint.Append(nil, 0x1337), []byte("foobar")...)
+	parser := NewFrameParser(true, true)
+	parser.RegisterFrameType(0x1337, func(b []byte, _ protocol.Version) (Frame, int, error) {
+		return &DatagramFrame{Data: b}, len(b), nil
+	})
+	r := NewFrameReader(iotest.OneByteReader(bytes.NewReader(data)), parser, protocol.Encryption1RTT, protocol.Version1)
+	f, err := r.ReadFrame()
+	require.NoError(t, err)
+	require.Equal(t, &DatagramFrame{Data: []byte("foobar")}, f)
+	_, err = r.ReadFrame()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestFrameReaderErrors(t *testing.T) {
+	t.Run("invalid frame", func(t *testing.T) {
+		data := appendFrames(t, &PingFrame{})
+		data = append(data, maxDataFrameType)
+		r := NewFrameReader(bytes.NewReader(data), NewFrameParser(true, true), protocol.Encryption1RTT, protocol.Version1)
+		_, err := r.ReadFrame()
+		require.NoError(t, err)
+		_, err = r.ReadFrame()
+		require.ErrorIs(t, err, &qerr.TransportError{FrameType: maxDataFrameType, ErrorCode: qerr.FrameEncodingError})
+	})
+
+	t.Run("malformed frame", func(t *testing.T) {
+		// an ACK frame with an invalid ACK range, followed by an infinite amount of data
+		data := []byte{ackFrameType}
+		for _, v := range []uint64{10, 0, 1, 20, 0, 0} {
+			data = quicvarint.Append(data, v)
+		}
+		r := NewFrameReader(io.MultiReader(bytes.NewReader(data), zeroReader{}), NewFrameParser(true, true), protocol.Encryption1RTT, protocol.Version1)
+		_, err := r.ReadFrame()
+		require.ErrorIs(t, err, &qerr.TransportError{FrameType: ackFrameType, ErrorCode: qerr.FrameEncodingError})
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		r := NewFrameReader(iotest.ErrReader(errors.New("test error")), NewFrameParser(true, true), protocol.Encryption1RTT, protocol.Version1)
+		_, err := r.ReadFrame()
+		require.EqualError(t, err, "test error")
+	})
+
+	t.Run("frame too large", func(t *testing.T) {
+		data := appendFrames(t, &DatagramFrame{Data: []byte("foobar")})
+		r := NewFrameReader(io.MultiReader(bytes.NewReader(data), zeroReader{}), NewFrameParser(true, true), protocol.Encryption1RTT, protocol.Version1)
+		_, err := r.ReadFrame()
+		require.EqualError(t, err, "frame larger than 65536 bytes")
+	})
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	clear(b)
+	return len(b), nil
+}
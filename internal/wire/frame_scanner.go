@@ -0,0 +1,262 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+)
+
+// scanFrames iterates over the frames contained in b, without materializing them.
+// PADDING frames are skipped.
+// For every other frame, fn is called with the frame type, the frame's offset in b,
+// and the length of the frame type (which might not be encoded as a minimal-length varint).
+// The frame body starts at offset+typeLen.
+// Scanning stops as soon as fn returns false.
+// Only the framing is checked, the frame contents are not validated.
+func scanFrames(b []byte, fn func(typ uint64, offset, typeLen int) (cont bool)) error {
+	c := newCursor(b)
+	for c.remaining() > 0 {
+		offset := c.consumed()
+		typ, err := c.readVarInt()
+		if err != nil {
+			return &qerr.TransportError{
+				ErrorCode:    qerr.FrameEncodingError,
+				ErrorMessage: err.Error(),
+			}
+		}
+		if typ == 0x0 { // skip PADDING frames
+			continue
+		}
+		if !fn(typ, offset, c.consumed()-offset) {
+			return nil
+		}
+		if err := skipFrame(&c, typ); err != nil {
+			return &qerr.TransportError{
+				FrameType:    typ,
+				ErrorCode:    qerr.FrameEncodingError,
+				ErrorMessage: err.Error(),
+			}
+		}
+	}
+	return nil
+}
+
+// skipFrame advances the cursor past the frame of type typ.
+// The frame type must already have been consumed.
+func skipFrame(c *cursor, typ uint64) error {
+	if typ&0xf8 == 0x8 { // STREAM
+		_, err := skipStreamFrame(c, typ)
+		return err
+	}
+	switch typ {
+	case pingFrameType, handshakeDoneFrameType:
+		return nil
+	case ackFrameType, ackECNFrameType:
+		// Largest Acknowledged, ACK Delay, ACK Range Count, First ACK Range
+		if err := skipVarInts(c, 2); err != nil {
+			return err
+		}
+		numRanges, err := c.readVarInt()
+		if err != nil {
+			return err
+		}
+		if err := skipVarInts(c, 1); err != nil {
+			return err
+		}
+		// every ACK Range consists of at least 2 bytes
+		if numRanges > uint64(c.remaining())/2 {
+			return errInvalidAckRanges
+		}
+		if err := skipVarInts(c, 2*int(numRanges)); err != nil {
+			return err
+		}
+		if typ == ackECNFrameType {
+			return skipVarInts(c, 3)
+		}
+		return nil
+	case resetStreamFrameType:
+		return skipVarInts(c, 3)
+	case resetStreamAtFrameType:
+		return skipVarInts(c, 4)
+	case stopSendingFrameType, maxStreamDataFrameType, streamDataBlockedFrameType:
+		return skipVarInts(c, 2)
+	case maxDataFrameType, bidiMaxStreamsFrameType, uniMaxStreamsFrameType, dataBlockedFrameType,
+		bidiStreamBlockedFrameType, uniStreamBlockedFrameType, retireConnectionIDFrameType:
+		return skipVarInts(c, 1)
+	case cryptoFrameType:
+		if err := skipVarInts(c, 1); err != nil {
+			return err
+		}
+		return skipLengthPrefixed(c)
+	case newTokenFrameType:
+		return skipLengthPrefixed(c)
+	case newConnectionIDFrameType:
+		if err := skipVarInts(c, 2); err != nil {
+			return err
+		}
+		l, err := c.readByte()
+		if err != nil {
+			return err
+		}
+		_, err = c.readBytes(uint64(l) + uint64(len(protocol.StatelessResetToken{})))
+		return err
+	case pathChallengeFrameType, pathResponseFrameType:
+		_, err := c.readBytes(8)
+		return err
+	case connectionCloseFrameType:
+		if err := skipVarInts(c, 2); err != nil {
+			return err
+		}
+		return skipLengthPrefixed(c)
+	case applicationCloseFrameType:
+		if err := skipVarInts(c, 1); err != nil {
+			return err
+		}
+		return skipLengthPrefixed(c)
+	case 0x30: // DATAGRAM without a Length field extends to the end of the packet
+		_, err := c.readBytes(uint64(c.remaining()))
+		return err
+	case 0x31:
+		return skipLengthPrefixed(c)
+	default:
+		return errUnknownFrameType
+	}
+}
+
+// skipStreamFrame advances the cursor past a STREAM frame of type typ,
+// and returns the length of the STREAM data.
+func skipStreamFrame(c *cursor, typ uint64) (protocol.ByteCount, error) {
+	if err := skipVarInts(c, 1); err != nil { // Stream ID
+		return 0, err
+	}
+	if typ&0b100 > 0 { // Offset
+		if err := skipVarInts(c, 1); err != nil {
+			return 0, err
+		}
+	}
+	// If there's no Length field, the rest of the packet is data
+	dataLen := uint64(c.remaining())
+	if typ&0b10 > 0 {
+		var err error
+		dataLen, err = c.readVarInt()
+		if err != nil {
+			return 0, err
+		}
+	}
+	if _, err := c.readBytes(dataLen); err != nil {
+		return 0, err
+	}
+	return protocol.ByteCount(dataLen), nil
+}
+
+func skipVarInts(c *cursor, n int) error {
+	for range n {
+		if _, err := c.readVarInt(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func skipLengthPrefixed(c *cursor) error {
+	l, err := c.readVarInt()
+	if err != nil {
+		return err
+	}
+	_, err = c.readBytes(l)
+	return err
+}
+
+// IsAckOnlyPayload says if the payload contains only ACK (and PADDING) frames.
+// Packets with such a payload are not ack-eliciting, and don't need to be acknowledged.
+// This is cheaper than parsing the payload, since no frames are materialized.
+// Only the framing is checked: an error is returned for malformed or unknown frames,
+// but the contents of the frames are not validated.
+func IsAckOnlyPayload(payload []byte, _ protocol.Version) (bool, error) {
+	ackOnly := true
+	if err := scanFrames(payload, func(typ uint64, _, _ int) bool {
+		if typ != ackFrameType && typ != ackECNFrameType {
+			ackOnly = false
+		}
+		return ackOnly
+	}); err != nil {
+		return false, err
+	}
+	return ackOnly, nil
+}
+
+// PeekStreamDataLen returns the sum of the data lengths of all STREAM frames in the payload.
+// No frames are materialized and no data is copied,
+// allowing flow control accounting and buffer sizing to happen before the payload is parsed.
+// Only the framing is checked: an error is returned for malformed or unknown frames,
+// but the contents of the frames are not validated.
+func PeekStreamDataLen(payload []byte, _ protocol.Version) (protocol.ByteCount, error) {
+	var dataLen protocol.ByteCount
+	if err := scanFrames(payload, func(typ uint64, offset, typeLen int) bool {
+		if typ&0xf8 != 0x8 {
+			return true
+		}
+		c := newCursor(payload[offset+typeLen:])
+		// If the frame is malformed, scanFrames returns an error when skipping it.
+		if l, err := skipStreamFrame(&c, typ); err == nil {
+			dataLen += l
+		}
+		return true
+	}); err != nil {
+		return 0, err
+	}
+	return dataLen, nil
+}
+
+// FindFrame finds the first frame in the payload whose type matches pred.
+// It returns the frame type and the offset of the frame in the payload,
+// such that the frame can then be parsed using FrameParser.ParseNext.
+// Frames following the matching frame are not inspected.
+// PADDING frames are never passed to pred.
+func FindFrame(payload []byte, pred func(FrameType) bool) (typ FrameType, offset int, found bool, err error) {
+	err = scanFrames(payload, func(t uint64, o, _ int) bool {
+		if pred(FrameType(t)) {
+			typ, offset, found = FrameType(t), o, true
+		}
+		return !found
+	})
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return typ, offset, found, nil
+}
+
+// ParseConnectionCloseOnly parses the first CONNECTION_CLOSE frame in the payload.
+// All other frames are skipped without being materialized.
+// This is used when the connection is already closed, and only CONNECTION_CLOSE frames are of interest.
+// If the payload doesn't contain a CONNECTION_CLOSE frame, it returns false.
+func ParseConnectionCloseOnly(payload []byte, encLevel protocol.EncryptionLevel, v protocol.Version) (*ConnectionCloseFrame, bool, error) {
+	var typ FrameType
+	var body int
+	var found bool
+	if err := scanFrames(payload, func(t uint64, offset, typeLen int) bool {
+		if t == connectionCloseFrameType || t == applicationCloseFrameType {
+			typ, body, found = FrameType(t), offset+typeLen, true
+		}
+		return !found
+	}); err != nil || !found {
+		return nil, false, err
+	}
+	if encLevel == protocol.Encryption0RTT {
+		return nil, false, &qerr.TransportError{
+			FrameType:    uint64(typ),
+			ErrorCode:    qerr.FrameEncodingError,
+			ErrorMessage: fmt.Sprintf("%s frame not allowed at encryption level %s", typ, encLevel),
+		}
+	}
+	f, _, err := parseConnectionCloseFrame(payload[body:], uint64(typ), v)
+	if err != nil {
+		return nil, false, &qerr.TransportError{
+			FrameType:    uint64(typ),
+			ErrorCode:    qerr.FrameEncodingError,
+			ErrorMessage: err.Error(),
+		}
+	}
+	return f, true, nil
+}
@@ -0,0 +1,247 @@
+package wire
+
+import (
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func appendFrames(t testing.TB, frames ...Frame) []byte {
+	t.Helper()
+	var b []byte
+	for _, f := range frames {
+		var err error
+		b, err = f.Append(b, protocol.Version1)
+		require.NoError(t, err)
+	}
+	return b
+}
+
+func TestScanFramesSkipsAllFrameTypes(t *testing.T) {
+	frames := []Frame{
+		&PingFrame{},
+		&AckFrame{AckRanges: []AckRange{{Smallest: 10, Largest: 20}, {Smallest: 1, Largest: 5}}, ECT0: 1, ECT1: 2, ECNCE: 3},
+		&ResetStreamFrame{StreamID: 4, ErrorCode: 1, FinalSize: 100},
+		&ResetStreamFrame{StreamID: 4, ErrorCode: 1, FinalSize: 100, ReliableSize: 50},
+		&StopSendingFrame{StreamID: 4, ErrorCode: 1},
+		&CryptoFrame{Offset: 10, Data: []byte("crypto")},
+		&NewTokenFrame{Token: []byte("token")},
+		&StreamFrame{StreamID: 4, Offset: 10, Data: []byte("foo"), DataLenPresent: true},
+		&MaxDataFrame{MaximumData: 1000},
+		&MaxStreamDataFrame{StreamID: 4, MaximumStreamData: 1000},
+		&MaxStreamsFrame{Type: protocol.StreamTypeBidi, MaxStreamNum: 10},
+		&DataBlockedFrame{MaximumData: 1000},
+		&StreamDataBlockedFrame{StreamID: 4, MaximumStreamData: 1000},
+		&StreamsBlockedFrame{Type: protocol.StreamTypeUni, StreamLimit: 10},
+		&NewConnectionIDFrame{SequenceNumber: 2, ConnectionID: protocol.ParseConnectionID([]byte{1, 2, 3, 4})},
+		&RetireConnectionIDFrame{SequenceNumber: 1},
+		&PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		&PathResponseFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		&ConnectionCloseFrame{ErrorCode: 1, FrameType: 2, ReasonPhrase: "foo"},
+		&ConnectionCloseFrame{IsApplicationError: true, ErrorCode: 1, ReasonPhrase: "bar"},
+		&HandshakeDoneFrame{},
+		&DatagramFrame{Data: []byte("datagram"), DataLenPresent: true},
+		&StreamFrame{StreamID: 8, Data: []byte("foobar")},
+	}
+	b := appendFrames(t, frames...)
+	b = append(make([]byte, 3), b...) // PADDING
+
+	var offsets []int
+	require.NoError(t, scanFrames(b, func(typ uint64, offset, typeLen int) bool {
+		require.Equal(t, quicvarint.Len(typ), typeLen)
+		offsets = append(offsets, offset)
+		return true
+	}))
+	require.Len(t, offsets, len(frames))
+	offset := 3
+	for i, f := range frames {
+		require.Equal(t, offset, offsets[i], "frame %d (%T)", i, f)
+		offset += int(f.Length(protocol.Version1))
+	}
+}
+
+func TestScanFramesNonMinimalFrameType(t *testing.T) {
+	// a PING frame and a MAX_DATA frame, with their types encoded using 2 and 4 bytes
+	b := quicvarint.AppendWithLen(nil, pingFrameType, 2)
+	b = quicvarint.AppendWithLen(b, maxDataFrameType, 4)
+	b = quicvarint.Append(b, 1337)
+
+	type scannedFrame struct{ typ, offset, typeLen int }
+	var scanned []scannedFrame
+	require.NoError(t, scanFrames(b, func(typ uint64, offset, typeLen int) bool {
+		scanned = append(scanned, scannedFrame{typ: int(typ), offset: offset, typeLen: typeLen})
+		return true
+	}))
+	require.Equal(t, []scannedFrame{
+		{typ: pingFrameType, offset: 0, typeLen: 2},
+		{typ: maxDataFrameType, offset: 2, typeLen: 4},
+	}, scanned)
+}
+
+func TestScanFramesErrors(t *testing.T) {
+	b := appendFrames(t, &CryptoFrame{Data: []byte("foobar")})
+	err := scanFrames(b[:len(b)-1], func(uint64, int, int) bool { return true })
+	require.Equal(t, &qerr.TransportError{
+		FrameType:    cryptoFrameType,
+		ErrorCode:    qerr.FrameEncodingError,
+		ErrorMessage: io.ErrUnexpectedEOF.Error(),
+	}, err)
+
+	err = scanFrames([]byte{0x2f}, func(uint64, int, int) bool { return true })
+	require.Equal(t, &qerr.TransportError{
+		FrameType:    0x2f,
+		ErrorCode:    qerr.FrameEncodingError,
+		ErrorMessage: errUnknownFrameType.Error(),
+	}, err)
+}
+
+func TestIsAckOnlyPayload(t *testing.T) {
+	ack := &AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}}
+
+	ackOnly, err := IsAckOnlyPayload(append(appendFrames(t, ack, ack), 0, 0, 0), protocol.Version1)
+	require.NoError(t, err)
+	require.True(t, ackOnly)
+
+	ackOnly, err = IsAckOnlyPayload(appendFrames(t, ack, &PingFrame{}), protocol.Version1)
+	require.NoError(t, err)
+	require.False(t, ackOnly)
+
+	// scanning stops at the first ack-eliciting frame
+	ackOnly, err = IsAckOnlyPayload(append(appendFrames(t, &PingFrame{}), 0x2f), protocol.Version1)
+	require.NoError(t, err)
+	require.False(t, ackOnly)
+
+	b := appendFrames(t, ack)
+	_, err = IsAckOnlyPayload(b[:len(b)-1], protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: ackFrameType, ErrorCode: qerr.FrameEncodingError})
+}
+
+func TestPeekStreamDataLen(t *testing.T) {
+	b := appendFrames(t,
+		&StreamFrame{StreamID: 4, Data: make([]byte, 100), DataLenPresent: true},
+		&CryptoFrame{Data: make([]byte, 50)},
+		&StreamFrame{StreamID: 8, Offset: 1000, Data: make([]byte, 200), DataLenPresent: true},
+		&StreamFrame{StreamID: 12, Data: make([]byte, 300)}, // extends to the end of the packet
+	)
+	dataLen, err := PeekStreamDataLen(b, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, protocol.ByteCount(600), dataLen)
+
+	dataLen, err = PeekStreamDataLen(appendFrames(t, &PingFrame{}), protocol.Version1)
+	require.NoError(t, err)
+	require.Zero(t, dataLen)
+
+	b = appendFrames(t, &StreamFrame{StreamID: 4, Data: make([]byte, 100), DataLenPresent: true})
+	_, err = PeekStreamDataLen(b[:len(b)-1], protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: 0xa, ErrorCode: qerr.FrameEncodingError})
+}
+
+func TestPeekStreamDataLenNonMinimalFrameType(t *testing.T) {
+	// a STREAM frame with a Length field, with the frame type encoded using 2 bytes
+	b := quicvarint.AppendWithLen(nil, 0x8|0x2, 2)
+	b = quicvarint.Append(b, 4) // stream ID
+	b = quicvarint.Append(b, 3) // data length
+	b = append(b, "foo"...)
+
+	dataLen, err := PeekStreamDataLen(b, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, protocol.ByteCount(3), dataLen)
+
+	l, f, err := NewFrameParser(true, true).ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(b), l)
+	require.Equal(t, dataLen, f.(*StreamFrame).DataLen())
+}
+
+func TestFindFrame(t *testing.T) {
+	isPathResponse := func(typ FrameType) bool { return typ == pathResponseFrameType }
+
+	b := appendFrames(t,
+		&PingFrame{},
+		&StreamFrame{StreamID: 4, Data: []byte("foobar"), DataLenPresent: true},
+		&PathResponseFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+	)
+	l := len(b)
+	b = append(b, 0x2f) // unknown frame type, not inspected
+	typ, offset, found, err := FindFrame(b, isPathResponse)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, FrameType(pathResponseFrameType), typ)
+	n, f, err := NewFrameParser(false, false).ParseNext(b[offset:l], protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, l-offset, n)
+	require.Equal(t, &PathResponseFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}, f)
+
+	_, _, found, err = FindFrame(appendFrames(t, &PingFrame{}), isPathResponse)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	_, _, _, err = FindFrame([]byte{0x2f}, isPathResponse)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: 0x2f, ErrorCode: qerr.FrameEncodingError})
+}
+
+func TestParseConnectionCloseOnly(t *testing.T) {
+	ccf := &ConnectionCloseFrame{IsApplicationError: true, ErrorCode: 42, ReasonPhrase: "bye"}
+	b := appendFrames(t,
+		&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}},
+		&StreamFrame{StreamID: 4, Data: []byte("foobar"), DataLenPresent: true},
+		ccf,
+	)
+	f, ok, err := ParseConnectionCloseOnly(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, ccf, f)
+
+	_, ok, err = ParseConnectionCloseOnly(appendFrames(t, &PingFrame{}), protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, _, err = ParseConnectionCloseOnly(b, protocol.Encryption0RTT, protocol.Version1)
+	require.Equal(t, &qerr.TransportError{
+		FrameType:    applicationCloseFrameType,
+		ErrorCode:    qerr.FrameEncodingError,
+		ErrorMessage: "CONNECTION_CLOSE frame not allowed at encryption level 0-RTT",
+	}, err)
+
+	// the reason phrase is truncated
+	_, _, err = ParseConnectionCloseOnly(b[:len(b)-1], protocol.Encryption1RTT, protocol.Version1)
+	require.Equal(t, &qerr.TransportError{
+		FrameType:    applicationCloseFrameType,
+		ErrorCode:    qerr.FrameEncodingError,
+		ErrorMessage: io.ErrUnexpectedEOF.Error(),
+	}, err)
+}
+
+func TestParseConnectionCloseOnlyNonMinimalFrameType(t *testing.T) {
+	ccf := &ConnectionCloseFrame{ErrorCode: 1, FrameType: 2, ReasonPhrase: "foo"}
+	b, err := ccf.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	// re-encode the frame type using 2 bytes
+	b = append(quicvarint.AppendWithLen(nil, connectionCloseFrameType, 2), b[1:]...)
+	b = append(appendFrames(t, &PingFrame{}), b...)
+
+	f, ok, err := ParseConnectionCloseOnly(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, ccf, f)
+}
+
+func TestParseConnectionCloseOnlyAllocations(t *testing.T) {
+	b := appendFrames(t,
+		&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}},
+		&StreamFrame{StreamID: 4, Data: []byte("foobar"), DataLenPresent: true},
+		&PingFrame{},
+	)
+	allocs := testing.AllocsPerRun(100, func() {
+		_, ok, err := ParseConnectionCloseOnly(b, protocol.Encryption1RTT, protocol.Version1)
+		if err != nil || ok {
+			t.Fatal("unexpected result")
+		}
+	})
+	require.Zero(t, allocs)
+}
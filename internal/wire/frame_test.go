@@ -3,6 +3,8 @@ package wire
 import (
 	"testing"
 
+	"github.com/quic-go/quic-go/internal/protocol"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,3 +29,59 @@ func TestProbingFrames(t *testing.T) {
 		require.Equal(t, expected, IsProbingFrame(f))
 	}
 }
+
+func TestFramesExtendingToEndOfPacket(t *testing.T) {
+	testCases := map[Frame]bool{
+		&StreamFrame{DataLenPresent: true}:   false,
+		&StreamFrame{DataLenPresent: false}:  true,
+		&DatagramFrame{DataLenPresent: true}: false,
+		&DatagramFrame{}:                     true,
+		&CryptoFrame{}:                       false,
+		&PingFrame{}:                         false,
+	}
+
+	for f, expected := range testCases {
+		require.Equal(t, expected, ExtendsToEndOfPacket(f))
+	}
+}
+
+func TestParsedFramesExtendingToEndOfPacket(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	for _, f := range []Frame{
+		&StreamFrame{StreamID: 4, Data: []byte("foobar")},
+		&DatagramFrame{Data: []byte("foobar")},
+	} {
+		b, err := f.Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		l, frame, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+		require.NoError(t, err)
+		require.True(t, ExtendsToEndOfPacket(frame))
+		require.Equal(t, len(b), l)
+	}
+}
+
+func TestAppendN(t *testing.T) {
+	for _, f := range []Frame{
+		&PingFrame{},
+		&MaxDataFrame{MaximumData: 1337},
+		&StreamFrame{StreamID: 4, Offset: 1000, Data: []byte("foobar"), DataLenPresent: true},
+		&AckFrame{AckRanges: []AckRange{{Smallest: 10, Largest: 100}, {Smallest: 1, Largest: 5}}},
+	} {
+		b, n, err := AppendN([]byte("prefix"), f, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, "prefix", string(b[:6]))
+		require.Equal(t, len(b)-6, n)
+		require.Equal(t, int(f.Length(protocol.Version1)), n)
+		require.NoError(t, checkAppendedLength(f, n, protocol.Version1))
+	}
+}
+
+func TestAppendNErrors(t *testing.T) {
+	_, _, err := AppendN(nil, &StreamFrame{StreamID: 4}, protocol.Version1)
+	require.Error(t, err)
+
+	require.EqualError(t,
+		checkAppendedLength(&PingFrame{}, 2, protocol.Version1),
+		"BUG: *wire.PingFrame appended 2 bytes, but its length is 1 bytes",
+	)
+}
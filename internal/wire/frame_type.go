@@ -0,0 +1,108 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// A FrameType is the type of a QUIC frame.
+type FrameType uint64
+
+// frameTypeNames contains the names of the frame types, as defined in section 19 of RFC 9000,
+// RFC 9221 (DATAGRAM) and the reliable stream reset extension (RESET_STREAM_AT).
+var frameTypeNames = [...]string{
+	0x0:                         "PADDING",
+	pingFrameType:               "PING",
+	ackFrameType:                "ACK",
+	ackECNFrameType:             "ACK_ECN",
+	resetStreamFrameType:        "RESET_STREAM",
+	stopSendingFrameType:        "STOP_SENDING",
+	cryptoFrameType:             "CRYPTO",
+	newTokenFrameType:           "NEW_TOKEN",
+	0x8:                         "STREAM",
+	0x9:                         "STREAM",
+	0xa:                         "STREAM",
+	0xb:                         "STREAM",
+	0xc:                         "STREAM",
+	0xd:                         "STREAM",
+	0xe:                         "STREAM",
+	0xf:                         "STREAM",
+	maxDataFrameType:            "MAX_DATA",
+	maxStreamDataFrameType:      "MAX_STREAM_DATA",
+	bidiMaxStreamsFrameType:     "MAX_STREAMS",
+	uniMaxStreamsFrameType:      "MAX_STREAMS",
+	dataBlockedFrameType:        "DATA_BLOCKED",
+	streamDataBlockedFrameType:  "STREAM_DATA_BLOCKED",
+	bidiStreamBlockedFrameType:  "STREAMS_BLOCKED",
+	uniStreamBlockedFrameType:   "STREAMS_BLOCKED",
+	newConnectionIDFrameType:    "NEW_CONNECTION_ID",
+	retireConnectionIDFrameType: "RETIRE_CONNECTION_ID",
+	pathChallengeFrameType:      "PATH_CHALLENGE",
+	pathResponseFrameType:       "PATH_RESPONSE",
+	connectionCloseFrameType:    "CONNECTION_CLOSE",
+	applicationCloseFrameType:   "CONNECTION_CLOSE",
+	handshakeDoneFrameType:      "HANDSHAKE_DONE",
+	resetStreamAtFrameType:      "RESET_STREAM_AT",
+	0x30:                        "DATAGRAM",
+	0x31:                        "DATAGRAM",
+}
+
+func (t FrameType) String() string {
+	if t < FrameType(len(frameTypeNames)) {
+		if name := frameTypeNames[t]; name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("unknown frame type (%#x)", uint64(t))
+}
+
+// A FrameTypeClass classifies a frame type.
+type FrameTypeClass uint8
+
+const (
+	// FrameTypeInvalid is returned if the frame type couldn't be parsed.
+	FrameTypeInvalid FrameTypeClass = iota
+	// FrameTypeUnknown is a frame type that is neither defined by RFC 9000, nor by any extension implemented by this package.
+	// Since RFC 9000 doesn't reserve any frame types for greasing, greased frame types are unknown as well.
+	FrameTypeUnknown
+	// FrameTypeStandard is a frame type defined by RFC 9000.
+	FrameTypeStandard
+	// FrameTypeExtension is a frame type defined by an extension: DATAGRAM (RFC 9221) or RESET_STREAM_AT.
+	// These frames are only parsed if support for the extension was negotiated.
+	FrameTypeExtension
+)
+
+func (c FrameTypeClass) String() string {
+	switch c {
+	case FrameTypeInvalid:
+		return "invalid"
+	case FrameTypeUnknown:
+		return "unknown"
+	case FrameTypeStandard:
+		return "standard"
+	case FrameTypeExtension:
+		return "extension"
+	default:
+		return fmt.Sprintf("unknown frame type class (%d)", uint8(c))
+	}
+}
+
+// ClassifyFrameType parses the frame type at the beginning of b, and classifies it.
+// It returns an error if b doesn't start with a complete varint.
+// Like the FrameParser, it accepts frame types that are not encoded as a minimal-length varint,
+// and classifies them by their value.
+func ClassifyFrameType(b []byte) (FrameType, FrameTypeClass, error) {
+	typ, _, err := quicvarint.Parse(b)
+	if err != nil {
+		return 0, FrameTypeInvalid, err
+	}
+	switch {
+	case typ == resetStreamAtFrameType, typ == 0x30, typ == 0x31:
+		return FrameType(typ), FrameTypeExtension, nil
+	case typ < uint64(len(frameTypeNames)) && frameTypeNames[typ] != "":
+		return FrameType(typ), FrameTypeStandard, nil
+	default:
+		return FrameType(typ), FrameTypeUnknown, nil
+	}
+}
@@ -0,0 +1,68 @@
+package wire
+
+import (
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameTypeStringer(t *testing.T) {
+	for typ, expected := range map[FrameType]string{
+		0x0:                    "PADDING",
+		ackECNFrameType:        "ACK_ECN",
+		0x8:                    "STREAM",
+		0xf:                    "STREAM",
+		uniMaxStreamsFrameType: "MAX_STREAMS",
+		handshakeDoneFrameType: "HANDSHAKE_DONE",
+		resetStreamAtFrameType: "RESET_STREAM_AT",
+		0x31:                   "DATAGRAM",
+		0x1f:                   "unknown frame type (0x1f)",
+		0x1337:                 "unknown frame type (0x1337)",
+	} {
+		require.Equal(t, expected, typ.String())
+	}
+}
+
+func TestClassifyFrameType(t *testing.T) {
+	for _, tc := range []struct {
+		b     []byte
+		typ   FrameType
+		class FrameTypeClass
+	}{
+		{b: []byte{0x0}, typ: 0x0, class: FrameTypeStandard},
+		{b: []byte{ackECNFrameType}, typ: ackECNFrameType, class: FrameTypeStandard},
+		{b: []byte{0xf, 0x42}, typ: 0xf, class: FrameTypeStandard},
+		{b: []byte{handshakeDoneFrameType}, typ: handshakeDoneFrameType, class: FrameTypeStandard},
+		{b: []byte{resetStreamAtFrameType}, typ: resetStreamAtFrameType, class: FrameTypeExtension},
+		{b: []byte{0x30}, typ: 0x30, class: FrameTypeExtension},
+		{b: []byte{0x31}, typ: 0x31, class: FrameTypeExtension},
+		{b: []byte{0x1f}, typ: 0x1f, class: FrameTypeUnknown},
+		{b: quicvarint.Append(nil, 0x1337), typ: 0x1337, class: FrameTypeUnknown},
+		// non-minimal encodings are accepted by the FrameParser, and classified by their value
+		{b: quicvarint.AppendWithLen(nil, pingFrameType, 2), typ: pingFrameType, class: FrameTypeStandard},
+		{b: quicvarint.AppendWithLen(nil, 0x30, 4), typ: 0x30, class: FrameTypeExtension},
+		{b: quicvarint.AppendWithLen(nil, 0x1f, 8), typ: 0x1f, class: FrameTypeUnknown},
+	} {
+		typ, class, err := ClassifyFrameType(tc.b)
+		require.NoError(t, err)
+		require.Equal(t, tc.typ, typ)
+		require.Equal(t, tc.class, class, "%x: %s instead of %s", tc.b, class, tc.class)
+	}
+
+	// the FrameParser agrees with the classification of non-minimal encodings
+	b := quicvarint.AppendWithLen(nil, pingFrameType, 2)
+	l, frame, err := NewFrameParser(true, true).ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, 2, l)
+	require.Equal(t, &PingFrame{}, frame)
+
+	_, class, err := ClassifyFrameType(nil)
+	require.Equal(t, FrameTypeInvalid, class)
+	require.ErrorIs(t, err, io.EOF)
+	_, _, err = ClassifyFrameType([]byte{0x40})
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
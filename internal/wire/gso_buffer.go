@@ -0,0 +1,118 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// A GSOBuffer packs multiple packets back-to-back into a single buffer,
+// such that they can be sent using generic segmentation offload (GSO).
+// The kernel splits the buffer into segments of the segment size,
+// so every packet except for the last one needs to have exactly the segment size.
+type GSOBuffer struct {
+	buf         []byte
+	segmentSize protocol.ByteCount
+	segments    []protocol.ByteCount
+}
+
+// NewGSOBuffer creates a GSOBuffer appending to buf.
+// The capacity of buf limits the number of segments.
+func NewGSOBuffer(buf []byte, segmentSize protocol.ByteCount) *GSOBuffer {
+	return &GSOBuffer{buf: buf[:0], segmentSize: segmentSize}
+}
+
+// CanAppend says if another packet can be appended.
+// This is the case if all packets appended so far have the segment size,
+// and the buffer has enough capacity for another packet of the segment size.
+func (g *GSOBuffer) CanAppend() bool {
+	if n := len(g.segments); n > 0 && g.segments[n-1] != g.segmentSize {
+		return false
+	}
+	return protocol.ByteCount(cap(g.buf)-len(g.buf)) >= g.segmentSize
+}
+
+// AppendSegment appends a packet.
+// The append function is called with the buffer, and the maximum packet size.
+// It returns an error if the packet is larger than the segment size.
+func (g *GSOBuffer) AppendSegment(appendPacket func(b []byte, maxSize protocol.ByteCount) ([]byte, error)) error {
+	if !g.CanAppend() {
+		return fmt.Errorf("GSOBuffer: can't append another segment")
+	}
+	start := len(g.buf)
+	b, err := appendPacket(g.buf, g.segmentSize)
+	if err != nil {
+		return err
+	}
+	l := protocol.ByteCount(len(b) - start)
+	if l > g.segmentSize {
+		return fmt.Errorf("GSOBuffer: segment too large (%d bytes, segment size: %d bytes)", l, g.segmentSize)
+	}
+	if l == 0 {
+		return nil
+	}
+	g.buf = b
+	g.segments = append(g.segments, l)
+	return nil
+}
+
+// AppendPacket builds a packet using the PayloadBuilder, and appends it as a new segment.
+// The packet consists of the header hdr, followed by the payload, and is then sealed using seal.
+// hdr must be a short header: the Length field of a long header depends on the size of the payload,
+// which is only known after building it.
+// seal is passed the packet and the length of the header, and must grow the packet by exactly overhead bytes.
+// If the PayloadBuilder still has frames queued afterwards, the payload is padded,
+// such that the sealed packet has the segment size, and the next packet can be appended to the same buffer.
+// It returns the frames packed. If the PayloadBuilder doesn't have any frames to pack, no segment is appended.
+func (g *GSOBuffer) AppendPacket(
+	hdr []byte,
+	pb *PayloadBuilder,
+	overhead protocol.ByteCount,
+	seal func(packet []byte, hdrLen int) []byte,
+	v protocol.Version,
+) ([]Frame, error) {
+	if len(hdr) > 0 && IsLongHeaderPacket(hdr[0]) {
+		return nil, errors.New("GSOBuffer: AppendPacket only supports short header packets")
+	}
+	var frames []Frame
+	err := g.AppendSegment(func(b []byte, maxSize protocol.ByteCount) ([]byte, error) {
+		if protocol.ByteCount(len(hdr))+overhead >= maxSize {
+			return nil, fmt.Errorf("GSOBuffer: no space for a payload (header: %d bytes, overhead: %d bytes)", len(hdr), overhead)
+		}
+		start := len(b)
+		maxPayloadSize := maxSize - protocol.ByteCount(len(hdr)) - overhead
+		b = append(b, hdr...)
+		var err error
+		b, frames, err = pb.build(b, maxPayloadSize, v, true)
+		if err != nil {
+			return nil, err
+		}
+		if len(frames) == 0 {
+			return b[:start], nil
+		}
+		packetLen := len(b) - start
+		sealed := seal(b[start:], len(hdr))
+		// Otherwise, appending the sealed packet could exceed the capacity of the buffer.
+		if len(sealed) != packetLen+int(overhead) {
+			return nil, fmt.Errorf("GSOBuffer: sealing added %d bytes, expected %d bytes", len(sealed)-packetLen, overhead)
+		}
+		return append(b[:start], sealed...), nil
+	})
+	return frames, err
+}
+
+// Segments returns the sizes of the packets appended.
+func (g *GSOBuffer) Segments() []protocol.ByteCount {
+	return g.segments
+}
+
+// SegmentSize returns the segment size.
+func (g *GSOBuffer) SegmentSize() protocol.ByteCount {
+	return g.segmentSize
+}
+
+// Bytes returns the packets appended.
+func (g *GSOBuffer) Bytes() []byte {
+	return g.buf
+}
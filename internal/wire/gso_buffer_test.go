@@ -0,0 +1,158 @@
+package wire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGSOBufferAppendSegments(t *testing.T) {
+	g := NewGSOBuffer(make([]byte, 0, 250), 100)
+	require.Equal(t, protocol.ByteCount(100), g.SegmentSize())
+	require.True(t, g.CanAppend())
+	appendN := func(n int, c byte) func([]byte, protocol.ByteCount) ([]byte, error) {
+		return func(b []byte, maxSize protocol.ByteCount) ([]byte, error) {
+			require.Equal(t, protocol.ByteCount(100), maxSize)
+			for range n {
+				b = append(b, c)
+			}
+			return b, nil
+		}
+	}
+	require.NoError(t, g.AppendSegment(appendN(100, 'a')))
+	require.True(t, g.CanAppend())
+	require.NoError(t, g.AppendSegment(appendN(100, 'b')))
+	// only 50 bytes of capacity left
+	require.False(t, g.CanAppend())
+	require.Equal(t, []protocol.ByteCount{100, 100}, g.Segments())
+	require.Len(t, g.Bytes(), 200)
+	require.Equal(t, byte('a'), g.Bytes()[99])
+	require.Equal(t, byte('b'), g.Bytes()[100])
+}
+
+func TestGSOBufferShortSegmentIsLast(t *testing.T) {
+	g := NewGSOBuffer(make([]byte, 0, 1000), 100)
+	require.NoError(t, g.AppendSegment(func(b []byte, _ protocol.ByteCount) ([]byte, error) {
+		return append(b, make([]byte, 42)...), nil
+	}))
+	require.False(t, g.CanAppend())
+	err := g.AppendSegment(func(b []byte, _ protocol.ByteCount) ([]byte, error) { return b, nil })
+	require.EqualError(t, err, "GSOBuffer: can't append another segment")
+	require.Equal(t, []protocol.ByteCount{42}, g.Segments())
+}
+
+func TestGSOBufferSegmentTooLarge(t *testing.T) {
+	g := NewGSOBuffer(make([]byte, 0, 1000), 100)
+	err := g.AppendSegment(func(b []byte, _ protocol.ByteCount) ([]byte, error) {
+		return append(b, make([]byte, 101)...), nil
+	})
+	require.EqualError(t, err, "GSOBuffer: segment too large (101 bytes, segment size: 100 bytes)")
+	require.Empty(t, g.Segments())
+	require.Empty(t, g.Bytes())
+}
+
+func TestGSOBufferAppendPacket(t *testing.T) {
+	pb := NewPayloadBuilder(0)
+	var datagrams []Frame
+	for range 3 {
+		f := &DatagramFrame{Data: make([]byte, 60)}
+		datagrams = append(datagrams, f)
+		pb.Add(f, FramePriorityDatagram)
+	}
+
+	const overhead = 16
+	hdr := []byte("header")
+	// append a fake AEAD tag
+	seal := func(packet []byte, hdrLen int) []byte {
+		require.Equal(t, hdr, packet[:hdrLen])
+		return append(packet, bytes.Repeat([]byte{0xaa}, overhead)...)
+	}
+	g := NewGSOBuffer(make([]byte, 0, 1000), 100)
+	for i := range 3 {
+		require.True(t, g.CanAppend())
+		frames, err := g.AppendPacket(hdr, pb, overhead, seal, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, []Frame{datagrams[i]}, frames)
+	}
+	// the padding follows the frames, so they need a Length field
+	require.True(t, datagrams[0].(*DatagramFrame).DataLenPresent)
+	require.True(t, datagrams[1].(*DatagramFrame).DataLenPresent)
+	// the last packet is not padded
+	l := protocol.ByteCount(len(hdr)) + datagrams[2].Length(protocol.Version1) + overhead
+	require.Equal(t, []protocol.ByteCount{100, 100, l}, g.Segments())
+	require.False(t, g.CanAppend())
+
+	// every packet has the header, a payload that can be parsed on its own, and the AEAD tag
+	b := g.Bytes()
+	for i, segLen := range g.Segments() {
+		packet := b[:segLen]
+		require.Equal(t, hdr, packet[:len(hdr)])
+		require.Equal(t, bytes.Repeat([]byte{0xaa}, overhead), packet[len(packet)-overhead:])
+		requireParsesBack(t, packet[len(hdr):len(packet)-overhead], []Frame{datagrams[i]})
+		b = b[segLen:]
+	}
+	require.Empty(t, b)
+
+	// nothing left to pack
+	g = NewGSOBuffer(make([]byte, 0, 1000), 100)
+	frames, err := g.AppendPacket(hdr, pb, overhead, seal, protocol.Version1)
+	require.NoError(t, err)
+	require.Empty(t, frames)
+	require.Empty(t, g.Segments())
+	require.Empty(t, g.Bytes())
+}
+
+func TestGSOBufferAppendPacketNoSpaceForPayload(t *testing.T) {
+	pb := NewPayloadBuilder(0)
+	pb.Add(&PingFrame{}, FramePriorityControl)
+	g := NewGSOBuffer(make([]byte, 0, 1000), 20)
+	_, err := g.AppendPacket(make([]byte, 4), pb, 16, func(b []byte, _ int) []byte { return b }, protocol.Version1)
+	require.EqualError(t, err, "GSOBuffer: no space for a payload (header: 4 bytes, overhead: 16 bytes)")
+	require.Empty(t, g.Segments())
+}
+
+func TestGSOBufferAppendPacketPaddingBeforeLastFrame(t *testing.T) {
+	pb := NewPayloadBuilder(0)
+	// With a 2 byte Length field, this frame doesn't fit into the 78 byte payload.
+	datagram := &DatagramFrame{Data: make([]byte, 76)}
+	pb.Add(datagram, FramePriorityDatagram)
+	pb.Add(&PingFrame{}, FramePriorityDatagram)
+
+	const overhead = 16
+	hdr := []byte("header")
+	seal := func(packet []byte, _ int) []byte { return append(packet, make([]byte, overhead)...) }
+	g := NewGSOBuffer(make([]byte, 0, 1000), 100)
+	frames, err := g.AppendPacket(hdr, pb, overhead, seal, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, []Frame{datagram}, frames)
+	require.False(t, datagram.DataLenPresent)
+	require.Equal(t, []protocol.ByteCount{100}, g.Segments())
+	payload := g.Bytes()[len(hdr) : 100-overhead]
+	require.Zero(t, payload[0]) // PADDING
+	requireParsesBack(t, payload, frames)
+}
+
+func TestGSOBufferAppendPacketLongHeader(t *testing.T) {
+	pb := NewPayloadBuilder(0)
+	pb.Add(&PingFrame{}, FramePriorityControl)
+	g := NewGSOBuffer(make([]byte, 0, 1000), 100)
+	_, err := g.AppendPacket([]byte{0xc0, 0, 0, 0, 1}, pb, 16, func(b []byte, _ int) []byte { return b }, protocol.Version1)
+	require.EqualError(t, err, "GSOBuffer: AppendPacket only supports short header packets")
+	require.Empty(t, g.Segments())
+}
+
+func TestGSOBufferAppendPacketSealOverhead(t *testing.T) {
+	pb := NewPayloadBuilder(0)
+	pb.Add(&PingFrame{}, FramePriorityControl)
+	buf := make([]byte, 0, 100)
+	g := NewGSOBuffer(buf, 100)
+	// seal adds more bytes than announced
+	seal := func(packet []byte, _ int) []byte { return append(packet, make([]byte, 200)...) }
+	_, err := g.AppendPacket([]byte("header"), pb, 16, seal, protocol.Version1)
+	require.EqualError(t, err, "GSOBuffer: sealing added 200 bytes, expected 16 bytes")
+	require.Empty(t, g.Segments())
+	require.Equal(t, cap(buf), cap(g.Bytes()))
+}
@@ -1,6 +1,7 @@
 package wire
 
 import (
+	"crypto/rand"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -62,6 +63,97 @@ func ParseArbitraryLenConnectionIDs(data []byte) (bytesParsed int, dest, src pro
 	return startLen - len(data) + int(srcConnIDLen), destConnID, srcConnID, nil
 }
 
+// An InvariantHeader contains the version-independent fields of a QUIC packet header,
+// as described in Section 5 of RFC 8999.
+type InvariantHeader struct {
+	IsLongHeader bool
+	// The following fields are only set for Long Header packets.
+	// A Short Header packet doesn't contain a length for its Destination Connection ID,
+	// use ParseConnectionID to parse it.
+	Version          protocol.Version
+	DestConnectionID protocol.ArbitraryLenConnectionID
+	SrcConnectionID  protocol.ArbitraryLenConnectionID
+	// ParsedLen is the number of bytes of the invariant header.
+	ParsedLen int
+}
+
+// ParseInvariantHeader parses the version-independent fields of a packet header.
+// Unlike ParsePacket, it also parses packets of versions that we don't support,
+// which allows demultiplexing packets and sending Version Negotiation packets before the version is known.
+// The connection IDs reference the data passed in, and must be copied if they are retained.
+func ParseInvariantHeader(b []byte) (*InvariantHeader, error) {
+	if len(b) == 0 {
+		return nil, io.EOF
+	}
+	if !IsLongHeaderPacket(b[0]) {
+		return &InvariantHeader{ParsedLen: 1}, nil
+	}
+	c := newCursor(b)
+	if _, err := c.readBytes(1); err != nil { // first byte
+		return nil, io.EOF
+	}
+	v, err := c.readBytes(4)
+	if err != nil {
+		return nil, io.EOF
+	}
+	h := &InvariantHeader{
+		IsLongHeader: true,
+		Version:      protocol.Version(binary.BigEndian.Uint32(v)),
+	}
+	for _, connID := range []*protocol.ArbitraryLenConnectionID{&h.DestConnectionID, &h.SrcConnectionID} {
+		l, err := c.readByte()
+		if err != nil {
+			return nil, io.EOF
+		}
+		id, err := c.readBytes(uint64(l))
+		if err != nil {
+			return nil, io.EOF
+		}
+		*connID = id
+	}
+	h.ParsedLen = c.consumed()
+	return h, nil
+}
+
+// RequiresVersionNegotiation says if a server receiving this packet needs to respond with a Version Negotiation packet,
+// i.e. if it is a Long Header packet using a version that is not supported.
+// This includes packets using a reserved version (see section 6 of RFC 9000).
+func (h *InvariantHeader) RequiresVersionNegotiation() bool {
+	return h.IsLongHeader && h.Version != 0 && !protocol.IsSupportedVersion(protocol.SupportedVersions, h.Version)
+}
+
+// AppendUnknownVersionPacket appends a Long Header packet using the given version to b.
+// Only the version-independent part of the header is written, followed by the payload.
+// This is intended for composing packets using reserved versions (see protocol.GetReservedVersion),
+// in order to test version negotiation.
+// Note that servers only respond with a Version Negotiation packet to datagrams of at least 1200 bytes.
+func AppendUnknownVersionPacket(b []byte, v protocol.Version, dest, src protocol.ArbitraryLenConnectionID, payload []byte) []byte {
+	var typeByte [1]byte
+	_, _ = rand.Read(typeByte[:])
+	return appendUnknownVersionPacket(b, v, dest, src, payload, typeByte[0])
+}
+
+// AppendUnknownVersionPacketWithRand is like AppendUnknownVersionPacket,
+// but uses r as the source of randomness for the unused bits of the first byte.
+// It returns an error if reading from r fails.
+func AppendUnknownVersionPacketWithRand(b []byte, v protocol.Version, dest, src protocol.ArbitraryLenConnectionID, payload []byte, r io.Reader) ([]byte, error) {
+	var typeByte [1]byte
+	if _, err := io.ReadFull(r, typeByte[:]); err != nil {
+		return b, err
+	}
+	return appendUnknownVersionPacket(b, v, dest, src, payload, typeByte[0]), nil
+}
+
+func appendUnknownVersionPacket(b []byte, v protocol.Version, dest, src protocol.ArbitraryLenConnectionID, payload []byte, typeByte byte) []byte {
+	b = append(b, 0xc0|typeByte&0x3f)
+	b = binary.BigEndian.AppendUint32(b, uint32(v))
+	b = append(b, uint8(dest.Len()))
+	b = append(b, dest.Bytes()...)
+	b = append(b, uint8(src.Len()))
+	b = append(b, src.Bytes()...)
+	return append(b, payload...)
+}
+
 func IsPotentialQUICPacket(firstByte byte) bool {
 	return firstByte&0x40 > 0
 }
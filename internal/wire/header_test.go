@@ -129,6 +129,93 @@ func TestParseArbitraryLengthConnectionIDs(t *testing.T) {
 	}
 }
 
+func TestParseInvariantHeaderLongHeader(t *testing.T) {
+	dest := make(protocol.ArbitraryLenConnectionID, 100) // longer than allowed by QUIC v1
+	rand.Read(dest)
+	src := protocol.ArbitraryLenConnectionID{5, 6, 7}
+	b := []byte{0x80, 0x1a, 0x2a, 0x3a, 0x4a} // an unsupported version
+	b = append(b, uint8(dest.Len()))
+	b = append(b, dest.Bytes()...)
+	b = append(b, uint8(src.Len()))
+	b = append(b, src.Bytes()...)
+	l := len(b)
+	b = append(b, []byte("foobar")...) // add some payload
+
+	h, err := ParseInvariantHeader(b)
+	require.NoError(t, err)
+	require.True(t, h.IsLongHeader)
+	require.Equal(t, protocol.Version(0x1a2a3a4a), h.Version)
+	require.Equal(t, dest, h.DestConnectionID)
+	require.Equal(t, src, h.SrcConnectionID)
+	require.Equal(t, l, h.ParsedLen)
+
+	for i := range b[:l] {
+		_, err := ParseInvariantHeader(b[:i])
+		require.ErrorIs(t, err, io.EOF)
+	}
+}
+
+func TestParseInvariantHeaderShortHeader(t *testing.T) {
+	h, err := ParseInvariantHeader([]byte{0x40, 1, 2, 3, 4})
+	require.NoError(t, err)
+	require.Equal(t, &InvariantHeader{ParsedLen: 1}, h)
+}
+
+func TestComposeUnknownVersionPacket(t *testing.T) {
+	v := protocol.GetReservedVersion()
+	dest := protocol.ArbitraryLenConnectionID{1, 2, 3, 4}
+	src := protocol.ArbitraryLenConnectionID{5, 6, 7, 8, 9}
+	b := AppendUnknownVersionPacket(nil, v, dest, src, make([]byte, 1200))
+	require.True(t, IsLongHeaderPacket(b[0]))
+	require.True(t, IsPotentialQUICPacket(b[0]))
+
+	h, err := ParseInvariantHeader(b)
+	require.NoError(t, err)
+	require.Equal(t, v, h.Version)
+	require.Equal(t, dest, h.DestConnectionID)
+	require.Equal(t, src, h.SrcConnectionID)
+	require.Equal(t, len(b)-1200, h.ParsedLen)
+	require.True(t, h.RequiresVersionNegotiation())
+
+	// ParsePacket classifies the packet as using an unsupported version
+	hdr, _, _, err := ParsePacket(b)
+	require.ErrorIs(t, err, ErrUnsupportedVersion)
+	require.Equal(t, v, hdr.Version)
+}
+
+func TestComposeUnknownVersionPacketWithRand(t *testing.T) {
+	dest := protocol.ArbitraryLenConnectionID{1, 2, 3, 4}
+	b, err := AppendUnknownVersionPacketWithRand([]byte("foo"), 0x1a2a3a4a, dest, nil, []byte("bar"), bytes.NewReader([]byte{0xff}))
+	require.NoError(t, err)
+	require.Equal(t, []byte("foo"), b[:3])
+	require.Equal(t, AppendUnknownVersionPacket(nil, 0x1a2a3a4a, dest, nil, []byte("bar"))[1:], b[4:])
+	require.Equal(t, byte(0xff), b[3])
+
+	b, err = AppendUnknownVersionPacketWithRand([]byte("foo"), 0x1a2a3a4a, dest, nil, []byte("bar"), bytes.NewReader(nil))
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, []byte("foo"), b)
+}
+
+func TestInvariantHeaderRequiresVersionNegotiation(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		data     []byte
+		expected bool
+	}{
+		{name: "QUIC v1", data: AppendUnknownVersionPacket(nil, protocol.Version1, nil, nil, nil), expected: false},
+		{name: "QUIC v2", data: AppendUnknownVersionPacket(nil, protocol.Version2, nil, nil, nil), expected: false},
+		{name: "unknown version", data: AppendUnknownVersionPacket(nil, 0x42, nil, nil, nil), expected: true},
+		{name: "Version Negotiation packet", data: AppendUnknownVersionPacket(nil, 0, nil, nil, nil), expected: false},
+		{name: "short header", data: []byte{0x40, 1, 2, 3, 4}, expected: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := ParseInvariantHeader(tc.data)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, h.RequiresVersionNegotiation())
+		})
+	}
+}
+
 func TestIdentifyVersionNegotiationPackets(t *testing.T) {
 	require.True(t, IsVersionNegotiationPacket([]byte{0x80 | 0x56, 0, 0, 0, 0}))
 	require.False(t, IsVersionNegotiationPacket([]byte{0x56, 0, 0, 0, 0}))
@@ -0,0 +1,48 @@
+package wire
+
+import "github.com/quic-go/quic-go/internal/protocol"
+
+// A KeyPhaseTracker determines the key phase (i.e. the generation of 1-RTT keys) of a sequence of Short Header packets,
+// based on their key phase bit and packet number.
+// It implements the logic described in section 6 of RFC 9001:
+// A change of the key phase bit indicates a key update, unless the packet number is smaller than the packet number
+// of the first packet of the current key phase, in which case the packet was sent before the key update (i.e. it was reordered).
+// This allows the offline analyzer and key update tests to determine which keys are needed to decrypt a packet.
+type KeyPhaseTracker struct {
+	keyPhase protocol.KeyPhase
+	// the packet number of the first packet received in the current key phase
+	firstPacketNumber protocol.PacketNumber
+	started           bool
+}
+
+// KeyPhase returns the current key phase.
+func (t *KeyPhaseTracker) KeyPhase() protocol.KeyPhase {
+	return t.keyPhase
+}
+
+// Track processes the next packet.
+// It returns the key phase of the packet, and whether this packet initiated a key update.
+// It must only be called for packets that were successfully decrypted, since the key phase bit
+// and the packet number are protected by header protection.
+func (t *KeyPhaseTracker) Track(pn protocol.PacketNumber, kp protocol.KeyPhaseBit) (_ protocol.KeyPhase, isKeyUpdate bool) {
+	if !t.started {
+		t.started = true
+		t.firstPacketNumber = pn
+		if kp != t.keyPhase.Bit() {
+			// The first packet was sent after the peer already updated its keys.
+			t.keyPhase++
+		}
+		return t.keyPhase, false
+	}
+	if kp == t.keyPhase.Bit() {
+		t.firstPacketNumber = min(t.firstPacketNumber, pn)
+		return t.keyPhase, false
+	}
+	// a reordered packet from the previous key phase
+	if t.keyPhase > 0 && pn < t.firstPacketNumber {
+		return t.keyPhase - 1, false
+	}
+	t.keyPhase++
+	t.firstPacketNumber = pn
+	return t.keyPhase, true
+}
@@ -0,0 +1,47 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyPhaseTracker(t *testing.T) {
+	var tracker KeyPhaseTracker
+	for _, tc := range []struct {
+		pn               protocol.PacketNumber
+		kp               protocol.KeyPhaseBit
+		expectedKeyPhase protocol.KeyPhase
+		isKeyUpdate      bool
+	}{
+		{pn: 0, kp: protocol.KeyPhaseZero, expectedKeyPhase: 0},
+		{pn: 2, kp: protocol.KeyPhaseZero, expectedKeyPhase: 0},
+		{pn: 1, kp: protocol.KeyPhaseZero, expectedKeyPhase: 0},
+		{pn: 10, kp: protocol.KeyPhaseOne, expectedKeyPhase: 1, isKeyUpdate: true},
+		{pn: 11, kp: protocol.KeyPhaseOne, expectedKeyPhase: 1},
+		{pn: 5, kp: protocol.KeyPhaseZero, expectedKeyPhase: 0}, // reordered
+		{pn: 12, kp: protocol.KeyPhaseOne, expectedKeyPhase: 1},
+		{pn: 20, kp: protocol.KeyPhaseZero, expectedKeyPhase: 2, isKeyUpdate: true},
+		{pn: 15, kp: protocol.KeyPhaseOne, expectedKeyPhase: 1}, // reordered
+		{pn: 21, kp: protocol.KeyPhaseZero, expectedKeyPhase: 2},
+	} {
+		keyPhase, isKeyUpdate := tracker.Track(tc.pn, tc.kp)
+		require.Equal(t, tc.expectedKeyPhase, keyPhase, "packet %d", tc.pn)
+		require.Equal(t, tc.isKeyUpdate, isKeyUpdate, "packet %d", tc.pn)
+	}
+	require.Equal(t, protocol.KeyPhase(2), tracker.KeyPhase())
+}
+
+func TestKeyPhaseTrackerStartsAfterKeyUpdate(t *testing.T) {
+	var tracker KeyPhaseTracker
+	keyPhase, isKeyUpdate := tracker.Track(100, protocol.KeyPhaseOne)
+	require.Equal(t, protocol.KeyPhase(1), keyPhase)
+	require.False(t, isKeyUpdate)
+
+	// a packet from before the first key update
+	keyPhase, isKeyUpdate = tracker.Track(99, protocol.KeyPhaseZero)
+	require.Equal(t, protocol.KeyPhase(0), keyPhase)
+	require.False(t, isKeyUpdate)
+}
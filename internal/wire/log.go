@@ -8,11 +8,13 @@ import (
 	"github.com/quic-go/quic-go/internal/utils"
 )
 
-// LogFrame logs a frame, either sent or received
+// LogFrame logs a frame, either sent or received.
+// Sensitive frame contents are redacted according to LogRedactionMode.
 func LogFrame(logger utils.Logger, frame Frame, sent bool) {
-	if !logger.Debug() {
-		return
-	}
+	LogFrameRedacted(logger, frame, sent, LogRedactionMode)
+}
+
+func logFrame(logger utils.Logger, frame Frame, sent bool) {
 	dir := "<-"
 	if sent {
 		dir = "->"
@@ -168,7 +168,7 @@ func TestLogNewConnectionIDFrame(t *testing.T) {
 		ConnectionID:        protocol.ParseConnectionID([]byte{0xde, 0xad, 0xbe, 0xef}),
 		StatelessResetToken: protocol.StatelessResetToken{0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8, 0x9, 0xa, 0xb, 0xc, 0xd, 0xe, 0xf, 0x10},
 	}, false)
-	require.Contains(t, buf.String(), "\t<- &wire.NewConnectionIDFrame{SequenceNumber: 42, RetirePriorTo: 24, ConnectionID: deadbeef, StatelessResetToken: 0x0102030405060708090a0b0c0d0e0f10}")
+	require.Contains(t, buf.String(), "\t<- &wire.NewConnectionIDFrame{SequenceNumber: 42, RetirePriorTo: 24, ConnectionID: deadbeef, StatelessResetToken: 0x00000000000000000000000000000000}")
 }
 
 func TestLogRetireConnectionIDFrame(t *testing.T) {
@@ -184,5 +184,17 @@ func TestLogNewTokenFrame(t *testing.T) {
 	LogFrame(logger, &NewTokenFrame{
 		Token: []byte{0xde, 0xad, 0xbe, 0xef},
 	}, true)
+	require.Contains(t, buf.String(), "\t-> &wire.NewTokenFrame{Token: 0x00000000")
+	require.NotContains(t, buf.String(), "deadbeef")
+}
+
+func TestLogFrameRedactionNone(t *testing.T) {
+	orig := LogRedactionMode
+	t.Cleanup(func() { LogRedactionMode = orig })
+	LogRedactionMode = RedactionNone
+
+	buf := &bytes.Buffer{}
+	logger := setupLogTest(t, buf)
+	LogFrame(logger, &NewTokenFrame{Token: []byte{0xde, 0xad, 0xbe, 0xef}}, true)
 	require.Contains(t, buf.String(), "\t-> &wire.NewTokenFrame{Token: 0xdeadbeef")
 }
@@ -12,13 +12,12 @@ type MaxDataFrame struct {
 
 // parseMaxDataFrame parses a MAX_DATA frame
 func parseMaxDataFrame(b []byte, _ protocol.Version) (*MaxDataFrame, int, error) {
-	frame := &MaxDataFrame{}
-	byteOffset, l, err := quicvarint.Parse(b)
+	c := newCursor(b)
+	byteOffset, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	frame.MaximumData = protocol.ByteCount(byteOffset)
-	return frame, l, nil
+	return &MaxDataFrame{MaximumData: protocol.ByteCount(byteOffset)}, c.consumed(), nil
 }
 
 func (f *MaxDataFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
@@ -12,22 +12,20 @@ type MaxStreamDataFrame struct {
 }
 
 func parseMaxStreamDataFrame(b []byte, _ protocol.Version) (*MaxStreamDataFrame, int, error) {
-	startLen := len(b)
-	sid, l, err := quicvarint.Parse(b)
+	c := newCursor(b)
+	sid, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
-	offset, l, err := quicvarint.Parse(b)
+	offset, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
 
 	return &MaxStreamDataFrame{
 		StreamID:          protocol.StreamID(sid),
 		MaximumStreamData: protocol.ByteCount(offset),
-	}, startLen - len(b), nil
+	}, c.consumed(), nil
 }
 
 func (f *MaxStreamDataFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
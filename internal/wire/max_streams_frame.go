@@ -13,6 +13,21 @@ type MaxStreamsFrame struct {
 	MaxStreamNum protocol.StreamNum
 }
 
+// NewMaxStreamsFrame creates a MAX_STREAMS frame.
+func NewMaxStreamsFrame(stype protocol.StreamType, maxStreamNum protocol.StreamNum) (*MaxStreamsFrame, error) {
+	if err := protocol.ValidateStreamCount(maxStreamNum, stype); err != nil {
+		return nil, err
+	}
+	return &MaxStreamsFrame{Type: stype, MaxStreamNum: maxStreamNum}, nil
+}
+
+// NewMaxStreamsFrameForStreamID creates a MAX_STREAMS frame of the given stream type that allows the peer
+// to open all streams up to and including the given stream ID.
+// If id is protocol.InvalidStreamID, the peer isn't allowed to open any streams.
+func NewMaxStreamsFrameForStreamID(stype protocol.StreamType, id protocol.StreamID) *MaxStreamsFrame {
+	return &MaxStreamsFrame{Type: stype, MaxStreamNum: protocol.StreamCountForMaxStreamID(id)}
+}
+
 func parseMaxStreamsFrame(b []byte, typ uint64, _ protocol.Version) (*MaxStreamsFrame, int, error) {
 	f := &MaxStreamsFrame{}
 	switch typ {
@@ -21,15 +36,16 @@ func parseMaxStreamsFrame(b []byte, typ uint64, _ protocol.Version) (*MaxStreams
 	case uniMaxStreamsFrameType:
 		f.Type = protocol.StreamTypeUni
 	}
-	streamID, l, err := quicvarint.Parse(b)
+	c := newCursor(b)
+	streamID, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
 	f.MaxStreamNum = protocol.StreamNum(streamID)
 	if f.MaxStreamNum > protocol.MaxStreamCount {
 		return nil, 0, fmt.Errorf("%d exceeds the maximum stream count", f.MaxStreamNum)
 	}
-	return f, l, nil
+	return f, c.consumed(), nil
 }
 
 func (f *MaxStreamsFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
@@ -47,3 +63,9 @@ func (f *MaxStreamsFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
 func (f *MaxStreamsFrame) Length(protocol.Version) protocol.ByteCount {
 	return 1 + protocol.ByteCount(quicvarint.Len(uint64(f.MaxStreamNum)))
 }
+
+// MaxStreamID returns the highest stream ID that streams initiated by pers are allowed to use.
+// It returns protocol.InvalidStreamID if no streams are allowed.
+func (f *MaxStreamsFrame) MaxStreamID(pers protocol.Perspective) protocol.StreamID {
+	return f.MaxStreamNum.StreamID(f.Type, pers)
+}
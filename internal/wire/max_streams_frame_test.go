@@ -37,7 +37,7 @@ func TestParseMaxStreamsErrorsOnEOF(t *testing.T) {
 	require.Equal(t, len(data), l)
 	for i := range data {
 		_, _, err := parseMaxStreamsFrame(data[:i], typ, protocol.Version1)
-		require.Equal(t, io.EOF, err)
+		require.Equal(t, io.ErrUnexpectedEOF, err)
 	}
 }
 
@@ -115,3 +115,34 @@ func TestWriteMaxStreamsUnidirectional(t *testing.T) {
 	require.Equal(t, expected, b)
 	require.Len(t, b, int(f.Length(protocol.Version1)))
 }
+
+func TestNewMaxStreamsFrame(t *testing.T) {
+	f, err := NewMaxStreamsFrame(protocol.StreamTypeUni, 3)
+	require.NoError(t, err)
+	require.Equal(t, &MaxStreamsFrame{Type: protocol.StreamTypeUni, MaxStreamNum: 3}, f)
+	require.Equal(t, protocol.StreamID(10), f.MaxStreamID(protocol.PerspectiveClient))
+	require.Equal(t, protocol.StreamID(11), f.MaxStreamID(protocol.PerspectiveServer))
+
+	_, err = NewMaxStreamsFrame(protocol.StreamTypeBidi, protocol.MaxStreamCount+1)
+	require.EqualError(t, err, fmt.Sprintf("invalid stream count: %d (maximum %d)", protocol.MaxStreamCount+1, protocol.MaxStreamCount))
+	_, err = NewMaxStreamsFrame(42, 1)
+	require.EqualError(t, err, "invalid stream type: 42")
+
+	f, err = NewMaxStreamsFrame(protocol.StreamTypeBidi, 0)
+	require.NoError(t, err)
+	require.Equal(t, protocol.InvalidStreamID, f.MaxStreamID(protocol.PerspectiveClient))
+}
+
+func TestNewMaxStreamsFrameForStreamID(t *testing.T) {
+	for _, id := range []protocol.StreamID{0, 1, 2, 3, 4, 9, 1337} {
+		f := NewMaxStreamsFrameForStreamID(id.Type(), id)
+		require.Equal(t, id.Type(), f.Type)
+		require.Equal(t, id, f.MaxStreamID(id.InitiatedBy()))
+	}
+
+	for _, stype := range []protocol.StreamType{protocol.StreamTypeUni, protocol.StreamTypeBidi} {
+		f := NewMaxStreamsFrameForStreamID(stype, protocol.InvalidStreamID)
+		require.Equal(t, &MaxStreamsFrame{Type: stype, MaxStreamNum: 0}, f)
+		require.Equal(t, protocol.InvalidStreamID, f.MaxStreamID(protocol.PerspectiveServer))
+	}
+}
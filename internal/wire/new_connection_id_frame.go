@@ -3,7 +3,6 @@ package wire
 import (
 	"errors"
 	"fmt"
-	"io"
 
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/quicvarint"
@@ -18,46 +17,44 @@ type NewConnectionIDFrame struct {
 }
 
 func parseNewConnectionIDFrame(b []byte, _ protocol.Version) (*NewConnectionIDFrame, int, error) {
-	startLen := len(b)
-	seq, l, err := quicvarint.Parse(b)
+	c := newCursor(b)
+	seq, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
-	ret, l, err := quicvarint.Parse(b)
+	ret, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
 	if ret > seq {
 		//nolint:staticcheck // SA1021: Retire Prior To is the name of the field
 		return nil, 0, fmt.Errorf("Retire Prior To value (%d) larger than Sequence Number (%d)", ret, seq)
 	}
-	if len(b) == 0 {
-		return nil, 0, io.EOF
+	connIDLen, err := c.readByte()
+	if err != nil {
+		return nil, 0, err
 	}
-	connIDLen := int(b[0])
-	b = b[1:]
 	if connIDLen == 0 {
 		return nil, 0, errors.New("invalid zero-length connection ID")
 	}
 	if connIDLen > protocol.MaxConnIDLen {
 		return nil, 0, protocol.ErrInvalidConnectionIDLen
 	}
-	if len(b) < connIDLen {
-		return nil, 0, io.EOF
+	connID, err := c.readBytes(uint64(connIDLen))
+	if err != nil {
+		return nil, 0, err
 	}
 	frame := &NewConnectionIDFrame{
 		SequenceNumber: seq,
 		RetirePriorTo:  ret,
-		ConnectionID:   protocol.ParseConnectionID(b[:connIDLen]),
+		ConnectionID:   protocol.ParseConnectionID(connID),
 	}
-	b = b[connIDLen:]
-	if len(b) < len(frame.StatelessResetToken) {
-		return nil, 0, io.EOF
+	token, err := c.readBytes(uint64(len(frame.StatelessResetToken)))
+	if err != nil {
+		return nil, 0, err
 	}
-	copy(frame.StatelessResetToken[:], b)
-	return frame, startLen - len(b) + len(frame.StatelessResetToken), nil
+	copy(frame.StatelessResetToken[:], token)
+	return frame, c.consumed(), nil
 }
 
 func (f *NewConnectionIDFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
@@ -2,9 +2,10 @@ package wire
 
 import (
 	"errors"
-	"io"
+	"fmt"
 
 	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
 	"github.com/quic-go/quic-go/quicvarint"
 )
 
@@ -14,20 +15,21 @@ type NewTokenFrame struct {
 }
 
 func parseNewTokenFrame(b []byte, _ protocol.Version) (*NewTokenFrame, int, error) {
-	tokenLen, l, err := quicvarint.Parse(b)
+	c := newCursor(b)
+	tokenLen, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
 	if tokenLen == 0 {
 		return nil, 0, errors.New("token must not be empty")
 	}
-	if uint64(len(b)) < tokenLen {
-		return nil, 0, io.EOF
+	data, err := c.readBytes(tokenLen)
+	if err != nil {
+		return nil, 0, err
 	}
-	token := make([]byte, int(tokenLen))
-	copy(token, b)
-	return &NewTokenFrame{Token: token}, l + int(tokenLen), nil
+	token := make([]byte, len(data))
+	copy(token, data)
+	return &NewTokenFrame{Token: token}, c.consumed(), nil
 }
 
 func (f *NewTokenFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
@@ -41,3 +43,32 @@ func (f *NewTokenFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
 func (f *NewTokenFrame) Length(protocol.Version) protocol.ByteCount {
 	return 1 + protocol.ByteCount(quicvarint.Len(uint64(len(f.Token)))+len(f.Token))
 }
+
+// A newTokenBudget limits the number of NEW_TOKEN frames and the total number of token bytes
+// accepted over the lifetime of a connection.
+// This protects clients from servers sending large numbers of tokens in order to make them consume memory.
+type newTokenBudget struct {
+	maxFrames int // if 0, the number of frames is not limited
+	maxBytes  int // if 0, the number of token bytes is not limited
+
+	frames int
+	bytes  int
+}
+
+func (b *newTokenBudget) consume(f *NewTokenFrame) error {
+	b.frames++
+	b.bytes += len(f.Token)
+	if b.maxFrames > 0 && b.frames > b.maxFrames {
+		return &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			ErrorMessage: fmt.Sprintf("received more than %d NEW_TOKEN frames", b.maxFrames),
+		}
+	}
+	if b.maxBytes > 0 && b.bytes > b.maxBytes {
+		return &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			ErrorMessage: fmt.Sprintf("received more than %d bytes of NEW_TOKEN tokens", b.maxBytes),
+		}
+	}
+	return nil
+}
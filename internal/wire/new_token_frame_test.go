@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
 
 	"github.com/stretchr/testify/require"
 )
@@ -34,7 +35,7 @@ func TestParseNewTokenFrameErrorsOnEOFs(t *testing.T) {
 	require.Equal(t, len(data), l)
 	for i := range data {
 		_, _, err := parseNewTokenFrame(data[:i], protocol.Version1)
-		require.Equal(t, io.EOF, err)
+		require.Equal(t, io.ErrUnexpectedEOF, err)
 	}
 }
 
@@ -49,3 +50,30 @@ func TestWriteNewTokenFrame(t *testing.T) {
 	require.Equal(t, expected, b)
 	require.Equal(t, len(b), int(f.Length(protocol.Version1)))
 }
+
+func TestNewTokenBudgetLimitsFrames(t *testing.T) {
+	b := newTokenBudget{maxFrames: 2}
+	require.NoError(t, b.consume(&NewTokenFrame{Token: make([]byte, 1000)}))
+	require.NoError(t, b.consume(&NewTokenFrame{Token: make([]byte, 1000)}))
+	require.Equal(t, &qerr.TransportError{
+		ErrorCode:    qerr.ProtocolViolation,
+		ErrorMessage: "received more than 2 NEW_TOKEN frames",
+	}, b.consume(&NewTokenFrame{Token: []byte("foo")}))
+}
+
+func TestNewTokenBudgetLimitsBytes(t *testing.T) {
+	b := newTokenBudget{maxBytes: 10}
+	require.NoError(t, b.consume(&NewTokenFrame{Token: []byte("foo")}))
+	require.NoError(t, b.consume(&NewTokenFrame{Token: []byte("foobar")}))
+	require.Equal(t, &qerr.TransportError{
+		ErrorCode:    qerr.ProtocolViolation,
+		ErrorMessage: "received more than 10 bytes of NEW_TOKEN tokens",
+	}, b.consume(&NewTokenFrame{Token: []byte("ab")}))
+}
+
+func TestNewTokenBudgetUnlimited(t *testing.T) {
+	var b newTokenBudget
+	for range 100 {
+		require.NoError(t, b.consume(&NewTokenFrame{Token: make([]byte, 1000)}))
+	}
+}
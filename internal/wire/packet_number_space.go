@@ -0,0 +1,102 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+)
+
+// A PacketNumberSpace is a packet number space, see section 12.3 of RFC 9000.
+type PacketNumberSpace uint8
+
+const (
+	// PacketNumberSpaceInitial is the packet number space of Initial packets.
+	PacketNumberSpaceInitial PacketNumberSpace = iota
+	// PacketNumberSpaceHandshake is the packet number space of Handshake packets.
+	PacketNumberSpaceHandshake
+	// PacketNumberSpaceAppData is the packet number space of 0-RTT and 1-RTT packets.
+	PacketNumberSpaceAppData
+
+	numPacketNumberSpaces
+)
+
+// PacketNumberSpaceFromEncryptionLevel returns the packet number space of an encryption level.
+// 0-RTT and 1-RTT packets share the Application Data packet number space.
+func PacketNumberSpaceFromEncryptionLevel(encLevel protocol.EncryptionLevel) PacketNumberSpace {
+	switch encLevel {
+	case protocol.EncryptionInitial:
+		return PacketNumberSpaceInitial
+	case protocol.EncryptionHandshake:
+		return PacketNumberSpaceHandshake
+	case protocol.Encryption0RTT, protocol.Encryption1RTT:
+		return PacketNumberSpaceAppData
+	default:
+		panic(fmt.Sprintf("unexpected encryption level: %s", encLevel))
+	}
+}
+
+func (s PacketNumberSpace) String() string {
+	switch s {
+	case PacketNumberSpaceInitial:
+		return "initial"
+	case PacketNumberSpaceHandshake:
+		return "handshake"
+	case PacketNumberSpaceAppData:
+		return "application_data"
+	default:
+		return fmt.Sprintf("unknown packet number space (%d)", uint8(s))
+	}
+}
+
+// A PacketNumberSpaces tracks the largest packet number sent,
+// and the largest packet number acknowledged by the peer, per packet number space.
+// It is used by the FrameParser to validate ACK frames, see SetPacketNumberSpaces.
+// The zero value is not valid, use NewPacketNumberSpaces.
+type PacketNumberSpaces struct {
+	largestSent  [numPacketNumberSpaces]protocol.PacketNumber
+	largestAcked [numPacketNumberSpaces]protocol.PacketNumber
+}
+
+// NewPacketNumberSpaces creates a new PacketNumberSpaces.
+func NewPacketNumberSpaces() *PacketNumberSpaces {
+	s := &PacketNumberSpaces{}
+	for i := range numPacketNumberSpaces {
+		s.largestSent[i] = protocol.InvalidPacketNumber
+		s.largestAcked[i] = protocol.InvalidPacketNumber
+	}
+	return s
+}
+
+// SentPacket is called when a packet is sent.
+func (s *PacketNumberSpaces) SentPacket(space PacketNumberSpace, pn protocol.PacketNumber) {
+	s.largestSent[space] = max(s.largestSent[space], pn)
+}
+
+// LargestSent returns the largest packet number sent in a packet number space.
+// It returns protocol.InvalidPacketNumber if no packet was sent yet.
+func (s *PacketNumberSpaces) LargestSent(space PacketNumberSpace) protocol.PacketNumber {
+	return s.largestSent[space]
+}
+
+// LargestAcked returns the largest packet number acknowledged by the peer in a packet number space.
+// It returns protocol.InvalidPacketNumber if no packet was acknowledged yet.
+func (s *PacketNumberSpaces) LargestAcked(space PacketNumberSpace) protocol.PacketNumber {
+	return s.largestAcked[space]
+}
+
+// ReceivedAck validates an ACK frame received in a packet number space,
+// and updates the largest acknowledged packet number.
+// ACK frames acknowledging a packet that was never sent in this packet number space
+// are rejected with a PROTOCOL_VIOLATION (see section 13.1 of RFC 9000).
+func (s *PacketNumberSpaces) ReceivedAck(space PacketNumberSpace, ack *AckFrame) error {
+	largestAcked := ack.LargestAcked()
+	if largestAcked > s.largestSent[space] {
+		return &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			ErrorMessage: fmt.Sprintf("received ACK for an unsent packet in the %s packet number space", space),
+		}
+	}
+	s.largestAcked[space] = max(s.largestAcked[space], largestAcked)
+	return nil
+}
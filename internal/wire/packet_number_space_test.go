@@ -0,0 +1,81 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketNumberSpaceFromEncryptionLevel(t *testing.T) {
+	require.Equal(t, PacketNumberSpaceInitial, PacketNumberSpaceFromEncryptionLevel(protocol.EncryptionInitial))
+	require.Equal(t, PacketNumberSpaceHandshake, PacketNumberSpaceFromEncryptionLevel(protocol.EncryptionHandshake))
+	require.Equal(t, PacketNumberSpaceAppData, PacketNumberSpaceFromEncryptionLevel(protocol.Encryption0RTT))
+	require.Equal(t, PacketNumberSpaceAppData, PacketNumberSpaceFromEncryptionLevel(protocol.Encryption1RTT))
+	require.Panics(t, func() { PacketNumberSpaceFromEncryptionLevel(42) })
+
+	require.Equal(t, "initial", PacketNumberSpaceInitial.String())
+	require.Equal(t, "handshake", PacketNumberSpaceHandshake.String())
+	require.Equal(t, "application_data", PacketNumberSpaceAppData.String())
+	require.Equal(t, "unknown packet number space (42)", PacketNumberSpace(42).String())
+}
+
+func TestFrameParserPacketNumberSpaces(t *testing.T) {
+	spaces := NewPacketNumberSpaces()
+	spaces.SentPacket(PacketNumberSpaceHandshake, 10)
+	spaces.SentPacket(PacketNumberSpaceAppData, 100)
+	spaces.SentPacket(PacketNumberSpaceAppData, 50)
+	require.Equal(t, protocol.InvalidPacketNumber, spaces.LargestSent(PacketNumberSpaceInitial))
+	require.Equal(t, protocol.PacketNumber(100), spaces.LargestSent(PacketNumberSpaceAppData))
+
+	parser := NewFrameParser(true, true)
+	parser.SetPacketNumberSpaces(spaces)
+
+	for _, tc := range []struct {
+		encLevel     protocol.EncryptionLevel
+		largestAcked protocol.PacketNumber
+		valid        bool
+	}{
+		{encLevel: protocol.EncryptionInitial, largestAcked: 0, valid: false},
+		{encLevel: protocol.EncryptionHandshake, largestAcked: 10, valid: true},
+		{encLevel: protocol.EncryptionHandshake, largestAcked: 11, valid: false},
+		{encLevel: protocol.Encryption1RTT, largestAcked: 100, valid: true},
+		{encLevel: protocol.Encryption1RTT, largestAcked: 101, valid: false},
+	} {
+		b, err := (&AckFrame{
+			AckRanges: []AckRange{{Smallest: tc.largestAcked, Largest: tc.largestAcked}},
+		}).Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		_, _, err = parser.ParseNext(b, tc.encLevel, protocol.Version1)
+		if tc.valid {
+			require.NoError(t, err)
+			continue
+		}
+		require.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.ProtocolViolation, FrameType: ackFrameType})
+	}
+	require.Equal(t, protocol.InvalidPacketNumber, spaces.LargestAcked(PacketNumberSpaceInitial))
+	require.Equal(t, protocol.PacketNumber(10), spaces.LargestAcked(PacketNumberSpaceHandshake))
+	require.Equal(t, protocol.PacketNumber(100), spaces.LargestAcked(PacketNumberSpaceAppData))
+
+	// the largest acked packet number is never decreased
+	b, err := (&AckFrame{AckRanges: []AckRange{{Smallest: 5, Largest: 5}}}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	_, _, err = parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, protocol.PacketNumber(100), spaces.LargestAcked(PacketNumberSpaceAppData))
+}
+
+func TestFrameParserDiagnosticDoesntUpdatePacketNumberSpaces(t *testing.T) {
+	spaces := NewPacketNumberSpaces()
+	spaces.SentPacket(PacketNumberSpaceAppData, 100)
+	parser := NewFrameParser(true, true)
+	parser.SetPacketNumberSpaces(spaces)
+
+	b := appendFrames(t, &AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 42}}})
+	d := parser.ParseDiagnostic(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, d.Err)
+	require.Len(t, d.Frames, 1)
+	require.Equal(t, protocol.InvalidPacketNumber, spaces.LargestAcked(PacketNumberSpaceAppData))
+}
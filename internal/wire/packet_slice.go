@@ -0,0 +1,80 @@
+package wire
+
+import (
+	"errors"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// A PacketSlice describes a single QUIC packet contained in a UDP datagram.
+// It contains all information needed to remove header protection and decrypt the packet.
+type PacketSlice struct {
+	IsLongHeader bool
+	// Type is only set for Long Header packets.
+	Type protocol.PacketType
+	// Version is only set for Long Header packets.
+	Version          protocol.Version
+	DestConnectionID protocol.ConnectionID
+	// Offset is the offset of the packet in the datagram.
+	Offset int
+	// PayloadOffset is the offset of the (protected) packet number, relative to the start of the packet.
+	// For Retry packets, it is equal to the length of the packet.
+	PayloadOffset int
+	// Data contains the packet, and references the datagram.
+	Data []byte
+}
+
+// SplitCoalescedPackets splits a UDP datagram into the QUIC packets it contains (see section 12.2 of RFC 9000).
+// The Destination Connection ID of Short Header packets is parsed assuming a length of shortHeaderConnIDLen.
+// Short Header packets, Version Negotiation packets and Long Header packets of an unsupported version
+// extend to the end of the datagram.
+// If an error occurs, the packets parsed so far are returned along with the error.
+// For packets of an unsupported version, ErrUnsupportedVersion is returned.
+func SplitCoalescedPackets(datagram []byte, shortHeaderConnIDLen int) ([]PacketSlice, error) {
+	var packets []PacketSlice
+	var offset int
+	for offset < len(datagram) {
+		data := datagram[offset:]
+		if !IsLongHeaderPacket(data[0]) {
+			connID, err := ParseConnectionID(data, shortHeaderConnIDLen)
+			if err != nil {
+				return packets, err
+			}
+			return append(packets, PacketSlice{
+				DestConnectionID: connID,
+				Offset:           offset,
+				PayloadOffset:    1 + shortHeaderConnIDLen,
+				Data:             data,
+			}), nil
+		}
+		hdr, packetData, _, err := ParsePacket(data)
+		if err != nil {
+			if errors.Is(err, ErrUnsupportedVersion) && hdr != nil {
+				packets = append(packets, PacketSlice{
+					IsLongHeader:     true,
+					Version:          hdr.Version,
+					DestConnectionID: hdr.DestConnectionID,
+					Offset:           offset,
+					PayloadOffset:    int(hdr.ParsedLen()),
+					Data:             data,
+				})
+			}
+			return packets, err
+		}
+		// Version Negotiation packets don't have a length field.
+		if hdr.Version == 0 {
+			packetData = data
+		}
+		packets = append(packets, PacketSlice{
+			IsLongHeader:     true,
+			Type:             hdr.Type,
+			Version:          hdr.Version,
+			DestConnectionID: hdr.DestConnectionID,
+			Offset:           offset,
+			PayloadOffset:    int(hdr.ParsedLen()),
+			Data:             packetData,
+		})
+		offset += len(packetData)
+	}
+	return packets, nil
+}
@@ -0,0 +1,89 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func appendLongHeaderPacket(t *testing.T, b []byte, typ protocol.PacketType, connID protocol.ConnectionID, payload []byte) ([]byte, int) {
+	t.Helper()
+	start := len(b)
+	b, err := (&ExtendedHeader{
+		Header: Header{
+			Type:             typ,
+			DestConnectionID: connID,
+			Length:           protocol.ByteCount(2 + len(payload)),
+			Version:          protocol.Version1,
+		},
+		PacketNumber:    0x42,
+		PacketNumberLen: protocol.PacketNumberLen2,
+	}).Append(b, protocol.Version1)
+	require.NoError(t, err)
+	hdrLen := len(b) - start - 2
+	return append(b, payload...), hdrLen
+}
+
+func TestSplitCoalescedPackets(t *testing.T) {
+	connID := protocol.ParseConnectionID([]byte{1, 2, 3, 4})
+	b, initialHdrLen := appendLongHeaderPacket(t, nil, protocol.PacketTypeInitial, connID, []byte("initial"))
+	initialLen := len(b)
+	b, handshakeHdrLen := appendLongHeaderPacket(t, b, protocol.PacketTypeHandshake, connID, []byte("handshake"))
+	handshakeLen := len(b) - initialLen
+	b, err := AppendShortHeader(b, connID, 0x1337, protocol.PacketNumberLen2, protocol.KeyPhaseOne)
+	require.NoError(t, err)
+	b = append(b, []byte("1-RTT")...)
+
+	packets, err := SplitCoalescedPackets(b, connID.Len())
+	require.NoError(t, err)
+	require.Len(t, packets, 3)
+
+	require.True(t, packets[0].IsLongHeader)
+	require.Equal(t, protocol.PacketTypeInitial, packets[0].Type)
+	require.Equal(t, protocol.Version1, packets[0].Version)
+	require.Equal(t, connID, packets[0].DestConnectionID)
+	require.Zero(t, packets[0].Offset)
+	require.Equal(t, initialHdrLen, packets[0].PayloadOffset)
+	require.Equal(t, b[:initialLen], packets[0].Data)
+
+	require.Equal(t, protocol.PacketTypeHandshake, packets[1].Type)
+	require.Equal(t, initialLen, packets[1].Offset)
+	require.Equal(t, handshakeHdrLen, packets[1].PayloadOffset)
+	require.Len(t, packets[1].Data, handshakeLen)
+
+	require.False(t, packets[2].IsLongHeader)
+	require.Equal(t, connID, packets[2].DestConnectionID)
+	require.Equal(t, initialLen+handshakeLen, packets[2].Offset)
+	require.Equal(t, 1+connID.Len(), packets[2].PayloadOffset)
+	require.Equal(t, b[initialLen+handshakeLen:], packets[2].Data)
+}
+
+func TestSplitCoalescedPacketsUnsupportedVersion(t *testing.T) {
+	connID := protocol.ParseConnectionID([]byte{1, 2, 3, 4})
+	b, _ := appendLongHeaderPacket(t, nil, protocol.PacketTypeInitial, connID, []byte("initial"))
+	initialLen := len(b)
+	b = AppendUnknownVersionPacket(b, 0x1a2a3a4a, connID.Bytes(), nil, []byte("foobar"))
+
+	packets, err := SplitCoalescedPackets(b, connID.Len())
+	require.ErrorIs(t, err, ErrUnsupportedVersion)
+	require.Len(t, packets, 2)
+	require.Equal(t, protocol.PacketTypeInitial, packets[0].Type)
+	require.Equal(t, protocol.Version(0x1a2a3a4a), packets[1].Version)
+	require.Equal(t, initialLen, packets[1].Offset)
+	require.Equal(t, b[initialLen:], packets[1].Data)
+}
+
+func TestSplitCoalescedPacketsErrors(t *testing.T) {
+	connID := protocol.ParseConnectionID([]byte{1, 2, 3, 4})
+	b, _ := appendLongHeaderPacket(t, nil, protocol.PacketTypeInitial, connID, []byte("initial"))
+	initialLen := len(b)
+	b, _ = appendLongHeaderPacket(t, b, protocol.PacketTypeHandshake, connID, []byte("handshake"))
+
+	// the second packet is truncated
+	packets, err := SplitCoalescedPackets(b[:len(b)-1], connID.Len())
+	require.Error(t, err)
+	require.Len(t, packets, 1)
+	require.Equal(t, b[:initialLen], packets[0].Data)
+}
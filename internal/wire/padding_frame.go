@@ -0,0 +1,8 @@
+package wire
+
+// AppendPaddingFrames appends n PADDING frames.
+// Since a PADDING frame is a single 0x0 byte, this zero-fills n bytes in bulk.
+// The compiler recognizes this pattern, and doesn't allocate the temporary slice.
+func AppendPaddingFrames(b []byte, n int) []byte {
+	return append(b, make([]byte, n)...)
+}
@@ -0,0 +1,52 @@
+package wire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendPaddingFrames(t *testing.T) {
+	b := AppendPaddingFrames([]byte("foo"), 10)
+	require.Equal(t, append([]byte("foo"), make([]byte, 10)...), b)
+	require.Equal(t, []byte("foo"), AppendPaddingFrames([]byte("foo"), 0))
+
+	// reused buffers are zeroed
+	buf := bytes.Repeat([]byte{0xff}, 20)
+	b = AppendPaddingFrames(buf[:5], 10)
+	require.Len(t, b, 15)
+	require.Equal(t, make([]byte, 10), b[5:])
+	require.Equal(t, byte(0xff), buf[15])
+
+	parser := NewFrameParser(true, true)
+	l, f, err := parser.ParseNext(b[5:], protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Nil(t, f)
+	require.Equal(t, 10, l)
+}
+
+func BenchmarkAppendPaddingFrames(b *testing.B) {
+	// padding an Initial packet to the minimum size
+	const paddingLen = protocol.MinInitialPacketSize
+	buf := make([]byte, 0, protocol.MinInitialPacketSize)
+
+	b.Run("bulk", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			buf = AppendPaddingFrames(buf[:0], paddingLen)
+		}
+	})
+
+	b.Run("byte by byte", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			buf = buf[:0]
+			for range paddingLen {
+				buf = append(buf, 0)
+			}
+		}
+	})
+}
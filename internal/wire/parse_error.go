@@ -0,0 +1,221 @@
+package wire
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// maxAttributedFields is the maximum number of fields decoded for a FrameErrorAttribution.
+// No frame defined in RFC 9000 has more than 4 varint fields before variable-length data.
+const maxAttributedFields = 4
+
+// A FrameErrorAttribution describes the frame that caused a parsing error.
+type FrameErrorAttribution struct {
+	FrameType uint64
+	// Offset is the offset of the frame type in the payload.
+	Offset int
+	// Raw contains the bytes of the offending frame, starting with the frame type.
+	// Since the length of a malformed frame can't be determined, it extends to the end of the payload.
+	Raw []byte
+	// Fields contains the varint-encoded fields following the frame type,
+	// up to the first field that couldn't be decoded, and at most 4 fields.
+	// Note that not every frame exclusively consists of varints.
+	Fields []uint64
+}
+
+func (a *FrameErrorAttribution) String() string {
+	return fmt.Sprintf("%s frame at offset %d (fields: %v): %x", FrameType(a.FrameType), a.Offset, a.Fields, a.Raw)
+}
+
+// AttributeError locates the frame that caused the error returned when parsing the payload,
+// and decodes as much of it as possible.
+// It must be called with the same payload, encryption level and version that were passed to ParseNext.
+// It returns false if the error is not a frame parsing error.
+// The state of the FrameParser is not modified.
+func (p *FrameParser) AttributeError(payload []byte, encLevel protocol.EncryptionLevel, v protocol.Version, err error) (*FrameErrorAttribution, bool) {
+	var transportErr *qerr.TransportError
+	if !errors.As(err, &transportErr) || transportErr.ErrorCode != qerr.FrameEncodingError {
+		return nil, false
+	}
+
+	a, _ := p.parseAll(payload, encLevel, v, func(_ uint64, f Frame) error {
+		if sf, ok := f.(*StreamFrame); ok {
+			sf.PutBack()
+		}
+		return nil
+	})
+	return a, a != nil
+}
+
+// A QlogFrameError is a frame parsing error that exposes the fields needed to emit a qlog event,
+// such that the tracer doesn't need to parse the error string.
+type QlogFrameError interface {
+	error
+	// QlogTrigger is the trigger, using the qlog name of the transport error code (e.g. "frame_encoding_error").
+	QlogTrigger() string
+	// FrameTypeName is the name of the type of the frame that caused the error (e.g. "STREAM").
+	FrameTypeName() string
+	// FrameOffset is the offset of the frame that caused the error in the packet payload.
+	FrameOffset() int
+}
+
+// A FrameParsingError is returned when parsing a payload fails.
+// It wraps the transport error used to close the connection.
+type FrameParsingError struct {
+	Err *qerr.TransportError
+	// Offset is the offset of the frame type in the payload.
+	// For errors returned by ParseNext, it is the offset in the data passed to ParseNext.
+	Offset int
+}
+
+var _ QlogFrameError = &FrameParsingError{}
+
+func (e *FrameParsingError) Error() string { return e.Err.Error() }
+func (e *FrameParsingError) Unwrap() error { return e.Err }
+
+func (e *FrameParsingError) QlogTrigger() string {
+	return TransportErrorName(e.Err.ErrorCode)
+}
+
+func (e *FrameParsingError) FrameTypeName() string {
+	return FrameType(e.Err.FrameType).String()
+}
+
+func (e *FrameParsingError) FrameOffset() int { return e.Offset }
+
+// A ParseDiagnostic is the result of parsing a payload in diagnostics mode.
+type ParseDiagnostic struct {
+	// Frames contains all frames that were successfully parsed.
+	Frames []Frame
+	// Err is the parsing error, if any.
+	// Errors caused by a frame are of type *FrameParsingError.
+	Err error
+	// Failure describes the frame that caused the parsing error.
+	// It is only set if Err is set.
+	Failure *FrameErrorAttribution
+	// PathChallenges and PathResponses are the number of PATH_CHALLENGE and PATH_RESPONSE frames in the payload.
+	PathChallenges int
+	PathResponses  int
+}
+
+// ErrParseBudgetExceeded is returned when parsing is aborted because the ParseBudget was exhausted.
+var ErrParseBudgetExceeded = errors.New("parse budget exceeded")
+
+// A ParseBudget limits the amount of work done when parsing a payload.
+type ParseBudget struct {
+	// MaxFrames is the maximum number of frames parsed, excluding PADDING frames.
+	// If 0, the number of frames is not limited.
+	MaxFrames int
+	// MaxBytes is the maximum number of bytes of parsed frames, excluding PADDING frames.
+	// If 0, the number of bytes is not limited.
+	MaxBytes protocol.ByteCount
+}
+
+// ParseDiagnostic parses the whole payload.
+// Unlike ParseNext, it doesn't discard the frames parsed before a parsing error occurs.
+// This is useful for analysis tools, and it shouldn't be used on the hot path:
+// ACK frames are copied, and the state of the FrameParser is not modified.
+func (p *FrameParser) ParseDiagnostic(payload []byte, encLevel protocol.EncryptionLevel, v protocol.Version) *ParseDiagnostic {
+	return p.ParseDiagnosticContext(context.Background(), payload, encLevel, v, ParseBudget{})
+}
+
+// ParseDiagnosticContext is like ParseDiagnostic, but aborts parsing when the context is canceled,
+// or when the budget is exhausted.
+// In that case, Frames contains the frames parsed so far, and Err is set to the context's error
+// or to ErrParseBudgetExceeded, respectively.
+func (p *FrameParser) ParseDiagnosticContext(ctx context.Context, payload []byte, encLevel protocol.EncryptionLevel, v protocol.Version, budget ParseBudget) *ParseDiagnostic {
+	var d ParseDiagnostic
+	var parsedBytes protocol.ByteCount
+	d.Failure, d.Err = p.parseAll(payload, encLevel, v, func(_ uint64, f Frame) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		parsedBytes += f.Length(v)
+		if (budget.MaxFrames > 0 && len(d.Frames) >= budget.MaxFrames) || (budget.MaxBytes > 0 && parsedBytes > budget.MaxBytes) {
+			return ErrParseBudgetExceeded
+		}
+		switch frame := f.(type) {
+		case *AckFrame:
+			f = frame.Clone()
+		case *PathChallengeFrame:
+			d.PathChallenges++
+		case *PathResponseFrame:
+			d.PathResponses++
+		}
+		d.Frames = append(d.Frames, f)
+		return nil
+	})
+	return &d
+}
+
+// parseAll parses all frames in the payload, and calls fn for every frame.
+// PADDING frames are skipped.
+// If fn returns an error, parsing is aborted and the error is returned.
+// It uses a copy of the parser (see reparser), so the state of p is not modified.
+func (p *FrameParser) parseAll(payload []byte, encLevel protocol.EncryptionLevel, v protocol.Version, fn func(typ uint64, f Frame) error) (*FrameErrorAttribution, error) {
+	parser := p.reparser()
+	var offset int
+	for offset < len(payload) {
+		for offset < len(payload) && payload[offset] == 0x0 { // skip PADDING frames
+			offset++
+		}
+		if offset == len(payload) {
+			break
+		}
+		frame, l, err := parser.parseNext(payload[offset:], encLevel, v)
+		if err != nil {
+			parseErr := err.(*FrameParsingError)
+			parseErr.Offset += offset
+			return newFrameErrorAttribution(payload[parseErr.Offset:], parseErr.Offset), parseErr
+		}
+		// parseNext succeeded, so the frame type can be decoded
+		typ, _, _ := quicvarint.Parse(payload[offset:])
+		if err := fn(typ, frame); err != nil {
+			return nil, err
+		}
+		// A frame without a Length field extends to the end of the packet (see section 12.4 of RFC 9000).
+		// There's no need to check for trailing data: the STREAM and DATAGRAM parsers consume the rest of the payload.
+		offset += l
+	}
+	return nil, nil
+}
+
+// reparser returns a parser with the same configuration as p, that doesn't share any state with p:
+// The ACK frame held by the caller isn't overwritten, and the largest acknowledged packet numbers are not updated.
+// Middlewares are not applied, since they might have side effects.
+func (p *FrameParser) reparser() *FrameParser {
+	c := p.Clone()
+	c.middlewares = nil
+	c.parse = nil
+	c.onFrameParsed = nil
+	c.newTokenBudget = p.newTokenBudget
+	if p.pnSpaces != nil {
+		pnSpaces := *p.pnSpaces
+		c.pnSpaces = &pnSpaces
+	}
+	return c
+}
+
+func newFrameErrorAttribution(b []byte, offset int) *FrameErrorAttribution {
+	a := &FrameErrorAttribution{Offset: offset, Raw: b}
+	typ, l, err := quicvarint.Parse(b)
+	if err != nil {
+		return a
+	}
+	a.FrameType = typ
+	b = b[l:]
+	for len(b) > 0 && len(a.Fields) < maxAttributedFields {
+		field, l, err := quicvarint.Parse(b)
+		if err != nil {
+			break
+		}
+		a.Fields = append(a.Fields, field)
+		b = b[l:]
+	}
+	return a
+}
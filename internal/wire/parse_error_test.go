@@ -0,0 +1,237 @@
+package wire
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameParserAttributeError(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	b, err := (&PingFrame{}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	b, err = (&StreamFrame{StreamID: 4, Data: []byte("foobar"), DataLenPresent: true}).Append(b, protocol.Version1)
+	require.NoError(t, err)
+	b = append(b, 0, 0) // PADDING
+	offset := len(b)
+	// a MAX_STREAM_DATA frame that's missing the maximum stream data
+	b = quicvarint.Append(b, maxStreamDataFrameType)
+	b = quicvarint.Append(b, 1337)
+
+	var parseErr error
+	for data := b; len(data) > 0; {
+		l, _, err := parser.ParseNext(data, protocol.Encryption1RTT, protocol.Version1)
+		if err != nil {
+			parseErr = err
+			break
+		}
+		data = data[l:]
+	}
+	require.Error(t, parseErr)
+
+	a, ok := parser.AttributeError(b, protocol.Encryption1RTT, protocol.Version1, parseErr)
+	require.True(t, ok)
+	require.Equal(t, uint64(maxStreamDataFrameType), a.FrameType)
+	require.Equal(t, offset, a.Offset)
+	require.Equal(t, b[offset:], a.Raw)
+	require.Equal(t, []uint64{1337}, a.Fields)
+}
+
+func TestFrameParserAttributeErrorEncryptionLevel(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	b, err := (&MaxDataFrame{MaximumData: 0x42}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	_, _, parseErr := parser.ParseNext(b, protocol.EncryptionInitial, protocol.Version1)
+	require.Error(t, parseErr)
+
+	a, ok := parser.AttributeError(b, protocol.EncryptionInitial, protocol.Version1, parseErr)
+	require.True(t, ok)
+	require.Equal(t, uint64(maxDataFrameType), a.FrameType)
+	require.Zero(t, a.Offset)
+	require.Equal(t, []uint64{0x42}, a.Fields)
+}
+
+func TestFrameParserAttributeErrorDoesntModifyState(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	spaces := NewPacketNumberSpaces()
+	spaces.SentPacket(PacketNumberSpaceAppData, 100)
+	parser.SetPacketNumberSpaces(spaces)
+	var calls, parsed int
+	parser.SetOnFrameParsed(func(FrameType, int) { parsed++ })
+	parser.Use(func(next ParseFunc) ParseFunc {
+		return func(b []byte, typ uint64, encLevel protocol.EncryptionLevel, v protocol.Version) (Frame, int, error) {
+			calls++
+			return next(b, typ, encLevel, v)
+		}
+	})
+
+	b := appendFrames(t, &AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 42}}})
+	b = append(b, maxDataFrameType) // truncated MAX_DATA frame
+	l, _, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	_, _, parseErr := parser.ParseNext(b[l:], protocol.Encryption1RTT, protocol.Version1)
+	require.Error(t, parseErr)
+	require.Equal(t, 2, calls)
+	require.Equal(t, 1, parsed)
+	require.Equal(t, protocol.PacketNumber(42), spaces.LargestAcked(PacketNumberSpaceAppData))
+
+	a, ok := parser.AttributeError(b, protocol.Encryption1RTT, protocol.Version1, parseErr)
+	require.True(t, ok)
+	require.Equal(t, uint64(maxDataFrameType), a.FrameType)
+	require.Equal(t, 2, calls)
+	require.Equal(t, 1, parsed)
+	require.Equal(t, protocol.PacketNumber(42), spaces.LargestAcked(PacketNumberSpaceAppData))
+}
+
+func TestFrameParserAttributeErrorNotAParsingError(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	_, ok := parser.AttributeError([]byte{pingFrameType}, protocol.Encryption1RTT, protocol.Version1, errors.New("foobar"))
+	require.False(t, ok)
+}
+
+func TestFrameParserParseDiagnostic(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	ack1 := &AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}}
+	ack2 := &AckFrame{AckRanges: []AckRange{{Smallest: 20, Largest: 30}}}
+	b, err := ack1.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	b, err = ack2.Append(b, protocol.Version1)
+	require.NoError(t, err)
+	b, err = (&MaxDataFrame{MaximumData: 1337}).Append(b, protocol.Version1)
+	require.NoError(t, err)
+	offset := len(b)
+	b = append(b, newTokenFrameType, 10, 'f', 'o', 'o') // token too short
+
+	d := parser.ParseDiagnostic(b, protocol.Encryption1RTT, protocol.Version1)
+	require.Error(t, d.Err)
+	require.Equal(t, []Frame{ack1, ack2, &MaxDataFrame{MaximumData: 1337}}, d.Frames)
+	require.NotNil(t, d.Failure)
+	require.Equal(t, uint64(newTokenFrameType), d.Failure.FrameType)
+	require.Equal(t, offset, d.Failure.Offset)
+
+	d = parser.ParseDiagnostic(b[:offset], protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, d.Err)
+	require.Nil(t, d.Failure)
+	require.Len(t, d.Frames, 3)
+}
+
+func TestFrameParserParseDiagnosticCountsPathFrames(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	var b []byte
+	for range 3 {
+		var err error
+		b, err = (&PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}).Append(b, protocol.Version1)
+		require.NoError(t, err)
+	}
+	b, err := (&PathResponseFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}).Append(b, protocol.Version1)
+	require.NoError(t, err)
+
+	d := parser.ParseDiagnostic(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, d.Err)
+	require.Equal(t, 3, d.PathChallenges)
+	require.Equal(t, 1, d.PathResponses)
+
+	parser.SetMaxPathFramesPerPayload(3)
+	d = parser.ParseDiagnostic(b, protocol.Encryption1RTT, protocol.Version1)
+	require.Equal(t, &FrameParsingError{
+		Err: &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			FrameType:    pathResponseFrameType,
+			ErrorMessage: "received more than 3 PATH_CHALLENGE / PATH_RESPONSE frames in a packet",
+		},
+		Offset: 27,
+	}, d.Err)
+	require.Equal(t, 3, d.PathChallenges)
+	require.Zero(t, d.PathResponses)
+}
+
+func TestFrameParserNoLengthFrameConsumesPayload(t *testing.T) {
+	// everything following a frame without a Length field is part of that frame
+	for _, frame := range []Frame{
+		&DatagramFrame{Data: []byte("foo")},
+		&StreamFrame{StreamID: 4, Data: []byte("foo")},
+	} {
+		b, err := frame.Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		b = append(b, pingFrameType, pingFrameType)
+
+		parser := NewFrameParser(true, true)
+		l, f, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, len(b), l)
+		require.True(t, ExtendsToEndOfPacket(f))
+
+		d := parser.ParseDiagnostic(b, protocol.Encryption1RTT, protocol.Version1)
+		require.NoError(t, d.Err)
+		require.Len(t, d.Frames, 1)
+		switch f := d.Frames[0].(type) {
+		case *DatagramFrame:
+			require.Equal(t, []byte{'f', 'o', 'o', pingFrameType, pingFrameType}, f.Data)
+		case *StreamFrame:
+			require.Equal(t, []byte{'f', 'o', 'o', pingFrameType, pingFrameType}, f.Data)
+		}
+	}
+}
+
+func TestFrameParserParseDiagnosticBudget(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	var b []byte
+	for i := range 5 {
+		var err error
+		b, err = (&MaxDataFrame{MaximumData: protocol.ByteCount(i + 1)}).Append(b, protocol.Version1)
+		require.NoError(t, err)
+	}
+
+	t.Run("frames", func(t *testing.T) {
+		d := parser.ParseDiagnosticContext(context.Background(), b, protocol.Encryption1RTT, protocol.Version1, ParseBudget{MaxFrames: 3})
+		require.ErrorIs(t, d.Err, ErrParseBudgetExceeded)
+		require.Nil(t, d.Failure)
+		require.Equal(t, []Frame{&MaxDataFrame{MaximumData: 1}, &MaxDataFrame{MaximumData: 2}, &MaxDataFrame{MaximumData: 3}}, d.Frames)
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		// every MAX_DATA frame is 2 bytes long
+		d := parser.ParseDiagnosticContext(context.Background(), b, protocol.Encryption1RTT, protocol.Version1, ParseBudget{MaxBytes: 5})
+		require.ErrorIs(t, d.Err, ErrParseBudgetExceeded)
+		require.Equal(t, []Frame{&MaxDataFrame{MaximumData: 1}, &MaxDataFrame{MaximumData: 2}}, d.Frames)
+	})
+
+	t.Run("sufficient budget", func(t *testing.T) {
+		d := parser.ParseDiagnosticContext(context.Background(), b, protocol.Encryption1RTT, protocol.Version1, ParseBudget{MaxFrames: 5, MaxBytes: 10})
+		require.NoError(t, d.Err)
+		require.Len(t, d.Frames, 5)
+	})
+}
+
+func TestFrameParserParseDiagnosticContextCanceled(t *testing.T) {
+	parser := NewFrameParser(true, true)
+	b, err := (&PingFrame{}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d := parser.ParseDiagnosticContext(ctx, b, protocol.Encryption1RTT, protocol.Version1, ParseBudget{})
+	require.ErrorIs(t, d.Err, context.Canceled)
+	require.Nil(t, d.Failure)
+	require.Empty(t, d.Frames)
+}
+
+func TestFrameParsingErrorQlogFields(t *testing.T) {
+	b, err := (&PingFrame{}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	b, err = (&StreamFrame{StreamID: 4, Data: []byte("foobar"), DataLenPresent: true}).Append(b, protocol.Version1)
+	require.NoError(t, err)
+
+	d := NewFrameParser(false, false).ParseDiagnostic(b[:len(b)-1], protocol.Encryption1RTT, protocol.Version1)
+	var qlogErr QlogFrameError
+	require.ErrorAs(t, d.Err, &qlogErr)
+	require.Equal(t, "frame_encoding_error", qlogErr.QlogTrigger())
+	require.Equal(t, "STREAM", qlogErr.FrameTypeName())
+	require.Equal(t, 1, qlogErr.FrameOffset())
+	require.ErrorIs(t, d.Err, &qerr.TransportError{ErrorCode: qerr.FrameEncodingError, FrameType: 0xa})
+}
@@ -0,0 +1,34 @@
+package wire
+
+import (
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// ReceiveMetadata describes the packet that a frame was received in.
+type ReceiveMetadata struct {
+	RcvTime time.Time
+	ECN     protocol.ECN
+}
+
+// A ParsedFrame is a frame, together with the metadata of the packet it was received in.
+// This allows processing that depends on the packet (e.g. RTT sampling, ECN accounting)
+// to happen after parsing, without looking up the packet in a separate data structure.
+type ParsedFrame struct {
+	Frame Frame
+	// Space is the packet number space of the packet the frame was received in.
+	Space PacketNumberSpace
+	ReceiveMetadata
+}
+
+// ParseNextWithMetadata is like ParseNext, but attaches the metadata of the packet to the frame,
+// and tags it with the packet number space of the encryption level.
+// If ParseNext returns a nil frame, the Frame of the ParsedFrame is nil.
+func (p *FrameParser) ParseNextWithMetadata(data []byte, encLevel protocol.EncryptionLevel, v protocol.Version, meta ReceiveMetadata) (int, ParsedFrame, error) {
+	l, f, err := p.ParseNext(data, encLevel, v)
+	if err != nil {
+		return l, ParsedFrame{}, err
+	}
+	return l, ParsedFrame{Frame: f, Space: PacketNumberSpaceFromEncryptionLevel(encLevel), ReceiveMetadata: meta}, nil
+}
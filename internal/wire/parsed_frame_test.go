@@ -0,0 +1,34 @@
+package wire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNextWithMetadata(t *testing.T) {
+	meta := ReceiveMetadata{RcvTime: time.Now(), ECN: protocol.ECNCE}
+	b := appendFrames(t, &PingFrame{}, &MaxDataFrame{MaximumData: 1337})
+
+	p := NewFrameParser(true, true)
+	l, f, err := p.ParseNextWithMetadata(b, protocol.Encryption1RTT, protocol.Version1, meta)
+	require.NoError(t, err)
+	require.Equal(t, 1, l)
+	require.Equal(t, ParsedFrame{Frame: &PingFrame{}, Space: PacketNumberSpaceAppData, ReceiveMetadata: meta}, f)
+	_, f, err = p.ParseNextWithMetadata(b[l:], protocol.Encryption1RTT, protocol.Version1, meta)
+	require.NoError(t, err)
+	require.Equal(t, &MaxDataFrame{MaximumData: 1337}, f.Frame)
+	require.Equal(t, meta.RcvTime, f.RcvTime)
+	require.Equal(t, protocol.ECNCE, f.ECN)
+
+	_, f, err = p.ParseNextWithMetadata(b, protocol.EncryptionHandshake, protocol.Version1, meta)
+	require.NoError(t, err)
+	require.Equal(t, PacketNumberSpaceHandshake, f.Space)
+
+	_, _, err = p.ParseNextWithMetadata([]byte{maxDataFrameType}, protocol.Encryption1RTT, protocol.Version1, meta)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: maxDataFrameType, ErrorCode: qerr.FrameEncodingError})
+}
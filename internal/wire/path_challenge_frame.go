@@ -11,13 +11,26 @@ type PathChallengeFrame struct {
 	Data [8]byte
 }
 
-func parsePathChallengeFrame(b []byte, _ protocol.Version) (*PathChallengeFrame, int, error) {
+// NewPathChallengeFrame creates a PATH_CHALLENGE frame with data read from r.
+// Usually, r is crypto/rand.Reader.
+// It returns an error if reading from r fails.
+func NewPathChallengeFrame(r io.Reader) (*PathChallengeFrame, error) {
 	f := &PathChallengeFrame{}
-	if len(b) < 8 {
-		return nil, 0, io.EOF
+	if _, err := io.ReadFull(r, f.Data[:]); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func parsePathChallengeFrame(b []byte, _ protocol.Version) (*PathChallengeFrame, int, error) {
+	c := newCursor(b)
+	data, err := c.readBytes(8)
+	if err != nil {
+		return nil, 0, err
 	}
-	copy(f.Data[:], b)
-	return f, 8, nil
+	f := &PathChallengeFrame{}
+	copy(f.Data[:], data)
+	return f, c.consumed(), nil
 }
 
 func (f *PathChallengeFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
@@ -1,6 +1,7 @@
 package wire
 
 import (
+	"bytes"
 	"io"
 	"testing"
 
@@ -24,7 +25,7 @@ func TestParsePathChallengeErrorsOnEOFs(t *testing.T) {
 	require.Equal(t, len(data), l)
 	for i := range data {
 		_, _, err := parsePathChallengeFrame(data[:i], protocol.Version1)
-		require.Equal(t, io.EOF, err)
+		require.Equal(t, io.ErrUnexpectedEOF, err)
 	}
 }
 
@@ -35,3 +36,12 @@ func TestWritePathChallenge(t *testing.T) {
 	require.Equal(t, []byte{pathChallengeFrameType, 0xde, 0xad, 0xbe, 0xef, 0xca, 0xfe, 0x13, 0x37}, b)
 	require.Len(t, b, int(frame.Length(protocol.Version1)))
 }
+
+func TestNewPathChallengeFrame(t *testing.T) {
+	f, err := NewPathChallengeFrame(bytes.NewReader([]byte("foobarba")))
+	require.NoError(t, err)
+	require.Equal(t, [8]byte{'f', 'o', 'o', 'b', 'a', 'r', 'b', 'a'}, f.Data)
+
+	_, err = NewPathChallengeFrame(bytes.NewReader([]byte("foo")))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
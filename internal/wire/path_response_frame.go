@@ -1,8 +1,6 @@
 package wire
 
 import (
-	"io"
-
 	"github.com/quic-go/quic-go/internal/protocol"
 )
 
@@ -12,12 +10,14 @@ type PathResponseFrame struct {
 }
 
 func parsePathResponseFrame(b []byte, _ protocol.Version) (*PathResponseFrame, int, error) {
-	f := &PathResponseFrame{}
-	if len(b) < 8 {
-		return nil, 0, io.EOF
+	c := newCursor(b)
+	data, err := c.readBytes(8)
+	if err != nil {
+		return nil, 0, err
 	}
-	copy(f.Data[:], b)
-	return f, 8, nil
+	f := &PathResponseFrame{}
+	copy(f.Data[:], data)
+	return f, c.consumed(), nil
 }
 
 func (f *PathResponseFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
@@ -0,0 +1,264 @@
+package wire
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// A FramePriority is the priority class of a frame queued in a PayloadBuilder.
+// Lower values are packed first.
+type FramePriority uint8
+
+const (
+	// FramePriorityControl is used for control frames, e.g. flow control and connection ID frames.
+	FramePriorityControl FramePriority = iota
+	// FramePriorityRetransmission is used for retransmissions of lost frames.
+	FramePriorityRetransmission
+	// FramePriorityStreamData is used for STREAM frames carrying new data.
+	FramePriorityStreamData
+	// FramePriorityDatagram is used for DATAGRAM frames.
+	FramePriorityDatagram
+
+	numFramePriorities = iota
+)
+
+func (p FramePriority) String() string {
+	switch p {
+	case FramePriorityControl:
+		return "control"
+	case FramePriorityRetransmission:
+		return "retransmission"
+	case FramePriorityStreamData:
+		return "stream data"
+	case FramePriorityDatagram:
+		return "datagram"
+	default:
+		return fmt.Sprintf("unknown priority: %d", uint8(p))
+	}
+}
+
+// An OrderingPolicy encodes the packing recommendations of RFC 9000.
+// The zero value packs frames in the order they were selected.
+type OrderingPolicy struct {
+	// AckFirst places ACK frames at the beginning of the payload.
+	AckFirst bool
+	// CloseAlone sends a queued CONNECTION_CLOSE frame in a payload of its own.
+	// Once the connection is closed, the other frames won't be processed by the peer anyway.
+	CloseAlone bool
+	// PadPathChallenge pads payloads containing a PATH_CHALLENGE frame to the maximum size,
+	// since these datagrams have to be expanded to at least 1200 bytes (see section 8.2.1 of RFC 9000).
+	PadPathChallenge bool
+	// CryptoBeforeStream places CRYPTO frames before STREAM frames (and all other frames except ACK frames),
+	// such that the handshake data is processed first.
+	CryptoBeforeStream bool
+}
+
+// RFCOrderingPolicy is the OrderingPolicy that follows all packing recommendations.
+var RFCOrderingPolicy = OrderingPolicy{
+	AckFirst:           true,
+	CloseAlone:         true,
+	PadPathChallenge:   true,
+	CryptoBeforeStream: true,
+}
+
+// rank returns the position of the frame in the payload. Frames with a lower rank are placed first.
+func (p OrderingPolicy) rank(f Frame) int {
+	switch f.(type) {
+	case *AckFrame:
+		if p.AckFirst {
+			return 0
+		}
+	case *CryptoFrame:
+		if p.CryptoBeforeStream {
+			return 1
+		}
+	}
+	return 2
+}
+
+// A PayloadBuilder builds packet payloads from queued frames.
+// Frames are packed by priority class, and in the order they were added within each class.
+// To avoid starvation, a class that had frames queued but wasn't able to pack any of them
+// for maxStarvation consecutive payloads is packed first.
+// The OrderingPolicy determines the order of the frames within a payload.
+type PayloadBuilder struct {
+	queues        [numFramePriorities][]Frame
+	starved       [numFramePriorities]int
+	maxStarvation int
+	policy        OrderingPolicy
+}
+
+// NewPayloadBuilder creates a new PayloadBuilder.
+// If maxStarvation is 0, starvation avoidance is disabled.
+func NewPayloadBuilder(maxStarvation int) *PayloadBuilder {
+	return &PayloadBuilder{maxStarvation: maxStarvation}
+}
+
+// SetOrderingPolicy sets the OrderingPolicy used for all following payloads.
+func (b *PayloadBuilder) SetOrderingPolicy(p OrderingPolicy) {
+	b.policy = p
+}
+
+// Add queues a frame.
+func (b *PayloadBuilder) Add(f Frame, prio FramePriority) {
+	if prio >= numFramePriorities {
+		panic(fmt.Sprintf("PayloadBuilder: invalid priority %d", prio))
+	}
+	b.queues[prio] = append(b.queues[prio], f)
+}
+
+// HasData returns true if any frames are queued.
+func (b *PayloadBuilder) HasData() bool {
+	for _, q := range b.queues {
+		if len(q) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Queued returns the number of frames queued in a priority class.
+func (b *PayloadBuilder) Queued(prio FramePriority) int {
+	return len(b.queues[prio])
+}
+
+// Build appends as many queued frames as fit into maxSize bytes to buf.
+// It returns the frames that were appended.
+// STREAM and DATAGRAM frames are packed with a Length field, since other frames might follow them.
+// A frame queued without a Length field is only packed without one if it doesn't fit otherwise,
+// and is then the last frame of the payload.
+func (b *PayloadBuilder) Build(buf []byte, maxSize protocol.ByteCount, v protocol.Version) ([]byte, []Frame, error) {
+	return b.build(buf, maxSize, v, false)
+}
+
+// build builds a payload. If padIfQueued is set and frames remain queued, the payload is padded to maxSize.
+func (b *PayloadBuilder) build(buf []byte, maxSize protocol.ByteCount, v protocol.Version, padIfQueued bool) ([]byte, []Frame, error) {
+	var hadFrames [numFramePriorities]bool
+	for prio, q := range b.queues {
+		hadFrames[prio] = len(q) > 0
+	}
+	var packed [numFramePriorities]bool
+	var frames []Frame
+	if b.policy.CloseAlone {
+		frames = b.popConnectionClose(maxSize, v, &packed)
+	}
+	if frames == nil {
+		var length protocol.ByteCount
+	pack:
+		for _, prio := range b.order() {
+			for len(b.queues[prio]) > 0 {
+				f := b.queues[prio][0]
+				l := f.Length(v)
+				extendsToEnd := ExtendsToEndOfPacket(f)
+				if extendsToEnd {
+					setDataLenPresent(f, true)
+					if withLen := f.Length(v); length+withLen <= maxSize {
+						l = withLen
+						extendsToEnd = false
+					} else {
+						setDataLenPresent(f, false)
+					}
+				}
+				if length+l > maxSize {
+					break
+				}
+				length += l
+				frames = append(frames, f)
+				b.queues[prio][0] = nil
+				b.queues[prio] = b.queues[prio][1:]
+				packed[prio] = true
+				if extendsToEnd {
+					break pack
+				}
+			}
+		}
+		// STREAM and DATAGRAM frames have the highest rank, and sorting is stable,
+		// so a frame without a Length field remains the last frame.
+		if b.policy.AckFirst || b.policy.CryptoBeforeStream {
+			slices.SortStableFunc(frames, func(a, c Frame) int { return cmp.Compare(b.policy.rank(a), b.policy.rank(c)) })
+		}
+	}
+	for prio := range b.starved {
+		if hadFrames[prio] && !packed[prio] {
+			b.starved[prio]++
+		} else {
+			b.starved[prio] = 0
+		}
+	}
+
+	var padding int
+	if len(frames) > 0 && ((padIfQueued && b.HasData()) || (b.policy.PadPathChallenge && slices.ContainsFunc(frames, isPathChallenge))) {
+		padding = int(maxSize)
+		for _, f := range frames {
+			padding -= int(f.Length(v))
+		}
+	}
+	for i, f := range frames {
+		// The padding can't follow a frame that extends to the end of the payload.
+		// Such a frame only didn't fit with a Length field, so the padding is shorter than the Length field would be.
+		if i == len(frames)-1 && ExtendsToEndOfPacket(f) {
+			buf = AppendPaddingFrames(buf, padding)
+			padding = 0
+		}
+		var err error
+		buf, err = f.Append(buf, v)
+		if err != nil {
+			return buf, frames, err
+		}
+	}
+	return AppendPaddingFrames(buf, padding), frames, nil
+}
+
+func isPathChallenge(f Frame) bool {
+	_, ok := f.(*PathChallengeFrame)
+	return ok
+}
+
+// setDataLenPresent sets the DataLenPresent field of STREAM and DATAGRAM frames.
+func setDataLenPresent(f Frame, present bool) {
+	switch f := f.(type) {
+	case *StreamFrame:
+		f.DataLenPresent = present
+	case *DatagramFrame:
+		f.DataLenPresent = present
+	}
+}
+
+// popConnectionClose removes the first queued CONNECTION_CLOSE frame, if it fits into maxSize bytes.
+func (b *PayloadBuilder) popConnectionClose(maxSize protocol.ByteCount, v protocol.Version, packed *[numFramePriorities]bool) []Frame {
+	for prio, q := range b.queues {
+		for i, f := range q {
+			if _, ok := f.(*ConnectionCloseFrame); !ok {
+				continue
+			}
+			if f.Length(v) > maxSize {
+				return nil
+			}
+			b.queues[prio] = slices.Delete(q, i, i+1)
+			packed[prio] = true
+			return []Frame{f}
+		}
+	}
+	return nil
+}
+
+// order returns the order in which the priority classes are packed.
+func (b *PayloadBuilder) order() []FramePriority {
+	order := make([]FramePriority, 0, numFramePriorities)
+	if b.maxStarvation > 0 {
+		for prio, n := range b.starved {
+			if n >= b.maxStarvation {
+				order = append(order, FramePriority(prio))
+			}
+		}
+	}
+	for prio, n := range b.starved {
+		if b.maxStarvation == 0 || n < b.maxStarvation {
+			order = append(order, FramePriority(prio))
+		}
+	}
+	return order
+}
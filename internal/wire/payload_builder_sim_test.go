@@ -0,0 +1,154 @@
+package wire
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+// A syntheticFrameSource generates frames of a single priority class.
+type syntheticFrameSource struct {
+	prio FramePriority
+	// every packet, a frame is generated with this probability
+	rate float64
+	// newFrame generates a frame with the given sequence number
+	newFrame func(seq uint64) Frame
+	seq      uint64
+}
+
+// The payloadBuilderSimulation drives a PayloadBuilder with synthetic frame sources.
+type payloadBuilderSimulation struct {
+	builder *PayloadBuilder
+	sources []*syntheticFrameSource
+	rand    *rand.Rand
+
+	prios map[Frame]FramePriority
+	seqs  map[Frame]uint64
+}
+
+func newPayloadBuilderSimulation(maxStarvation int, seed uint64, sources ...*syntheticFrameSource) *payloadBuilderSimulation {
+	return &payloadBuilderSimulation{
+		builder: NewPayloadBuilder(maxStarvation),
+		sources: sources,
+		rand:    rand.New(rand.NewPCG(seed, seed)),
+		prios:   make(map[Frame]FramePriority),
+		seqs:    make(map[Frame]uint64),
+	}
+}
+
+// step generates new frames and builds a single payload.
+// It returns the frames packed, and the number of frames queued per priority class before building the payload.
+func (s *payloadBuilderSimulation) step(t *testing.T, maxSize protocol.ByteCount) ([]Frame, [numFramePriorities]int) {
+	for _, src := range s.sources {
+		if s.rand.Float64() < src.rate {
+			f := src.newFrame(src.seq)
+			s.prios[f] = src.prio
+			s.seqs[f] = src.seq
+			src.seq++
+			s.builder.Add(f, src.prio)
+		}
+	}
+	var queued [numFramePriorities]int
+	for prio := range queued {
+		queued[prio] = s.builder.Queued(FramePriority(prio))
+	}
+	b, frames, err := s.builder.Build(nil, maxSize, protocol.Version1)
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(b), int(maxSize))
+	return frames, queued
+}
+
+func TestPayloadBuilderSimulationFairness(t *testing.T) {
+	const (
+		numPackets    = 5000
+		maxStarvation = 3
+		maxSize       = 100
+	)
+	sim := newPayloadBuilderSimulation(maxStarvation, 42,
+		&syntheticFrameSource{
+			prio: FramePriorityControl,
+			rate: 0.9,
+			newFrame: func(seq uint64) Frame {
+				return &MaxStreamDataFrame{StreamID: 4, MaximumStreamData: protocol.ByteCount(seq)}
+			},
+		},
+		&syntheticFrameSource{
+			prio:     FramePriorityRetransmission,
+			rate:     0.3,
+			newFrame: func(seq uint64) Frame { return &MaxDataFrame{MaximumData: protocol.ByteCount(seq)} },
+		},
+		&syntheticFrameSource{
+			prio: FramePriorityStreamData,
+			rate: 0.8,
+			newFrame: func(seq uint64) Frame {
+				return &StreamFrame{StreamID: 8, Offset: protocol.ByteCount(seq) * 40, Data: make([]byte, 40), DataLenPresent: true}
+			},
+		},
+		&syntheticFrameSource{
+			prio:     FramePriorityDatagram,
+			rate:     0.5,
+			newFrame: func(seq uint64) Frame { return &DatagramFrame{Data: make([]byte, 30+seq%20), DataLenPresent: true} },
+		},
+	)
+
+	var starved [numFramePriorities]int
+	var lastSeq [numFramePriorities]int64
+	for i := range lastSeq {
+		lastSeq[i] = -1
+	}
+	for range numPackets {
+		frames, queued := sim.step(t, maxSize)
+		var packed [numFramePriorities]bool
+		for _, f := range frames {
+			prio := sim.prios[f]
+			packed[prio] = true
+			// frames of the same priority class are packed in FIFO order
+			seq := int64(sim.seqs[f])
+			require.Equal(t, lastSeq[prio]+1, seq, "frames of class %s reordered", prio)
+			lastSeq[prio] = seq
+		}
+		// no priority class is starved for longer than maxStarvation payloads
+		for prio, n := range queued {
+			if n > 0 && !packed[prio] {
+				starved[prio]++
+			} else {
+				starved[prio] = 0
+			}
+			require.LessOrEqual(t, starved[prio], maxStarvation, "class %s starved", FramePriority(prio))
+		}
+	}
+	// all priority classes made progress
+	for prio, seq := range lastSeq {
+		require.Positive(t, seq, "class %s never packed", FramePriority(prio))
+	}
+}
+
+func TestPayloadBuilderSimulationOrdering(t *testing.T) {
+	// without starvation avoidance, frames are strictly ordered by priority class
+	sim := newPayloadBuilderSimulation(0, 1337,
+		&syntheticFrameSource{
+			prio:     FramePriorityDatagram,
+			rate:     1,
+			newFrame: func(uint64) Frame { return &DatagramFrame{Data: make([]byte, 10), DataLenPresent: true} },
+		},
+		&syntheticFrameSource{
+			prio:     FramePriorityControl,
+			rate:     0.5,
+			newFrame: func(seq uint64) Frame { return &MaxDataFrame{MaximumData: protocol.ByteCount(seq)} },
+		},
+		&syntheticFrameSource{
+			prio:     FramePriorityRetransmission,
+			rate:     0.5,
+			newFrame: func(seq uint64) Frame { return &DataBlockedFrame{MaximumData: protocol.ByteCount(seq)} },
+		},
+	)
+	for range 2000 {
+		frames, _ := sim.step(t, 50)
+		for i := 1; i < len(frames); i++ {
+			require.LessOrEqual(t, sim.prios[frames[i-1]], sim.prios[frames[i]])
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadBuilderPriorities(t *testing.T) {
+	b := NewPayloadBuilder(0)
+	require.False(t, b.HasData())
+	datagram := &DatagramFrame{Data: []byte("foobar")}
+	stream := &StreamFrame{StreamID: 4, Data: []byte("foobar"), DataLenPresent: true}
+	retransmission := &MaxStreamDataFrame{StreamID: 4, MaximumStreamData: 1000}
+	control := &MaxDataFrame{MaximumData: 1337}
+	b.Add(datagram, FramePriorityDatagram)
+	b.Add(stream, FramePriorityStreamData)
+	b.Add(retransmission, FramePriorityRetransmission)
+	b.Add(control, FramePriorityControl)
+	require.True(t, b.HasData())
+
+	buf, frames, err := b.Build(nil, 1000, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, []Frame{control, retransmission, stream, datagram}, frames)
+	var expected []byte
+	for _, f := range frames {
+		expected, err = f.Append(expected, protocol.Version1)
+		require.NoError(t, err)
+	}
+	require.Equal(t, expected, buf)
+	require.False(t, b.HasData())
+}
+
+func TestPayloadBuilderSizeLimit(t *testing.T) {
+	b := NewPayloadBuilder(0)
+	control := &MaxDataFrame{MaximumData: 1337}
+	datagram := &DatagramFrame{Data: make([]byte, 100)}
+	b.Add(control, FramePriorityControl)
+	b.Add(datagram, FramePriorityDatagram)
+
+	buf, frames, err := b.Build(nil, 50, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, []Frame{control}, frames)
+	require.Len(t, buf, int(control.Length(protocol.Version1)))
+	require.Equal(t, 1, b.Queued(FramePriorityDatagram))
+}
+
+func TestPayloadBuilderStarvationAvoidance(t *testing.T) {
+	b := NewPayloadBuilder(2)
+	datagram := &DatagramFrame{Data: make([]byte, 40)}
+	b.Add(datagram, FramePriorityDatagram)
+	control := &MaxStreamDataFrame{StreamID: 4, MaximumStreamData: 1000}
+	for range 100 {
+		b.Add(control, FramePriorityControl)
+	}
+	maxSize := datagram.Length(protocol.Version1)
+
+	// The first two payloads are filled with control frames...
+	for range 2 {
+		_, frames, err := b.Build(nil, maxSize, protocol.Version1)
+		require.NoError(t, err)
+		require.NotEmpty(t, frames)
+		require.NotContains(t, frames, datagram)
+	}
+	// ... but then the DATAGRAM frame was starved for long enough.
+	_, frames, err := b.Build(nil, maxSize, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, []Frame{datagram}, frames)
+	// Afterwards, control frames are prioritized again.
+	_, frames, err = b.Build(nil, maxSize, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, control, frames[0])
+}
+
+// parsePayloadFrameTypes returns the types of the frames in the payload.
+func parsePayloadFrameTypes(t *testing.T, b []byte) []FrameType {
+	t.Helper()
+	var types []FrameType
+	require.NoError(t, scanFrames(b, func(typ uint64, _, _ int) bool {
+		types = append(types, FrameType(typ))
+		return true
+	}))
+	return types
+}
+
+// requireParsesBack parses the payload, and checks that it contains the frames.
+func requireParsesBack(t *testing.T, b []byte, frames []Frame) {
+	t.Helper()
+	parser := NewFrameParser(true, true)
+	var parsed []Frame
+	for len(b) > 0 {
+		l, f, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+		require.NoError(t, err)
+		if f != nil {
+			parsed = append(parsed, f)
+		}
+		b = b[l:]
+	}
+	require.Len(t, parsed, len(frames))
+	for i, f := range frames {
+		require.Equal(t, appendFrames(t, f), appendFrames(t, parsed[i]))
+	}
+}
+
+func TestPayloadBuilderLengthFields(t *testing.T) {
+	t.Run("frames followed by other frames", func(t *testing.T) {
+		b := NewPayloadBuilder(0)
+		stream := &StreamFrame{StreamID: 4, Data: []byte("foobar")}
+		datagram := &DatagramFrame{Data: []byte("lorem ipsum")}
+		control := &MaxDataFrame{MaximumData: 1337}
+		b.Add(stream, FramePriorityStreamData)
+		b.Add(datagram, FramePriorityDatagram)
+		b.Add(control, FramePriorityDatagram)
+		buf, frames, err := b.Build(nil, 1000, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, []Frame{stream, datagram, control}, frames)
+		require.True(t, stream.DataLenPresent)
+		require.True(t, datagram.DataLenPresent)
+		requireParsesBack(t, buf, frames)
+	})
+
+	t.Run("frame only fitting without a Length field", func(t *testing.T) {
+		b := NewPayloadBuilder(0)
+		control := &MaxDataFrame{MaximumData: 1337}
+		datagram := &DatagramFrame{Data: make([]byte, 100)}
+		b.Add(control, FramePriorityControl)
+		b.Add(datagram, FramePriorityDatagram)
+		b.Add(&PingFrame{}, FramePriorityDatagram)
+		maxSize := control.Length(protocol.Version1) + datagram.Length(protocol.Version1)
+		buf, frames, err := b.Build(nil, maxSize, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, []Frame{control, datagram}, frames)
+		require.False(t, datagram.DataLenPresent)
+		require.Len(t, buf, int(maxSize))
+		requireParsesBack(t, buf, frames)
+		require.Equal(t, 1, b.Queued(FramePriorityDatagram))
+	})
+
+	t.Run("frame that doesn't fit", func(t *testing.T) {
+		b := NewPayloadBuilder(0)
+		stream := &StreamFrame{StreamID: 4, Data: make([]byte, 100)}
+		b.Add(stream, FramePriorityStreamData)
+		_, frames, err := b.Build(nil, 50, protocol.Version1)
+		require.NoError(t, err)
+		require.Empty(t, frames)
+		require.False(t, stream.DataLenPresent)
+	})
+
+	t.Run("padding", func(t *testing.T) {
+		b := NewPayloadBuilder(0)
+		b.SetOrderingPolicy(OrderingPolicy{PadPathChallenge: true})
+		pathChallenge := &PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}
+		stream := &StreamFrame{StreamID: 4, Data: make([]byte, 100)}
+		b.Add(pathChallenge, FramePriorityControl)
+		b.Add(stream, FramePriorityStreamData)
+		// the 2 byte Length field doesn't fit, so there's 1 byte of padding that has to be placed before the STREAM frame
+		maxSize := pathChallenge.Length(protocol.Version1) + stream.Length(protocol.Version1) + 1
+		buf, frames, err := b.Build(nil, maxSize, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, []Frame{pathChallenge, stream}, frames)
+		require.False(t, stream.DataLenPresent)
+		require.Len(t, buf, int(maxSize))
+		requireParsesBack(t, buf, frames)
+	})
+}
+
+func TestPayloadBuilderOrderingPolicy(t *testing.T) {
+	ack := &AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}}
+	crypto := &CryptoFrame{Data: []byte("foobar")}
+	control := &MaxDataFrame{MaximumData: 1337}
+	var stream, retransmission *StreamFrame
+	add := func(b *PayloadBuilder) {
+		// The STREAM frames are queued without a Length field.
+		stream = &StreamFrame{StreamID: 4, Data: []byte("foobar")}
+		retransmission = &StreamFrame{StreamID: 8, Data: []byte("lorem ipsum")}
+		b.Add(control, FramePriorityControl)
+		b.Add(stream, FramePriorityStreamData)
+		b.Add(retransmission, FramePriorityRetransmission)
+		b.Add(crypto, FramePriorityRetransmission)
+		b.Add(ack, FramePriorityRetransmission)
+	}
+
+	t.Run("without policy", func(t *testing.T) {
+		b := NewPayloadBuilder(0)
+		add(b)
+		buf, frames, err := b.Build(nil, 1000, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, []Frame{control, retransmission, crypto, ack, stream}, frames)
+		types := parsePayloadFrameTypes(t, buf)
+		require.Equal(t, []FrameType{maxDataFrameType, 0xa, cryptoFrameType, ackFrameType, 0xa}, types)
+		requireParsesBack(t, buf, frames)
+	})
+
+	t.Run("RFC policy", func(t *testing.T) {
+		b := NewPayloadBuilder(0)
+		b.SetOrderingPolicy(RFCOrderingPolicy)
+		add(b)
+		buf, frames, err := b.Build(nil, 1000, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, []Frame{ack, crypto, control, retransmission, stream}, frames)
+		types := parsePayloadFrameTypes(t, buf)
+		require.Equal(t, []FrameType{ackFrameType, cryptoFrameType, maxDataFrameType, 0xa, 0xa}, types)
+		requireParsesBack(t, buf, frames)
+	})
+
+	t.Run("RFC policy, with a frame only fitting without a Length field", func(t *testing.T) {
+		b := NewPayloadBuilder(0)
+		b.SetOrderingPolicy(RFCOrderingPolicy)
+		add(b)
+		// the STREAM frame carrying new data only fits without a Length field
+		var maxSize protocol.ByteCount
+		for _, f := range []Frame{control, retransmission, crypto, ack, stream} {
+			maxSize += f.Length(protocol.Version1)
+		}
+		maxSize += protocol.ByteCount(quicvarint.Len(uint64(retransmission.DataLen())))
+		buf, frames, err := b.Build(nil, maxSize, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, []Frame{ack, crypto, control, retransmission, stream}, frames)
+		require.True(t, retransmission.DataLenPresent)
+		require.False(t, stream.DataLenPresent)
+		types := parsePayloadFrameTypes(t, buf)
+		require.Equal(t, []FrameType{ackFrameType, cryptoFrameType, maxDataFrameType, 0xa, 0x8}, types)
+		requireParsesBack(t, buf, frames)
+	})
+}
+
+func TestPayloadBuilderConnectionCloseAlone(t *testing.T) {
+	b := NewPayloadBuilder(0)
+	b.SetOrderingPolicy(OrderingPolicy{CloseAlone: true})
+	control := &MaxDataFrame{MaximumData: 1337}
+	ccf := NewTransportClose(0x1, 0, "")
+	b.Add(control, FramePriorityControl)
+	b.Add(&PingFrame{}, FramePriorityRetransmission)
+	b.Add(ccf, FramePriorityRetransmission)
+
+	buf, frames, err := b.Build(nil, 1000, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, []Frame{ccf}, frames)
+	types := parsePayloadFrameTypes(t, buf)
+	require.Equal(t, []FrameType{connectionCloseFrameType}, types)
+	requireParsesBack(t, buf, frames)
+	require.Equal(t, 1, b.Queued(FramePriorityControl))
+	require.Equal(t, 1, b.Queued(FramePriorityRetransmission))
+}
+
+func TestPayloadBuilderPathChallengePadding(t *testing.T) {
+	b := NewPayloadBuilder(0)
+	b.SetOrderingPolicy(OrderingPolicy{PadPathChallenge: true})
+	b.Add(&PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}, FramePriorityControl)
+	buf, frames, err := b.Build([]byte("header"), 1200, protocol.Version1)
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+	require.Len(t, buf, 6+1200)
+	require.Equal(t, []FrameType{pathChallengeFrameType}, parsePayloadFrameTypes(t, buf[6:]))
+	require.Equal(t, make([]byte, 1200-9), buf[6+9:])
+	requireParsesBack(t, buf[6:], frames)
+
+	// payloads without a PATH_CHALLENGE frame are not padded
+	b.Add(&PingFrame{}, FramePriorityControl)
+	buf, _, err = b.Build(nil, 1200, protocol.Version1)
+	require.NoError(t, err)
+	require.Len(t, buf, 1)
+}
@@ -0,0 +1,77 @@
+package wire
+
+// A PayloadIntent classifies a packet payload by its frame composition.
+type PayloadIntent uint8
+
+const (
+	// PayloadIntentUnknown is used for payloads that don't contain any frames (other than PADDING).
+	PayloadIntentUnknown PayloadIntent = iota
+	// PayloadIntentKeepAlive is used for payloads that only contain PING frames,
+	// and possibly ACK frames.
+	PayloadIntentKeepAlive
+	// PayloadIntentMTUProbe is used for padded payloads that are otherwise keep-alives.
+	PayloadIntentMTUProbe
+	// PayloadIntentPathProbe is used for payloads that contain probing frames (see section 9.1 of RFC 9000),
+	// and otherwise only contain PING and ACK frames.
+	PayloadIntentPathProbe
+	// PayloadIntentAckOnly is used for payloads that only contain ACK frames.
+	PayloadIntentAckOnly
+	// PayloadIntentDataBearing is used for all other payloads,
+	// e.g. payloads carrying STREAM, CRYPTO, DATAGRAM or flow control frames.
+	PayloadIntentDataBearing
+)
+
+func (i PayloadIntent) String() string {
+	switch i {
+	case PayloadIntentUnknown:
+		return "unknown"
+	case PayloadIntentKeepAlive:
+		return "keep-alive"
+	case PayloadIntentMTUProbe:
+		return "MTU probe"
+	case PayloadIntentPathProbe:
+		return "path probe"
+	case PayloadIntentAckOnly:
+		return "ACK-only"
+	case PayloadIntentDataBearing:
+		return "data-bearing"
+	default:
+		return "invalid payload intent"
+	}
+}
+
+// ClassifyPayload classifies a payload by the frames it contains.
+// Since the FrameParser skips PADDING frames, padded must be set if the payload contains any PADDING.
+func ClassifyPayload(frames []Frame, padded bool) PayloadIntent {
+	if len(frames) == 0 {
+		return PayloadIntentUnknown
+	}
+	var hasPing, hasProbing, hasOther bool
+	for _, f := range frames {
+		switch f.(type) {
+		case *PingFrame:
+			hasPing = true
+		case *AckFrame:
+			// ACK frames are sent alongside any other frames, and don't change the intent.
+		default:
+			if IsProbingFrame(f) {
+				hasProbing = true
+			} else {
+				hasOther = true
+			}
+		}
+	}
+	switch {
+	case hasOther:
+		return PayloadIntentDataBearing
+	case hasProbing:
+		return PayloadIntentPathProbe
+	case hasPing:
+		if padded {
+			return PayloadIntentMTUProbe
+		}
+		return PayloadIntentKeepAlive
+	default:
+		return PayloadIntentAckOnly
+	}
+}
@@ -0,0 +1,47 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyPayload(t *testing.T) {
+	ack := &AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 1}}}
+	for _, tc := range []struct {
+		name     string
+		frames   []Frame
+		padded   bool
+		expected PayloadIntent
+	}{
+		{name: "empty", frames: nil, expected: PayloadIntentUnknown},
+		{name: "PING", frames: []Frame{&PingFrame{}}, expected: PayloadIntentKeepAlive},
+		{name: "padded PING", frames: []Frame{&PingFrame{}}, padded: true, expected: PayloadIntentMTUProbe},
+		{name: "PATH_CHALLENGE", frames: []Frame{&PathChallengeFrame{}}, padded: true, expected: PayloadIntentPathProbe},
+		{
+			name:     "PATH_CHALLENGE and NEW_CONNECTION_ID",
+			frames:   []Frame{&PathChallengeFrame{}, &NewConnectionIDFrame{}, &PingFrame{}},
+			expected: PayloadIntentPathProbe,
+		},
+		{name: "PATH_RESPONSE", frames: []Frame{&PathResponseFrame{}}, expected: PayloadIntentPathProbe},
+		{name: "ACK and PATH_CHALLENGE", frames: []Frame{ack, &PathChallengeFrame{}}, expected: PayloadIntentPathProbe},
+		{name: "ACK and PATH_RESPONSE", frames: []Frame{ack, &PathResponseFrame{}}, expected: PayloadIntentPathProbe},
+		{name: "PING and PATH_CHALLENGE", frames: []Frame{&PingFrame{}, &PathChallengeFrame{}}, expected: PayloadIntentPathProbe},
+		{name: "ACK", frames: []Frame{ack}, expected: PayloadIntentAckOnly},
+		{name: "ACK and PING", frames: []Frame{ack, &PingFrame{}}, expected: PayloadIntentKeepAlive},
+		{name: "STREAM", frames: []Frame{&StreamFrame{}}, expected: PayloadIntentDataBearing},
+		{name: "ACK and MAX_DATA", frames: []Frame{ack, &MaxDataFrame{}}, expected: PayloadIntentDataBearing},
+		{name: "PATH_CHALLENGE and STREAM", frames: []Frame{&PathChallengeFrame{}, &StreamFrame{}}, expected: PayloadIntentDataBearing},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, ClassifyPayload(tc.frames, tc.padded))
+		})
+	}
+}
+
+func TestPayloadIntentStringer(t *testing.T) {
+	require.Equal(t, "keep-alive", PayloadIntentKeepAlive.String())
+	require.Equal(t, "MTU probe", PayloadIntentMTUProbe.String())
+	require.Equal(t, "ACK-only", PayloadIntentAckOnly.String())
+	require.Equal(t, "invalid payload intent", PayloadIntent(42).String())
+}
@@ -0,0 +1,82 @@
+package wire
+
+import (
+	"math"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// PayloadStats are statistics about the frames in a packet payload.
+type PayloadStats struct {
+	// Bytes is the length of the payload.
+	Bytes int
+	// Frames is the number of frames, PADDING frames excluded.
+	Frames int
+	// PaddingBytes is the number of PADDING bytes.
+	PaddingBytes int
+	// StreamBytes is the number of bytes of Stream Data carried in STREAM frames.
+	StreamBytes int
+	// DatagramBytes is the number of bytes of data carried in DATAGRAM frames.
+	DatagramBytes int
+	// UnknownFrames is the number of frames of unknown types (included in Frames).
+	// Since their length can't be determined, an unknown frame is assumed to extend to the end of the payload.
+	UnknownFrames int
+	// FrameTypeEntropy is the Shannon entropy (in bits) of the distribution of frame types, PADDING excluded.
+	// All STREAM frame types are counted as the same frame type, as are the ACK and ACK_ECN frame types, etc.
+	// It is 0 if the payload contains no frames, or only frames of a single type.
+	FrameTypeEntropy float64
+}
+
+// PaddingRatio is the share of PADDING bytes in the payload.
+func (s PayloadStats) PaddingRatio() float64 {
+	if s.Bytes == 0 {
+		return 0
+	}
+	return float64(s.PaddingBytes) / float64(s.Bytes)
+}
+
+// DatagramRatio is the share of DATAGRAM data in the application data (STREAM and DATAGRAM) carried by the payload.
+func (s PayloadStats) DatagramRatio() float64 {
+	if s.StreamBytes+s.DatagramBytes == 0 {
+		return 0
+	}
+	return float64(s.DatagramBytes) / float64(s.StreamBytes+s.DatagramBytes)
+}
+
+// AnalyzePayload parses the payload and computes its PayloadStats.
+// Frames are parsed using ProfileAnalyzer, i.e. regardless of the encryption level they are allowed at.
+// Frames of unknown types don't cause an error, see UnknownFrameExtendsToEnd.
+// It is meant for the offline analysis of traffic, and must not be used on the hot path.
+func AnalyzePayload(b []byte, v protocol.Version) (PayloadStats, error) {
+	stats := PayloadStats{Bytes: len(b), PaddingBytes: len(b)}
+	counts := make(map[string]int)
+	parser := NewFrameParser(true, true)
+	parser.SetStrictnessProfile(ProfileAnalyzer)
+	parser.SetUnknownFrameLength(UnknownFrameExtendsToEnd)
+	parser.SetOnFrameParsed(func(typ FrameType, length int) {
+		stats.Frames++
+		stats.PaddingBytes -= length
+		counts[typ.String()]++
+	})
+	for len(b) > 0 {
+		frame, l, err := parser.parseNext(b, protocol.Encryption1RTT, v)
+		if err != nil {
+			return PayloadStats{}, err
+		}
+		b = b[l:]
+		switch f := frame.(type) {
+		case *StreamFrame:
+			stats.StreamBytes += len(f.Data)
+			f.PutBack()
+		case *DatagramFrame:
+			stats.DatagramBytes += len(f.Data)
+		case *UnknownFrame:
+			stats.UnknownFrames++
+		}
+	}
+	for _, n := range counts {
+		p := float64(n) / float64(stats.Frames)
+		stats.FrameTypeEntropy -= p * math.Log2(p)
+	}
+	return stats, nil
+}
@@ -0,0 +1,62 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzePayload(t *testing.T) {
+	b := appendFrames(t,
+		&StreamFrame{StreamID: 4, Data: make([]byte, 30), DataLenPresent: true},
+		&StreamFrame{StreamID: 8, Offset: 100, Data: make([]byte, 30), DataLenPresent: true},
+		&DatagramFrame{Data: make([]byte, 20), DataLenPresent: true},
+		&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 10}}},
+	)
+	b = append(b, make([]byte, 50)...) // PADDING
+	stats, err := AnalyzePayload(b, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(b), stats.Bytes)
+	require.Equal(t, 4, stats.Frames)
+	require.Equal(t, 50, stats.PaddingBytes)
+	require.Equal(t, 60, stats.StreamBytes)
+	require.Equal(t, 20, stats.DatagramBytes)
+	require.InDelta(t, float64(50)/float64(len(b)), stats.PaddingRatio(), 1e-9)
+	require.InDelta(t, 0.25, stats.DatagramRatio(), 1e-9)
+	// 2 STREAM frames, 1 DATAGRAM and 1 ACK frame
+	require.InDelta(t, 1.5, stats.FrameTypeEntropy, 1e-9)
+}
+
+func TestAnalyzePayloadSingleFrameType(t *testing.T) {
+	stats, err := AnalyzePayload(appendFrames(t, &PingFrame{}, &PingFrame{}), protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Frames)
+	require.Zero(t, stats.FrameTypeEntropy)
+	require.Zero(t, stats.PaddingRatio())
+	require.Zero(t, stats.DatagramRatio())
+
+	stats, err = AnalyzePayload(nil, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, PayloadStats{}, stats)
+}
+
+func TestAnalyzePayloadUnknownFrame(t *testing.T) {
+	b := appendFrames(t, &PingFrame{}, &DatagramFrame{Data: make([]byte, 10), DataLenPresent: true})
+	b = append(b, make([]byte, 5)...) // PADDING
+	b = quicvarint.Append(b, 0x1337)
+	b = append(b, []byte("foobar")...)
+	stats, err := AnalyzePayload(b, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, 3, stats.Frames)
+	require.Equal(t, 1, stats.UnknownFrames)
+	require.Equal(t, 5, stats.PaddingBytes)
+	require.Equal(t, 10, stats.DatagramBytes)
+}
+
+func TestAnalyzePayloadInvalid(t *testing.T) {
+	_, err := AnalyzePayload([]byte{maxDataFrameType}, protocol.Version1)
+	require.Error(t, err)
+}
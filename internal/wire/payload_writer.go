@@ -0,0 +1,71 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// A PayloadWriter appends frames to a packet payload,
+// making sure that the payload never exceeds the target size.
+// It writes into the buffer it was created with, which is usually taken from a buffer pool.
+// A frame that doesn't fit is not appended, and an error is returned.
+type PayloadWriter struct {
+	b       []byte
+	start   int
+	maxSize protocol.ByteCount
+	version protocol.Version
+}
+
+// NewPayloadWriter creates a PayloadWriter that appends a payload of at most maxSize bytes to b.
+func NewPayloadWriter(b []byte, maxSize protocol.ByteCount, v protocol.Version) PayloadWriter {
+	return PayloadWriter{
+		b:       b,
+		start:   len(b),
+		maxSize: maxSize,
+		version: v,
+	}
+}
+
+// AppendFrame appends a frame.
+// It returns an error if the frame doesn't fit into the remaining space.
+func (w *PayloadWriter) AppendFrame(f Frame) error {
+	if l := f.Length(w.version); l > w.Remaining() {
+		return fmt.Errorf("PayloadWriter: %T of %d bytes exceeds remaining space (%d bytes)", f, l, w.Remaining())
+	}
+	b, _, err := AppendN(w.b, f, w.version)
+	if err != nil {
+		return err
+	}
+	// the frame might have appended more than its Length
+	if protocol.ByteCount(len(b)-w.start) > w.maxSize {
+		return fmt.Errorf("PayloadWriter: %T exceeds the payload size (%d > %d bytes)", f, len(b)-w.start, w.maxSize)
+	}
+	w.b = b
+	return nil
+}
+
+// AppendPadding appends n PADDING frames.
+// It returns an error if they don't fit into the remaining space.
+func (w *PayloadWriter) AppendPadding(n protocol.ByteCount) error {
+	if n > w.Remaining() {
+		return fmt.Errorf("PayloadWriter: %d bytes of padding exceed remaining space (%d bytes)", n, w.Remaining())
+	}
+	w.b = AppendPaddingFrames(w.b, int(n))
+	return nil
+}
+
+// Len returns the number of bytes written to the payload.
+func (w *PayloadWriter) Len() protocol.ByteCount {
+	return protocol.ByteCount(len(w.b) - w.start)
+}
+
+// Remaining returns the number of bytes that can still be written to the payload.
+func (w *PayloadWriter) Remaining() protocol.ByteCount {
+	return w.maxSize - w.Len()
+}
+
+// Finish returns the buffer, including the bytes it contained when the PayloadWriter was created.
+func (w *PayloadWriter) Finish() []byte {
+	return w.b
+}
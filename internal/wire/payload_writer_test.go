@@ -0,0 +1,67 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadWriter(t *testing.T) {
+	hdr := []byte("header")
+	ping := &PingFrame{}
+	maxData := &MaxDataFrame{MaximumData: 1337}
+	maxSize := ping.Length(protocol.Version1) + maxData.Length(protocol.Version1) + 3
+
+	w := NewPayloadWriter(hdr, maxSize, protocol.Version1)
+	require.Zero(t, w.Len())
+	require.Equal(t, maxSize, w.Remaining())
+	require.NoError(t, w.AppendFrame(ping))
+	require.NoError(t, w.AppendFrame(maxData))
+	require.Equal(t, protocol.ByteCount(3), w.Remaining())
+	require.NoError(t, w.AppendPadding(3))
+	require.Zero(t, w.Remaining())
+	require.Equal(t, maxSize, w.Len())
+
+	b := w.Finish()
+	require.Equal(t, hdr, b[:len(hdr)])
+	expected := appendFrames(t, ping, maxData)
+	expected = append(expected, 0, 0, 0)
+	require.Equal(t, expected, b[len(hdr):])
+}
+
+func TestPayloadWriterOverrun(t *testing.T) {
+	w := NewPayloadWriter(nil, 5, protocol.Version1)
+	require.NoError(t, w.AppendFrame(&PingFrame{}))
+
+	err := w.AppendFrame(&MaxDataFrame{MaximumData: 1 << 40}) // 9 bytes
+	require.EqualError(t, err, "PayloadWriter: *wire.MaxDataFrame of 9 bytes exceeds remaining space (4 bytes)")
+	err = w.AppendPadding(5)
+	require.EqualError(t, err, "PayloadWriter: 5 bytes of padding exceed remaining space (4 bytes)")
+	// nothing was appended
+	require.Equal(t, protocol.ByteCount(1), w.Len())
+	require.Equal(t, []byte{pingFrameType}, w.Finish())
+
+	require.NoError(t, w.AppendPadding(4))
+	require.Zero(t, w.Remaining())
+}
+
+func BenchmarkPayloadWriter(b *testing.B) {
+	buf := make([]byte, 0, protocol.MaxPacketBufferSize)
+	frames := []Frame{
+		&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 1000}}},
+		&MaxDataFrame{MaximumData: 1 << 30},
+		&StreamFrame{StreamID: 4, Offset: 1 << 20, Data: make([]byte, 1000), DataLenPresent: true},
+	}
+	b.ReportAllocs()
+	for range b.N {
+		w := NewPayloadWriter(buf[:0], 1200, protocol.Version1)
+		for _, f := range frames {
+			if err := w.AppendFrame(f); err != nil {
+				b.Fatal(err)
+			}
+		}
+		buf = w.Finish()
+	}
+}
@@ -6,7 +6,11 @@ import (
 	"github.com/quic-go/quic-go/internal/protocol"
 )
 
-var pool sync.Pool
+// smallStreamFrameBufferSize is the buffer size of the pool for small STREAM frames.
+// This way, small frames don't pin buffers of the maximum packet size while they're buffered for reassembly.
+const smallStreamFrameBufferSize = 256
+
+var pool, smallPool sync.Pool
 
 func init() {
 	pool.New = func() interface{} {
@@ -15,6 +19,12 @@ func init() {
 			fromPool: true,
 		}
 	}
+	smallPool.New = func() interface{} {
+		return &StreamFrame{
+			Data:     make([]byte, 0, smallStreamFrameBufferSize),
+			fromPool: true,
+		}
+	}
 }
 
 func GetStreamFrame() *StreamFrame {
@@ -22,12 +32,22 @@ func GetStreamFrame() *StreamFrame {
 	return f
 }
 
+func getSmallStreamFrame() *StreamFrame {
+	return smallPool.Get().(*StreamFrame)
+}
+
 func putStreamFrame(f *StreamFrame) {
 	if !f.fromPool {
 		return
 	}
-	if protocol.ByteCount(cap(f.Data)) != protocol.MaxPacketBufferSize {
+	f.OffsetPresent = false
+	f.Provenance = Provenance{}
+	switch protocol.ByteCount(cap(f.Data)) {
+	case protocol.MaxPacketBufferSize:
+		pool.Put(f)
+	case smallStreamFrameBufferSize:
+		smallPool.Put(f)
+	default:
 		panic("wire.PutStreamFrame called with packet of wrong size!")
 	}
-	pool.Put(f)
 }
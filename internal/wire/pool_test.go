@@ -22,3 +22,13 @@ func TestAcceptStreamFramesNotFromBuffer(t *testing.T) {
 	putStreamFrame(f)
 	// No assertion needed as we're just checking it doesn't panic
 }
+
+func TestGetAndPutSmallStreamFrames(t *testing.T) {
+	f := getSmallStreamFrame()
+	require.Equal(t, smallStreamFrameBufferSize, cap(f.Data))
+	f.OffsetPresent = true
+	putStreamFrame(f)
+	f = getSmallStreamFrame()
+	require.False(t, f.OffsetPresent)
+	putStreamFrame(f)
+}
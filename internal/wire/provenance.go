@@ -0,0 +1,23 @@
+package wire
+
+import (
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// Provenance describes where the data carried by a frame was originally sent.
+// It is not serialized, and ignored by the parser.
+// It is preserved when a frame is split (see MaybeSplitOffFrame),
+// such that bytes sent in retransmissions can be attributed to the packet they were first sent in.
+type Provenance struct {
+	// PacketNumber is the packet number of the packet the data was first sent in.
+	PacketNumber protocol.PacketNumber
+	// EnqueueTime is the time the data was first sent.
+	EnqueueTime time.Time
+}
+
+// IsSet says if the provenance was set.
+func (p Provenance) IsSet() bool {
+	return !p.EnqueueTime.IsZero()
+}
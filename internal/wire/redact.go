@@ -0,0 +1,124 @@
+package wire
+
+import (
+	"crypto/sha256"
+	"os"
+	"strings"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/utils"
+)
+
+// A RedactionMode determines how sensitive frame contents are rendered when logging frames.
+// Sensitive contents are the payload bytes of STREAM, CRYPTO and DATAGRAM frames,
+// address validation tokens, stateless reset tokens, and the data of PATH_CHALLENGE and PATH_RESPONSE frames.
+// Structural fields (stream IDs, offsets, lengths, etc.) are never redacted.
+type RedactionMode uint8
+
+const (
+	// RedactionOmit replaces sensitive contents with zeros.
+	// This is the zero value, such that sensitive contents are redacted unless configured otherwise.
+	RedactionOmit RedactionMode = iota
+	// RedactionHash replaces sensitive contents with bytes derived from their SHA-256 hash.
+	// This allows correlating identical contents (e.g. a token that's received and later used)
+	// without revealing them.
+	RedactionHash
+	// RedactionNone doesn't redact anything.
+	// It should only be used for debugging.
+	RedactionNone
+)
+
+// redactionEnv is the environment variable used to configure the LogRedactionMode.
+const redactionEnv = "QUIC_GO_LOG_REDACTION"
+
+// LogRedactionMode is the RedactionMode used by LogFrame, and for frames exported to qlog.
+// It is read from the QUIC_GO_LOG_REDACTION environment variable ("omit", "hash" or "none"),
+// and defaults to RedactionOmit.
+var LogRedactionMode = readRedactionEnv()
+
+func readRedactionEnv() RedactionMode {
+	switch strings.ToLower(os.Getenv(redactionEnv)) {
+	case "hash":
+		return RedactionHash
+	case "none":
+		return RedactionNone
+	default:
+		return RedactionOmit
+	}
+}
+
+func (m RedactionMode) String() string {
+	switch m {
+	case RedactionOmit:
+		return "omit"
+	case RedactionHash:
+		return "hash"
+	case RedactionNone:
+		return "none"
+	default:
+		return "unknown redaction mode"
+	}
+}
+
+// RedactFrame returns a copy of the frame with the sensitive contents redacted.
+// The length of redacted fields is preserved, so the frame serializes to the same number of bytes.
+// Frames that don't have any sensitive contents, and all frames if the mode is RedactionNone,
+// are returned unchanged.
+func RedactFrame(f Frame, mode RedactionMode) Frame {
+	if mode == RedactionNone {
+		return f
+	}
+	switch f := f.(type) {
+	case *StreamFrame:
+		c := *f
+		c.Data = mode.redact(f.Data)
+		c.fromPool = false
+		return &c
+	case *CryptoFrame:
+		return &CryptoFrame{Offset: f.Offset, Data: mode.redact(f.Data)}
+	case *DatagramFrame:
+		return &DatagramFrame{DataLenPresent: f.DataLenPresent, Data: mode.redact(f.Data)}
+	case *NewTokenFrame:
+		return &NewTokenFrame{Token: mode.redact(f.Token)}
+	case *NewConnectionIDFrame:
+		c := *f
+		copy(c.StatelessResetToken[:], mode.redact(f.StatelessResetToken[:]))
+		return &c
+	case *PathChallengeFrame:
+		c := &PathChallengeFrame{}
+		copy(c.Data[:], mode.redact(f.Data[:]))
+		return c
+	case *PathResponseFrame:
+		c := &PathResponseFrame{}
+		copy(c.Data[:], mode.redact(f.Data[:]))
+		return c
+	}
+	return f
+}
+
+func (m RedactionMode) redact(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	r := make([]byte, len(b))
+	if m == RedactionHash && len(b) > 0 {
+		h := sha256.Sum256(b)
+		for i := 0; i < len(r); i += len(h) {
+			copy(r[i:], h[:])
+		}
+	}
+	return r
+}
+
+// LogFrameRedacted is like LogFrame, but uses the given RedactionMode.
+func LogFrameRedacted(logger utils.Logger, frame Frame, sent bool, mode RedactionMode) {
+	if !logger.Debug() {
+		return
+	}
+	logFrame(logger, RedactFrame(frame, mode), sent)
+}
+
+// DescribeFrameRedacted is like DescribeFrame, but redacts sensitive frame contents.
+func DescribeFrameRedacted(f Frame, v protocol.Version, mode RedactionMode) string {
+	return DescribeFrame(RedactFrame(f, mode), v)
+}
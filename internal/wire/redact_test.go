@@ -0,0 +1,79 @@
+package wire
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactFrame(t *testing.T) {
+	sensitive := []byte("secret")
+	for _, f := range []Frame{
+		&StreamFrame{StreamID: 4, Offset: 1337, Data: sensitive, Fin: true, DataLenPresent: true},
+		&CryptoFrame{Offset: 42, Data: sensitive},
+		&DatagramFrame{Data: sensitive, DataLenPresent: true},
+		&NewTokenFrame{Token: sensitive},
+		&NewConnectionIDFrame{
+			SequenceNumber:      1,
+			ConnectionID:        protocol.ParseConnectionID([]byte{1, 2, 3, 4}),
+			StatelessResetToken: protocol.StatelessResetToken{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		},
+		&PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		&PathResponseFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+	} {
+		t.Run(fmt.Sprintf("%T", f), func(t *testing.T) {
+			orig := appendFrames(t, f)
+			require.Same(t, f, RedactFrame(f, RedactionNone))
+
+			omitted := appendFrames(t, RedactFrame(f, RedactionOmit))
+			hashed := appendFrames(t, RedactFrame(f, RedactionHash))
+			// the original frame is not modified
+			require.Equal(t, orig, appendFrames(t, f))
+			// the structure is preserved
+			require.Len(t, omitted, len(orig))
+			require.Len(t, hashed, len(orig))
+			require.NotEqual(t, orig, omitted)
+			require.NotEqual(t, orig, hashed)
+			require.NotEqual(t, omitted, hashed)
+			// hashing is deterministic
+			require.Equal(t, hashed, appendFrames(t, RedactFrame(f, RedactionHash)))
+		})
+	}
+
+	// frames without sensitive contents are not copied
+	f := &MaxDataFrame{MaximumData: 1337}
+	require.Same(t, f, RedactFrame(f, RedactionHash))
+}
+
+func TestRedactFrameRedactsByDefault(t *testing.T) {
+	var mode RedactionMode
+	require.Equal(t, RedactionOmit, mode)
+	f := &NewTokenFrame{Token: []byte("secret")}
+	require.Equal(t, &NewTokenFrame{Token: make([]byte, 6)}, RedactFrame(f, mode))
+}
+
+func TestRedactFrameStructuralFields(t *testing.T) {
+	f := &StreamFrame{StreamID: 4, Offset: 1337, Data: []byte("foobar"), Fin: true, DataLenPresent: true}
+	redacted := RedactFrame(f, RedactionOmit).(*StreamFrame)
+	require.Equal(t, &StreamFrame{StreamID: 4, Offset: 1337, Data: make([]byte, 6), Fin: true, DataLenPresent: true}, redacted)
+}
+
+func TestLogFrameRedacted(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := setupLogTest(t, buf)
+	LogFrameRedacted(logger, &NewTokenFrame{Token: []byte{0xde, 0xad, 0xbe, 0xef}}, true, RedactionOmit)
+	require.Contains(t, buf.String(), "\t-> &wire.NewTokenFrame{Token: 0x00000000")
+	require.NotContains(t, buf.String(), "deadbeef")
+}
+
+func TestDescribeFrameRedacted(t *testing.T) {
+	f := &PathChallengeFrame{Data: [8]byte{0xde, 0xad, 0xbe, 0xef, 0xca, 0xfe, 0xba, 0xbe}}
+	require.Contains(t, DescribeFrame(f, protocol.Version1), "deadbeefcafebabe")
+	desc := DescribeFrameRedacted(f, protocol.Version1, RedactionOmit)
+	require.NotContains(t, desc, "deadbeefcafebabe")
+	require.Contains(t, desc, "Data: 0000000000000000")
+}
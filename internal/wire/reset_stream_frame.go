@@ -17,30 +17,26 @@ type ResetStreamFrame struct {
 }
 
 func parseResetStreamFrame(b []byte, isResetStreamAt bool, _ protocol.Version) (*ResetStreamFrame, int, error) {
-	startLen := len(b)
-	streamID, l, err := quicvarint.Parse(b)
+	c := newCursor(b)
+	streamID, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
-	errorCode, l, err := quicvarint.Parse(b)
+	errorCode, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
-	finalSize, l, err := quicvarint.Parse(b)
+	finalSize, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
 
 	var reliableSize uint64
 	if isResetStreamAt {
-		reliableSize, l, err = quicvarint.Parse(b)
+		reliableSize, err = c.readVarInt()
 		if err != nil {
-			return nil, 0, replaceUnexpectedEOF(err)
+			return nil, 0, err
 		}
-		b = b[l:]
 	}
 	if reliableSize > finalSize {
 		return nil, 0, fmt.Errorf("RESET_STREAM_AT: reliable size can't be larger than final size (%d vs %d)", reliableSize, finalSize)
@@ -51,7 +47,7 @@ func parseResetStreamFrame(b []byte, isResetStreamAt bool, _ protocol.Version) (
 		ErrorCode:    qerr.StreamErrorCode(errorCode),
 		FinalSize:    protocol.ByteCount(finalSize),
 		ReliableSize: protocol.ByteCount(reliableSize),
-	}, startLen - len(b), nil
+	}, c.consumed(), nil
 }
 
 func (f *ResetStreamFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
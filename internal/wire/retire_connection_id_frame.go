@@ -1,7 +1,10 @@
 package wire
 
 import (
+	"fmt"
+
 	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
 	"github.com/quic-go/quic-go/quicvarint"
 )
 
@@ -11,11 +14,12 @@ type RetireConnectionIDFrame struct {
 }
 
 func parseRetireConnectionIDFrame(b []byte, _ protocol.Version) (*RetireConnectionIDFrame, int, error) {
-	seq, l, err := quicvarint.Parse(b)
+	c := newCursor(b)
+	seq, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	return &RetireConnectionIDFrame{SequenceNumber: seq}, l, nil
+	return &RetireConnectionIDFrame{SequenceNumber: seq}, c.consumed(), nil
 }
 
 func (f *RetireConnectionIDFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
@@ -28,3 +32,62 @@ func (f *RetireConnectionIDFrame) Append(b []byte, _ protocol.Version) ([]byte,
 func (f *RetireConnectionIDFrame) Length(protocol.Version) protocol.ByteCount {
 	return 1 + protocol.ByteCount(quicvarint.Len(f.SequenceNumber))
 }
+
+// IssuedConnectionIDs tracks the sequence numbers of the connection IDs issued to the peer,
+// such that RETIRE_CONNECTION_ID frames can be validated.
+// The connection ID used during the handshake has sequence number 0.
+type IssuedConnectionIDs struct {
+	highestSeq uint64
+	active     map[uint64]protocol.ConnectionID
+}
+
+// NewIssuedConnectionIDs creates a new IssuedConnectionIDs,
+// with initialConnID being the connection ID used during the handshake.
+func NewIssuedConnectionIDs(initialConnID protocol.ConnectionID) *IssuedConnectionIDs {
+	return &IssuedConnectionIDs{
+		active: map[uint64]protocol.ConnectionID{0: initialConnID},
+	}
+}
+
+// Issue records that a connection ID was issued in a NEW_CONNECTION_ID frame.
+// Sequence numbers must be increasing.
+func (c *IssuedConnectionIDs) Issue(seq uint64, connID protocol.ConnectionID) {
+	if seq <= c.highestSeq {
+		panic(fmt.Sprintf("connection ID sequence number %d already issued (highest issued: %d)", seq, c.highestSeq))
+	}
+	c.highestSeq = seq
+	c.active[seq] = connID
+}
+
+// Retire validates a RETIRE_CONNECTION_ID frame received in a packet sent to sentWithDestConnID,
+// and removes the retired connection ID.
+// It returns the retired connection ID, or false if the connection ID was already retired (i.e. the frame is a duplicate).
+// Retiring a connection ID that was never issued, or the connection ID that the packet was sent to,
+// is a PROTOCOL_VIOLATION (see section 19.16 of RFC 9000).
+func (c *IssuedConnectionIDs) Retire(f *RetireConnectionIDFrame, sentWithDestConnID protocol.ConnectionID) (protocol.ConnectionID, bool, error) {
+	if f.SequenceNumber > c.highestSeq {
+		return protocol.ConnectionID{}, false, &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			FrameType:    retireConnectionIDFrameType,
+			ErrorMessage: fmt.Sprintf("retired connection ID %d (highest issued: %d)", f.SequenceNumber, c.highestSeq),
+		}
+	}
+	connID, ok := c.active[f.SequenceNumber]
+	if !ok {
+		return protocol.ConnectionID{}, false, nil
+	}
+	if connID == sentWithDestConnID {
+		return protocol.ConnectionID{}, false, &qerr.TransportError{
+			ErrorCode:    qerr.ProtocolViolation,
+			FrameType:    retireConnectionIDFrameType,
+			ErrorMessage: fmt.Sprintf("retired connection ID %d (%s), which was used as the Destination Connection ID on this packet", f.SequenceNumber, connID),
+		}
+	}
+	delete(c.active, f.SequenceNumber)
+	return connID, true, nil
+}
+
+// Active returns the number of connection IDs that were issued and not yet retired.
+func (c *IssuedConnectionIDs) Active() int {
+	return len(c.active)
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
 
 	"github.com/stretchr/testify/require"
 )
@@ -24,7 +25,7 @@ func TestParseRetireConnectionIDErrorsOnEOFs(t *testing.T) {
 	require.Equal(t, len(data), l)
 	for i := range data {
 		_, _, err := parseRetireConnectionIDFrame(data[:i], protocol.Version1)
-		require.Equal(t, io.EOF, err)
+		require.Equal(t, io.ErrUnexpectedEOF, err)
 	}
 }
 
@@ -37,3 +38,60 @@ func TestWriteRetireConnectionID(t *testing.T) {
 	require.Equal(t, expected, b)
 	require.Len(t, b, int(frame.Length(protocol.Version1)))
 }
+
+func TestIssuedConnectionIDsRetire(t *testing.T) {
+	initialConnID := protocol.ParseConnectionID([]byte{1, 2, 3, 4})
+	connID1 := protocol.ParseConnectionID([]byte{5, 6, 7, 8})
+	connID2 := protocol.ParseConnectionID([]byte{9, 10, 11, 12})
+	c := NewIssuedConnectionIDs(initialConnID)
+	c.Issue(1, connID1)
+	c.Issue(2, connID2)
+	require.Equal(t, 3, c.Active())
+
+	connID, ok, err := c.Retire(&RetireConnectionIDFrame{SequenceNumber: 1}, connID2)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, connID1, connID)
+	require.Equal(t, 2, c.Active())
+
+	// duplicate frame
+	_, ok, err = c.Retire(&RetireConnectionIDFrame{SequenceNumber: 1}, connID2)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	connID, ok, err = c.Retire(&RetireConnectionIDFrame{SequenceNumber: 0}, connID2)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, initialConnID, connID)
+	require.Equal(t, 1, c.Active())
+}
+
+func TestIssuedConnectionIDsRetireNeverIssued(t *testing.T) {
+	c := NewIssuedConnectionIDs(protocol.ParseConnectionID([]byte{1, 2, 3, 4}))
+	c.Issue(1, protocol.ParseConnectionID([]byte{5, 6, 7, 8}))
+	_, _, err := c.Retire(&RetireConnectionIDFrame{SequenceNumber: 2}, protocol.ParseConnectionID([]byte{1, 2, 3, 4}))
+	require.Equal(t, &qerr.TransportError{
+		ErrorCode:    qerr.ProtocolViolation,
+		FrameType:    retireConnectionIDFrameType,
+		ErrorMessage: "retired connection ID 2 (highest issued: 1)",
+	}, err)
+}
+
+func TestIssuedConnectionIDsRetireDestConnID(t *testing.T) {
+	connID := protocol.ParseConnectionID([]byte{5, 6, 7, 8})
+	c := NewIssuedConnectionIDs(protocol.ParseConnectionID([]byte{1, 2, 3, 4}))
+	c.Issue(1, connID)
+	_, _, err := c.Retire(&RetireConnectionIDFrame{SequenceNumber: 1}, connID)
+	require.Equal(t, &qerr.TransportError{
+		ErrorCode:    qerr.ProtocolViolation,
+		FrameType:    retireConnectionIDFrameType,
+		ErrorMessage: "retired connection ID 1 (05060708), which was used as the Destination Connection ID on this packet",
+	}, err)
+	require.Equal(t, 2, c.Active())
+}
+
+func TestIssuedConnectionIDsIssueOutOfOrder(t *testing.T) {
+	c := NewIssuedConnectionIDs(protocol.ParseConnectionID([]byte{1, 2, 3, 4}))
+	c.Issue(2, protocol.ParseConnectionID([]byte{5, 6, 7, 8}))
+	require.Panics(t, func() { c.Issue(2, protocol.ParseConnectionID([]byte{9, 10, 11, 12})) })
+}
@@ -0,0 +1,211 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The golden tests in this file pin the codec to fixed encodings, instead of testing it against its own output.
+// Where the RFCs contain examples, these are used, and the source is cited.
+// All other encodings are hand-built from the frame formats defined in section 19 of RFC 9000 and in RFC 9221.
+// Failures are reported as hex strings, which makes them easy to compare with the expected encoding.
+
+// mustDecodeHex decodes a hex string, ignoring whitespace.
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	require.NoError(t, err)
+	return b
+}
+
+// RFC 9000, Appendix A.1: Sample Variable-Length Integer Decoding
+func TestGoldenRFC9000VarInts(t *testing.T) {
+	for _, tc := range []struct {
+		encoded string
+		value   uint64
+		// 0x4025 is a valid, but non-minimal encoding of 37
+		minimal bool
+	}{
+		{encoded: "c2197c5eff14e88c", value: 151288809941952652, minimal: true},
+		{encoded: "9d7f3e7d", value: 494878333, minimal: true},
+		{encoded: "7bbd", value: 15293, minimal: true},
+		{encoded: "25", value: 37, minimal: true},
+		{encoded: "4025", value: 37, minimal: false},
+	} {
+		t.Run(tc.encoded, func(t *testing.T) {
+			b := mustDecodeHex(t, tc.encoded)
+			v, l, err := quicvarint.Parse(b)
+			require.NoError(t, err)
+			require.Equal(t, tc.value, v)
+			require.Equal(t, len(b), l)
+			if tc.minimal {
+				require.Equal(t, tc.encoded, hex.EncodeToString(quicvarint.Append(nil, tc.value)))
+			}
+		})
+	}
+}
+
+var goldenFrames = []struct {
+	name    string
+	encoded string
+	frame   Frame
+}{
+	{
+		// RFC 9001, Appendix A.5
+		name:    "PING",
+		encoded: "01",
+		frame:   &PingFrame{},
+	},
+	{
+		// RFC 9001, Appendix A.3
+		name:    "ACK",
+		encoded: "02 00 00 00 00",
+		frame:   &AckFrame{AckRanges: []AckRange{{Smallest: 0, Largest: 0}}},
+	},
+	{
+		// Largest Acknowledged: 10, ACK Delay: 0, ACK Range Count: 1, First ACK Range: 2,
+		// Gap: 1 (packets 6 and 7 are missing), ACK Range Length: 3
+		name:    "ACK with ranges",
+		encoded: "02 0a 00 01 02 01 03",
+		frame:   &AckFrame{AckRanges: []AckRange{{Smallest: 8, Largest: 10}, {Smallest: 2, Largest: 5}}},
+	},
+	{
+		name:    "ACK_ECN",
+		encoded: "03 0a 00 00 02 01 02 03",
+		frame:   &AckFrame{AckRanges: []AckRange{{Smallest: 8, Largest: 10}}, ECNPresent: true, ECT0: 1, ECT1: 2, ECNCE: 3},
+	},
+	{
+		name:    "RESET_STREAM",
+		encoded: "04 04 4101 7bbd",
+		frame:   &ResetStreamFrame{StreamID: 4, ErrorCode: 0x101, FinalSize: 15293},
+	},
+	{
+		name:    "STOP_SENDING",
+		encoded: "05 08 410c",
+		frame:   &StopSendingFrame{StreamID: 8, ErrorCode: 0x10c},
+	},
+	{
+		name:    "NEW_TOKEN",
+		encoded: "07 03 666f6f",
+		frame:   &NewTokenFrame{Token: []byte("foo")},
+	},
+	{
+		name:    "STREAM with OFF, LEN and FIN bits",
+		encoded: "0f 04 25 02 6869",
+		frame:   &StreamFrame{StreamID: 4, Offset: 37, Data: []byte("hi"), Fin: true, DataLenPresent: true},
+	},
+	{
+		name:    "STREAM without any optional fields",
+		encoded: "08 00 6869",
+		frame:   &StreamFrame{StreamID: 0, Data: []byte("hi")},
+	},
+	{
+		name:    "MAX_DATA",
+		encoded: "10 9d7f3e7d",
+		frame:   &MaxDataFrame{MaximumData: 494878333},
+	},
+	{
+		name:    "MAX_STREAMS (bidirectional)",
+		encoded: "12 4064",
+		frame:   &MaxStreamsFrame{Type: protocol.StreamTypeBidi, MaxStreamNum: 100},
+	},
+	{
+		name:    "STREAMS_BLOCKED (unidirectional)",
+		encoded: "17 03",
+		frame:   &StreamsBlockedFrame{Type: protocol.StreamTypeUni, StreamLimit: 3},
+	},
+	{
+		name:    "NEW_CONNECTION_ID",
+		encoded: "18 02 01 04 deadbeef 00112233445566778899aabbccddeeff",
+		frame: &NewConnectionIDFrame{
+			SequenceNumber:      2,
+			RetirePriorTo:       1,
+			ConnectionID:        protocol.ParseConnectionID([]byte{0xde, 0xad, 0xbe, 0xef}),
+			StatelessResetToken: protocol.StatelessResetToken{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+		},
+	},
+	{
+		name:    "PATH_CHALLENGE",
+		encoded: "1a 0102030405060708",
+		frame:   &PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+	},
+	{
+		// FRAME_ENCODING_ERROR (0x7), triggered by a STREAM frame (0x8)
+		name:    "CONNECTION_CLOSE",
+		encoded: "1c 07 08 03 626164",
+		frame:   &ConnectionCloseFrame{ErrorCode: 0x7, FrameType: 0x8, ReasonPhrase: "bad"},
+	},
+	{
+		name:    "CONNECTION_CLOSE (application)",
+		encoded: "1d 4100 00",
+		frame:   &ConnectionCloseFrame{IsApplicationError: true, ErrorCode: 0x100},
+	},
+	{
+		name:    "HANDSHAKE_DONE",
+		encoded: "1e",
+		frame:   &HandshakeDoneFrame{},
+	},
+	{
+		name:    "DATAGRAM with length",
+		encoded: "31 03 666f6f",
+		frame:   &DatagramFrame{DataLenPresent: true, Data: []byte("foo")},
+	},
+	{
+		name:    "DATAGRAM without length",
+		encoded: "30 666f6f",
+		frame:   &DatagramFrame{Data: []byte("foo")},
+	},
+}
+
+func TestGoldenFrameSerialization(t *testing.T) {
+	for _, tc := range goldenFrames {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := hex.EncodeToString(mustDecodeHex(t, tc.encoded))
+			b, err := tc.frame.Append(nil, protocol.Version1)
+			require.NoError(t, err)
+			require.Equal(t, expected, hex.EncodeToString(b))
+			require.Len(t, b, int(tc.frame.Length(protocol.Version1)))
+		})
+	}
+}
+
+func TestGoldenFrameParsing(t *testing.T) {
+	for _, tc := range goldenFrames {
+		t.Run(tc.name, func(t *testing.T) {
+			b := mustDecodeHex(t, tc.encoded)
+			parser := NewFrameParser(true, true)
+			l, f, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+			require.NoError(t, err)
+			require.Equal(t, len(b), l)
+			require.Equal(t, tc.frame, f)
+		})
+	}
+}
+
+// RFC 9001, Appendix A.2 and A.3: the CRYPTO frames carried in the sample Initial packets
+func TestGoldenRFC9001CryptoFrames(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		header  string
+		dataLen int
+	}{
+		{name: "client Initial", header: "06 00 40f1", dataLen: 241},
+		{name: "server Initial", header: "06 00 405a", dataLen: 90},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := bytes.Repeat([]byte{0x42}, tc.dataLen)
+			b, err := (&CryptoFrame{Data: data}).Append(nil, protocol.Version1)
+			require.NoError(t, err)
+			hdr := mustDecodeHex(t, tc.header)
+			require.Equal(t, hex.EncodeToString(hdr), hex.EncodeToString(b[:len(hdr)]))
+			require.Equal(t, data, b[len(hdr):])
+		})
+	}
+}
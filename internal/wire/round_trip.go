@@ -0,0 +1,93 @@
+package wire
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// A RoundTripMismatch describes a frame that was serialized differently than it was received.
+type RoundTripMismatch struct {
+	FrameType FrameType
+	// Offset is the offset of the frame in the payload.
+	Offset int
+	// Original contains the received bytes of the frame, Reserialized the bytes of the serialized frame.
+	Original, Reserialized []byte
+	// Positions contains the positions in the payload at which the serialized frame differs.
+	// If the serialized frame has a different length, this includes all positions beyond the shorter encoding.
+	Positions []int
+}
+
+// A RoundTripError is returned by VerifyRoundTrip if serializing the parsed frames doesn't reproduce the payload.
+type RoundTripError struct {
+	Mismatches []RoundTripMismatch
+}
+
+func (e *RoundTripError) Error() string {
+	parts := make([]string, 0, len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		parts = append(parts, fmt.Sprintf("%s frame at offset %d differs at positions %v (received %x, serialized %x)", m.FrameType, m.Offset, m.Positions, m.Original, m.Reserialized))
+	}
+	return "round-trip mismatch: " + strings.Join(parts, "; ")
+}
+
+// VerifyRoundTrip parses the payload, serializes every frame and checks that the result is identical to the payload.
+// It returns the parsing error if the payload can't be parsed, and a *RoundTripError if the serialized frames differ.
+// Differences are expected if the peer uses a non-minimal encoding (e.g. for varints), which is valid,
+// so this is a conformance check for fuzzing and interop analysis, and not a validity check.
+func VerifyRoundTrip(b []byte, encLevel protocol.EncryptionLevel, v protocol.Version) error {
+	parser := NewFrameParser(true, true)
+	// ACK frames are serialized using our ACK delay exponent
+	parser.SetAckDelayExponent(protocol.AckDelayExponent)
+
+	var mismatches []RoundTripMismatch
+	var offset int
+	for offset < len(b) {
+		if b[offset] == 0x0 { // PADDING frames are always serialized identically
+			offset++
+			continue
+		}
+		frame, l, err := parser.parseNext(b[offset:], encLevel, v)
+		if err != nil {
+			parseErr := err.(*FrameParsingError)
+			parseErr.Offset += offset
+			return parseErr
+		}
+		original := b[offset : offset+l]
+		reserialized, err := frame.Append(nil, v)
+		if sf, ok := frame.(*StreamFrame); ok {
+			sf.PutBack()
+		}
+		if err != nil {
+			return err
+		}
+		if positions := diffPositions(original, reserialized, offset); len(positions) > 0 {
+			// parseNext succeeded, so the frame type can be decoded
+			typ, _, _ := quicvarint.Parse(original)
+			mismatches = append(mismatches, RoundTripMismatch{
+				FrameType:    FrameType(typ),
+				Offset:       offset,
+				Original:     original,
+				Reserialized: reserialized,
+				Positions:    positions,
+			})
+		}
+		offset += l
+	}
+	if len(mismatches) > 0 {
+		return &RoundTripError{Mismatches: mismatches}
+	}
+	return nil
+}
+
+func diffPositions(a, b []byte, offset int) []int {
+	var positions []int
+	for i := range max(len(a), len(b)) {
+		if i >= len(a) || i >= len(b) || a[i] != b[i] {
+			positions = append(positions, offset+i)
+		}
+	}
+	return positions
+}
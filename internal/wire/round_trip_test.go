@@ -0,0 +1,59 @@
+package wire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyRoundTrip(t *testing.T) {
+	b := appendFrames(t,
+		&AckFrame{AckRanges: []AckRange{{Smallest: 10, Largest: 20}, {Smallest: 1, Largest: 5}}, DelayTime: time.Millisecond},
+		&CryptoFrame{Offset: 10, Data: []byte("crypto")},
+		&StreamFrame{StreamID: 4, Offset: 10, Data: []byte("foo"), DataLenPresent: true},
+		&DatagramFrame{Data: []byte("datagram"), DataLenPresent: true},
+	)
+	b = append(b, make([]byte, 10)...) // PADDING
+	require.NoError(t, VerifyRoundTrip(b, protocol.Encryption1RTT, protocol.Version1))
+}
+
+func TestVerifyRoundTripNonMinimalEncoding(t *testing.T) {
+	ping := appendFrames(t, &PingFrame{})
+	// a MAX_DATA frame with a 2-byte encoding of the value 1
+	b := append(ping, maxDataFrameType)
+	b = quicvarint.AppendWithLen(b, 1, 2)
+
+	err := VerifyRoundTrip(b, protocol.Encryption1RTT, protocol.Version1)
+	require.Equal(t, &RoundTripError{
+		Mismatches: []RoundTripMismatch{{
+			FrameType:    maxDataFrameType,
+			Offset:       1,
+			Original:     []byte{maxDataFrameType, 0x40, 0x1},
+			Reserialized: []byte{maxDataFrameType, 0x1},
+			Positions:    []int{2, 3},
+		}},
+	}, err)
+	require.EqualError(t, err, "round-trip mismatch: MAX_DATA frame at offset 1 differs at positions [2 3] (received 104001, serialized 1001)")
+}
+
+func TestVerifyRoundTripParsingError(t *testing.T) {
+	b := appendFrames(t, &StreamFrame{StreamID: 4, Data: []byte("foo")})
+	err := VerifyRoundTrip(b, protocol.EncryptionInitial, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: 0x8, ErrorCode: qerr.FrameEncodingError})
+}
+
+func TestVerifyRoundTripParsingErrorOffset(t *testing.T) {
+	b := appendFrames(t, &PingFrame{}, &PingFrame{})
+	// a MAX_STREAM_DATA frame that's missing the maximum stream data
+	b = append(b, maxStreamDataFrameType, 0x5)
+	err := VerifyRoundTrip(b, protocol.Encryption1RTT, protocol.Version1)
+	var parseErr *FrameParsingError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, 2, parseErr.Offset)
+	require.Equal(t, uint64(maxStreamDataFrameType), parseErr.Err.FrameType)
+}
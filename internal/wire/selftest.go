@@ -0,0 +1,159 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// SelfTest checks that the codec works correctly on the current platform.
+// It encodes and decodes varints at the boundaries of every encoding length,
+// serializes and parses a frame of every frame type, and checks that limits are enforced.
+// It is meant to be run once at startup by deployments that want to verify the codec
+// on the target architecture before serving traffic. It doesn't have any side effects.
+func SelfTest() error {
+	if err := selfTestVarInts(); err != nil {
+		return fmt.Errorf("self test: varints: %w", err)
+	}
+	if err := selfTestFrames(); err != nil {
+		return fmt.Errorf("self test: frames: %w", err)
+	}
+	if err := selfTestLimits(); err != nil {
+		return fmt.Errorf("self test: limits: %w", err)
+	}
+	return nil
+}
+
+func selfTestVarInts() error {
+	for _, tc := range []struct {
+		value  uint64
+		length int
+	}{
+		{0, 1}, {63, 1},
+		{64, 2}, {16383, 2},
+		{16384, 4}, {1<<30 - 1, 4},
+		{1 << 30, 8}, {quicvarint.Max, 8},
+	} {
+		b := quicvarint.Append(nil, tc.value)
+		if len(b) != tc.length || quicvarint.Len(tc.value) != tc.length {
+			return fmt.Errorf("%d encoded in %d bytes, expected %d", tc.value, len(b), tc.length)
+		}
+		v, l, err := quicvarint.Parse(b)
+		if err != nil {
+			return err
+		}
+		if v != tc.value || l != tc.length {
+			return fmt.Errorf("%d decoded as %d (%d bytes)", tc.value, v, l)
+		}
+	}
+	return nil
+}
+
+func selfTestFrames() error {
+	connID := protocol.ParseConnectionID([]byte{0xde, 0xad, 0xbe, 0xef})
+	frames := []Frame{
+		&PingFrame{},
+		&AckFrame{
+			AckRanges: []AckRange{{Smallest: 1000, Largest: 1 << 40}, {Smallest: 1, Largest: 10}},
+			DelayTime: 42 * time.Millisecond,
+			ECT0:      1, ECT1: 2, ECNCE: 3,
+		},
+		&ResetStreamFrame{StreamID: 4, ErrorCode: 1 << 40, FinalSize: 1 << 50},
+		&ResetStreamFrame{StreamID: 4, ErrorCode: 42, FinalSize: 1 << 50, ReliableSize: 1 << 20},
+		&StopSendingFrame{StreamID: 1 << 60, ErrorCode: 42},
+		&CryptoFrame{Offset: 1 << 20, Data: []byte("foobar")},
+		&NewTokenFrame{Token: []byte("token")},
+		&MaxDataFrame{MaximumData: quicvarint.Max},
+		&MaxStreamDataFrame{StreamID: 4, MaximumStreamData: 1 << 30},
+		&MaxStreamsFrame{Type: protocol.StreamTypeBidi, MaxStreamNum: protocol.MaxStreamCount},
+		&DataBlockedFrame{MaximumData: 16384},
+		&StreamDataBlockedFrame{StreamID: 4, MaximumStreamData: 64},
+		&StreamsBlockedFrame{Type: protocol.StreamTypeUni, StreamLimit: 1337},
+		&NewConnectionIDFrame{SequenceNumber: 1, RetirePriorTo: 1, ConnectionID: connID, StatelessResetToken: protocol.StatelessResetToken{1, 2, 3}},
+		&RetireConnectionIDFrame{SequenceNumber: 1 << 30},
+		&PathChallengeFrame{Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		&PathResponseFrame{Data: [8]byte{8, 7, 6, 5, 4, 3, 2, 1}},
+		&HandshakeDoneFrame{},
+		&DatagramFrame{Data: []byte("datagram"), DataLenPresent: true},
+		&ConnectionCloseFrame{ErrorCode: 0x1, FrameType: 0x8, ReasonPhrase: "reason"},
+		&StreamFrame{StreamID: 1 << 40, Offset: 1 << 50, Data: []byte("foobar"), Fin: true},
+	}
+	var payload []byte
+	for _, f := range frames {
+		l := len(payload)
+		var err error
+		payload, err = f.Append(payload, protocol.Version1)
+		if err != nil {
+			return err
+		}
+		if got := protocol.ByteCount(len(payload) - l); got != f.Length(protocol.Version1) {
+			return fmt.Errorf("%T has length %d, serialized to %d bytes", f, f.Length(protocol.Version1), got)
+		}
+	}
+	if err := VerifyRoundTrip(payload, protocol.Encryption1RTT, protocol.Version1); err != nil {
+		return err
+	}
+
+	parser := NewFrameParser(true, true)
+	parser.SetAckDelayExponent(protocol.AckDelayExponent)
+	var i int
+	for f, err := range parser.ParseAll(payload, protocol.Encryption1RTT, protocol.Version1) {
+		if err != nil {
+			return err
+		}
+		if i >= len(frames) {
+			return errors.New("parsed more frames than serialized")
+		}
+		if ack, ok := f.(*AckFrame); ok {
+			if ack.DelayTime != frames[i].(*AckFrame).DelayTime {
+				return fmt.Errorf("ACK delay parsed as %s", ack.DelayTime)
+			}
+		}
+		if sf, ok := f.(*StreamFrame); ok {
+			sf.PutBack()
+		}
+		i++
+	}
+	if i != len(frames) {
+		return fmt.Errorf("parsed %d frames, expected %d", i, len(frames))
+	}
+	return nil
+}
+
+func selfTestLimits() error {
+	parser := NewFrameParser(true, true)
+	// stream data beyond the maximum offset
+	if _, err := (&StreamFrame{StreamID: 4, Offset: protocol.MaxByteCount - 1, Data: []byte("foo")}).Append(nil, protocol.Version1); err == nil {
+		return errors.New("serialized a STREAM frame beyond the maximum offset")
+	}
+	b := quicvarint.Append([]byte{0xc}, 4)
+	b = quicvarint.Append(b, uint64(protocol.MaxByteCount-1))
+	b = append(b, []byte("foo")...)
+	if _, _, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1); err == nil {
+		return errors.New("parsed a STREAM frame beyond the maximum offset")
+	}
+	// truncated frames
+	b, err := (&MaxStreamDataFrame{StreamID: 4, MaximumStreamData: 1 << 30}).Append(nil, protocol.Version1)
+	if err != nil {
+		return err
+	}
+	_, _, err = parser.ParseNext(b[:len(b)-1], protocol.Encryption1RTT, protocol.Version1)
+	var transportErr *qerr.TransportError
+	if !errors.As(err, &transportErr) || transportErr.ErrorCode != qerr.FrameEncodingError || transportErr.ErrorMessage != io.ErrUnexpectedEOF.Error() {
+		return fmt.Errorf("truncated frame: unexpected error %v", err)
+	}
+	// frames not allowed at the encryption level
+	b, err = (&MaxDataFrame{MaximumData: 1337}).Append(nil, protocol.Version1)
+	if err != nil {
+		return err
+	}
+	if _, _, err := parser.ParseNext(b, protocol.EncryptionInitial, protocol.Version1); err == nil {
+		return errors.New("parsed a MAX_DATA frame at the Initial encryption level")
+	}
+	return nil
+}
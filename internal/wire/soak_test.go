@@ -0,0 +1,137 @@
+//go:build soak
+
+package wire
+
+import (
+	"flag"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The soak test parses a large number of generated frames, and makes sure that the heap doesn't grow over time.
+// This catches slow leaks (e.g. STREAM frames that are never returned to the pool) that unit tests can't detect.
+//
+// Run using:
+//
+//	go test -tags soak -run TestFrameParserSoak -timeout 0 -soak.frames=<frames>
+var (
+	soakFrames        = flag.Int64("soak.frames", 1e9, "number of frames to parse")
+	soakInterval      = flag.Int64("soak.interval", 1e7, "number of frames parsed between two heap measurements")
+	soakMaxHeapGrowth = flag.Uint64("soak.max-heap-growth", 16<<20, "maximum growth of the heap (in bytes) over the course of the test")
+	soakMaxPoolMisses = flag.Int64("soak.max-pool-misses", 1024, "maximum number of STREAM frames allocated by the pool per interval")
+)
+
+const soakPayloads = 4096
+
+// generateSoakPayloads generates packet payloads containing random frames that can be parsed.
+func generateSoakPayloads(t *testing.T, r *rand.Rand) [][]byte {
+	parser := NewFrameParser(true, true)
+	parser.SetStrictnessProfile(ProfileAnalyzer)
+	parses := func(b []byte) bool {
+		parser.StartPayload()
+		for len(b) > 0 {
+			l, f, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+			if err != nil {
+				return false
+			}
+			if sf, ok := f.(*StreamFrame); ok {
+				sf.PutBack()
+			}
+			b = b[l:]
+		}
+		return true
+	}
+
+	payloads := make([][]byte, 0, soakPayloads)
+	for len(payloads) < soakPayloads {
+		var b []byte
+		for len(b) < int(protocol.MaxPacketBufferSize) {
+			f := randFrame(r)
+			// all frames except for the last one need a Length field
+			switch f := f.(type) {
+			case *StreamFrame:
+				f.DataLenPresent = true
+			case *DatagramFrame:
+				f.DataLenPresent = true
+			}
+			fb, err := f.Append(nil, protocol.Version1)
+			require.NoError(t, err)
+			if len(b) > 0 && len(b)+len(fb) > int(protocol.MaxPacketBufferSize) {
+				break
+			}
+			if !parses(fb) {
+				continue
+			}
+			b = append(b, fb...)
+		}
+		if parses(b) {
+			payloads = append(payloads, b)
+		}
+	}
+	return payloads
+}
+
+func heapInUse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
+
+func TestFrameParserSoak(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	t.Logf("seed: %d", seed)
+	payloads := generateSoakPayloads(t, rand.New(rand.NewPCG(seed, seed)))
+
+	// count the STREAM frames allocated because the pool was empty
+	var poolMisses atomic.Int64
+	newStreamFrame := pool.New
+	pool.New = func() any {
+		poolMisses.Add(1)
+		return newStreamFrame()
+	}
+	t.Cleanup(func() { pool.New = newStreamFrame })
+
+	parser := NewFrameParser(true, true)
+	parser.SetStrictnessProfile(ProfileAnalyzer)
+	var parsed int64
+	// the first measurement is taken after a warm-up interval
+	nextMeasurement := *soakInterval
+	var baseline uint64
+	for i := 0; parsed < *soakFrames; i++ {
+		b := payloads[i%len(payloads)]
+		parser.StartPayload()
+		for len(b) > 0 {
+			l, f, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+			require.NoError(t, err)
+			if sf, ok := f.(*StreamFrame); ok {
+				sf.PutBack()
+			}
+			b = b[l:]
+			parsed++
+		}
+
+		if parsed < nextMeasurement {
+			continue
+		}
+		nextMeasurement = parsed + *soakInterval
+		heap := heapInUse()
+		misses := poolMisses.Swap(0)
+		if baseline == 0 {
+			baseline = heap
+			continue
+		}
+		t.Logf("%d frames parsed, heap in use: %d bytes (baseline: %d bytes), pool misses: %d", parsed, heap, baseline, misses)
+		if heap > baseline {
+			require.LessOrEqual(t, heap-baseline, *soakMaxHeapGrowth, "heap grew by %d bytes after parsing %d frames", heap-baseline, parsed)
+		}
+		require.LessOrEqual(t, misses, *soakMaxPoolMisses, "pool allocated %d STREAM frames after parsing %d frames", misses, parsed)
+	}
+}
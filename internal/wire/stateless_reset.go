@@ -0,0 +1,21 @@
+package wire
+
+import (
+	"io"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// AppendStatelessReset appends a Stateless Reset (see section 10.3 of RFC 9000) to b.
+// The token is preceded by unpredictable bits read from r,
+// such that the packet is indistinguishable from a short header packet.
+// It returns an error if reading from r fails.
+func AppendStatelessReset(b []byte, token protocol.StatelessResetToken, r io.Reader) ([]byte, error) {
+	start := len(b)
+	b = append(b, make([]byte, protocol.MinStatelessResetSize-len(token))...)
+	if _, err := io.ReadFull(r, b[start:]); err != nil {
+		return b[:start], err
+	}
+	b[start] = (b[start] & 0x7f) | 0x40
+	return append(b, token[:]...), nil
+}
@@ -0,0 +1,29 @@
+package wire
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendStatelessReset(t *testing.T) {
+	token := protocol.StatelessResetToken{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	b, err := AppendStatelessReset([]byte("foo"), token, bytes.NewReader(bytes.Repeat([]byte{0xff}, 100)))
+	require.NoError(t, err)
+	require.Equal(t, []byte("foo"), b[:3])
+	b = b[3:]
+	require.Len(t, b, protocol.MinStatelessResetSize)
+	require.Equal(t, byte(0x7f), b[0])
+	require.False(t, IsLongHeaderPacket(b[0]))
+	require.Equal(t, bytes.Repeat([]byte{0xff}, protocol.MinStatelessResetSize-17), b[1:protocol.MinStatelessResetSize-16])
+	require.Equal(t, token[:], b[protocol.MinStatelessResetSize-16:])
+
+	// errors reading the randomness are returned
+	b, err = AppendStatelessReset([]byte("foo"), token, bytes.NewReader([]byte{1, 2, 3}))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.Equal(t, []byte("foo"), b)
+}
@@ -14,22 +14,20 @@ type StopSendingFrame struct {
 
 // parseStopSendingFrame parses a STOP_SENDING frame
 func parseStopSendingFrame(b []byte, _ protocol.Version) (*StopSendingFrame, int, error) {
-	startLen := len(b)
-	streamID, l, err := quicvarint.Parse(b)
+	c := newCursor(b)
+	streamID, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
-	errorCode, l, err := quicvarint.Parse(b)
+	errorCode, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
 
 	return &StopSendingFrame{
 		StreamID:  protocol.StreamID(streamID),
 		ErrorCode: qerr.StreamErrorCode(errorCode),
-	}, startLen - len(b), nil
+	}, c.consumed(), nil
 }
 
 // Length of a written frame
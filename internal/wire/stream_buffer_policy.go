@@ -0,0 +1,50 @@
+package wire
+
+import "github.com/quic-go/quic-go/internal/protocol"
+
+// largeStreamFrameThreshold is the average STREAM frame data length above which
+// a connection is considered to carry large frames.
+const largeStreamFrameThreshold = protocol.MaxPacketBufferSize / 2
+
+// A streamBufferPolicy chooses the buffer for the data of received STREAM frames,
+// adapting to the sizes of the STREAM frames recently received on the connection:
+//   - Frames smaller than MinStreamFrameBufferSize are allocated, since they're cheap to allocate.
+//   - On connections carrying large frames, all other frames use buffers of the maximum packet size.
+//     That way, a single pool is kept warm, and the buffers are reused when the frame size varies.
+//   - Otherwise, frames use the smallest pooled buffer that fits,
+//     so that connections carrying small frames don't pin buffers of the maximum packet size.
+//
+// A nil streamBufferPolicy always uses the smallest buffer that fits.
+type streamBufferPolicy struct {
+	// exponentially weighted moving average of the data length, scaled by 8
+	avgLen uint64
+}
+
+func (p *streamBufferPolicy) newStreamFrame(dataLen uint64) *StreamFrame {
+	if p != nil {
+		if p.avgLen == 0 {
+			p.avgLen = dataLen << 3
+		} else {
+			p.avgLen = p.avgLen - p.avgLen>>3 + dataLen
+		}
+	}
+	if dataLen < protocol.MinStreamFrameBufferSize {
+		f := &StreamFrame{}
+		if dataLen > 0 {
+			f.Data = make([]byte, dataLen)
+		}
+		return f
+	}
+	var f *StreamFrame
+	if dataLen <= smallStreamFrameBufferSize && !p.carriesLargeFrames() {
+		f = getSmallStreamFrame()
+	} else {
+		f = GetStreamFrame()
+	}
+	f.Data = f.Data[:dataLen]
+	return f
+}
+
+func (p *streamBufferPolicy) carriesLargeFrames() bool {
+	return p != nil && p.avgLen>>3 >= uint64(largeStreamFrameThreshold)
+}
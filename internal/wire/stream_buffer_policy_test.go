@@ -0,0 +1,80 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamBufferPolicy(t *testing.T) {
+	var p streamBufferPolicy
+
+	// tiny frames are allocated
+	f := p.newStreamFrame(10)
+	require.False(t, f.fromPool)
+	require.Len(t, f.Data, 10)
+
+	// small frames use the small pool
+	f = p.newStreamFrame(smallStreamFrameBufferSize)
+	require.True(t, f.fromPool)
+	require.Len(t, f.Data, smallStreamFrameBufferSize)
+	require.Equal(t, smallStreamFrameBufferSize, cap(f.Data))
+	f.PutBack()
+
+	f = p.newStreamFrame(smallStreamFrameBufferSize + 1)
+	require.True(t, f.fromPool)
+	require.Equal(t, int(protocol.MaxPacketBufferSize), cap(f.Data))
+	f.PutBack()
+
+	// after receiving a number of large frames, small frames use the large pool as well
+	for range 20 {
+		p.newStreamFrame(uint64(protocol.MaxPacketBufferSize)).PutBack()
+	}
+	require.True(t, p.carriesLargeFrames())
+	f = p.newStreamFrame(smallStreamFrameBufferSize)
+	require.Equal(t, int(protocol.MaxPacketBufferSize), cap(f.Data))
+	f.PutBack()
+
+	// once the connection carries small frames again, the small pool is used again
+	for range 20 {
+		p.newStreamFrame(200).PutBack()
+	}
+	require.False(t, p.carriesLargeFrames())
+	f = p.newStreamFrame(200)
+	require.Equal(t, smallStreamFrameBufferSize, cap(f.Data))
+	f.PutBack()
+}
+
+func TestStreamBufferPolicyNil(t *testing.T) {
+	var p *streamBufferPolicy
+	f := p.newStreamFrame(200)
+	require.Equal(t, smallStreamFrameBufferSize, cap(f.Data))
+	f.PutBack()
+	f = p.newStreamFrame(1000)
+	require.Equal(t, int(protocol.MaxPacketBufferSize), cap(f.Data))
+	f.PutBack()
+}
+
+func TestFrameParserStreamBufferAdaptation(t *testing.T) {
+	parser := NewFrameParser(false, false)
+	parse := func(dataLen int) *StreamFrame {
+		t.Helper()
+		b, err := (&StreamFrame{StreamID: 4, Data: make([]byte, dataLen)}).Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		_, f, err := parser.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+		require.NoError(t, err)
+		return f.(*StreamFrame)
+	}
+
+	f := parse(200)
+	require.Equal(t, smallStreamFrameBufferSize, cap(f.Data))
+	f.PutBack()
+	for range 20 {
+		parse(1000).PutBack()
+	}
+	f = parse(200)
+	require.Equal(t, int(protocol.MaxPacketBufferSize), cap(f.Data))
+	f.PutBack()
+}
@@ -12,21 +12,20 @@ type StreamDataBlockedFrame struct {
 }
 
 func parseStreamDataBlockedFrame(b []byte, _ protocol.Version) (*StreamDataBlockedFrame, int, error) {
-	startLen := len(b)
-	sid, l, err := quicvarint.Parse(b)
+	c := newCursor(b)
+	sid, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
-	offset, l, err := quicvarint.Parse(b)
+	offset, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
 
 	return &StreamDataBlockedFrame{
 		StreamID:          protocol.StreamID(sid),
 		MaximumStreamData: protocol.ByteCount(offset),
-	}, startLen - len(b) + l, nil
+	}, c.consumed(), nil
 }
 
 func (f *StreamDataBlockedFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
@@ -15,87 +15,88 @@ type StreamFrame struct {
 	Data           []byte
 	Fin            bool
 	DataLenPresent bool
+	// OffsetPresent forces the Offset field to be written, even if the offset is 0.
+	// The parser sets it for frames carrying an explicit zero Offset field,
+	// such that parsed frames are serialized exactly as they were received.
+	OffsetPresent bool
+	// Provenance records where the data was originally sent.
+	Provenance Provenance
 
 	fromPool bool
 }
 
-func parseStreamFrame(b []byte, typ uint64, _ protocol.Version) (*StreamFrame, int, error) {
-	startLen := len(b)
+func parseStreamFrame(b []byte, typ uint64, v protocol.Version) (*StreamFrame, int, error) {
+	return parseStreamFrameWithPolicy(b, typ, v, nil)
+}
+
+// parseStreamFrameWithPolicy parses a STREAM frame.
+// If policy is nil, the buffer of the frame is chosen based on the length of its data alone.
+func parseStreamFrameWithPolicy(b []byte, typ uint64, _ protocol.Version, policy *streamBufferPolicy) (*StreamFrame, int, error) {
+	c := newCursor(b)
 	hasOffset := typ&0b100 > 0
 	fin := typ&0b1 > 0
 	hasDataLen := typ&0b10 > 0
 
-	streamID, l, err := quicvarint.Parse(b)
+	streamID, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
-	b = b[l:]
 	var offset uint64
 	if hasOffset {
-		offset, l, err = quicvarint.Parse(b)
+		offset, err = c.readVarInt()
 		if err != nil {
-			return nil, 0, replaceUnexpectedEOF(err)
+			return nil, 0, err
 		}
-		b = b[l:]
 	}
 
-	var dataLen uint64
+	// If there's no Length field, the rest of the packet is data
+	dataLen := uint64(c.remaining())
 	if hasDataLen {
-		var err error
-		var l int
-		dataLen, l, err = quicvarint.Parse(b)
+		dataLen, err = c.readVarInt()
 		if err != nil {
-			return nil, 0, replaceUnexpectedEOF(err)
+			return nil, 0, err
 		}
-		b = b[l:]
-		if dataLen > uint64(len(b)) {
-			return nil, 0, io.EOF
-		}
-	} else {
-		// The rest of the packet is data
-		dataLen = uint64(len(b))
+	}
+	data, err := c.readBytes(dataLen)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	var frame *StreamFrame
-	if dataLen < protocol.MinStreamFrameBufferSize {
-		frame = &StreamFrame{}
-		if dataLen > 0 {
-			frame.Data = make([]byte, dataLen)
-		}
-	} else {
-		frame = GetStreamFrame()
-		// The STREAM frame can't be larger than the StreamFrame we obtained from the buffer,
-		// since those StreamFrames have a buffer length of the maximum packet size.
-		if dataLen > uint64(cap(frame.Data)) {
-			return nil, 0, io.EOF
-		}
-		frame.Data = frame.Data[:dataLen]
+	// The STREAM frame can't be larger than the largest pooled buffer,
+	// since those buffers have the maximum packet size.
+	if dataLen > uint64(protocol.MaxPacketBufferSize) {
+		return nil, 0, io.ErrUnexpectedEOF
 	}
+	frame := policy.newStreamFrame(dataLen)
 
 	frame.StreamID = protocol.StreamID(streamID)
 	frame.Offset = protocol.ByteCount(offset)
 	frame.Fin = fin
 	frame.DataLenPresent = hasDataLen
+	frame.OffsetPresent = hasOffset && offset == 0
 
 	if dataLen > 0 {
-		copy(frame.Data, b)
+		copy(frame.Data, data)
 	}
 	if frame.Offset+frame.DataLen() > protocol.MaxByteCount {
 		return nil, 0, errors.New("stream data overflows maximum offset")
 	}
-	return frame, startLen - len(b) + int(dataLen), nil
+	return frame, c.consumed(), nil
 }
 
 func (f *StreamFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
-	if len(f.Data) == 0 && !f.Fin {
+	if f.IsEmpty() {
 		return nil, errors.New("StreamFrame: attempting to write empty frame without FIN")
 	}
+	if f.Offset+f.DataLen() > protocol.MaxByteCount {
+		return nil, errors.New("StreamFrame: stream data overflows maximum offset")
+	}
 
 	typ := byte(0x8)
 	if f.Fin {
 		typ ^= 0b1
 	}
-	hasOffset := f.Offset != 0
+	hasOffset := f.hasOffset()
 	if f.DataLenPresent {
 		typ ^= 0b10
 	}
@@ -117,7 +118,7 @@ func (f *StreamFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
 // Length returns the total length of the STREAM frame
 func (f *StreamFrame) Length(protocol.Version) protocol.ByteCount {
 	length := 1 + quicvarint.Len(uint64(f.StreamID))
-	if f.Offset != 0 {
+	if f.hasOffset() {
 		length += quicvarint.Len(uint64(f.Offset))
 	}
 	if f.DataLenPresent {
@@ -126,16 +127,40 @@ func (f *StreamFrame) Length(protocol.Version) protocol.ByteCount {
 	return protocol.ByteCount(length) + f.DataLen()
 }
 
+func (f *StreamFrame) hasOffset() bool {
+	return f.Offset != 0 || f.OffsetPresent
+}
+
+// IsFinOnly returns true if the frame doesn't carry any data, but has the FIN bit set.
+// Such a frame is sent to close a stream after all data has been sent,
+// and sets the final size of the stream to its offset.
+func (f *StreamFrame) IsFinOnly() bool {
+	return len(f.Data) == 0 && f.Fin
+}
+
+// IsEmpty returns true if the frame carries neither data nor the FIN bit.
+// Receiving such a frame is valid (RFC 9000 doesn't forbid it), but it carries no information.
+// It is never sent, and trying to serialize it results in an error.
+func (f *StreamFrame) IsEmpty() bool {
+	return len(f.Data) == 0 && !f.Fin
+}
+
 // DataLen gives the length of data in bytes
 func (f *StreamFrame) DataLen() protocol.ByteCount {
 	return protocol.ByteCount(len(f.Data))
 }
 
+// ExtendsToEndOfPacket returns true if the frame doesn't have a Length field,
+// and therefore consumes the rest of the packet.
+func (f *StreamFrame) ExtendsToEndOfPacket() bool {
+	return !f.DataLenPresent
+}
+
 // MaxDataLen returns the maximum data length
 // If 0 is returned, writing will fail (a STREAM frame must contain at least 1 byte of data).
 func (f *StreamFrame) MaxDataLen(maxSize protocol.ByteCount, _ protocol.Version) protocol.ByteCount {
 	headerLen := 1 + protocol.ByteCount(quicvarint.Len(uint64(f.StreamID)))
-	if f.Offset != 0 {
+	if f.hasOffset() {
 		headerLen += protocol.ByteCount(quicvarint.Len(uint64(f.Offset)))
 	}
 	if f.DataLenPresent {
@@ -173,6 +198,8 @@ func (f *StreamFrame) MaybeSplitOffFrame(maxSize protocol.ByteCount, version pro
 	new.Offset = f.Offset
 	new.Fin = false
 	new.DataLenPresent = f.DataLenPresent
+	new.OffsetPresent = f.OffsetPresent
+	new.Provenance = f.Provenance
 
 	// swap the data slices
 	new.Data, f.Data = f.Data, new.Data
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/quic-go/quic-go/internal/protocol"
 
@@ -73,7 +74,7 @@ func TestParseStreamFrameRejectsLongFrames(t *testing.T) {
 	data = append(data, encodeVarInt(uint64(protocol.MaxPacketBufferSize)+1)...) // data length
 	data = append(data, make([]byte, protocol.MaxPacketBufferSize+1)...)
 	_, _, err := parseStreamFrame(data, 0x8^0x2, protocol.Version1)
-	require.Equal(t, io.EOF, err)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
 }
 
 func TestParseStreamFrameRejectsFramesExceedingRemainingSize(t *testing.T) {
@@ -81,7 +82,7 @@ func TestParseStreamFrameRejectsFramesExceedingRemainingSize(t *testing.T) {
 	data = append(data, encodeVarInt(7)...) // data length
 	data = append(data, []byte("foobar")...)
 	_, _, err := parseStreamFrame(data, 0x8^0x2, protocol.Version1)
-	require.Equal(t, io.EOF, err)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
 }
 
 func TestParseStreamFrameErrorsOnEOFs(t *testing.T) {
@@ -292,6 +293,17 @@ func TestStreamSplitting(t *testing.T) {
 	require.Equal(t, []byte("bar"), f.Data)
 }
 
+func TestStreamSplittingPreservesProvenance(t *testing.T) {
+	prov := Provenance{PacketNumber: 42, EnqueueTime: time.Now()}
+	f := &StreamFrame{StreamID: 0x1337, Data: []byte("foobar"), Provenance: prov}
+	frame, needsSplit := f.MaybeSplitOffFrame(f.Length(protocol.Version1)-3, protocol.Version1)
+	require.True(t, needsSplit)
+	require.Equal(t, prov, frame.Provenance)
+	require.Equal(t, prov, f.Provenance)
+	f.PutBack()
+	require.False(t, GetStreamFrame().Provenance.IsSet())
+}
+
 func TestStreamSplittingNoSplitForShortFrame(t *testing.T) {
 	f := &StreamFrame{
 		StreamID:       0x1337,
@@ -377,3 +389,80 @@ func TestStreamSplittingProducesCorrectLengthFramesWithDataLen(t *testing.T) {
 	}
 	require.Equal(t, 1, frameOneByteTooSmallCounter)
 }
+
+func TestStreamFrameZeroLength(t *testing.T) {
+	t.Run("FIN-only", func(t *testing.T) {
+		data := encodeVarInt(4)                    // stream ID
+		data = append(data, encodeVarInt(1337)...) // offset
+		f, l, err := parseStreamFrame(data, 0x8^0x4^0x1, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, len(data), l)
+		require.True(t, f.IsFinOnly())
+		require.False(t, f.IsEmpty())
+		require.Zero(t, f.DataLen())
+	})
+
+	t.Run("without FIN", func(t *testing.T) {
+		data := encodeVarInt(4)                    // stream ID
+		data = append(data, encodeVarInt(1337)...) // offset
+		f, _, err := parseStreamFrame(data, 0x8^0x4, protocol.Version1)
+		require.NoError(t, err)
+		require.True(t, f.IsEmpty())
+		require.False(t, f.IsFinOnly())
+		// empty frames are accepted, but never sent
+		_, err = f.Append(nil, protocol.Version1)
+		require.EqualError(t, err, "StreamFrame: attempting to write empty frame without FIN")
+	})
+}
+
+func TestStreamFrameMaxOffset(t *testing.T) {
+	t.Run("FIN-only at the maximum offset", func(t *testing.T) {
+		data := encodeVarInt(4)                                             // stream ID
+		data = append(data, encodeVarInt(uint64(protocol.MaxByteCount))...) // offset
+		f, _, err := parseStreamFrame(data, 0x8^0x4^0x1, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, protocol.MaxByteCount, f.Offset)
+		require.True(t, f.IsFinOnly())
+		b, err := f.Append(nil, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, append([]byte{0x8 ^ 0x4 ^ 0x1}, data...), b)
+		require.Len(t, b, int(f.Length(protocol.Version1)))
+	})
+
+	t.Run("data ending at the maximum offset", func(t *testing.T) {
+		data := encodeVarInt(4)                                               // stream ID
+		data = append(data, encodeVarInt(uint64(protocol.MaxByteCount-3))...) // offset
+		data = append(data, []byte("foo")...)
+		f, _, err := parseStreamFrame(data, 0x8^0x4, protocol.Version1)
+		require.NoError(t, err)
+		require.Equal(t, protocol.MaxByteCount, f.Offset+f.DataLen())
+	})
+
+	t.Run("data beyond the maximum offset", func(t *testing.T) {
+		data := encodeVarInt(4)                                             // stream ID
+		data = append(data, encodeVarInt(uint64(protocol.MaxByteCount))...) // offset
+		data = append(data, 'a')
+		_, _, err := parseStreamFrame(data, 0x8^0x4, protocol.Version1)
+		require.EqualError(t, err, "stream data overflows maximum offset")
+
+		f := &StreamFrame{StreamID: 4, Offset: protocol.MaxByteCount, Data: []byte("a")}
+		_, err = f.Append(nil, protocol.Version1)
+		require.EqualError(t, err, "StreamFrame: stream data overflows maximum offset")
+	})
+}
+
+func TestStreamFrameRoundTripExplicitZeroOffset(t *testing.T) {
+	data := encodeVarInt(4)                 // stream ID
+	data = append(data, encodeVarInt(0)...) // offset
+	data = append(data, encodeVarInt(0)...) // data length
+	f, l, err := parseStreamFrame(data, 0x8^0x4^0x2^0x1, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(data), l)
+	require.True(t, f.OffsetPresent)
+	require.True(t, f.IsFinOnly())
+	b, err := f.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{0x8 ^ 0x4 ^ 0x2 ^ 0x1}, data...), b)
+	require.Equal(t, protocol.ByteCount(len(b)), f.Length(protocol.Version1))
+	require.Equal(t, f.MaxDataLen(f.Length(protocol.Version1), protocol.Version1), protocol.ByteCount(0))
+}
@@ -0,0 +1,142 @@
+package wire
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+)
+
+// A ByteRange is a range of stream data, from Start (inclusive) to End (exclusive).
+type ByteRange struct {
+	Start, End protocol.ByteCount
+}
+
+// Len returns the number of bytes in the range.
+func (r ByteRange) Len() protocol.ByteCount { return r.End - r.Start }
+
+// A ReceivedRanges tracks which ranges of a stream have been received, for the purpose of reassembly.
+// It doesn't store the data itself.
+// It also checks that the peer doesn't violate the final size of the stream (see section 4.5 of RFC 9000).
+// The zero value is a ReceivedRanges for a stream of which no data has been received.
+type ReceivedRanges struct {
+	// sorted, non-overlapping and non-adjacent
+	ranges []ByteRange
+
+	highestOffset protocol.ByteCount
+	finalSize     protocol.ByteCount
+	hasFinalSize  bool
+}
+
+// Insert records the receipt of data at [offset, offset+length).
+// If fin is set, offset+length is the final size of the stream.
+// It returns a FINAL_SIZE_ERROR if the data is inconsistent with the final size.
+func (r *ReceivedRanges) Insert(offset, length protocol.ByteCount, fin bool) error {
+	end := offset + length
+	if r.hasFinalSize {
+		if fin && end != r.finalSize {
+			return &qerr.TransportError{
+				ErrorCode:    qerr.FinalSizeError,
+				ErrorMessage: fmt.Sprintf("final size changed (old: %d, new: %d)", r.finalSize, end),
+			}
+		}
+		if end > r.finalSize {
+			return &qerr.TransportError{
+				ErrorCode:    qerr.FinalSizeError,
+				ErrorMessage: fmt.Sprintf("data beyond the final size (final size: %d, offset: %d)", r.finalSize, end),
+			}
+		}
+	} else if fin {
+		if end < r.highestOffset {
+			return &qerr.TransportError{
+				ErrorCode:    qerr.FinalSizeError,
+				ErrorMessage: fmt.Sprintf("final size smaller than the data received (final size: %d, received: %d)", end, r.highestOffset),
+			}
+		}
+		r.finalSize = end
+		r.hasFinalSize = true
+	}
+	r.highestOffset = max(r.highestOffset, end)
+	if length == 0 {
+		return nil
+	}
+
+	// the first range that ends at or after the start of the new range, i.e. the first range that can be merged
+	i, _ := slices.BinarySearchFunc(r.ranges, offset, func(rng ByteRange, offset protocol.ByteCount) int {
+		if rng.End < offset {
+			return -1
+		}
+		return 1
+	})
+	// the ranges [i, j) overlap with or are adjacent to the new range
+	j := i
+	for j < len(r.ranges) && r.ranges[j].Start <= end {
+		j++
+	}
+	merged := ByteRange{Start: offset, End: end}
+	if i < j {
+		merged.Start = min(merged.Start, r.ranges[i].Start)
+		merged.End = max(merged.End, r.ranges[j-1].End)
+	}
+	r.ranges = slices.Replace(r.ranges, i, j, merged)
+	return nil
+}
+
+// InsertStreamFrame records the receipt of the data of a STREAM frame.
+func (r *ReceivedRanges) InsertStreamFrame(f *StreamFrame) error {
+	return r.Insert(f.Offset, f.DataLen(), f.Fin)
+}
+
+// ContiguousLen returns the length of the data that has been received without any gaps, starting at offset 0.
+func (r *ReceivedRanges) ContiguousLen() protocol.ByteCount {
+	if len(r.ranges) == 0 || r.ranges[0].Start != 0 {
+		return 0
+	}
+	return r.ranges[0].End
+}
+
+// OutOfOrderLen returns the length of the data that has been received beyond the first gap.
+// This is the data that needs to be buffered until the gaps are filled.
+func (r *ReceivedRanges) OutOfOrderLen() protocol.ByteCount {
+	var l protocol.ByteCount
+	for i, rng := range r.ranges {
+		if i == 0 && rng.Start == 0 {
+			continue
+		}
+		l += rng.Len()
+	}
+	return l
+}
+
+// Gaps returns the ranges that haven't been received yet, up to the highest offset received.
+func (r *ReceivedRanges) Gaps() []ByteRange {
+	var gaps []ByteRange
+	var pos protocol.ByteCount
+	for _, rng := range r.ranges {
+		if rng.Start > pos {
+			gaps = append(gaps, ByteRange{Start: pos, End: rng.Start})
+		}
+		pos = rng.End
+	}
+	if pos < r.highestOffset {
+		gaps = append(gaps, ByteRange{Start: pos, End: r.highestOffset})
+	}
+	return gaps
+}
+
+// Ranges returns the number of disjoint ranges received.
+// Since every range uses memory, the number of ranges should be limited when receiving data from an untrusted peer.
+func (r *ReceivedRanges) Ranges() int {
+	return len(r.ranges)
+}
+
+// FinalSize returns the final size of the stream, if known.
+func (r *ReceivedRanges) FinalSize() (protocol.ByteCount, bool) {
+	return r.finalSize, r.hasFinalSize
+}
+
+// Complete returns true if the final size is known, and all data up to the final size has been received.
+func (r *ReceivedRanges) Complete() bool {
+	return r.hasFinalSize && r.ContiguousLen() == r.finalSize
+}
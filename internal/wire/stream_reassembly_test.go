@@ -0,0 +1,110 @@
+package wire
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceivedRanges(t *testing.T) {
+	var r ReceivedRanges
+	require.Zero(t, r.ContiguousLen())
+	require.Empty(t, r.Gaps())
+
+	require.NoError(t, r.Insert(10, 10, false))
+	require.Zero(t, r.ContiguousLen())
+	require.Equal(t, []ByteRange{{Start: 0, End: 10}}, r.Gaps())
+
+	require.NoError(t, r.Insert(30, 5, false))
+	require.Equal(t, []ByteRange{{Start: 0, End: 10}, {Start: 20, End: 30}}, r.Gaps())
+	require.Equal(t, 2, r.Ranges())
+	require.Equal(t, protocol.ByteCount(15), r.OutOfOrderLen())
+
+	// adjacent to the first range
+	require.NoError(t, r.Insert(0, 10, false))
+	require.Equal(t, protocol.ByteCount(20), r.ContiguousLen())
+	require.Equal(t, []ByteRange{{Start: 20, End: 30}}, r.Gaps())
+	require.Equal(t, protocol.ByteCount(5), r.OutOfOrderLen())
+
+	// overlapping with both ranges
+	require.NoError(t, r.Insert(15, 18, false))
+	require.Equal(t, protocol.ByteCount(35), r.ContiguousLen())
+	require.Empty(t, r.Gaps())
+	require.Equal(t, 1, r.Ranges())
+	require.Zero(t, r.OutOfOrderLen())
+
+	// duplicate data
+	require.NoError(t, r.Insert(5, 10, false))
+	require.Equal(t, protocol.ByteCount(35), r.ContiguousLen())
+	require.False(t, r.Complete())
+
+	require.NoError(t, r.InsertStreamFrame(&StreamFrame{Offset: 35, Data: []byte("foobar"), Fin: true}))
+	finalSize, ok := r.FinalSize()
+	require.True(t, ok)
+	require.Equal(t, protocol.ByteCount(41), finalSize)
+	require.True(t, r.Complete())
+}
+
+func TestReceivedRangesFinalSizeViolations(t *testing.T) {
+	t.Run("data beyond the final size", func(t *testing.T) {
+		var r ReceivedRanges
+		require.NoError(t, r.Insert(0, 10, true))
+		err := r.Insert(5, 10, false)
+		require.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.FinalSizeError})
+		require.ErrorContains(t, err, "data beyond the final size")
+		// retransmissions are fine
+		require.NoError(t, r.Insert(0, 10, true))
+		require.NoError(t, r.Insert(0, 0, false))
+	})
+
+	t.Run("final size changed", func(t *testing.T) {
+		var r ReceivedRanges
+		require.NoError(t, r.Insert(0, 10, true))
+		err := r.Insert(0, 5, true)
+		require.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.FinalSizeError})
+		require.ErrorContains(t, err, "final size changed")
+	})
+
+	t.Run("final size smaller than the data received", func(t *testing.T) {
+		var r ReceivedRanges
+		require.NoError(t, r.Insert(20, 10, false))
+		err := r.Insert(0, 10, true)
+		require.ErrorIs(t, err, &qerr.TransportError{ErrorCode: qerr.FinalSizeError})
+		require.ErrorContains(t, err, "final size smaller than the data received")
+		_, ok := r.FinalSize()
+		require.False(t, ok)
+	})
+}
+
+func TestReceivedRangesRandomized(t *testing.T) {
+	const size = 1000
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 100 {
+		var rr ReceivedRanges
+		var received [size]bool
+		for range 50 {
+			offset := r.IntN(size)
+			length := r.IntN(min(100, size-offset) + 1)
+			require.NoError(t, rr.Insert(protocol.ByteCount(offset), protocol.ByteCount(length), false))
+			for i := offset; i < offset+length; i++ {
+				received[i] = true
+			}
+
+			var contiguous protocol.ByteCount
+			for contiguous < size && received[contiguous] {
+				contiguous++
+			}
+			require.Equal(t, contiguous, rr.ContiguousLen())
+			for _, gap := range rr.Gaps() {
+				require.NotZero(t, gap.Len())
+				for i := gap.Start; i < gap.End; i++ {
+					require.False(t, received[i])
+				}
+			}
+		}
+	}
+}
@@ -13,6 +13,21 @@ type StreamsBlockedFrame struct {
 	StreamLimit protocol.StreamNum
 }
 
+// NewStreamsBlockedFrame creates a STREAMS_BLOCKED frame.
+func NewStreamsBlockedFrame(stype protocol.StreamType, streamLimit protocol.StreamNum) (*StreamsBlockedFrame, error) {
+	if err := protocol.ValidateStreamCount(streamLimit, stype); err != nil {
+		return nil, err
+	}
+	return &StreamsBlockedFrame{Type: stype, StreamLimit: streamLimit}, nil
+}
+
+// NewStreamsBlockedFrameForStreamID creates a STREAMS_BLOCKED frame of the given stream type for an endpoint
+// that is blocked from opening the stream following the given stream ID.
+// If id is protocol.InvalidStreamID, the endpoint is blocked from opening any stream.
+func NewStreamsBlockedFrameForStreamID(stype protocol.StreamType, id protocol.StreamID) *StreamsBlockedFrame {
+	return &StreamsBlockedFrame{Type: stype, StreamLimit: protocol.StreamCountForMaxStreamID(id)}
+}
+
 func parseStreamsBlockedFrame(b []byte, typ uint64, _ protocol.Version) (*StreamsBlockedFrame, int, error) {
 	f := &StreamsBlockedFrame{}
 	switch typ {
@@ -21,15 +36,16 @@ func parseStreamsBlockedFrame(b []byte, typ uint64, _ protocol.Version) (*Stream
 	case uniStreamBlockedFrameType:
 		f.Type = protocol.StreamTypeUni
 	}
-	streamLimit, l, err := quicvarint.Parse(b)
+	c := newCursor(b)
+	streamLimit, err := c.readVarInt()
 	if err != nil {
-		return nil, 0, replaceUnexpectedEOF(err)
+		return nil, 0, err
 	}
 	f.StreamLimit = protocol.StreamNum(streamLimit)
 	if f.StreamLimit > protocol.MaxStreamCount {
 		return nil, 0, fmt.Errorf("%d exceeds the maximum stream count", f.StreamLimit)
 	}
-	return f, l, nil
+	return f, c.consumed(), nil
 }
 
 func (f *StreamsBlockedFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
@@ -47,3 +63,10 @@ func (f *StreamsBlockedFrame) Append(b []byte, _ protocol.Version) ([]byte, erro
 func (f *StreamsBlockedFrame) Length(_ protocol.Version) protocol.ByteCount {
 	return 1 + protocol.ByteCount(quicvarint.Len(uint64(f.StreamLimit)))
 }
+
+// MaxStreamID returns the highest stream ID that streams initiated by pers were allowed to use
+// when the frame was sent.
+// It returns protocol.InvalidStreamID if no streams were allowed.
+func (f *StreamsBlockedFrame) MaxStreamID(pers protocol.Perspective) protocol.StreamID {
+	return f.StreamLimit.StreamID(f.Type, pers)
+}
@@ -36,7 +36,7 @@ func TestParseStreamsBlockedFrameErrorsOnEOFs(t *testing.T) {
 	require.Equal(t, len(data), l)
 	for i := range data {
 		_, _, err := parseStreamsBlockedFrame(data[:i], bidiStreamBlockedFrameType, protocol.Version1)
-		require.Equal(t, io.EOF, err)
+		require.Equal(t, io.ErrUnexpectedEOF, err)
 	}
 }
 
@@ -115,3 +115,25 @@ func TestWriteStreamsBlockedFrameUnidirectional(t *testing.T) {
 	require.Equal(t, expected, b)
 	require.Equal(t, int(f.Length(protocol.Version1)), len(b))
 }
+
+func TestNewStreamsBlockedFrame(t *testing.T) {
+	f, err := NewStreamsBlockedFrame(protocol.StreamTypeBidi, 2)
+	require.NoError(t, err)
+	require.Equal(t, &StreamsBlockedFrame{Type: protocol.StreamTypeBidi, StreamLimit: 2}, f)
+	require.Equal(t, protocol.StreamID(4), f.MaxStreamID(protocol.PerspectiveClient))
+
+	_, err = NewStreamsBlockedFrame(protocol.StreamTypeUni, protocol.MaxStreamCount+1)
+	require.Error(t, err)
+
+	for _, id := range []protocol.StreamID{0, 3, 6, 1337} {
+		f := NewStreamsBlockedFrameForStreamID(id.Type(), id)
+		require.Equal(t, id.Type(), f.Type)
+		require.Equal(t, id, f.MaxStreamID(id.InitiatedBy()))
+	}
+
+	for _, stype := range []protocol.StreamType{protocol.StreamTypeUni, protocol.StreamTypeBidi} {
+		f := NewStreamsBlockedFrameForStreamID(stype, protocol.InvalidStreamID)
+		require.Equal(t, &StreamsBlockedFrame{Type: stype, StreamLimit: 0}, f)
+		require.Equal(t, protocol.InvalidStreamID, f.MaxStreamID(protocol.PerspectiveClient))
+	}
+}
@@ -0,0 +1,27 @@
+#!/bin/bash
+
+# Parses the payloads of a frame trace using the frame parser of an upstream quic-go checkout,
+# and writes the results used by TestUpstreamDifferential.
+#
+# Usage: generate.sh <upstream checkout> <trace> <results.json>
+
+set -e
+
+if [ $# -ne 3 ]; then
+  echo "Usage: $0 <upstream checkout> <trace> <results.json>"
+  exit 1
+fi
+
+dir=$(cd "$(dirname "$0")" && pwd)
+upstream=$(cd "$1" && pwd)
+trace=$(cd "$(dirname "$2")" && pwd)/$(basename "$2")
+results=$(cd "$(dirname "$3")" && pwd)/$(basename "$3")
+
+# upstream doesn't implement the frame trace format, so we need to copy the trace reader
+cp "$dir/../../trace.go" "$upstream/internal/wire/zz_upstream_trace.go"
+cp "$dir/upstream_results_test.go" "$upstream/internal/wire/zz_upstream_results_test.go"
+trap 'rm -f "$upstream/internal/wire/zz_upstream_trace.go" "$upstream/internal/wire/zz_upstream_results_test.go"' EXIT
+
+cd "$upstream"
+go test ./internal/wire -count=1 -run '^TestGenerateUpstreamResults$' -v \
+  -upstream.trace="$trace" -upstream.results="$results"
@@ -0,0 +1,84 @@
+package wire
+
+// This file is copied into the internal/wire package of an upstream quic-go checkout by generate.sh,
+// together with the frame trace reader (trace.go).
+// It parses the payloads of a frame trace using upstream's FrameParser,
+// and writes the results in the format expected by TestUpstreamDifferential.
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+var (
+	upstreamTrace   = flag.String("upstream.trace", "", "frame trace containing the payloads to parse")
+	upstreamResults = flag.String("upstream.results", "", "file to write the JSON-encoded results to")
+)
+
+// upstreamResult has the same JSON encoding as the ReplayResult.
+type upstreamResult struct {
+	FrameTypes []uint64
+	Err        string
+}
+
+func TestGenerateUpstreamResults(t *testing.T) {
+	if *upstreamTrace == "" || *upstreamResults == "" {
+		t.Skip("-upstream.trace and -upstream.results are required")
+	}
+	trace, err := os.ReadFile(*upstreamTrace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := NewTraceReader(bytes.NewReader(trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []upstreamResult{}
+	for {
+		rec, err := tr.ReadRecord()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := upstreamResult{}
+		parser := NewFrameParser(true, true)
+		data := rec.Payload
+		for len(data) > 0 {
+			for len(data) > 0 && data[0] == 0x0 { // skip PADDING frames
+				data = data[1:]
+			}
+			if len(data) == 0 {
+				break
+			}
+			l, frame, err := parser.ParseNext(data, rec.EncryptionLevel, rec.Version)
+			if err != nil {
+				res.Err = err.Error()
+				break
+			}
+			// ParseNext succeeded, so the frame type can be decoded
+			typ, _, _ := quicvarint.Parse(data)
+			res.FrameTypes = append(res.FrameTypes, typ)
+			if sf, ok := frame.(*StreamFrame); ok {
+				sf.PutBack()
+			}
+			data = data[l:]
+		}
+		results = append(results, res)
+	}
+	b, err := json.Marshal(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(*upstreamResults, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("parsed %d records", len(results))
+}
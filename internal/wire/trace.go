@@ -0,0 +1,120 @@
+package wire
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// A frame trace is a container for captured frame payloads.
+// It consists of a header (the magic string "QFTR" followed by the format version),
+// and a sequence of records. Every record consists of:
+// * the encryption level (1 byte)
+// * the QUIC version (4 bytes)
+// * the length of the payload (varint)
+// * the payload
+const (
+	traceMagic   = "QFTR"
+	traceVersion = 1
+)
+
+// maxTraceRecordSize is the maximum size of a payload stored in a trace.
+// It is larger than any QUIC packet payload.
+const maxTraceRecordSize = 1 << 16
+
+var errInvalidTraceHeader = errors.New("invalid frame trace header")
+
+// A TraceRecord is a frame payload stored in a frame trace.
+type TraceRecord struct {
+	EncryptionLevel protocol.EncryptionLevel
+	Version         protocol.Version
+	Payload         []byte
+}
+
+// A TraceWriter writes frame payloads to a frame trace.
+type TraceWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewTraceWriter creates a new TraceWriter, and writes the trace header.
+func NewTraceWriter(w io.Writer) (*TraceWriter, error) {
+	if _, err := w.Write(append([]byte(traceMagic), traceVersion)); err != nil {
+		return nil, err
+	}
+	return &TraceWriter{w: w}, nil
+}
+
+// WriteRecord writes a record.
+func (w *TraceWriter) WriteRecord(r *TraceRecord) error {
+	if r.EncryptionLevel < protocol.EncryptionInitial || r.EncryptionLevel > protocol.Encryption1RTT {
+		return fmt.Errorf("invalid encryption level: %d", r.EncryptionLevel)
+	}
+	if len(r.Payload) > maxTraceRecordSize {
+		return fmt.Errorf("payload too large: %d bytes", len(r.Payload))
+	}
+	w.buf = append(w.buf[:0], uint8(r.EncryptionLevel))
+	w.buf = binary.BigEndian.AppendUint32(w.buf, uint32(r.Version))
+	w.buf = quicvarint.Append(w.buf, uint64(len(r.Payload)))
+	w.buf = append(w.buf, r.Payload...)
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+// A TraceReader reads frame payloads from a frame trace.
+type TraceReader struct {
+	r *bufio.Reader
+}
+
+// NewTraceReader creates a new TraceReader, and reads the trace header.
+func NewTraceReader(r io.Reader) (*TraceReader, error) {
+	br := bufio.NewReader(r)
+	hdr := make([]byte, len(traceMagic)+1)
+	if _, err := io.ReadFull(br, hdr); err != nil {
+		return nil, errInvalidTraceHeader
+	}
+	if string(hdr[:len(traceMagic)]) != traceMagic {
+		return nil, errInvalidTraceHeader
+	}
+	if v := hdr[len(traceMagic)]; v != traceVersion {
+		return nil, fmt.Errorf("unsupported frame trace version: %d", v)
+	}
+	return &TraceReader{r: br}, nil
+}
+
+// ReadRecord reads the next record.
+// It returns io.EOF if there are no more records.
+func (r *TraceReader) ReadRecord() (*TraceRecord, error) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(r.r, hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("truncated frame trace record")
+		}
+		return nil, err
+	}
+	encLevel := protocol.EncryptionLevel(hdr[0])
+	if encLevel < protocol.EncryptionInitial || encLevel > protocol.Encryption1RTT {
+		return nil, fmt.Errorf("invalid encryption level: %d", encLevel)
+	}
+	l, err := quicvarint.Read(r.r)
+	if err != nil {
+		return nil, errors.New("truncated frame trace record")
+	}
+	if l > maxTraceRecordSize {
+		return nil, fmt.Errorf("payload too large: %d bytes", l)
+	}
+	payload := make([]byte, l)
+	if _, err := io.ReadFull(r.r, payload); err != nil {
+		return nil, errors.New("truncated frame trace record")
+	}
+	return &TraceRecord{
+		EncryptionLevel: encLevel,
+		Version:         protocol.Version(binary.BigEndian.Uint32(hdr[1:])),
+		Payload:         payload,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package wire
+
+import (
+	"context"
+	"io"
+	"slices"
+	"time"
+)
+
+// A ReplayResult is the outcome of parsing a single trace record.
+type ReplayResult struct {
+	// FrameTypes contains the types of all frames parsed, excluding PADDING frames.
+	FrameTypes []uint64
+	// Err is the error message, if parsing failed.
+	Err string
+}
+
+func (r *ReplayResult) equal(other *ReplayResult) bool {
+	return r.Err == other.Err && slices.Equal(r.FrameTypes, other.FrameTypes)
+}
+
+// A ReplayReport summarizes the replay of a frame trace.
+type ReplayReport struct {
+	Results []ReplayResult
+
+	Records      int
+	Frames       int
+	FramesByType map[uint64]int
+	Errors       int
+	// Mismatches contains the indices of the records whose result differs from the expected result.
+	Mismatches []int
+}
+
+// A TraceReplayer feeds the payloads stored in a frame trace through a FrameParser.
+// This allows regression-testing parser changes against recorded traffic.
+type TraceReplayer struct {
+	Parser *FrameParser
+	// Interval is the time waited between two records.
+	// If 0, records are replayed as fast as possible.
+	Interval time.Duration
+	// Expected are the expected results, e.g. the results of a previous replay of the same trace.
+	// If nil, results are not compared.
+	Expected []ReplayResult
+}
+
+// Replay replays all records read from the TraceReader.
+func (r *TraceReplayer) Replay(ctx context.Context, tr *TraceReader) (*ReplayReport, error) {
+	var ticker *time.Ticker
+	if r.Interval > 0 {
+		ticker = time.NewTicker(r.Interval)
+		defer ticker.Stop()
+	}
+
+	report := &ReplayReport{FramesByType: make(map[uint64]int)}
+	for {
+		rec, err := tr.ReadRecord()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return report, err
+		}
+		if ticker != nil && report.Records > 0 {
+			select {
+			case <-ctx.Done():
+				return report, ctx.Err()
+			case <-ticker.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		var res ReplayResult
+		_, err = r.Parser.parseAll(rec.Payload, rec.EncryptionLevel, rec.Version, func(typ uint64, f Frame) error {
+			res.FrameTypes = append(res.FrameTypes, typ)
+			report.FramesByType[typ]++
+			if sf, ok := f.(*StreamFrame); ok {
+				sf.PutBack()
+			}
+			return nil
+		})
+		if err != nil {
+			res.Err = err.Error()
+			report.Errors++
+		}
+		report.Frames += len(res.FrameTypes)
+		if r.Expected != nil && (report.Records >= len(r.Expected) || !r.Expected[report.Records].equal(&res)) {
+			report.Mismatches = append(report.Mismatches, report.Records)
+		}
+		report.Results = append(report.Results, res)
+		report.Records++
+	}
+	if r.Expected != nil {
+		// records missing from the trace
+		for i := report.Records; i < len(r.Expected); i++ {
+			report.Mismatches = append(report.Mismatches, i)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,83 @@
+package wire
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestTrace(t *testing.T, payloads ...[]byte) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewTraceWriter(&buf)
+	require.NoError(t, err)
+	for _, p := range payloads {
+		require.NoError(t, w.WriteRecord(&TraceRecord{EncryptionLevel: protocol.Encryption1RTT, Version: protocol.Version1, Payload: p}))
+	}
+	return &buf
+}
+
+func TestTraceReplay(t *testing.T) {
+	p1, err := (&PingFrame{}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	p1 = append(p1, 0, 0, 0) // PADDING
+	p2, err := (&MaxDataFrame{MaximumData: 1337}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	p2, err = (&StreamFrame{StreamID: 4, Data: []byte("foobar")}).Append(p2, protocol.Version1)
+	require.NoError(t, err)
+	p3 := []byte{maxDataFrameType} // missing the maximum data
+
+	replayer := &TraceReplayer{Parser: NewFrameParser(false, false)}
+	tr, err := NewTraceReader(writeTestTrace(t, p1, p2, p3))
+	require.NoError(t, err)
+	report, err := replayer.Replay(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, 3, report.Records)
+	require.Equal(t, 3, report.Frames)
+	require.Equal(t, 1, report.Errors)
+	require.Equal(t, map[uint64]int{pingFrameType: 1, maxDataFrameType: 1, 0x8: 1}, report.FramesByType)
+	require.Empty(t, report.Mismatches)
+	require.Equal(t, []uint64{pingFrameType}, report.Results[0].FrameTypes)
+	require.Equal(t, []uint64{maxDataFrameType, 0x8}, report.Results[1].FrameTypes)
+	require.NotEmpty(t, report.Results[2].Err)
+
+	// replay the trace again, with the first replay as the expectation
+	replayer.Expected = report.Results
+	tr, err = NewTraceReader(writeTestTrace(t, p1, p1, p3))
+	require.NoError(t, err)
+	report, err = replayer.Replay(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, report.Mismatches)
+
+	tr, err = NewTraceReader(writeTestTrace(t, p1))
+	require.NoError(t, err)
+	report, err = replayer.Replay(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, report.Mismatches)
+}
+
+func TestTraceReplayInterval(t *testing.T) {
+	p, err := (&PingFrame{}).Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	replayer := &TraceReplayer{Parser: NewFrameParser(false, false), Interval: 10 * time.Millisecond}
+	tr, err := NewTraceReader(writeTestTrace(t, p, p, p, p))
+	require.NoError(t, err)
+	start := time.Now()
+	report, err := replayer.Replay(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Records)
+	require.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tr, err = NewTraceReader(writeTestTrace(t, p, p))
+	require.NoError(t, err)
+	report, err = replayer.Replay(ctx, tr)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Zero(t, report.Records)
+}
@@ -0,0 +1,69 @@
+package wire
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceWriteAndRead(t *testing.T) {
+	records := []*TraceRecord{
+		{EncryptionLevel: protocol.EncryptionInitial, Version: protocol.Version1, Payload: []byte("initial")},
+		{EncryptionLevel: protocol.Encryption1RTT, Version: protocol.Version2, Payload: bytes.Repeat([]byte{0x42}, 1000)},
+		{EncryptionLevel: protocol.EncryptionHandshake, Version: protocol.Version1, Payload: []byte{}},
+	}
+	var buf bytes.Buffer
+	w, err := NewTraceWriter(&buf)
+	require.NoError(t, err)
+	for _, r := range records {
+		require.NoError(t, w.WriteRecord(r))
+	}
+
+	r, err := NewTraceReader(&buf)
+	require.NoError(t, err)
+	for _, expected := range records {
+		rec, err := r.ReadRecord()
+		require.NoError(t, err)
+		require.Equal(t, expected, rec)
+	}
+	_, err = r.ReadRecord()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestTraceWriteInvalidRecords(t *testing.T) {
+	w, err := NewTraceWriter(io.Discard)
+	require.NoError(t, err)
+	require.EqualError(t, w.WriteRecord(&TraceRecord{Version: protocol.Version1}), "invalid encryption level: 0")
+	require.EqualError(t,
+		w.WriteRecord(&TraceRecord{EncryptionLevel: protocol.Encryption1RTT, Payload: make([]byte, maxTraceRecordSize+1)}),
+		"payload too large: 65537 bytes",
+	)
+}
+
+func TestTraceReadInvalidHeader(t *testing.T) {
+	_, err := NewTraceReader(bytes.NewReader([]byte("QFT")))
+	require.Equal(t, errInvalidTraceHeader, err)
+	_, err = NewTraceReader(bytes.NewReader([]byte("QFTX\x01")))
+	require.Equal(t, errInvalidTraceHeader, err)
+	_, err = NewTraceReader(bytes.NewReader([]byte("QFTR\x02")))
+	require.EqualError(t, err, "unsupported frame trace version: 2")
+}
+
+func TestTraceReadTruncatedRecord(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewTraceWriter(&buf)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRecord(&TraceRecord{EncryptionLevel: protocol.Encryption1RTT, Version: protocol.Version1, Payload: []byte("foobar")}))
+	data := buf.Bytes()
+
+	for i := len(traceMagic) + 2; i < len(data); i++ {
+		r, err := NewTraceReader(bytes.NewReader(data[:i]))
+		require.NoError(t, err)
+		_, err = r.ReadRecord()
+		require.EqualError(t, err, "truncated frame trace record")
+	}
+}
@@ -3,7 +3,9 @@ package wire
 import (
 	"bytes"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	mrand "math/rand/v2"
 	"net/netip"
@@ -136,6 +138,28 @@ func TestMarshalAdditionalTransportParameters(t *testing.T) {
 	require.False(t, bytes.Contains(params.Marshal(protocol.PerspectiveServer), result))
 }
 
+func TestMarshalTransportParametersWithRand(t *testing.T) {
+	random := []byte{2, 3, 'f', 'o', 'o', 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	params := &TransportParameters{InitialMaxData: 1337, ActiveConnectionIDLimit: 2}
+	data, err := params.MarshalWithRand(protocol.PerspectiveClient, bytes.NewReader(random))
+	require.NoError(t, err)
+	data2, err := params.MarshalWithRand(protocol.PerspectiveClient, bytes.NewReader(random))
+	require.NoError(t, err)
+	require.Equal(t, data, data2)
+	// the greased transport parameter comes first
+	greased := quicvarint.Append(nil, 27+31*2)
+	greased = quicvarint.Append(greased, 3)
+	greased = append(greased, "foo"...)
+	require.True(t, bytes.HasPrefix(data, greased))
+
+	var p TransportParameters
+	require.NoError(t, p.Unmarshal(data, protocol.PerspectiveClient))
+	require.Equal(t, protocol.ByteCount(1337), p.InitialMaxData)
+
+	_, err = params.MarshalWithRand(protocol.PerspectiveClient, bytes.NewReader(random[:10]))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
 func TestMarshalWithoutRetrySourceConnectionID(t *testing.T) {
 	data := (&TransportParameters{
 		StatelessResetToken:     &protocol.StatelessResetToken{},
@@ -424,6 +448,123 @@ func TestTransportParameterUnknownParameters(t *testing.T) {
 	require.Equal(t, protocol.ByteCount(0x42), p.InitialMaxStreamDataBidiRemote)
 }
 
+func TestTransportParameterDiagnostics(t *testing.T) {
+	b := quicvarint.Append(nil, 27+31*5) // reserved
+	b = quicvarint.Append(b, 3)
+	b = append(b, []byte("foo")...)
+	b = quicvarint.Append(b, 0x42) // unknown
+	b = quicvarint.Append(b, 0)
+	b = quicvarint.Append(b, 27) // reserved
+	b = quicvarint.Append(b, 0)
+	b = quicvarint.Append(b, uint64(initialMaxDataParameterID))
+	b = quicvarint.Append(b, uint64(quicvarint.Len(0x1337)))
+	b = quicvarint.Append(b, 0x1337)
+	b = appendInitialSourceConnectionID(b)
+
+	p := &TransportParameters{}
+	diag, err := p.UnmarshalWithDiagnostics(b, protocol.PerspectiveClient)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{27 + 31*5, 27}, diag.Reserved)
+	require.Equal(t, []uint64{0x42}, diag.Unknown)
+	require.Equal(t, protocol.ByteCount(0x1337), p.InitialMaxData)
+}
+
+func TestTransportParameterDiagnosticsGreasedParameter(t *testing.T) {
+	data := (&TransportParameters{
+		InitialSourceConnectionID:       protocol.ParseConnectionID([]byte{1, 2, 3, 4}),
+		OriginalDestinationConnectionID: protocol.ParseConnectionID([]byte{5, 6, 7, 8}),
+		ActiveConnectionIDLimit:         2,
+	}).Marshal(protocol.PerspectiveServer)
+	diag, err := (&TransportParameters{}).UnmarshalWithDiagnostics(data, protocol.PerspectiveServer)
+	require.NoError(t, err)
+	require.Len(t, diag.Reserved, 1)
+	require.True(t, isReservedTransportParameterID(diag.Reserved[0]))
+	require.Empty(t, diag.Unknown)
+}
+
+func TestIsReservedTransportParameterID(t *testing.T) {
+	for _, id := range []uint64{27, 58, 27 + 31*1000} {
+		require.True(t, isReservedTransportParameterID(id), "%d", id)
+	}
+	for _, id := range []uint64{0, 26, 28, 31, 0x20} {
+		require.False(t, isReservedTransportParameterID(id), "%d", id)
+	}
+}
+
+func TestParseTransportParameters(t *testing.T) {
+	data := (&TransportParameters{
+		InitialMaxData:            0x1337,
+		MaxIdleTimeout:            42 * time.Second,
+		InitialSourceConnectionID: protocol.ParseConnectionID([]byte{1, 2, 3, 4}),
+		ActiveConnectionIDLimit:   2,
+		MaxDatagramFrameSize:      protocol.InvalidByteCount,
+	}).Marshal(protocol.PerspectiveClient)
+
+	type param struct {
+		id    uint64
+		value []byte
+	}
+	var params []param
+	require.NoError(t, ParseTransportParameters(data, protocol.PerspectiveClient, func(id uint64, value []byte) error {
+		params = append(params, param{id: id, value: value})
+		return nil
+	}))
+	require.True(t, isReservedTransportParameterID(params[0].id)) // greased transport parameter
+	require.Contains(t, params, param{id: uint64(initialMaxDataParameterID), value: quicvarint.Append(nil, 0x1337)})
+	require.Contains(t, params, param{id: uint64(maxIdleTimeoutParameterID), value: quicvarint.Append(nil, 42000)})
+	require.Contains(t, params, param{id: uint64(initialSourceConnectionIDParameterID), value: []byte{1, 2, 3, 4}})
+}
+
+func TestParseTransportParametersAbortsOnInvalidParameter(t *testing.T) {
+	b := quicvarint.Append(nil, uint64(initialMaxDataParameterID))
+	b = quicvarint.Append(b, uint64(quicvarint.Len(0x1337)))
+	b = quicvarint.Append(b, 0x1337)
+	b = quicvarint.Append(b, uint64(ackDelayExponentParameterID))
+	b = quicvarint.Append(b, 1)
+	b = quicvarint.Append(b, 21)
+	b = quicvarint.Append(b, uint64(initialMaxStreamDataUniParameterID))
+	b = quicvarint.Append(b, uint64(quicvarint.Len(0x42)))
+	b = quicvarint.Append(b, 0x42)
+
+	var ids []uint64
+	err := ParseTransportParameters(b, protocol.PerspectiveClient, func(id uint64, _ []byte) error {
+		ids = append(ids, id)
+		return nil
+	})
+	require.Equal(t, &qerr.TransportError{
+		ErrorCode:    qerr.TransportParameterError,
+		ErrorMessage: "invalid value for ack_delay_exponent: 21 (maximum 20)",
+	}, err)
+	require.Equal(t, []uint64{uint64(initialMaxDataParameterID)}, ids)
+}
+
+func TestParseTransportParametersRejectsDuplicates(t *testing.T) {
+	b := quicvarint.Append(nil, 0x42)
+	b = quicvarint.Append(b, 0)
+	b = quicvarint.Append(b, 0x42)
+	b = quicvarint.Append(b, 0)
+	err := ParseTransportParameters(b, protocol.PerspectiveClient, func(uint64, []byte) error { return nil })
+	require.Equal(t, &qerr.TransportError{
+		ErrorCode:    qerr.TransportParameterError,
+		ErrorMessage: "received duplicate transport parameter 0x42",
+	}, err)
+}
+
+func TestParseTransportParametersCallbackError(t *testing.T) {
+	b := quicvarint.Append(nil, 0x42)
+	b = quicvarint.Append(b, 0)
+	b = quicvarint.Append(b, 0x43)
+	b = quicvarint.Append(b, 0)
+	testErr := errors.New("test error")
+	var calls int
+	err := ParseTransportParameters(b, protocol.PerspectiveClient, func(uint64, []byte) error {
+		calls++
+		return testErr
+	})
+	require.ErrorIs(t, err, testErr)
+	require.Equal(t, 1, calls)
+}
+
 func TestTransportParameterRejectsDuplicateParameters(t *testing.T) {
 	// write first parameter
 	b := quicvarint.Append(nil, uint64(initialMaxStreamDataBidiLocalParameterID))
@@ -21,7 +21,15 @@ import (
 // the usual size of less than 1 MTU.
 var AdditionalTransportParametersClient map[uint64][]byte
 
-const transportParameterMarshalingVersion = 1
+// The version of the encoding used for storing transport parameters in session tickets.
+// Version 1 uses the transport parameter encoding (see section 18 of RFC 9000).
+// Version 2 uses the compact encoding implemented in transport_parameters_session_ticket.go.
+const (
+	transportParameterMarshalingVersion1 = 1
+	transportParameterMarshalingVersion2 = 2
+
+	transportParameterMarshalingVersion = transportParameterMarshalingVersion2
+)
 
 type transportParameterID uint64
 
@@ -88,9 +96,24 @@ type TransportParameters struct {
 	EnableResetStreamAt  bool               // https://datatracker.ietf.org/doc/draft-ietf-quic-reliable-stream-reset/06/
 }
 
+// TransportParameterDiagnostics describes the transport parameters that were ignored during unmarshaling.
+type TransportParameterDiagnostics struct {
+	// Reserved contains the IDs of the reserved transport parameters (31 * N + 27) that were received.
+	// Reserved transport parameters are sent to exercise the requirement to ignore unknown
+	// transport parameters (see section 18.1 of RFC 9000).
+	Reserved []uint64
+	// Unknown contains the IDs of all other unknown transport parameters that were received.
+	Unknown []uint64
+}
+
+// isReservedTransportParameterID says if the transport parameter ID is reserved for greasing.
+func isReservedTransportParameterID(id uint64) bool {
+	return id >= 27 && (id-27)%31 == 0
+}
+
 // Unmarshal the transport parameters
 func (p *TransportParameters) Unmarshal(data []byte, sentBy protocol.Perspective) error {
-	if err := p.unmarshal(data, sentBy, false); err != nil {
+	if err := p.unmarshal(data, sentBy, false, nil); err != nil {
 		return &qerr.TransportError{
 			ErrorCode:    qerr.TransportParameterError,
 			ErrorMessage: err.Error(),
@@ -99,7 +122,21 @@ func (p *TransportParameters) Unmarshal(data []byte, sentBy protocol.Perspective
 	return nil
 }
 
-func (p *TransportParameters) unmarshal(b []byte, sentBy protocol.Perspective, fromSessionTicket bool) error {
+// UnmarshalWithDiagnostics unmarshals the transport parameters,
+// and reports the reserved and unknown transport parameters that were ignored.
+// This allows analysis tools to measure the greasing behavior of peers.
+func (p *TransportParameters) UnmarshalWithDiagnostics(data []byte, sentBy protocol.Perspective) (*TransportParameterDiagnostics, error) {
+	var diag TransportParameterDiagnostics
+	if err := p.unmarshal(data, sentBy, false, &diag); err != nil {
+		return &diag, &qerr.TransportError{
+			ErrorCode:    qerr.TransportParameterError,
+			ErrorMessage: err.Error(),
+		}
+	}
+	return &diag, nil
+}
+
+func (p *TransportParameters) unmarshal(b []byte, sentBy protocol.Perspective, fromSessionTicket bool, diag *TransportParameterDiagnostics) error {
 	// needed to check that every parameter is only sent at most once
 	parameterIDs := make([]transportParameterID, 0, 32)
 
@@ -113,103 +150,32 @@ func (p *TransportParameters) unmarshal(b []byte, sentBy protocol.Perspective, f
 	p.MaxAckDelay = protocol.DefaultMaxAckDelay
 	p.MaxDatagramFrameSize = protocol.InvalidByteCount
 
-	for len(b) > 0 {
-		paramIDInt, l, err := quicvarint.Parse(b)
-		if err != nil {
-			return err
-		}
-		paramID := transportParameterID(paramIDInt)
-		b = b[l:]
-		paramLen, l, err := quicvarint.Parse(b)
+	if err := walkTransportParameters(b, func(id uint64, value []byte) error {
+		paramID := transportParameterID(id)
+		parameterIDs = append(parameterIDs, paramID)
+		known, err := p.readTransportParameter(paramID, value, sentBy)
 		if err != nil {
 			return err
 		}
-		b = b[l:]
-		if uint64(len(b)) < paramLen {
-			return fmt.Errorf("remaining length (%d) smaller than parameter length (%d)", len(b), paramLen)
-		}
-		parameterIDs = append(parameterIDs, paramID)
+		//nolint:exhaustive // Only some transport parameters need to be tracked.
 		switch paramID {
 		case activeConnectionIDLimitParameterID:
 			readActiveConnectionIDLimit = true
-			fallthrough
-		case maxIdleTimeoutParameterID,
-			maxUDPPayloadSizeParameterID,
-			initialMaxDataParameterID,
-			initialMaxStreamDataBidiLocalParameterID,
-			initialMaxStreamDataBidiRemoteParameterID,
-			initialMaxStreamDataUniParameterID,
-			initialMaxStreamsBidiParameterID,
-			initialMaxStreamsUniParameterID,
-			maxAckDelayParameterID,
-			maxDatagramFrameSizeParameterID,
-			ackDelayExponentParameterID:
-			if err := p.readNumericTransportParameter(b, paramID, int(paramLen)); err != nil {
-				return err
-			}
-			b = b[paramLen:]
-		case preferredAddressParameterID:
-			if sentBy == protocol.PerspectiveClient {
-				return errors.New("client sent a preferred_address")
-			}
-			if err := p.readPreferredAddress(b, int(paramLen)); err != nil {
-				return err
-			}
-			b = b[paramLen:]
-		case disableActiveMigrationParameterID:
-			if paramLen != 0 {
-				return fmt.Errorf("wrong length for disable_active_migration: %d (expected empty)", paramLen)
-			}
-			p.DisableActiveMigration = true
-		case statelessResetTokenParameterID:
-			if sentBy == protocol.PerspectiveClient {
-				return errors.New("client sent a stateless_reset_token")
-			}
-			if paramLen != 16 {
-				return fmt.Errorf("wrong length for stateless_reset_token: %d (expected 16)", paramLen)
-			}
-			var token protocol.StatelessResetToken
-			if len(b) < len(token) {
-				return io.EOF
-			}
-			copy(token[:], b)
-			b = b[len(token):]
-			p.StatelessResetToken = &token
 		case originalDestinationConnectionIDParameterID:
-			if sentBy == protocol.PerspectiveClient {
-				return errors.New("client sent an original_destination_connection_id")
-			}
-			if paramLen > protocol.MaxConnIDLen {
-				return protocol.ErrInvalidConnectionIDLen
-			}
-			p.OriginalDestinationConnectionID = protocol.ParseConnectionID(b[:paramLen])
-			b = b[paramLen:]
 			readOriginalDestinationConnectionID = true
 		case initialSourceConnectionIDParameterID:
-			if paramLen > protocol.MaxConnIDLen {
-				return protocol.ErrInvalidConnectionIDLen
-			}
-			p.InitialSourceConnectionID = protocol.ParseConnectionID(b[:paramLen])
-			b = b[paramLen:]
 			readInitialSourceConnectionID = true
-		case retrySourceConnectionIDParameterID:
-			if sentBy == protocol.PerspectiveClient {
-				return errors.New("client sent a retry_source_connection_id")
-			}
-			if paramLen > protocol.MaxConnIDLen {
-				return protocol.ErrInvalidConnectionIDLen
-			}
-			connID := protocol.ParseConnectionID(b[:paramLen])
-			b = b[paramLen:]
-			p.RetrySourceConnectionID = &connID
-		case resetStreamAtParameterID:
-			if paramLen != 0 {
-				return fmt.Errorf("wrong length for reset_stream_at: %d (expected empty)", paramLen)
+		}
+		if !known && diag != nil {
+			if isReservedTransportParameterID(id) {
+				diag.Reserved = append(diag.Reserved, id)
+			} else {
+				diag.Unknown = append(diag.Unknown, id)
 			}
-			p.EnableResetStreamAt = true
-		default:
-			b = b[paramLen:]
 		}
+		return nil
+	}); err != nil {
+		return err
 	}
 
 	if !readActiveConnectionIDLimit {
@@ -243,6 +209,141 @@ func (p *TransportParameters) unmarshal(b []byte, sentBy protocol.Perspective, f
 	return nil
 }
 
+// ParseTransportParameters parses the transport parameters one at a time, calling fn for every parameter,
+// in the order they were sent.
+// Unlike Unmarshal, it doesn't decode the transport parameters into a TransportParameters struct,
+// which makes it suitable for processing very large transport parameter extensions.
+// The value passed to fn is a subslice of data, and must be copied if it is retained.
+// Known transport parameters are validated before fn is called, and parsing is aborted on the first invalid
+// or duplicate transport parameter, or if fn returns an error.
+// Checks that need to see all transport parameters (e.g. for missing transport parameters) are not performed.
+func ParseTransportParameters(data []byte, sentBy protocol.Perspective, fn func(id uint64, value []byte) error) error {
+	var parameterIDs []uint64
+	var fnErr error
+	if err := walkTransportParameters(data, func(id uint64, value []byte) error {
+		if slices.Contains(parameterIDs, id) {
+			return fmt.Errorf("received duplicate transport parameter %#x", id)
+		}
+		parameterIDs = append(parameterIDs, id)
+		var scratch TransportParameters
+		if _, err := scratch.readTransportParameter(transportParameterID(id), value, sentBy); err != nil {
+			return err
+		}
+		fnErr = fn(id, value)
+		return fnErr
+	}); err != nil {
+		if fnErr != nil {
+			return fnErr
+		}
+		return &qerr.TransportError{
+			ErrorCode:    qerr.TransportParameterError,
+			ErrorMessage: err.Error(),
+		}
+	}
+	return nil
+}
+
+// walkTransportParameters calls fn for every transport parameter, in the order they were sent.
+// The value is a subslice of b.
+// If fn returns an error, walking is aborted and the error is returned.
+func walkTransportParameters(b []byte, fn func(id uint64, value []byte) error) error {
+	for len(b) > 0 {
+		paramID, l, err := quicvarint.Parse(b)
+		if err != nil {
+			return err
+		}
+		b = b[l:]
+		paramLen, l, err := quicvarint.Parse(b)
+		if err != nil {
+			return err
+		}
+		b = b[l:]
+		if uint64(len(b)) < paramLen {
+			return fmt.Errorf("remaining length (%d) smaller than parameter length (%d)", len(b), paramLen)
+		}
+		if err := fn(paramID, b[:paramLen]); err != nil {
+			return err
+		}
+		b = b[paramLen:]
+	}
+	return nil
+}
+
+// readTransportParameter reads a single transport parameter.
+// It returns false for unknown transport parameters, which are ignored.
+func (p *TransportParameters) readTransportParameter(paramID transportParameterID, value []byte, sentBy protocol.Perspective) (bool, error) {
+	paramLen := len(value)
+	switch paramID {
+	case maxIdleTimeoutParameterID,
+		maxUDPPayloadSizeParameterID,
+		initialMaxDataParameterID,
+		initialMaxStreamDataBidiLocalParameterID,
+		initialMaxStreamDataBidiRemoteParameterID,
+		initialMaxStreamDataUniParameterID,
+		initialMaxStreamsBidiParameterID,
+		initialMaxStreamsUniParameterID,
+		maxAckDelayParameterID,
+		maxDatagramFrameSizeParameterID,
+		ackDelayExponentParameterID,
+		activeConnectionIDLimitParameterID:
+		if err := p.readNumericTransportParameter(value, paramID, paramLen); err != nil {
+			return true, err
+		}
+	case preferredAddressParameterID:
+		if sentBy == protocol.PerspectiveClient {
+			return true, errors.New("client sent a preferred_address")
+		}
+		if err := p.readPreferredAddress(value, paramLen); err != nil {
+			return true, err
+		}
+	case disableActiveMigrationParameterID:
+		if paramLen != 0 {
+			return true, fmt.Errorf("wrong length for disable_active_migration: %d (expected empty)", paramLen)
+		}
+		p.DisableActiveMigration = true
+	case statelessResetTokenParameterID:
+		if sentBy == protocol.PerspectiveClient {
+			return true, errors.New("client sent a stateless_reset_token")
+		}
+		if paramLen != 16 {
+			return true, fmt.Errorf("wrong length for stateless_reset_token: %d (expected 16)", paramLen)
+		}
+		var token protocol.StatelessResetToken
+		copy(token[:], value)
+		p.StatelessResetToken = &token
+	case originalDestinationConnectionIDParameterID:
+		if sentBy == protocol.PerspectiveClient {
+			return true, errors.New("client sent an original_destination_connection_id")
+		}
+		if paramLen > protocol.MaxConnIDLen {
+			return true, protocol.ErrInvalidConnectionIDLen
+		}
+		p.OriginalDestinationConnectionID = protocol.ParseConnectionID(value)
+	case initialSourceConnectionIDParameterID:
+		if paramLen > protocol.MaxConnIDLen {
+			return true, protocol.ErrInvalidConnectionIDLen
+		}
+		p.InitialSourceConnectionID = protocol.ParseConnectionID(value)
+	case retrySourceConnectionIDParameterID:
+		if sentBy == protocol.PerspectiveClient {
+			return true, errors.New("client sent a retry_source_connection_id")
+		}
+		if paramLen > protocol.MaxConnIDLen {
+			return true, protocol.ErrInvalidConnectionIDLen
+		}
+		connID := protocol.ParseConnectionID(value)
+		p.RetrySourceConnectionID = &connID
+	case resetStreamAtParameterID:
+		if paramLen != 0 {
+			return true, fmt.Errorf("wrong length for reset_stream_at: %d (expected empty)", paramLen)
+		}
+		p.EnableResetStreamAt = true
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
 func (p *TransportParameters) readPreferredAddress(b []byte, expectedLen int) error {
 	remainingLen := len(b)
 	pa := &PreferredAddress{}
@@ -283,11 +384,9 @@ func (p *TransportParameters) readPreferredAddress(b []byte, expectedLen int) er
 }
 
 func (p *TransportParameters) readNumericTransportParameter(b []byte, paramID transportParameterID, expectedLen int) error {
+	// b only contains the value, so an error means that the value is shorter than the varint
 	val, l, err := quicvarint.Parse(b)
-	if err != nil {
-		return fmt.Errorf("error while reading transport parameter %d: %s", paramID, err)
-	}
-	if l != expectedLen {
+	if err != nil || l != expectedLen {
 		return fmt.Errorf("inconsistent transport parameter length for transport parameter %#x", paramID)
 	}
 	//nolint:exhaustive // This only covers the numeric transport parameters.
@@ -342,14 +441,28 @@ func (p *TransportParameters) readNumericTransportParameter(b []byte, paramID tr
 
 // Marshal the transport parameters
 func (p *TransportParameters) Marshal(pers protocol.Perspective) []byte {
+	random := make([]byte, 18)
+	rand.Read(random)
+	return p.marshal(pers, random)
+}
+
+// MarshalWithRand is like Marshal, but uses r as the source of randomness for the greased transport parameter.
+// It returns an error if reading from r fails.
+func (p *TransportParameters) MarshalWithRand(pers protocol.Perspective, r io.Reader) ([]byte, error) {
+	random := make([]byte, 18)
+	if _, err := io.ReadFull(r, random); err != nil {
+		return nil, err
+	}
+	return p.marshal(pers, random), nil
+}
+
+func (p *TransportParameters) marshal(pers protocol.Perspective, random []byte) []byte {
 	// Typical Transport Parameters consume around 110 bytes, depending on the exact values,
 	// especially the lengths of the Connection IDs.
 	// Allocate 256 bytes, so we won't have to grow the slice in any case.
 	b := make([]byte, 0, 256)
 
 	// add a greased value
-	random := make([]byte, 18)
-	rand.Read(random)
 	b = quicvarint.Append(b, 27+31*uint64(random[0]))
 	length := random[1] % 16
 	b = quicvarint.Append(b, uint64(length))
@@ -470,46 +583,28 @@ func (p *TransportParameters) marshalVarintParam(b []byte, id transportParameter
 // Saving the transport parameters in the ticket gives the server the option to reject 0-RTT
 // if the transport parameters changed.
 // Since the session ticket is encrypted, the serialization format is defined by the server.
-// For convenience, we use the same format that we also use for sending the transport parameters.
+// We use a compact encoding that only contains the fields remembered for 0-RTT (see transport_parameters_session_ticket.go),
+// prefixed by the version of the encoding.
 func (p *TransportParameters) MarshalForSessionTicket(b []byte) []byte {
 	b = quicvarint.Append(b, transportParameterMarshalingVersion)
-
-	// initial_max_stream_data_bidi_local
-	b = p.marshalVarintParam(b, initialMaxStreamDataBidiLocalParameterID, uint64(p.InitialMaxStreamDataBidiLocal))
-	// initial_max_stream_data_bidi_remote
-	b = p.marshalVarintParam(b, initialMaxStreamDataBidiRemoteParameterID, uint64(p.InitialMaxStreamDataBidiRemote))
-	// initial_max_stream_data_uni
-	b = p.marshalVarintParam(b, initialMaxStreamDataUniParameterID, uint64(p.InitialMaxStreamDataUni))
-	// initial_max_data
-	b = p.marshalVarintParam(b, initialMaxDataParameterID, uint64(p.InitialMaxData))
-	// initial_max_bidi_streams
-	b = p.marshalVarintParam(b, initialMaxStreamsBidiParameterID, uint64(p.MaxBidiStreamNum))
-	// initial_max_uni_streams
-	b = p.marshalVarintParam(b, initialMaxStreamsUniParameterID, uint64(p.MaxUniStreamNum))
-	// active_connection_id_limit
-	b = p.marshalVarintParam(b, activeConnectionIDLimitParameterID, p.ActiveConnectionIDLimit)
-	// max_datagram_frame_size
-	if p.MaxDatagramFrameSize != protocol.InvalidByteCount {
-		b = p.marshalVarintParam(b, maxDatagramFrameSizeParameterID, uint64(p.MaxDatagramFrameSize))
-	}
-	// reset_stream_at
-	if p.EnableResetStreamAt {
-		b = quicvarint.Append(b, uint64(resetStreamAtParameterID))
-		b = quicvarint.Append(b, 0)
-	}
-	return b
+	return p.appendSessionTicketParams(b)
 }
 
 // UnmarshalFromSessionTicket unmarshals transport parameters from a session ticket.
+// Session tickets using an older encoding can still be decoded.
 func (p *TransportParameters) UnmarshalFromSessionTicket(b []byte) error {
 	version, l, err := quicvarint.Parse(b)
 	if err != nil {
 		return err
 	}
-	if version != transportParameterMarshalingVersion {
+	switch version {
+	case transportParameterMarshalingVersion1:
+		return p.unmarshal(b[l:], protocol.PerspectiveServer, true, nil)
+	case transportParameterMarshalingVersion2:
+		return p.parseSessionTicketParams(b[l:])
+	default:
 		return fmt.Errorf("unknown transport parameter marshaling version: %d", version)
 	}
-	return p.unmarshal(b[l:], protocol.PerspectiveServer, true)
 }
 
 // ValidFor0RTT checks if the transport parameters match those saved in the session ticket.
@@ -0,0 +1,188 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// Validate checks that the transport parameters can be sent by an endpoint with the given perspective.
+// It enforces the same constraints that the peer checks when unmarshaling the transport parameters,
+// such that invalid values are detected before they're sent in the handshake.
+// All violations are reported.
+func (p *TransportParameters) Validate(pers protocol.Perspective) error {
+	var errs []error
+	if p.AckDelayExponent > protocol.MaxAckDelayExponent {
+		errs = append(errs, fmt.Errorf("invalid value for ack_delay_exponent: %d (maximum %d)", p.AckDelayExponent, protocol.MaxAckDelayExponent))
+	}
+	if p.MaxAckDelay < 0 || p.MaxAckDelay > protocol.MaxMaxAckDelay {
+		errs = append(errs, fmt.Errorf("invalid value for max_ack_delay: %s (maximum %s)", p.MaxAckDelay, protocol.MaxMaxAckDelay))
+	}
+	if p.MaxUDPPayloadSize > 0 && p.MaxUDPPayloadSize < 1200 {
+		errs = append(errs, fmt.Errorf("invalid value for max_udp_payload_size: %d (minimum 1200)", p.MaxUDPPayloadSize))
+	}
+	// The idle timeout is encoded in milliseconds, and a value of 0 disables the idle timeout.
+	if p.MaxIdleTimeout < 0 {
+		errs = append(errs, fmt.Errorf("invalid value for max_idle_timeout: %s", p.MaxIdleTimeout))
+	} else if p.MaxIdleTimeout > 0 && p.MaxIdleTimeout < time.Millisecond {
+		errs = append(errs, fmt.Errorf("invalid value for max_idle_timeout: %s (would be encoded as 0, disabling the idle timeout)", p.MaxIdleTimeout))
+	} else if uint64(p.MaxIdleTimeout/time.Millisecond) > quicvarint.Max {
+		errs = append(errs, fmt.Errorf("invalid value for max_idle_timeout: %s", p.MaxIdleTimeout))
+	}
+	if p.ActiveConnectionIDLimit < 2 {
+		errs = append(errs, fmt.Errorf("invalid value for active_connection_id_limit: %d (minimum 2)", p.ActiveConnectionIDLimit))
+	}
+	if err := protocol.ValidateStreamCount(p.MaxBidiStreamNum, protocol.StreamTypeBidi); err != nil {
+		errs = append(errs, fmt.Errorf("invalid value for initial_max_streams_bidi: %w", err))
+	}
+	if err := protocol.ValidateStreamCount(p.MaxUniStreamNum, protocol.StreamTypeUni); err != nil {
+		errs = append(errs, fmt.Errorf("invalid value for initial_max_streams_uni: %w", err))
+	}
+	for _, v := range []struct {
+		name  string
+		value protocol.ByteCount
+	}{
+		{"initial_max_data", p.InitialMaxData},
+		{"initial_max_stream_data_bidi_local", p.InitialMaxStreamDataBidiLocal},
+		{"initial_max_stream_data_bidi_remote", p.InitialMaxStreamDataBidiRemote},
+		{"initial_max_stream_data_uni", p.InitialMaxStreamDataUni},
+	} {
+		if v.value < 0 || uint64(v.value) > quicvarint.Max {
+			errs = append(errs, fmt.Errorf("invalid value for %s: %d", v.name, v.value))
+		}
+	}
+	if pers == protocol.PerspectiveClient {
+		if p.StatelessResetToken != nil {
+			errs = append(errs, errors.New("client must not send a stateless_reset_token"))
+		}
+		if p.PreferredAddress != nil {
+			errs = append(errs, errors.New("client must not send a preferred_address"))
+		}
+		if p.RetrySourceConnectionID != nil {
+			errs = append(errs, errors.New("client must not send a retry_source_connection_id"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// A TransportParametersBuilder builds transport parameters.
+// Unset values take their default values (as defined in section 18.2 of RFC 9000).
+type TransportParametersBuilder struct {
+	params TransportParameters
+}
+
+// NewTransportParametersBuilder creates a new TransportParametersBuilder.
+func NewTransportParametersBuilder() *TransportParametersBuilder {
+	return &TransportParametersBuilder{
+		params: TransportParameters{
+			AckDelayExponent:        protocol.DefaultAckDelayExponent,
+			MaxAckDelay:             protocol.DefaultMaxAckDelay,
+			ActiveConnectionIDLimit: protocol.DefaultActiveConnectionIDLimit,
+			MaxDatagramFrameSize:    protocol.InvalidByteCount,
+		},
+	}
+}
+
+// InitialMaxData sets initial_max_data.
+func (b *TransportParametersBuilder) InitialMaxData(n protocol.ByteCount) *TransportParametersBuilder {
+	b.params.InitialMaxData = n
+	return b
+}
+
+// InitialMaxStreamData sets initial_max_stream_data_bidi_local, initial_max_stream_data_bidi_remote
+// and initial_max_stream_data_uni.
+func (b *TransportParametersBuilder) InitialMaxStreamData(bidiLocal, bidiRemote, uni protocol.ByteCount) *TransportParametersBuilder {
+	b.params.InitialMaxStreamDataBidiLocal = bidiLocal
+	b.params.InitialMaxStreamDataBidiRemote = bidiRemote
+	b.params.InitialMaxStreamDataUni = uni
+	return b
+}
+
+// MaxStreams sets initial_max_streams_bidi and initial_max_streams_uni.
+func (b *TransportParametersBuilder) MaxStreams(bidi, uni protocol.StreamNum) *TransportParametersBuilder {
+	b.params.MaxBidiStreamNum = bidi
+	b.params.MaxUniStreamNum = uni
+	return b
+}
+
+// MaxIdleTimeout sets max_idle_timeout.
+func (b *TransportParametersBuilder) MaxIdleTimeout(t time.Duration) *TransportParametersBuilder {
+	b.params.MaxIdleTimeout = t
+	return b
+}
+
+// MaxUDPPayloadSize sets max_udp_payload_size.
+func (b *TransportParametersBuilder) MaxUDPPayloadSize(s protocol.ByteCount) *TransportParametersBuilder {
+	b.params.MaxUDPPayloadSize = s
+	return b
+}
+
+// AckDelay sets ack_delay_exponent and max_ack_delay.
+func (b *TransportParametersBuilder) AckDelay(exponent uint8, maxAckDelay time.Duration) *TransportParametersBuilder {
+	b.params.AckDelayExponent = exponent
+	b.params.MaxAckDelay = maxAckDelay
+	return b
+}
+
+// DisableActiveMigration sets disable_active_migration.
+func (b *TransportParametersBuilder) DisableActiveMigration() *TransportParametersBuilder {
+	b.params.DisableActiveMigration = true
+	return b
+}
+
+// ActiveConnectionIDLimit sets active_connection_id_limit.
+func (b *TransportParametersBuilder) ActiveConnectionIDLimit(n uint64) *TransportParametersBuilder {
+	b.params.ActiveConnectionIDLimit = n
+	return b
+}
+
+// ConnectionIDs sets initial_source_connection_id and original_destination_connection_id.
+// The original destination connection ID is only sent by servers.
+func (b *TransportParametersBuilder) ConnectionIDs(initialSrc, origDest protocol.ConnectionID) *TransportParametersBuilder {
+	b.params.InitialSourceConnectionID = initialSrc
+	b.params.OriginalDestinationConnectionID = origDest
+	return b
+}
+
+// RetrySourceConnectionID sets retry_source_connection_id.
+func (b *TransportParametersBuilder) RetrySourceConnectionID(connID protocol.ConnectionID) *TransportParametersBuilder {
+	b.params.RetrySourceConnectionID = &connID
+	return b
+}
+
+// StatelessResetToken sets stateless_reset_token.
+func (b *TransportParametersBuilder) StatelessResetToken(token protocol.StatelessResetToken) *TransportParametersBuilder {
+	b.params.StatelessResetToken = &token
+	return b
+}
+
+// PreferredAddress sets preferred_address.
+func (b *TransportParametersBuilder) PreferredAddress(pa *PreferredAddress) *TransportParametersBuilder {
+	b.params.PreferredAddress = pa
+	return b
+}
+
+// MaxDatagramFrameSize sets max_datagram_frame_size (RFC 9221).
+func (b *TransportParametersBuilder) MaxDatagramFrameSize(s protocol.ByteCount) *TransportParametersBuilder {
+	b.params.MaxDatagramFrameSize = s
+	return b
+}
+
+// EnableResetStreamAt sets reset_stream_at.
+func (b *TransportParametersBuilder) EnableResetStreamAt() *TransportParametersBuilder {
+	b.params.EnableResetStreamAt = true
+	return b
+}
+
+// Build validates the transport parameters for the given perspective and returns them.
+// The builder can be reused after calling Build.
+func (b *TransportParametersBuilder) Build(pers protocol.Perspective) (*TransportParameters, error) {
+	if err := b.params.Validate(pers); err != nil {
+		return nil, err
+	}
+	params := b.params
+	return &params, nil
+}
@@ -0,0 +1,115 @@
+package wire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportParametersBuilder(t *testing.T) {
+	token := protocol.StatelessResetToken{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	params, err := NewTransportParametersBuilder().
+		InitialMaxData(1<<20).
+		InitialMaxStreamData(1<<10, 1<<11, 1<<12).
+		MaxStreams(100, 10).
+		MaxIdleTimeout(30*time.Second).
+		MaxUDPPayloadSize(1452).
+		AckDelay(5, 50*time.Millisecond).
+		ActiveConnectionIDLimit(4).
+		ConnectionIDs(protocol.ParseConnectionID([]byte{1, 2, 3, 4}), protocol.ParseConnectionID([]byte{5, 6, 7, 8})).
+		StatelessResetToken(token).
+		Build(protocol.PerspectiveServer)
+	require.NoError(t, err)
+
+	// the transport parameters can be marshaled and unmarshaled
+	var p TransportParameters
+	require.NoError(t, p.Unmarshal(params.Marshal(protocol.PerspectiveServer), protocol.PerspectiveServer))
+	require.Equal(t, protocol.ByteCount(1<<20), p.InitialMaxData)
+	require.Equal(t, protocol.ByteCount(1<<11), p.InitialMaxStreamDataBidiRemote)
+	require.Equal(t, protocol.StreamNum(100), p.MaxBidiStreamNum)
+	require.Equal(t, 30*time.Second, p.MaxIdleTimeout)
+	require.Equal(t, uint8(5), p.AckDelayExponent)
+	require.Equal(t, 50*time.Millisecond, p.MaxAckDelay)
+	require.Equal(t, uint64(4), p.ActiveConnectionIDLimit)
+	require.Equal(t, &token, p.StatelessResetToken)
+}
+
+func TestTransportParametersBuilderDefaults(t *testing.T) {
+	params, err := NewTransportParametersBuilder().Build(protocol.PerspectiveClient)
+	require.NoError(t, err)
+	require.Equal(t, uint8(protocol.DefaultAckDelayExponent), params.AckDelayExponent)
+	require.Equal(t, protocol.DefaultMaxAckDelay, params.MaxAckDelay)
+	require.Equal(t, uint64(protocol.DefaultActiveConnectionIDLimit), params.ActiveConnectionIDLimit)
+	require.Equal(t, protocol.InvalidByteCount, params.MaxDatagramFrameSize)
+}
+
+func TestTransportParametersValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		builder *TransportParametersBuilder
+		pers    protocol.Perspective
+		errMsg  string
+	}{
+		{
+			name:    "ack_delay_exponent",
+			builder: NewTransportParametersBuilder().AckDelay(21, protocol.DefaultMaxAckDelay),
+			errMsg:  "invalid value for ack_delay_exponent: 21 (maximum 20)",
+		},
+		{
+			name:    "max_ack_delay",
+			builder: NewTransportParametersBuilder().AckDelay(protocol.DefaultAckDelayExponent, 1<<14*time.Millisecond),
+			errMsg:  "invalid value for max_ack_delay: 16.384s (maximum 16.383s)",
+		},
+		{
+			name:    "max_udp_payload_size",
+			builder: NewTransportParametersBuilder().MaxUDPPayloadSize(1199),
+			errMsg:  "invalid value for max_udp_payload_size: 1199 (minimum 1200)",
+		},
+		{
+			name:    "negative max_idle_timeout",
+			builder: NewTransportParametersBuilder().MaxIdleTimeout(-time.Second),
+			errMsg:  "invalid value for max_idle_timeout: -1s",
+		},
+		{
+			name:    "sub-millisecond max_idle_timeout",
+			builder: NewTransportParametersBuilder().MaxIdleTimeout(time.Microsecond),
+			errMsg:  "invalid value for max_idle_timeout: 1µs (would be encoded as 0, disabling the idle timeout)",
+		},
+		{
+			name:    "active_connection_id_limit",
+			builder: NewTransportParametersBuilder().ActiveConnectionIDLimit(1),
+			errMsg:  "invalid value for active_connection_id_limit: 1 (minimum 2)",
+		},
+		{
+			name:    "initial_max_streams_bidi",
+			builder: NewTransportParametersBuilder().MaxStreams(protocol.MaxStreamCount+1, 0),
+			errMsg:  "invalid value for initial_max_streams_bidi: invalid stream count: 1152921504606846977 (maximum 1152921504606846976)",
+		},
+		{
+			name:    "stateless_reset_token sent by client",
+			builder: NewTransportParametersBuilder().StatelessResetToken(protocol.StatelessResetToken{}),
+			pers:    protocol.PerspectiveClient,
+			errMsg:  "client must not send a stateless_reset_token",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pers := tc.pers
+			if pers == 0 {
+				pers = protocol.PerspectiveServer
+			}
+			_, err := tc.builder.Build(pers)
+			require.EqualError(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestTransportParametersValidationReportsAllErrors(t *testing.T) {
+	_, err := NewTransportParametersBuilder().
+		AckDelay(21, protocol.DefaultMaxAckDelay).
+		MaxUDPPayloadSize(1000).
+		Build(protocol.PerspectiveClient)
+	require.EqualError(t, err, "invalid value for ack_delay_exponent: 21 (maximum 20)\ninvalid value for max_udp_payload_size: 1000 (minimum 1200)")
+}
@@ -0,0 +1,51 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+)
+
+// versionsCompatible says if a connection that started using version from
+// can be switched to version to using compatible version negotiation (RFC 9368).
+// QUIC v1 and QUIC v2 are compatible with each other (see section 4 of RFC 9369).
+func versionsCompatible(from, to protocol.Version) bool {
+	if from == to {
+		return true
+	}
+	isV1OrV2 := func(v protocol.Version) bool { return v == protocol.Version1 || v == protocol.Version2 }
+	return isV1OrV2(from) && isV1OrV2(to)
+}
+
+// CompatibleWith checks that the transport parameters p, sent on a connection that was switched from fromVersion
+// to toVersion using compatible version negotiation (RFC 9368), are consistent with the transport parameters other,
+// which were sent using fromVersion.
+// Compatible version negotiation doesn't change the connection, so the connection IDs authenticated using
+// the transport parameters (see section 7.3 of RFC 9000) and the stateless reset token must not change,
+// and the limits remembered for 0-RTT must not be reduced (see section 7.4.1 of RFC 9000).
+// All violations are reported.
+func (p *TransportParameters) CompatibleWith(other *TransportParameters, fromVersion, toVersion protocol.Version) error {
+	if !versionsCompatible(fromVersion, toVersion) {
+		return fmt.Errorf("version %s is not compatible with version %s", toVersion, fromVersion)
+	}
+	var errs []error
+	if p.OriginalDestinationConnectionID != other.OriginalDestinationConnectionID {
+		errs = append(errs, fmt.Errorf("original_destination_connection_id changed from %s to %s", other.OriginalDestinationConnectionID, p.OriginalDestinationConnectionID))
+	}
+	if p.InitialSourceConnectionID != other.InitialSourceConnectionID {
+		errs = append(errs, fmt.Errorf("initial_source_connection_id changed from %s to %s", other.InitialSourceConnectionID, p.InitialSourceConnectionID))
+	}
+	if (p.RetrySourceConnectionID == nil) != (other.RetrySourceConnectionID == nil) ||
+		(p.RetrySourceConnectionID != nil && *p.RetrySourceConnectionID != *other.RetrySourceConnectionID) {
+		errs = append(errs, errors.New("retry_source_connection_id changed"))
+	}
+	if (p.StatelessResetToken == nil) != (other.StatelessResetToken == nil) ||
+		(p.StatelessResetToken != nil && *p.StatelessResetToken != *other.StatelessResetToken) {
+		errs = append(errs, errors.New("stateless_reset_token changed"))
+	}
+	if !p.ValidFor0RTT(other) {
+		errs = append(errs, errors.New("transport parameters remembered for 0-RTT were reduced"))
+	}
+	return errors.Join(errs...)
+}
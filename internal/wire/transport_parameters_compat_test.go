@@ -0,0 +1,87 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getCompatibilityTestTransportParameters() *TransportParameters {
+	retrySrcConnID := protocol.ParseConnectionID([]byte{9, 10})
+	return &TransportParameters{
+		OriginalDestinationConnectionID: protocol.ParseConnectionID([]byte{1, 2, 3, 4}),
+		InitialSourceConnectionID:       protocol.ParseConnectionID([]byte{5, 6, 7, 8}),
+		RetrySourceConnectionID:         &retrySrcConnID,
+		StatelessResetToken:             &protocol.StatelessResetToken{1, 2, 3},
+		InitialMaxData:                  1000,
+		MaxBidiStreamNum:                10,
+		ActiveConnectionIDLimit:         2,
+		MaxDatagramFrameSize:            protocol.InvalidByteCount,
+	}
+}
+
+func TestTransportParametersCompatibleWith(t *testing.T) {
+	p := getCompatibilityTestTransportParameters()
+	other := getCompatibilityTestTransportParameters()
+	require.NoError(t, p.CompatibleWith(other, protocol.Version1, protocol.Version2))
+	require.NoError(t, p.CompatibleWith(other, protocol.Version2, protocol.Version1))
+	require.NoError(t, p.CompatibleWith(other, protocol.Version1, protocol.Version1))
+
+	// increasing limits is fine
+	p.InitialMaxData = 2000
+	require.NoError(t, p.CompatibleWith(other, protocol.Version1, protocol.Version2))
+}
+
+func TestTransportParametersCompatibleWithIncompatibleVersions(t *testing.T) {
+	p := getCompatibilityTestTransportParameters()
+	require.EqualError(t,
+		p.CompatibleWith(p, protocol.Version1, 0x1a2a3a4a),
+		"version 0x1a2a3a4a is not compatible with version v1",
+	)
+}
+
+func TestTransportParametersCompatibleWithChangedParameters(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(*TransportParameters)
+		errMsg string
+	}{
+		{
+			name: "original_destination_connection_id",
+			modify: func(p *TransportParameters) {
+				p.OriginalDestinationConnectionID = protocol.ParseConnectionID([]byte{4, 3, 2, 1})
+			},
+			errMsg: "original_destination_connection_id changed from 01020304 to 04030201",
+		},
+		{
+			name: "initial_source_connection_id",
+			modify: func(p *TransportParameters) {
+				p.InitialSourceConnectionID = protocol.ParseConnectionID([]byte{8, 7, 6, 5})
+			},
+			errMsg: "initial_source_connection_id changed from 05060708 to 08070605",
+		},
+		{
+			name:   "retry_source_connection_id missing",
+			modify: func(p *TransportParameters) { p.RetrySourceConnectionID = nil },
+			errMsg: "retry_source_connection_id changed",
+		},
+		{
+			name:   "stateless_reset_token",
+			modify: func(p *TransportParameters) { p.StatelessResetToken = &protocol.StatelessResetToken{3, 2, 1} },
+			errMsg: "stateless_reset_token changed",
+		},
+		{
+			name:   "reduced limits",
+			modify: func(p *TransportParameters) { p.MaxBidiStreamNum = 9 },
+			errMsg: "transport parameters remembered for 0-RTT were reduced",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := getCompatibilityTestTransportParameters()
+			tc.modify(p)
+			require.EqualError(t, p.CompatibleWith(getCompatibilityTestTransportParameters(), protocol.Version1, protocol.Version2), tc.errMsg)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package wire
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+)
+
+// RawTransportParameterValues gives access to the encoded values of selected transport parameters.
+// The values are subslices of the buffer they were parsed from, and nil if the transport parameter was not sent.
+// They are only valid as long as that buffer is not modified or reused.
+// Use Clone to retain the values beyond that.
+type RawTransportParameterValues struct {
+	OriginalDestinationConnectionID []byte
+	InitialSourceConnectionID       []byte
+	RetrySourceConnectionID         []byte
+	StatelessResetToken             []byte
+	PreferredAddress                []byte
+}
+
+// ParseRawTransportParameterValues extracts the values of the transport parameters contained in RawTransportParameterValues,
+// without copying them.
+// The length of each value is validated, but no further validation is performed.
+// It is intended to be used in addition to Unmarshal, not as a replacement for it.
+func ParseRawTransportParameterValues(data []byte) (RawTransportParameterValues, error) {
+	var r RawTransportParameterValues
+	if err := walkTransportParameters(data, func(id uint64, value []byte) error {
+		var dst *[]byte
+		var isConnID bool
+		//nolint:exhaustive // Only some transport parameters are accessible.
+		switch transportParameterID(id) {
+		case originalDestinationConnectionIDParameterID:
+			dst, isConnID = &r.OriginalDestinationConnectionID, true
+		case initialSourceConnectionIDParameterID:
+			dst, isConnID = &r.InitialSourceConnectionID, true
+		case retrySourceConnectionIDParameterID:
+			dst, isConnID = &r.RetrySourceConnectionID, true
+		case statelessResetTokenParameterID:
+			if len(value) != 16 {
+				return fmt.Errorf("wrong length for stateless_reset_token: %d (expected 16)", len(value))
+			}
+			dst = &r.StatelessResetToken
+		case preferredAddressParameterID:
+			dst = &r.PreferredAddress
+		default:
+			return nil
+		}
+		if isConnID && len(value) > protocol.MaxConnIDLen {
+			return protocol.ErrInvalidConnectionIDLen
+		}
+		if *dst != nil {
+			return fmt.Errorf("received duplicate transport parameter %#x", id)
+		}
+		// Limit the capacity, such that appending to the value doesn't overwrite the following transport parameters.
+		// The slice is non-nil even for empty values, so that zero-length connection IDs can be distinguished from missing transport parameters.
+		*dst = value[:len(value):len(value)]
+		return nil
+	}); err != nil {
+		return RawTransportParameterValues{}, &qerr.TransportError{
+			ErrorCode:    qerr.TransportParameterError,
+			ErrorMessage: err.Error(),
+		}
+	}
+	return r, nil
+}
+
+// Clone returns a copy of the values that doesn't reference the buffer the values were parsed from.
+func (r RawTransportParameterValues) Clone() RawTransportParameterValues {
+	return RawTransportParameterValues{
+		OriginalDestinationConnectionID: bytes.Clone(r.OriginalDestinationConnectionID),
+		InitialSourceConnectionID:       bytes.Clone(r.InitialSourceConnectionID),
+		RetrySourceConnectionID:         bytes.Clone(r.RetrySourceConnectionID),
+		StatelessResetToken:             bytes.Clone(r.StatelessResetToken),
+		PreferredAddress:                bytes.Clone(r.PreferredAddress),
+	}
+}
@@ -0,0 +1,96 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRawTransportParameterValues(t *testing.T) {
+	token := protocol.StatelessResetToken{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+	retrySrcConnID := protocol.ParseConnectionID(nil)
+	data := (&TransportParameters{
+		OriginalDestinationConnectionID: protocol.ParseConnectionID([]byte{1, 2, 3, 4}),
+		InitialSourceConnectionID:       protocol.ParseConnectionID([]byte{5, 6, 7, 8, 9}),
+		RetrySourceConnectionID:         &retrySrcConnID,
+		StatelessResetToken:             &token,
+		ActiveConnectionIDLimit:         2,
+		MaxDatagramFrameSize:            protocol.InvalidByteCount,
+	}).Marshal(protocol.PerspectiveServer)
+
+	r, err := ParseRawTransportParameterValues(data)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3, 4}, r.OriginalDestinationConnectionID)
+	require.Equal(t, []byte{5, 6, 7, 8, 9}, r.InitialSourceConnectionID)
+	require.NotNil(t, r.RetrySourceConnectionID) // zero-length connection ID
+	require.Empty(t, r.RetrySourceConnectionID)
+	require.Equal(t, token[:], r.StatelessResetToken)
+	require.Nil(t, r.PreferredAddress)
+
+	// the values reference the input
+	r.InitialSourceConnectionID[0] = 42
+	require.Contains(t, string(data), string([]byte{42, 6, 7, 8, 9}))
+	// appending doesn't overwrite the input
+	_ = append(r.OriginalDestinationConnectionID, 0xff)
+	require.Contains(t, string(data), string([]byte{1, 2, 3, 4}))
+
+	// clones don't reference the input
+	clone := r.Clone()
+	clone.InitialSourceConnectionID[0] = 5
+	require.Equal(t, byte(42), r.InitialSourceConnectionID[0])
+	require.NotNil(t, clone.RetrySourceConnectionID)
+	require.Nil(t, clone.PreferredAddress)
+}
+
+func TestParseRawTransportParameterValuesErrors(t *testing.T) {
+	t.Run("connection ID too long", func(t *testing.T) {
+		b := quicvarint.Append(nil, uint64(initialSourceConnectionIDParameterID))
+		b = quicvarint.Append(b, 21)
+		b = append(b, make([]byte, 21)...)
+		_, err := ParseRawTransportParameterValues(b)
+		require.Equal(t, &qerr.TransportError{
+			ErrorCode:    qerr.TransportParameterError,
+			ErrorMessage: protocol.ErrInvalidConnectionIDLen.Error(),
+		}, err)
+	})
+
+	t.Run("invalid stateless reset token", func(t *testing.T) {
+		b := quicvarint.Append(nil, uint64(statelessResetTokenParameterID))
+		b = quicvarint.Append(b, 15)
+		b = append(b, make([]byte, 15)...)
+		_, err := ParseRawTransportParameterValues(b)
+		require.Equal(t, &qerr.TransportError{
+			ErrorCode:    qerr.TransportParameterError,
+			ErrorMessage: "wrong length for stateless_reset_token: 15 (expected 16)",
+		}, err)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		b := appendInitialSourceConnectionID(nil)
+		b = appendInitialSourceConnectionID(b)
+		_, err := ParseRawTransportParameterValues(b)
+		require.Equal(t, &qerr.TransportError{
+			ErrorCode:    qerr.TransportParameterError,
+			ErrorMessage: "received duplicate transport parameter 0xf",
+		}, err)
+	})
+}
+
+func TestParseRawTransportParameterValuesNoAllocations(t *testing.T) {
+	data := (&TransportParameters{
+		OriginalDestinationConnectionID: protocol.ParseConnectionID([]byte{1, 2, 3, 4}),
+		InitialSourceConnectionID:       protocol.ParseConnectionID([]byte{5, 6, 7, 8}),
+		ActiveConnectionIDLimit:         2,
+		MaxDatagramFrameSize:            protocol.InvalidByteCount,
+	}).Marshal(protocol.PerspectiveServer)
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := ParseRawTransportParameterValues(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+	require.Zero(t, allocs)
+}
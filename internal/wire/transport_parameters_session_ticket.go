@@ -0,0 +1,125 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// The compact session ticket encoding stores the transport parameters remembered for 0-RTT
+// (see section 7.4.1 of RFC 9000).
+// It consists of a bitmap (encoded as a varint), followed by the values of the fields set in the bitmap,
+// in the order of their bits.
+// Fields that are not set take their default value.
+//
+// New fields must be added at the end, so that session tickets issued before the field was added can still be decoded.
+// Bits must never be reused.
+const (
+	sessionTicketInitialMaxStreamDataBidiLocal = 1 << iota
+	sessionTicketInitialMaxStreamDataBidiRemote
+	sessionTicketInitialMaxStreamDataUni
+	sessionTicketInitialMaxData
+	sessionTicketMaxBidiStreamNum
+	sessionTicketMaxUniStreamNum
+	sessionTicketActiveConnectionIDLimit
+	sessionTicketMaxDatagramFrameSize
+	sessionTicketEnableResetStreamAt // flag, no value
+
+	sessionTicketKnownFields = sessionTicketEnableResetStreamAt<<1 - 1
+)
+
+func (p *TransportParameters) appendSessionTicketParams(b []byte) []byte {
+	var fields uint64
+	values := make([]uint64, 0, 8)
+	for _, f := range []struct {
+		bit   uint64
+		value uint64
+		isSet bool
+	}{
+		{sessionTicketInitialMaxStreamDataBidiLocal, uint64(p.InitialMaxStreamDataBidiLocal), p.InitialMaxStreamDataBidiLocal != 0},
+		{sessionTicketInitialMaxStreamDataBidiRemote, uint64(p.InitialMaxStreamDataBidiRemote), p.InitialMaxStreamDataBidiRemote != 0},
+		{sessionTicketInitialMaxStreamDataUni, uint64(p.InitialMaxStreamDataUni), p.InitialMaxStreamDataUni != 0},
+		{sessionTicketInitialMaxData, uint64(p.InitialMaxData), p.InitialMaxData != 0},
+		{sessionTicketMaxBidiStreamNum, uint64(p.MaxBidiStreamNum), p.MaxBidiStreamNum != 0},
+		{sessionTicketMaxUniStreamNum, uint64(p.MaxUniStreamNum), p.MaxUniStreamNum != 0},
+		{sessionTicketActiveConnectionIDLimit, p.ActiveConnectionIDLimit, p.ActiveConnectionIDLimit != protocol.DefaultActiveConnectionIDLimit},
+		{sessionTicketMaxDatagramFrameSize, uint64(p.MaxDatagramFrameSize), p.MaxDatagramFrameSize != protocol.InvalidByteCount},
+	} {
+		if f.isSet {
+			fields |= f.bit
+			values = append(values, f.value)
+		}
+	}
+	if p.EnableResetStreamAt {
+		fields |= sessionTicketEnableResetStreamAt
+	}
+	b = quicvarint.Append(b, fields)
+	for _, v := range values {
+		b = quicvarint.Append(b, v)
+	}
+	return b
+}
+
+func (p *TransportParameters) parseSessionTicketParams(b []byte) error {
+	fields, l, err := quicvarint.Parse(b)
+	if err != nil {
+		return err
+	}
+	b = b[l:]
+	if fields&^sessionTicketKnownFields != 0 {
+		return fmt.Errorf("unknown session ticket transport parameter fields: %#x", fields&^sessionTicketKnownFields)
+	}
+
+	p.AckDelayExponent = protocol.DefaultAckDelayExponent
+	p.MaxAckDelay = protocol.DefaultMaxAckDelay
+	p.ActiveConnectionIDLimit = protocol.DefaultActiveConnectionIDLimit
+	p.MaxDatagramFrameSize = protocol.InvalidByteCount
+	readValue := func(bit uint64) (uint64, bool, error) {
+		if fields&bit == 0 {
+			return 0, false, nil
+		}
+		v, l, err := quicvarint.Parse(b)
+		if err != nil {
+			return 0, false, err
+		}
+		b = b[l:]
+		return v, true, nil
+	}
+	for _, f := range []struct {
+		bit uint64
+		set func(uint64)
+	}{
+		{sessionTicketInitialMaxStreamDataBidiLocal, func(v uint64) { p.InitialMaxStreamDataBidiLocal = protocol.ByteCount(v) }},
+		{sessionTicketInitialMaxStreamDataBidiRemote, func(v uint64) { p.InitialMaxStreamDataBidiRemote = protocol.ByteCount(v) }},
+		{sessionTicketInitialMaxStreamDataUni, func(v uint64) { p.InitialMaxStreamDataUni = protocol.ByteCount(v) }},
+		{sessionTicketInitialMaxData, func(v uint64) { p.InitialMaxData = protocol.ByteCount(v) }},
+		{sessionTicketMaxBidiStreamNum, func(v uint64) { p.MaxBidiStreamNum = protocol.StreamNum(v) }},
+		{sessionTicketMaxUniStreamNum, func(v uint64) { p.MaxUniStreamNum = protocol.StreamNum(v) }},
+		{sessionTicketActiveConnectionIDLimit, func(v uint64) { p.ActiveConnectionIDLimit = v }},
+		{sessionTicketMaxDatagramFrameSize, func(v uint64) { p.MaxDatagramFrameSize = protocol.ByteCount(v) }},
+	} {
+		v, ok, err := readValue(f.bit)
+		if err != nil {
+			return err
+		}
+		if ok {
+			f.set(v)
+		}
+	}
+	p.EnableResetStreamAt = fields&sessionTicketEnableResetStreamAt != 0
+	if len(b) > 0 {
+		return errors.New("trailing data after session ticket transport parameters")
+	}
+	if err := protocol.ValidateStreamCount(p.MaxBidiStreamNum, protocol.StreamTypeBidi); err != nil {
+		return fmt.Errorf("invalid initial_max_streams_bidi in session ticket transport parameters: %w", err)
+	}
+	if err := protocol.ValidateStreamCount(p.MaxUniStreamNum, protocol.StreamTypeUni); err != nil {
+		return fmt.Errorf("invalid initial_max_streams_uni in session ticket transport parameters: %w", err)
+	}
+	if p.ActiveConnectionIDLimit < 2 {
+		return fmt.Errorf("invalid active_connection_id_limit in session ticket transport parameters: %d", p.ActiveConnectionIDLimit)
+	}
+	return nil
+}
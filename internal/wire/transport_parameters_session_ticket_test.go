@@ -0,0 +1,102 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+// marshalForSessionTicketV1 marshals transport parameters using version 1 of the session ticket encoding,
+// as it was used by previous versions.
+func marshalForSessionTicketV1(p *TransportParameters) []byte {
+	b := quicvarint.Append(nil, transportParameterMarshalingVersion1)
+	b = p.marshalVarintParam(b, initialMaxStreamDataBidiLocalParameterID, uint64(p.InitialMaxStreamDataBidiLocal))
+	b = p.marshalVarintParam(b, initialMaxStreamDataBidiRemoteParameterID, uint64(p.InitialMaxStreamDataBidiRemote))
+	b = p.marshalVarintParam(b, initialMaxStreamDataUniParameterID, uint64(p.InitialMaxStreamDataUni))
+	b = p.marshalVarintParam(b, initialMaxDataParameterID, uint64(p.InitialMaxData))
+	b = p.marshalVarintParam(b, initialMaxStreamsBidiParameterID, uint64(p.MaxBidiStreamNum))
+	b = p.marshalVarintParam(b, initialMaxStreamsUniParameterID, uint64(p.MaxUniStreamNum))
+	b = p.marshalVarintParam(b, activeConnectionIDLimitParameterID, p.ActiveConnectionIDLimit)
+	if p.MaxDatagramFrameSize != protocol.InvalidByteCount {
+		b = p.marshalVarintParam(b, maxDatagramFrameSizeParameterID, uint64(p.MaxDatagramFrameSize))
+	}
+	if p.EnableResetStreamAt {
+		b = quicvarint.Append(b, uint64(resetStreamAtParameterID))
+		b = quicvarint.Append(b, 0)
+	}
+	return b
+}
+
+func TestSessionTicketTransportParametersV1(t *testing.T) {
+	params := &TransportParameters{
+		InitialMaxStreamDataBidiLocal:  1000,
+		InitialMaxStreamDataBidiRemote: 2000,
+		InitialMaxStreamDataUni:        3000,
+		InitialMaxData:                 4000,
+		MaxBidiStreamNum:               10,
+		MaxUniStreamNum:                20,
+		ActiveConnectionIDLimit:        4,
+		MaxDatagramFrameSize:           1200,
+		EnableResetStreamAt:            true,
+	}
+	var tp TransportParameters
+	require.NoError(t, tp.UnmarshalFromSessionTicket(marshalForSessionTicketV1(params)))
+	require.True(t, tp.ValidFor0RTT(params))
+	require.True(t, params.ValidFor0RTT(&tp))
+	require.True(t, tp.EnableResetStreamAt)
+
+	// the compact encoding is smaller
+	require.Less(t, len(params.MarshalForSessionTicket(nil)), len(marshalForSessionTicketV1(params)))
+}
+
+func TestSessionTicketTransportParametersDefaults(t *testing.T) {
+	params := &TransportParameters{
+		ActiveConnectionIDLimit: protocol.DefaultActiveConnectionIDLimit,
+		MaxDatagramFrameSize:    protocol.InvalidByteCount,
+	}
+	b := params.MarshalForSessionTicket(nil)
+	require.Equal(t, []byte{transportParameterMarshalingVersion2, 0}, b)
+	var tp TransportParameters
+	require.NoError(t, tp.UnmarshalFromSessionTicket(b))
+	require.Equal(t, uint64(protocol.DefaultActiveConnectionIDLimit), tp.ActiveConnectionIDLimit)
+	require.Equal(t, protocol.InvalidByteCount, tp.MaxDatagramFrameSize)
+	require.Equal(t, uint8(protocol.DefaultAckDelayExponent), tp.AckDelayExponent)
+	require.False(t, tp.EnableResetStreamAt)
+}
+
+func TestSessionTicketTransportParametersOlderFieldSet(t *testing.T) {
+	// a ticket issued before max_datagram_frame_size and reset_stream_at were added
+	b := quicvarint.Append(nil, transportParameterMarshalingVersion2)
+	b = quicvarint.Append(b, sessionTicketInitialMaxData|sessionTicketMaxUniStreamNum)
+	b = quicvarint.Append(b, 1337)
+	b = quicvarint.Append(b, 42)
+	var tp TransportParameters
+	require.NoError(t, tp.UnmarshalFromSessionTicket(b))
+	require.Equal(t, protocol.ByteCount(1337), tp.InitialMaxData)
+	require.Equal(t, protocol.StreamNum(42), tp.MaxUniStreamNum)
+	require.Equal(t, protocol.InvalidByteCount, tp.MaxDatagramFrameSize)
+}
+
+func TestSessionTicketTransportParametersErrors(t *testing.T) {
+	b := quicvarint.Append(nil, transportParameterMarshalingVersion2)
+	var tp TransportParameters
+	require.EqualError(t,
+		tp.UnmarshalFromSessionTicket(quicvarint.Append(b, sessionTicketKnownFields+1)),
+		"unknown session ticket transport parameter fields: 0x200",
+	)
+	// value missing
+	require.Error(t, tp.UnmarshalFromSessionTicket(quicvarint.Append(b, sessionTicketInitialMaxData)))
+	// trailing data
+	require.EqualError(t,
+		tp.UnmarshalFromSessionTicket(append(quicvarint.Append(b, 0), 0)),
+		"trailing data after session ticket transport parameters",
+	)
+	// invalid stream count
+	require.EqualError(t,
+		tp.UnmarshalFromSessionTicket(quicvarint.Append(quicvarint.Append(b, sessionTicketMaxUniStreamNum), uint64(protocol.MaxStreamCount+1))),
+		"invalid initial_max_streams_uni in session ticket transport parameters: invalid stream count: 1152921504606846977 (maximum 1152921504606846976)",
+	)
+}
@@ -0,0 +1,112 @@
+package wire
+
+import "github.com/quic-go/quic-go/internal/protocol"
+
+// A FrameKind tags the frame held by a TypedFrame.
+type FrameKind uint8
+
+const (
+	// FrameKindNone is used if no frame was parsed (e.g. the payload only contained PADDING).
+	FrameKindNone FrameKind = iota
+	FrameKindStream
+	FrameKindAck
+	FrameKindDatagram
+	// FrameKindOther is used for all other frames. They are only accessible via the Frame field.
+	FrameKindOther
+)
+
+func (k FrameKind) String() string {
+	switch k {
+	case FrameKindNone:
+		return "none"
+	case FrameKindStream:
+		return "STREAM"
+	case FrameKindAck:
+		return "ACK"
+	case FrameKindDatagram:
+		return "DATAGRAM"
+	case FrameKindOther:
+		return "other"
+	default:
+		return "unknown frame kind"
+	}
+}
+
+// A TypedFrame is a parsed frame, tagged with its kind.
+// The frames that are received most frequently (STREAM, ACK and DATAGRAM) are accessible via typed fields,
+// such that callers on the hot path don't need to use a type assertion on the Frame interface.
+// Only the field matching the Kind is set.
+// Note that the Ack field points to the ACK frame owned by the FrameParser,
+// and is only valid until the next frame is parsed.
+type TypedFrame struct {
+	Kind     FrameKind
+	Stream   *StreamFrame
+	Ack      *AckFrame
+	Datagram *DatagramFrame
+	// Frame is set for FrameKindOther.
+	Frame Frame
+}
+
+// ParseNextTyped is like ParseNext, but returns a TypedFrame.
+// If ParseNext returns a nil frame, the Kind of the TypedFrame is FrameKindNone.
+// Unless middlewares are used, STREAM, ACK and DATAGRAM frames are never boxed in the Frame interface.
+func (p *FrameParser) ParseNextTyped(data []byte, encLevel protocol.EncryptionLevel, v protocol.Version) (int, TypedFrame, error) {
+	l, err := p.parseNextTo(data, encLevel, v, &p.typed)
+	f := p.typed.take()
+	if err != nil {
+		return l, TypedFrame{}, err
+	}
+	return l, f, nil
+}
+
+// typedFrameBuilder is the FrameHandler used by ParseNextTyped.
+type typedFrameBuilder struct{ frame TypedFrame }
+
+var _ FrameHandler = &typedFrameBuilder{}
+
+// take returns the TypedFrame, and resets the typedFrameBuilder.
+func (b *typedFrameBuilder) take() TypedFrame {
+	f := b.frame
+	b.frame = TypedFrame{}
+	return f
+}
+
+func (b *typedFrameBuilder) OnStream(f *StreamFrame) error {
+	b.frame = TypedFrame{Kind: FrameKindStream, Stream: f}
+	return nil
+}
+
+func (b *typedFrameBuilder) OnAck(f *AckFrame) error {
+	b.frame = TypedFrame{Kind: FrameKindAck, Ack: f}
+	return nil
+}
+
+func (b *typedFrameBuilder) OnDatagram(f *DatagramFrame) error {
+	b.frame = TypedFrame{Kind: FrameKindDatagram, Datagram: f}
+	return nil
+}
+
+func (b *typedFrameBuilder) OnOther(f Frame) error {
+	b.frame = TypedFrame{Kind: FrameKindOther, Frame: f}
+	return nil
+}
+
+func (b *typedFrameBuilder) OnPing(f *PingFrame) error                           { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnResetStream(f *ResetStreamFrame) error             { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnStopSending(f *StopSendingFrame) error             { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnCrypto(f *CryptoFrame) error                       { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnNewToken(f *NewTokenFrame) error                   { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnMaxData(f *MaxDataFrame) error                     { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnMaxStreamData(f *MaxStreamDataFrame) error         { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnMaxStreams(f *MaxStreamsFrame) error               { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnDataBlocked(f *DataBlockedFrame) error             { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnStreamDataBlocked(f *StreamDataBlockedFrame) error { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnStreamsBlocked(f *StreamsBlockedFrame) error       { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnNewConnectionID(f *NewConnectionIDFrame) error     { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnRetireConnectionID(f *RetireConnectionIDFrame) error {
+	return b.OnOther(f)
+}
+func (b *typedFrameBuilder) OnPathChallenge(f *PathChallengeFrame) error     { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnPathResponse(f *PathResponseFrame) error       { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnConnectionClose(f *ConnectionCloseFrame) error { return b.OnOther(f) }
+func (b *typedFrameBuilder) OnHandshakeDone(f *HandshakeDoneFrame) error     { return b.OnOther(f) }
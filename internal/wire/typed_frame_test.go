@@ -0,0 +1,92 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNextTyped(t *testing.T) {
+	b := appendFrames(t,
+		&StreamFrame{StreamID: 4, Data: []byte("foobar"), DataLenPresent: true},
+		&AckFrame{AckRanges: []AckRange{{Smallest: 1, Largest: 5}}},
+		&DatagramFrame{Data: []byte("datagram"), DataLenPresent: true},
+		&MaxDataFrame{MaximumData: 1337},
+	)
+	b = append(b, make([]byte, 10)...) // PADDING
+
+	p := NewFrameParser(true, true)
+	l, f, err := p.ParseNextTyped(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, FrameKindStream, f.Kind)
+	require.Equal(t, []byte("foobar"), f.Stream.Data)
+	require.Nil(t, f.Frame)
+	b = b[l:]
+
+	l, f, err = p.ParseNextTyped(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, FrameKindAck, f.Kind)
+	require.Equal(t, protocol.PacketNumber(5), f.Ack.LargestAcked())
+	require.Nil(t, f.Stream)
+	b = b[l:]
+
+	l, f, err = p.ParseNextTyped(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, FrameKindDatagram, f.Kind)
+	require.Equal(t, []byte("datagram"), f.Datagram.Data)
+	b = b[l:]
+
+	l, f, err = p.ParseNextTyped(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, TypedFrame{Kind: FrameKindOther, Frame: &MaxDataFrame{MaximumData: 1337}}, f)
+	b = b[l:]
+
+	l, f, err = p.ParseNextTyped(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, 10, l)
+	require.Equal(t, FrameKindNone, f.Kind)
+
+	_, _, err = p.ParseNextTyped([]byte{maxDataFrameType}, protocol.Encryption1RTT, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: maxDataFrameType, ErrorCode: qerr.FrameEncodingError})
+}
+
+func TestParseNextTypedAllocations(t *testing.T) {
+	b := appendFrames(t, &AckFrame{AckRanges: []AckRange{{Smallest: 5, Largest: 10}, {Smallest: 1, Largest: 2}}})
+	p := NewFrameParser(true, true)
+	// warm up the ACK frame's ACK range slice
+	_, f, err := p.ParseNextTyped(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, FrameKindAck, f.Kind)
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, _, err := p.ParseNextTyped(b, protocol.Encryption1RTT, protocol.Version1); err != nil {
+			t.Fatal(err)
+		}
+	})
+	require.Zero(t, allocs)
+}
+
+func TestParseNextTypedMiddleware(t *testing.T) {
+	b := appendFrames(t,
+		&StreamFrame{StreamID: 4, Data: []byte("foobar"), DataLenPresent: true},
+		&MaxDataFrame{MaximumData: 1337},
+	)
+	p := NewFrameParser(true, true)
+	var parsed int
+	p.Use(func(next ParseFunc) ParseFunc {
+		return func(b []byte, typ uint64, encLevel protocol.EncryptionLevel, v protocol.Version) (Frame, int, error) {
+			parsed++
+			return next(b, typ, encLevel, v)
+		}
+	})
+	l, f, err := p.ParseNextTyped(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, FrameKindStream, f.Kind)
+	require.Equal(t, []byte("foobar"), f.Stream.Data)
+	_, f, err = p.ParseNextTyped(b[l:], protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, TypedFrame{Kind: FrameKindOther, Frame: &MaxDataFrame{MaximumData: 1337}}, f)
+	require.Equal(t, 2, parsed)
+}
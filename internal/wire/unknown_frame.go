@@ -0,0 +1,65 @@
+package wire
+
+import (
+	"fmt"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/quicvarint"
+)
+
+// An UnknownFrame is a placeholder for a frame of a type unknown to the FrameParser.
+// It is only returned if an UnknownFrameLengthFunc was set using SetUnknownFrameLength.
+type UnknownFrame struct {
+	FrameType FrameType
+	// Data is the contents of the frame, not including the frame type.
+	Data []byte
+}
+
+// An UnknownFrameLengthFunc determines the length of a frame of an unknown type.
+// It is passed the rest of the payload, starting right after the frame type,
+// and returns the length of the frame, not including the frame type.
+// If the length can't be determined, it returns false.
+type UnknownFrameLengthFunc func(typ FrameType, b []byte) (int, bool)
+
+// UnknownFrameExtendsToEnd is an UnknownFrameLengthFunc for use when nothing is known about a frame type.
+// It assumes that the frame extends to the end of the payload.
+// This is the only safe assumption, since any other length could make the following frames parse as garbage.
+func UnknownFrameExtendsToEnd(_ FrameType, b []byte) (int, bool) {
+	return len(b), true
+}
+
+// SetUnknownFrameLength configures the parser to return an UnknownFrame for frames of unknown types,
+// instead of failing with a FRAME_ENCODING_ERROR.
+// This is useful for diagnostic and analysis tools, where a single unknown extension frame
+// shouldn't prevent parsing the rest of the payload. It must not be used for live connections.
+// The length of the frame is determined by fn. If fn is nil, unknown frames fail parsing.
+// UnknownFrames are not allowed at the Initial and Handshake encryption level, unless ProfileAnalyzer is used.
+func (p *FrameParser) SetUnknownFrameLength(fn UnknownFrameLengthFunc) {
+	p.unknownFrameLength = fn
+}
+
+func (p *FrameParser) parseUnknownFrame(b []byte, typ uint64) (*UnknownFrame, int, error) {
+	l, ok := p.unknownFrameLength(FrameType(typ), b)
+	if !ok {
+		return nil, 0, errUnknownFrameType
+	}
+	if l < 0 || l > len(b) {
+		return nil, 0, fmt.Errorf("invalid length for frame of unknown type: %d (%d bytes remaining)", l, len(b))
+	}
+	f := &UnknownFrame{FrameType: FrameType(typ)}
+	if l > 0 {
+		f.Data = make([]byte, l)
+		copy(f.Data, b)
+	}
+	return f, l, nil
+}
+
+func (f *UnknownFrame) Append(b []byte, _ protocol.Version) ([]byte, error) {
+	b = quicvarint.Append(b, uint64(f.FrameType))
+	return append(b, f.Data...), nil
+}
+
+// Length of a written frame
+func (f *UnknownFrame) Length(_ protocol.Version) protocol.ByteCount {
+	return protocol.ByteCount(quicvarint.Len(uint64(f.FrameType)) + len(f.Data))
+}
@@ -0,0 +1,79 @@
+package wire
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/quicvarint"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUnknownFrame(t *testing.T) {
+	unknown := append(quicvarint.Append(nil, 0x4242), []byte("foo")...)
+	b := append(slices.Clone(unknown), appendFrames(t, &PingFrame{})...)
+
+	p := NewFrameParser(true, true)
+	_, _, err := p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	checkFrameUnsupported(t, err, 0x4242)
+
+	// the length of this frame type is known to be 3 bytes
+	p.SetUnknownFrameLength(func(typ FrameType, b []byte) (int, bool) {
+		if typ == 0x4242 {
+			return 3, true
+		}
+		return 0, false
+	})
+	l, f, err := p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &UnknownFrame{FrameType: 0x4242, Data: []byte("foo")}, f)
+	require.Equal(t, len(unknown), l)
+	require.Equal(t, protocol.ByteCount(l), f.Length(protocol.Version1))
+	serialized, err := f.Append(nil, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, unknown, serialized)
+	_, f, err = p.ParseNext(b[l:], protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, &PingFrame{}, f)
+	_, _, err = p.ParseNext(quicvarint.Append(nil, 0x1337), protocol.Encryption1RTT, protocol.Version1)
+	checkFrameUnsupported(t, err, 0x1337)
+
+	// by default, unknown frames extend to the end of the payload
+	p.SetUnknownFrameLength(UnknownFrameExtendsToEnd)
+	l, f, err = p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.NoError(t, err)
+	require.Equal(t, len(b), l)
+	require.Equal(t, &UnknownFrame{FrameType: 0x4242, Data: append([]byte("foo"), pingFrameType)}, f)
+
+	// unknown frames are not allowed in Initial packets
+	_, _, err = p.ParseNext(b, protocol.EncryptionInitial, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{FrameType: 0x4242, ErrorCode: qerr.FrameEncodingError})
+	p.SetStrictnessProfile(ProfileAnalyzer)
+	_, f, err = p.ParseNext(b, protocol.EncryptionInitial, protocol.Version1)
+	require.NoError(t, err)
+	require.IsType(t, &UnknownFrame{}, f)
+}
+
+func TestParseUnknownFrameInvalidLength(t *testing.T) {
+	p := NewFrameParser(true, true)
+	p.SetUnknownFrameLength(func(FrameType, []byte) (int, bool) { return 10, true })
+	b := append(quicvarint.Append(nil, 0x4242), []byte("foo")...)
+	_, _, err := p.ParseNext(b, protocol.Encryption1RTT, protocol.Version1)
+	require.ErrorIs(t, err, &qerr.TransportError{
+		FrameType:    0x4242,
+		ErrorCode:    qerr.FrameEncodingError,
+		ErrorMessage: "invalid length for frame of unknown type: 10 (3 bytes remaining)",
+	})
+}
+
+func TestParseUnknownFrameWithHandler(t *testing.T) {
+	p := NewFrameParser(true, true)
+	p.SetUnknownFrameLength(UnknownFrameExtendsToEnd)
+	b := append(quicvarint.Append(nil, 0x4242), []byte("foo")...)
+	var h frameRecorder
+	require.NoError(t, p.ParseWithHandler(b, protocol.Encryption1RTT, protocol.Version1, &h))
+	require.Len(t, h.frames, 1)
+	require.Equal(t, FrameType(0x4242), h.frames[0].(*UnknownFrame).FrameType)
+}
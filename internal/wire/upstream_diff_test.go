@@ -0,0 +1,60 @@
+package wire
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// upstreamDivergence describes a record that was parsed differently by upstream quic-go.
+type upstreamDivergence struct {
+	Index    int
+	Payload  []byte
+	Expected ReplayResult
+	Actual   ReplayResult
+}
+
+// diffAgainstUpstream compares the results of a replay with the upstream results.
+// Error messages differ between the two implementations, so only the frame types
+// and whether parsing failed are compared.
+func diffAgainstUpstream(records []*TraceRecord, actual, expected []ReplayResult) []upstreamDivergence {
+	var divergences []upstreamDivergence
+	for i, rec := range records {
+		var exp, act ReplayResult
+		if i < len(expected) {
+			exp = expected[i]
+		}
+		if i < len(actual) {
+			act = actual[i]
+		}
+		if slices.Equal(exp.FrameTypes, act.FrameTypes) && (exp.Err == "") == (act.Err == "") {
+			continue
+		}
+		divergences = append(divergences, upstreamDivergence{
+			Index:    i,
+			Payload:  rec.Payload,
+			Expected: exp,
+			Actual:   act,
+		})
+	}
+	return divergences
+}
+
+func TestUpstreamDiff(t *testing.T) {
+	records := []*TraceRecord{{Payload: []byte{1}}, {Payload: []byte{2}}, {Payload: []byte{3}}}
+	expected := []ReplayResult{
+		{FrameTypes: []uint64{1}},
+		{FrameTypes: []uint64{2}, Err: "upstream error"},
+		{FrameTypes: []uint64{3}},
+	}
+	actual := []ReplayResult{
+		{FrameTypes: []uint64{1}},
+		{FrameTypes: []uint64{2}, Err: "a different error"}, // error messages are not compared
+		{FrameTypes: []uint64{3, 3}},
+	}
+	require.Equal(t,
+		[]upstreamDivergence{{Index: 2, Payload: []byte{3}, Expected: expected[2], Actual: actual[2]}},
+		diffAgainstUpstream(records, actual, expected),
+	)
+}
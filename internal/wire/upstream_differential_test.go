@@ -0,0 +1,74 @@
+//go:build upstream
+
+package wire
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The differential test compares this parser against the frame parser of upstream quic-go.
+// Since upstream's parser lives in an internal package, it can't be imported here.
+// Instead, the payloads of a frame trace are parsed in an upstream checkout
+// (calling ParseNext until the payload is consumed or an error occurs),
+// and the results are stored as a JSON-encoded []ReplayResult, one entry per trace record.
+//
+// Generate the upstream results using:
+//
+//	testdata/upstream/generate.sh <upstream checkout> <trace> <results.json>
+//
+// Then run:
+//
+//	go test -tags upstream -run TestUpstreamDifferential -upstream.trace=<trace> -upstream.results=<results.json>
+var (
+	upstreamTrace   = flag.String("upstream.trace", "", "frame trace containing the payloads to compare")
+	upstreamResults = flag.String("upstream.results", "", "JSON-encoded results of parsing the trace using upstream quic-go")
+)
+
+func TestUpstreamDifferential(t *testing.T) {
+	if *upstreamTrace == "" || *upstreamResults == "" {
+		t.Skip("-upstream.trace and -upstream.results are required")
+	}
+	trace, err := os.ReadFile(*upstreamTrace)
+	require.NoError(t, err)
+	resultsData, err := os.ReadFile(*upstreamResults)
+	require.NoError(t, err)
+	var expected []ReplayResult
+	require.NoError(t, json.Unmarshal(resultsData, &expected))
+
+	tr, err := NewTraceReader(bytes.NewReader(trace))
+	require.NoError(t, err)
+	var records []*TraceRecord
+	for {
+		rec, err := tr.ReadRecord()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		records = append(records, rec)
+	}
+	require.Len(t, expected, len(records), "number of upstream results doesn't match the number of trace records")
+
+	tr, err = NewTraceReader(bytes.NewReader(trace))
+	require.NoError(t, err)
+	replayer := &TraceReplayer{Parser: NewFrameParser(true, true)}
+	report, err := replayer.Replay(context.Background(), tr)
+	require.NoError(t, err)
+
+	divergences := diffAgainstUpstream(records, report.Results, expected)
+	for _, d := range divergences {
+		t.Errorf("record %d (%x, %s): upstream parsed %v (error: %q), this parser parsed %v (error: %q)",
+			d.Index, d.Payload, records[d.Index].EncryptionLevel,
+			d.Expected.FrameTypes, d.Expected.Err,
+			d.Actual.FrameTypes, d.Actual.Err,
+		)
+	}
+	t.Logf("compared %d records (%d frames), %d divergences", report.Records, report.Frames, len(divergences))
+}
@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"errors"
+	"io"
+	"slices"
 
 	"github.com/quic-go/quic-go/internal/protocol"
 )
@@ -33,10 +35,30 @@ func ParseVersionNegotiationPacket(b []byte) (dest, src protocol.ArbitraryLenCon
 
 // ComposeVersionNegotiation composes a Version Negotiation
 func ComposeVersionNegotiation(destConnID, srcConnID protocol.ArbitraryLenConnectionID, versions []protocol.Version) []byte {
-	greasedVersions := protocol.GetGreasedVersions(versions)
+	var typeByte [1]byte
+	_, _ = rand.Read(typeByte[:]) // ignore the error here. It is not critical to have perfect random here.
+	return composeVersionNegotiation(destConnID, srcConnID, protocol.GetGreasedVersions(versions), typeByte[0])
+}
+
+// ComposeVersionNegotiationWithRand is like ComposeVersionNegotiation,
+// but uses r as the source of randomness for the unused bits of the first byte and the greased version.
+// It returns an error if reading from r fails.
+func ComposeVersionNegotiationWithRand(destConnID, srcConnID protocol.ArbitraryLenConnectionID, versions []protocol.Version, r io.Reader) ([]byte, error) {
+	var typeByte [1]byte
+	if _, err := io.ReadFull(r, typeByte[:]); err != nil {
+		return nil, err
+	}
+	greasedVersions, err := protocol.GetGreasedVersionsWithRand(versions, r)
+	if err != nil {
+		return nil, err
+	}
+	return composeVersionNegotiation(destConnID, srcConnID, greasedVersions, typeByte[0]), nil
+}
+
+func composeVersionNegotiation(destConnID, srcConnID protocol.ArbitraryLenConnectionID, greasedVersions []protocol.Version, typeByte byte) []byte {
 	expectedLen := 1 /* type byte */ + 4 /* version field */ + 1 /* dest connection ID length field */ + destConnID.Len() + 1 /* src connection ID length field */ + srcConnID.Len() + len(greasedVersions)*4
 	buf := make([]byte, 1+4 /* type byte and version field */, expectedLen)
-	_, _ = rand.Read(buf[:1]) // ignore the error here. It is not critical to have perfect random here.
+	buf[0] = typeByte
 	// Setting the "QUIC bit" (0x40) is not required by the RFC,
 	// but it allows clients to demultiplex QUIC with a long list of other protocols.
 	// See RFC 9443 and https://mailarchive.ietf.org/arch/msg/quic/oR4kxGKY6mjtPC1CZegY1ED4beg/ for details.
@@ -51,3 +73,31 @@ func ComposeVersionNegotiation(destConnID, srcConnID protocol.ArbitraryLenConnec
 	}
 	return buf
 }
+
+var (
+	// ErrVersionNegotiationOfferedVersion is returned when a Version Negotiation packet lists the version offered by the client.
+	// Such a packet was either corrupted or injected by an attacker, and must be ignored (see section 6.2 of RFC 9000).
+	ErrVersionNegotiationOfferedVersion = errors.New("Version Negotiation packet contains the offered version") //nolint:staticcheck // SA1021
+	// ErrVersionNegotiationNoCompatibleVersion is returned when a Version Negotiation packet doesn't list any supported version.
+	ErrVersionNegotiationNoCompatibleVersion = errors.New("Version Negotiation packet doesn't contain a supported version") //nolint:staticcheck // SA1021
+)
+
+// ValidateVersionNegotiation validates the versions received in a Version Negotiation packet,
+// in response to a packet using the offered version.
+// A Version Negotiation packet listing the offered version must be ignored,
+// and if none of the received versions are supported, the connection attempt fails.
+// Note that the version_information transport parameter (RFC 9368) is not implemented,
+// so a downgrade to a different version can't be detected after the handshake.
+func ValidateVersionNegotiation(received []protocol.Version, offered protocol.Version, supported []protocol.Version) error {
+	if len(received) == 0 {
+		//nolint:staticcheck // SA1021: the packet is called Version Negotiation packet
+		return errors.New("Version Negotiation packet has empty version list")
+	}
+	if slices.Contains(received, offered) {
+		return ErrVersionNegotiationOfferedVersion
+	}
+	if _, ok := protocol.ChooseSupportedVersion(supported, received); !ok {
+		return ErrVersionNegotiationNoCompatibleVersion
+	}
+	return nil
+}
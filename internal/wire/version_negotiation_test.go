@@ -1,8 +1,10 @@
 package wire
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/binary"
+	"io"
 	mrand "math/rand/v2"
 	"testing"
 
@@ -90,6 +92,28 @@ versionLoop:
 	require.True(t, reservedVersion&0x0f0f0f0f == 0x0a0a0a0a) // check that it's a greased version number
 }
 
+func TestComposeVersionNegotiationWithRand(t *testing.T) {
+	random := []byte{0x3f, 0, 0, 0, 1, 0x1a, 0x2a, 0x3a, 0x4a}
+	compose := func(r io.Reader) ([]byte, error) {
+		return ComposeVersionNegotiationWithRand(protocol.ArbitraryLenConnectionID{1, 2}, protocol.ArbitraryLenConnectionID{3}, []protocol.Version{1001, 1003}, r)
+	}
+	data, err := compose(bytes.NewReader(random))
+	require.NoError(t, err)
+	data2, err := compose(bytes.NewReader(random))
+	require.NoError(t, err)
+	require.Equal(t, data, data2)
+	require.Equal(t, byte(0xff), data[0])
+	_, _, versions, err := ParseVersionNegotiationPacket(data)
+	require.NoError(t, err)
+	require.Equal(t, []protocol.Version{1001, 0x1a2a3a4a, 1003}, versions)
+
+	// errors reading the randomness are returned
+	_, err = compose(bytes.NewReader(nil))
+	require.ErrorIs(t, err, io.EOF)
+	_, err = compose(bytes.NewReader(random[:5]))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
 func BenchmarkComposeVersionNegotiationPacket(b *testing.B) {
 	b.ReportAllocs()
 	supportedVersions := []protocol.Version{protocol.Version2, protocol.Version1, 0x1337}
@@ -99,3 +123,20 @@ func BenchmarkComposeVersionNegotiationPacket(b *testing.B) {
 		ComposeVersionNegotiation(destConnID, srcConnID, supportedVersions)
 	}
 }
+
+func TestValidateVersionNegotiation(t *testing.T) {
+	supported := []protocol.Version{protocol.Version2, protocol.Version1}
+	require.NoError(t, ValidateVersionNegotiation([]protocol.Version{0x1337, protocol.Version1}, protocol.Version2, supported))
+	require.ErrorIs(t,
+		ValidateVersionNegotiation([]protocol.Version{protocol.Version1, protocol.Version2}, protocol.Version2, supported),
+		ErrVersionNegotiationOfferedVersion,
+	)
+	require.ErrorIs(t,
+		ValidateVersionNegotiation([]protocol.Version{0x1337, 0x42}, protocol.Version1, supported),
+		ErrVersionNegotiationNoCompatibleVersion,
+	)
+	require.EqualError(t,
+		ValidateVersionNegotiation(nil, protocol.Version1, supported),
+		"Version Negotiation packet has empty version list",
+	)
+}
@@ -173,27 +173,17 @@ func newPacketPacker(
 
 // PackConnectionClose packs a packet that closes the connection with a transport error.
 func (p *packetPacker) PackConnectionClose(e *qerr.TransportError, maxPacketSize protocol.ByteCount, v protocol.Version) (*coalescedPacket, error) {
-	var reason string
-	// don't send details of crypto errors
-	if !e.ErrorCode.IsCryptoError() {
-		reason = e.ErrorMessage
-	}
-	return p.packConnectionClose(false, uint64(e.ErrorCode), e.FrameType, reason, maxPacketSize, v)
+	ccf, _ := wire.ConnectionCloseFromError(e)
+	return p.packConnectionClose(ccf, maxPacketSize, v)
 }
 
 // PackApplicationClose packs a packet that closes the connection with an application error.
 func (p *packetPacker) PackApplicationClose(e *qerr.ApplicationError, maxPacketSize protocol.ByteCount, v protocol.Version) (*coalescedPacket, error) {
-	return p.packConnectionClose(true, uint64(e.ErrorCode), 0, e.ErrorMessage, maxPacketSize, v)
+	ccf, _ := wire.ConnectionCloseFromError(e)
+	return p.packConnectionClose(ccf, maxPacketSize, v)
 }
 
-func (p *packetPacker) packConnectionClose(
-	isApplicationError bool,
-	errorCode uint64,
-	frameType uint64,
-	reason string,
-	maxPacketSize protocol.ByteCount,
-	v protocol.Version,
-) (*coalescedPacket, error) {
+func (p *packetPacker) packConnectionClose(frame *wire.ConnectionCloseFrame, maxPacketSize protocol.ByteCount, v protocol.Version) (*coalescedPacket, error) {
 	var sealers [4]sealer
 	var hdrs [3]*wire.ExtendedHeader
 	var payloads [4]payload
@@ -208,17 +198,11 @@ func (p *packetPacker) packConnectionClose(
 		if p.perspective == protocol.PerspectiveServer && encLevel == protocol.Encryption0RTT {
 			continue
 		}
-		ccf := &wire.ConnectionCloseFrame{
-			IsApplicationError: isApplicationError,
-			ErrorCode:          errorCode,
-			FrameType:          frameType,
-			ReasonPhrase:       reason,
-		}
+		ccf := &wire.ConnectionCloseFrame{}
+		*ccf = *frame
 		// don't send application errors in Initial or Handshake packets
-		if isApplicationError && (encLevel == protocol.EncryptionInitial || encLevel == protocol.EncryptionHandshake) {
-			ccf.IsApplicationError = false
-			ccf.ErrorCode = uint64(qerr.ApplicationErrorErrorCode)
-			ccf.ReasonPhrase = ""
+		if frame.IsApplicationError && (encLevel == protocol.EncryptionInitial || encLevel == protocol.EncryptionHandshake) {
+			ccf = wire.NewTransportClose(qerr.ApplicationErrorErrorCode, 0, "")
 		}
 		pl := payload{
 			frames: []ackhandler.Frame{{Frame: ccf}},
@@ -262,6 +246,43 @@ func (p *packetPacker) packConnectionClose(
 		}
 		payloads[i] = pl
 	}
+	// The size doesn't account for the AEAD overhead of the 1-RTT packet.
+	var oneRTTOverhead protocol.ByteCount
+	if sealers[3] != nil {
+		oneRTTOverhead = protocol.ByteCount(sealers[3].Overhead())
+	}
+	// Truncate the reason phrases, such that the connection can be closed regardless of the packet size.
+	for size+oneRTTOverhead > maxPacketSize {
+		var numReasons protocol.ByteCount
+		for i := range payloads {
+			if sealers[i] != nil && payloads[i].frames[0].Frame.(*wire.ConnectionCloseFrame).ReasonPhrase != "" {
+				numReasons++
+			}
+		}
+		if numReasons == 0 {
+			break
+		}
+		cut := (size + oneRTTOverhead - maxPacketSize + numReasons - 1) / numReasons
+		size = 0
+		for i, encLevel := range encLevels {
+			if sealers[i] == nil {
+				continue
+			}
+			if ccf := payloads[i].frames[0].Frame.(*wire.ConnectionCloseFrame); ccf.ReasonPhrase != "" {
+				maxLen := ccf.Length(v) - min(cut, protocol.ByteCount(len(ccf.ReasonPhrase)))
+				ccf, _, _ = wire.ComposeConnectionClose(ccf, 0, maxLen, v)
+				payloads[i] = payload{
+					frames: []ackhandler.Frame{{Frame: ccf}},
+					length: ccf.Length(v),
+				}
+			}
+			if encLevel == protocol.Encryption1RTT {
+				size += p.shortHeaderPacketLength(connID, oneRTTPacketNumberLen, payloads[i])
+			} else {
+				size += p.longHeaderPacketLength(hdrs[i], payloads[i], v) + protocol.ByteCount(sealers[i].Overhead())
+			}
+		}
+	}
 	buffer := getPacketBuffer()
 	packet := &coalescedPacket{
 		buffer:         buffer,
@@ -274,6 +295,7 @@ func (p *packetPacker) packConnectionClose(
 		if encLevel == protocol.Encryption1RTT {
 			shp, err := p.appendShortHeaderPacket(buffer, connID, oneRTTPacketNumber, oneRTTPacketNumberLen, keyPhase, payloads[i], 0, maxPacketSize, sealers[i], false, v)
 			if err != nil {
+				buffer.Release()
 				return nil, err
 			}
 			packet.shortHdrPacket = &shp
@@ -284,11 +306,17 @@ func (p *packetPacker) packConnectionClose(
 			}
 			longHdrPacket, err := p.appendLongHeaderPacket(buffer, hdrs[i], payloads[i], paddingLen, encLevel, sealers[i], v)
 			if err != nil {
+				buffer.Release()
 				return nil, err
 			}
 			packet.longHdrPackets = append(packet.longHdrPackets, longHdrPacket)
 		}
 	}
+	requiresMinSize := sealers[0] != nil && p.initialRequiresPadding(payloads[0].frames)
+	if err := wire.CheckDatagramSize(buffer.Len(), maxPacketSize, requiresMinSize); err != nil {
+		buffer.Release()
+		return nil, err
+	}
 	return packet, nil
 }
 
@@ -317,8 +345,7 @@ func (p *packetPacker) shortHeaderPacketLength(connID protocol.ConnectionID, pnL
 
 // size is the expected size of the packet, if no padding was applied.
 func (p *packetPacker) initialPaddingLen(frames []ackhandler.Frame, currentSize, maxPacketSize protocol.ByteCount) protocol.ByteCount {
-	// For the server, only ack-eliciting Initial packets need to be padded.
-	if p.perspective == protocol.PerspectiveServer && !ackhandler.HasAckElicitingFrames(frames) {
+	if !p.initialRequiresPadding(frames) {
 		return 0
 	}
 	if currentSize >= maxPacketSize {
@@ -327,6 +354,12 @@ func (p *packetPacker) initialPaddingLen(frames []ackhandler.Frame, currentSize,
 	return maxPacketSize - currentSize
 }
 
+// initialRequiresPadding says if the datagram containing an Initial packet with these frames needs to be padded.
+// Clients need to pad all datagrams containing Initial packets, servers only ack-eliciting ones.
+func (p *packetPacker) initialRequiresPadding(frames []ackhandler.Frame) bool {
+	return p.perspective == protocol.PerspectiveClient || ackhandler.HasAckElicitingFrames(frames)
+}
+
 // PackCoalescedPacket packs a new packet.
 // It packs an Initial / Handshake if there is data to send in these packet number spaces.
 // It should only be called before the handshake is confirmed.
@@ -431,6 +464,7 @@ func (p *packetPacker) PackCoalescedPacket(onlyAck bool, maxSize protocol.ByteCo
 		padding := p.initialPaddingLen(initialPayload.frames, size, maxSize)
 		cont, err := p.appendLongHeaderPacket(buffer, initialHdr, initialPayload, padding, protocol.EncryptionInitial, initialSealer, v)
 		if err != nil {
+			buffer.Release()
 			return nil, err
 		}
 		packet.longHdrPackets = append(packet.longHdrPackets, cont)
@@ -438,6 +472,7 @@ func (p *packetPacker) PackCoalescedPacket(onlyAck bool, maxSize protocol.ByteCo
 	if handshakePayload.length > 0 {
 		cont, err := p.appendLongHeaderPacket(buffer, handshakeHdr, handshakePayload, 0, protocol.EncryptionHandshake, handshakeSealer, v)
 		if err != nil {
+			buffer.Release()
 			return nil, err
 		}
 		packet.longHdrPackets = append(packet.longHdrPackets, cont)
@@ -445,16 +480,23 @@ func (p *packetPacker) PackCoalescedPacket(onlyAck bool, maxSize protocol.ByteCo
 	if zeroRTTPayload.length > 0 {
 		longHdrPacket, err := p.appendLongHeaderPacket(buffer, zeroRTTHdr, zeroRTTPayload, 0, protocol.Encryption0RTT, zeroRTTSealer, v)
 		if err != nil {
+			buffer.Release()
 			return nil, err
 		}
 		packet.longHdrPackets = append(packet.longHdrPackets, longHdrPacket)
 	} else if oneRTTPayload.length > 0 {
 		shp, err := p.appendShortHeaderPacket(buffer, connID, oneRTTPacketNumber, oneRTTPacketNumberLen, kp, oneRTTPayload, 0, maxSize, oneRTTSealer, false, v)
 		if err != nil {
+			buffer.Release()
 			return nil, err
 		}
 		packet.shortHdrPacket = &shp
 	}
+	requiresMinSize := initialPayload.length > 0 && p.initialRequiresPadding(initialPayload.frames)
+	if err := wire.CheckDatagramSize(buffer.Len(), maxSize, requiresMinSize); err != nil {
+		buffer.Release()
+		return nil, err
+	}
 	return packet, nil
 }
 
@@ -463,7 +505,11 @@ func (p *packetPacker) PackCoalescedPacket(onlyAck bool, maxSize protocol.ByteCo
 func (p *packetPacker) PackAckOnlyPacket(maxSize protocol.ByteCount, now time.Time, v protocol.Version) (shortHeaderPacket, *packetBuffer, error) {
 	buf := getPacketBuffer()
 	packet, err := p.appendPacket(buf, true, maxSize, now, v)
-	return packet, buf, err
+	if err != nil {
+		buf.Release()
+		return shortHeaderPacket{}, nil, err
+	}
+	return packet, buf, nil
 }
 
 // AppendPacket packs a packet in the application data packet number space.
@@ -492,7 +538,17 @@ func (p *packetPacker) appendPacket(
 	}
 	kp := sealer.KeyPhase()
 
-	return p.appendShortHeaderPacket(buf, connID, pn, pnLen, kp, pl, 0, maxPacketSize, sealer, false, v)
+	startLen := buf.Len()
+	packet, err := p.appendShortHeaderPacket(buf, connID, pn, pnLen, kp, pl, 0, maxPacketSize, sealer, false, v)
+	if err != nil {
+		return shortHeaderPacket{}, err
+	}
+	// The buffer might already contain other packets, each of which is sent in a separate datagram.
+	if err := wire.CheckDatagramSize(buf.Len()-startLen, maxPacketSize, false); err != nil {
+		buf.Data = buf.Data[:startLen]
+		return shortHeaderPacket{}, err
+	}
+	return packet, nil
 }
 
 func (p *packetPacker) maybeGetCryptoPacket(
@@ -763,6 +819,12 @@ func (p *packetPacker) PackPTOProbePacket(
 
 	longHdrPacket, err := p.appendLongHeaderPacket(buffer, hdr, pl, padding, encLevel, sealer, v)
 	if err != nil {
+		buffer.Release()
+		return nil, err
+	}
+	requiresMinSize := encLevel == protocol.EncryptionInitial && p.initialRequiresPadding(pl.frames)
+	if err := wire.CheckDatagramSize(buffer.Len(), maxPacketSize, requiresMinSize); err != nil {
+		buffer.Release()
 		return nil, err
 	}
 	packet.longHdrPackets = []*longHeaderPacket{longHdrPacket}
@@ -791,6 +853,11 @@ func (p *packetPacker) packPTOProbePacket1RTT(maxPacketSize protocol.ByteCount,
 	packet := &coalescedPacket{buffer: buffer}
 	shp, err := p.appendShortHeaderPacket(buffer, connID, pn, pnLen, kp, pl, 0, maxPacketSize, s, false, v)
 	if err != nil {
+		buffer.Release()
+		return nil, err
+	}
+	if err := wire.CheckDatagramSize(buffer.Len(), maxPacketSize, false); err != nil {
+		buffer.Release()
 		return nil, err
 	}
 	packet.shortHdrPacket = &shp
@@ -802,7 +869,6 @@ func (p *packetPacker) PackMTUProbePacket(ping ackhandler.Frame, size protocol.B
 		frames: []ackhandler.Frame{ping},
 		length: ping.Frame.Length(v),
 	}
-	buffer := getPacketBuffer()
 	s, err := p.cryptoSetup.Get1RTTSealer()
 	if err != nil {
 		return shortHeaderPacket{}, nil, err
@@ -811,13 +877,21 @@ func (p *packetPacker) PackMTUProbePacket(ping ackhandler.Frame, size protocol.B
 	pn, pnLen := p.pnManager.PeekPacketNumber(protocol.Encryption1RTT)
 	padding := size - p.shortHeaderPacketLength(connID, pnLen, pl) - protocol.ByteCount(s.Overhead())
 	kp := s.KeyPhase()
+	buffer := getPacketBuffer()
 	packet, err := p.appendShortHeaderPacket(buffer, connID, pn, pnLen, kp, pl, padding, size, s, true, v)
-	return packet, buffer, err
+	if err != nil {
+		buffer.Release()
+		return shortHeaderPacket{}, nil, err
+	}
+	if err := wire.CheckDatagramSize(buffer.Len(), size, false); err != nil {
+		buffer.Release()
+		return shortHeaderPacket{}, nil, err
+	}
+	return packet, buffer, nil
 }
 
 func (p *packetPacker) PackPathProbePacket(connID protocol.ConnectionID, frames []ackhandler.Frame, v protocol.Version) (shortHeaderPacket, *packetBuffer, error) {
 	pn, pnLen := p.pnManager.PeekPacketNumber(protocol.Encryption1RTT)
-	buf := getPacketBuffer()
 	s, err := p.cryptoSetup.Get1RTTSealer()
 	if err != nil {
 		return shortHeaderPacket{}, nil, err
@@ -831,12 +905,18 @@ func (p *packetPacker) PackPathProbePacket(connID protocol.ConnectionID, frames
 		length: l,
 	}
 	padding := protocol.MinInitialPacketSize - p.shortHeaderPacketLength(connID, pnLen, payload) - protocol.ByteCount(s.Overhead())
+	buf := getPacketBuffer()
 	packet, err := p.appendShortHeaderPacket(buf, connID, pn, pnLen, s.KeyPhase(), payload, padding, protocol.MinInitialPacketSize, s, false, v)
 	if err != nil {
+		buf.Release()
+		return shortHeaderPacket{}, nil, err
+	}
+	if err := wire.CheckDatagramSize(buf.Len(), protocol.MinInitialPacketSize, false); err != nil {
+		buf.Release()
 		return shortHeaderPacket{}, nil, err
 	}
 	packet.IsPathProbePacket = true
-	return packet, buf, err
+	return packet, buf, nil
 }
 
 func (p *packetPacker) getLongHeader(encLevel protocol.EncryptionLevel, v protocol.Version) *wire.ExtendedHeader {
@@ -955,16 +1035,16 @@ func (p *packetPacker) appendShortHeaderPacket(
 // appendPacketPayload serializes the payload of a packet into the raw byte slice.
 // It modifies the order of payload.frames.
 func (p *packetPacker) appendPacketPayload(raw []byte, pl payload, paddingLen protocol.ByteCount, v protocol.Version) ([]byte, error) {
-	payloadOffset := len(raw)
+	w := wire.NewPayloadWriter(raw, pl.length+paddingLen, v)
 	if pl.ack != nil {
-		var err error
-		raw, err = pl.ack.Append(raw, v)
-		if err != nil {
+		if err := w.AppendFrame(pl.ack); err != nil {
 			return nil, err
 		}
 	}
 	if paddingLen > 0 {
-		raw = append(raw, make([]byte, paddingLen)...)
+		if err := w.AppendPadding(paddingLen); err != nil {
+			return nil, err
+		}
 	}
 	// Randomize the order of the control frames.
 	// This makes sure that the receiver doesn't rely on the order in which frames are packed.
@@ -972,24 +1052,20 @@ func (p *packetPacker) appendPacketPayload(raw []byte, pl payload, paddingLen pr
 		p.rand.Shuffle(len(pl.frames), func(i, j int) { pl.frames[i], pl.frames[j] = pl.frames[j], pl.frames[i] })
 	}
 	for _, f := range pl.frames {
-		var err error
-		raw, err = f.Frame.Append(raw, v)
-		if err != nil {
+		if err := w.AppendFrame(f.Frame); err != nil {
 			return nil, err
 		}
 	}
 	for _, f := range pl.streamFrames {
-		var err error
-		raw, err = f.Frame.Append(raw, v)
-		if err != nil {
+		if err := w.AppendFrame(f.Frame); err != nil {
 			return nil, err
 		}
 	}
 
-	if payloadSize := protocol.ByteCount(len(raw)-payloadOffset) - paddingLen; payloadSize != pl.length {
-		return nil, fmt.Errorf("PacketPacker BUG: payload size inconsistent (expected %d, got %d bytes)", pl.length, payloadSize)
+	if w.Remaining() != 0 {
+		return nil, fmt.Errorf("PacketPacker BUG: payload size inconsistent (expected %d, got %d bytes)", pl.length, w.Len()-paddingLen)
 	}
-	return raw, nil
+	return w.Finish(), nil
 }
 
 func (p *packetPacker) encryptPacket(raw []byte, sealer sealer, pn protocol.PacketNumber, payloadOffset, pnLen protocol.ByteCount) []byte {
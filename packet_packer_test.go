@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/rand"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -239,6 +240,18 @@ func TestPack1RTTAckOnlyPacket(t *testing.T) {
 	parsePacket(t, buffer.Data)
 }
 
+func TestPack1RTTAckOnlyPacketNothingToSend(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	tp := newTestPacketPacker(t, mockCtrl, protocol.PerspectiveClient)
+	tp.pnManager.EXPECT().PeekPacketNumber(protocol.Encryption1RTT).Return(protocol.PacketNumber(0x42), protocol.PacketNumberLen2)
+	tp.sealingManager.EXPECT().Get1RTTSealer().Return(newMockShortHeaderSealer(mockCtrl), nil)
+	tp.ackFramer.EXPECT().GetAckFrame(protocol.Encryption1RTT, gomock.Any(), true)
+	_, buffer, err := tp.packer.PackAckOnlyPacket(protocol.MaxByteCount, time.Now(), protocol.Version1)
+	require.ErrorIs(t, err, errNothingToPack)
+	// the buffer was released
+	require.Nil(t, buffer)
+}
+
 func TestPack0RTTPacket(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	tp := newTestPacketPacker(t, mockCtrl, protocol.PerspectiveClient)
@@ -405,6 +418,36 @@ func testPackConnectionCloseCoalesced(t *testing.T, pers protocol.Perspective) {
 	}
 }
 
+func TestPackConnectionCloseLongReasonPhrase(t *testing.T) {
+	const maxPacketSize protocol.ByteCount = 1200
+	mockCtrl := gomock.NewController(t)
+	tp := newTestPacketPacker(t, mockCtrl, protocol.PerspectiveServer)
+	tp.pnManager.EXPECT().PeekPacketNumber(protocol.EncryptionInitial).Return(protocol.PacketNumber(1), protocol.PacketNumberLen2)
+	tp.pnManager.EXPECT().PopPacketNumber(protocol.EncryptionInitial).Return(protocol.PacketNumber(1))
+	tp.pnManager.EXPECT().PeekPacketNumber(protocol.EncryptionHandshake).Return(protocol.PacketNumber(2), protocol.PacketNumberLen2)
+	tp.pnManager.EXPECT().PopPacketNumber(protocol.EncryptionHandshake).Return(protocol.PacketNumber(2))
+	tp.pnManager.EXPECT().PeekPacketNumber(protocol.Encryption1RTT).Return(protocol.PacketNumber(3), protocol.PacketNumberLen2)
+	tp.pnManager.EXPECT().PopPacketNumber(protocol.Encryption1RTT).Return(protocol.PacketNumber(3))
+	tp.sealingManager.EXPECT().GetInitialSealer().Return(newMockShortHeaderSealer(mockCtrl), nil)
+	tp.sealingManager.EXPECT().GetHandshakeSealer().Return(newMockShortHeaderSealer(mockCtrl), nil)
+	tp.sealingManager.EXPECT().Get1RTTSealer().Return(newMockShortHeaderSealer(mockCtrl), nil)
+	p, err := tp.packer.PackConnectionClose(&qerr.TransportError{
+		ErrorCode:    qerr.ProtocolViolation,
+		ErrorMessage: strings.Repeat("a", 2000),
+	}, maxPacketSize, protocol.Version1)
+	require.NoError(t, err)
+	require.Len(t, p.longHdrPackets, 2)
+	require.NotNil(t, p.shortHdrPacket)
+	require.LessOrEqual(t, p.buffer.Len(), maxPacketSize)
+	require.Greater(t, p.buffer.Len(), maxPacketSize-10)
+	for _, f := range []ackhandler.Frame{p.longHdrPackets[0].frames[0], p.longHdrPackets[1].frames[0], p.shortHdrPacket.Frames[0]} {
+		ccf := f.Frame.(*wire.ConnectionCloseFrame)
+		require.Equal(t, uint64(qerr.ProtocolViolation), ccf.ErrorCode)
+		require.NotEmpty(t, ccf.ReasonPhrase)
+		require.Less(t, len(ccf.ReasonPhrase), 400)
+	}
+}
+
 func TestPackConnectionCloseCryptoError(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	tp := newTestPacketPacker(t, mockCtrl, protocol.PerspectiveServer)
@@ -630,7 +673,7 @@ func TestPackRetransmissions(t *testing.T) {
 	tp.sealingManager.EXPECT().GetHandshakeSealer().Return(nil, handshake.ErrKeysNotYetAvailable)
 	tp.sealingManager.EXPECT().Get1RTTSealer().Return(nil, handshake.ErrKeysNotYetAvailable)
 	tp.ackFramer.EXPECT().GetAckFrame(protocol.EncryptionInitial, gomock.Any(), false)
-	p, err := tp.packer.PackCoalescedPacket(false, 1000, time.Now(), protocol.Version1)
+	p, err := tp.packer.PackCoalescedPacket(false, protocol.MinInitialPacketSize, time.Now(), protocol.Version1)
 	require.NoError(t, err)
 	require.Len(t, p.longHdrPackets, 1)
 	require.Equal(t, protocol.EncryptionInitial, p.longHdrPackets[0].EncryptionLevel())
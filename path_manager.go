@@ -1,7 +1,7 @@
 package quic
 
 import (
-	"crypto/rand"
+	"io"
 	"net"
 	"slices"
 	"time"
@@ -43,6 +43,8 @@ type pathManager struct {
 
 	getConnID    func(pathID) (_ protocol.ConnectionID, ok bool)
 	retireConnID func(pathID)
+	// The source of randomness for the PATH_CHALLENGE data.
+	rand io.Reader
 
 	logger utils.Logger
 }
@@ -50,12 +52,14 @@ type pathManager struct {
 func newPathManager(
 	getConnID func(pathID) (_ protocol.ConnectionID, ok bool),
 	retireConnID func(pathID),
+	rand io.Reader,
 	logger utils.Logger,
 ) *pathManager {
 	return &pathManager{
 		paths:        make([]*path, 0, maxPaths+1),
 		getConnID:    getConnID,
 		retireConnID: retireConnID,
+		rand:         rand,
 		logger:       logger,
 	}
 }
@@ -120,19 +124,22 @@ func (pm *pathManager) HandlePacket(
 
 	frames := make([]ackhandler.Frame, 0, 2)
 	if p == nil {
-		var pathChallengeData [8]byte
-		rand.Read(pathChallengeData[:])
+		challenge, err := wire.NewPathChallengeFrame(pm.rand)
+		if err != nil {
+			pm.logger.Errorf("skipping validation of new path %s: %s", remoteAddr, err)
+			return protocol.ConnectionID{}, nil, shouldSwitch
+		}
 		p = &path{
 			id:             pm.nextPathID,
 			addr:           remoteAddr,
 			lastPacketTime: t,
 			rcvdNonProbing: isNonProbing,
-			pathChallenge:  pathChallengeData,
+			pathChallenge:  challenge.Data,
 		}
 		pm.nextPathID++
 		pm.paths = append(pm.paths, p)
 		frames = append(frames, ackhandler.Frame{
-			Frame:   &wire.PathChallengeFrame{Data: p.pathChallenge},
+			Frame:   challenge,
 			Handler: (*pathManagerAckHandler)(pm),
 		})
 		pm.logger.Debugf("enqueueing PATH_CHALLENGE for new path %s", remoteAddr)
@@ -2,8 +2,8 @@ package quic
 
 import (
 	"context"
-	"crypto/rand"
 	"errors"
+	"io"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -119,6 +119,8 @@ type pathManagerOutgoing struct {
 	getConnID       func(pathID) (_ protocol.ConnectionID, ok bool)
 	retireConnID    func(pathID)
 	scheduleSending func()
+	// The source of randomness for the PATH_CHALLENGE data.
+	rand io.Reader
 
 	mx             sync.Mutex
 	activePath     pathID
@@ -132,6 +134,7 @@ func newPathManagerOutgoing(
 	getConnID func(pathID) (_ protocol.ConnectionID, ok bool),
 	retireConnID func(pathID),
 	scheduleSending func(),
+	rand io.Reader,
 ) *pathManagerOutgoing {
 	return &pathManagerOutgoing{
 		activePath:      0, // at initialization time, we're guaranteed to be using the handshake path
@@ -139,6 +142,7 @@ func newPathManagerOutgoing(
 		getConnID:       getConnID,
 		retireConnID:    retireConnID,
 		scheduleSending: scheduleSending,
+		rand:            rand,
 		paths:           make(map[pathID]*pathOutgoing, 4),
 	}
 }
@@ -253,9 +257,12 @@ func (pm *pathManagerOutgoing) NextPathToProbe() (_ protocol.ConnectionID, _ ack
 		return protocol.ConnectionID{}, ackhandler.Frame{}, nil, false
 	}
 
-	var b [8]byte
-	_, _ = rand.Read(b[:])
-	p.pathChallenges = append(p.pathChallenges, b)
+	pathChallenge, err := wire.NewPathChallengeFrame(pm.rand)
+	if err != nil {
+		// The path stays in the queue, probing will be attempted again when the next packet is sent.
+		return protocol.ConnectionID{}, ackhandler.Frame{}, nil, false
+	}
+	p.pathChallenges = append(p.pathChallenges, pathChallenge.Data)
 
 	pm.pathsToProbe = pm.pathsToProbe[1:]
 	p.enablePath()
@@ -264,7 +271,7 @@ func (pm *pathManagerOutgoing) NextPathToProbe() (_ protocol.ConnectionID, _ ack
 	default:
 	}
 	frame := ackhandler.Frame{
-		Frame:   &wire.PathChallengeFrame{Data: b},
+		Frame:   pathChallenge,
 		Handler: (*pathManagerOutgoingAckHandler)(pm),
 	}
 	return connID, frame, p.tr, true
@@ -1,7 +1,9 @@
 package quic
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"testing"
 	"time"
 
@@ -23,6 +25,7 @@ func TestPathManagerOutgoingPathProbing(t *testing.T) {
 		},
 		func(id pathID) { t.Fatal("didn't expect any connection ID to be retired") },
 		func() {},
+		rand.Reader,
 	)
 
 	_, _, _, ok := pm.NextPathToProbe()
@@ -113,6 +116,7 @@ func TestPathManagerOutgoingRetransmissions(t *testing.T) {
 		func(id pathID) (protocol.ConnectionID, bool) { return connIDs[id], true },
 		func(id pathID) { retiredConnIDs = append(retiredConnIDs, connIDs[id]) },
 		func() { scheduledSending <- struct{}{} },
+		rand.Reader,
 	)
 
 	_, _, _, ok := pm.NextPathToProbe()
@@ -217,6 +221,7 @@ func TestPathManagerOutgoingAbandonPath(t *testing.T) {
 		},
 		func(id pathID) { retiredPaths = append(retiredPaths, id) },
 		func() {},
+		rand.Reader,
 	)
 
 	// path abandoned before the PATH_CHALLENGE is sent out
@@ -259,3 +264,38 @@ func TestPathManagerOutgoingAbandonPath(t *testing.T) {
 	// it's not possible to switch to an abandoned path
 	require.ErrorIs(t, p2.Switch(), ErrPathClosed)
 }
+
+func TestPathManagerOutgoingRandomSource(t *testing.T) {
+	var random bytes.Buffer
+	pm := newPathManagerOutgoing(
+		func(id pathID) (protocol.ConnectionID, bool) {
+			return protocol.ParseConnectionID([]byte{1, 2, 3, 4, 5, 6, 7, 8}), true
+		},
+		func(id pathID) {},
+		func() {},
+		&random,
+	)
+
+	p := pm.NewPath(&Transport{}, time.Second, func() {})
+	errChan := make(chan error, 1)
+	go func() { errChan <- p.Probe(context.Background()) }()
+
+	// wait for the path to be queued for probing
+	time.Sleep(scaleDuration(5 * time.Millisecond))
+	// reading from the source of randomness fails, so the path stays in the queue
+	_, _, _, ok := pm.NextPathToProbe()
+	require.False(t, ok)
+
+	random.Write([]byte{8, 7, 6, 5, 4, 3, 2, 1})
+	_, f, _, ok := pm.NextPathToProbe()
+	require.True(t, ok)
+	require.Equal(t, &wire.PathChallengeFrame{Data: [8]byte{8, 7, 6, 5, 4, 3, 2, 1}}, f.Frame)
+
+	pm.HandlePathResponseFrame(&wire.PathResponseFrame{Data: [8]byte{8, 7, 6, 5, 4, 3, 2, 1}})
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
@@ -1,6 +1,7 @@
 package quic
 
 import (
+	"bytes"
 	"crypto/rand"
 	"net"
 	"testing"
@@ -27,6 +28,7 @@ func TestPathManagerIntentionalMigration(t *testing.T) {
 	pm := newPathManager(
 		func(id pathID) (protocol.ConnectionID, bool) { return connIDs[id], true },
 		func(id pathID) { retiredConnIDs = append(retiredConnIDs, connIDs[id]) },
+		rand.Reader,
 		utils.DefaultLogger,
 	)
 	now := time.Now()
@@ -130,6 +132,7 @@ func TestPathManagerMultipleProbes(t *testing.T) {
 	pm := newPathManager(
 		func(id pathID) (protocol.ConnectionID, bool) { return connIDs[id], true },
 		func(id pathID) {},
+		rand.Reader,
 		utils.DefaultLogger,
 	)
 	now := time.Now()
@@ -184,6 +187,7 @@ func TestPathManagerNATRebinding(t *testing.T) {
 	pm := newPathManager(
 		func(id pathID) (protocol.ConnectionID, bool) { return connIDs[id], true },
 		func(id pathID) { retiredConnIDs = append(retiredConnIDs, connIDs[id]) },
+		rand.Reader,
 		utils.DefaultLogger,
 	)
 
@@ -216,6 +220,7 @@ func TestPathManagerLimits(t *testing.T) {
 	pm := newPathManager(
 		func(id pathID) (protocol.ConnectionID, bool) { return connIDs[id], true },
 		func(id pathID) { retiredConnIDs = append(retiredConnIDs, connIDs[id]) },
+		rand.Reader,
 		utils.DefaultLogger,
 	)
 
@@ -353,3 +358,27 @@ func TestAddrsEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestPathManagerRandomSource(t *testing.T) {
+	connIDs := []protocol.ConnectionID{
+		protocol.ParseConnectionID([]byte{1, 2, 3, 4, 5, 6, 7, 8}),
+		protocol.ParseConnectionID([]byte{2, 3, 4, 5, 6, 7, 8, 9}),
+	}
+	pm := newPathManager(
+		func(id pathID) (protocol.ConnectionID, bool) { return connIDs[id], true },
+		func(id pathID) {},
+		bytes.NewReader([]byte{8, 7, 6, 5, 4, 3, 2, 1}),
+		utils.DefaultLogger,
+	)
+	now := time.Now()
+	connID, frames, _ := pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1000}, now, nil, false)
+	require.Equal(t, connIDs[0], connID)
+	require.Len(t, frames, 1)
+	require.Equal(t, &wire.PathChallengeFrame{Data: [8]byte{8, 7, 6, 5, 4, 3, 2, 1}}, frames[0].Frame)
+
+	// the source of randomness is exhausted, so the new path can't be validated
+	connID, frames, shouldSwitch := pm.HandlePacket(&net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1001}, now, nil, false)
+	require.Zero(t, connID)
+	require.Empty(t, frames)
+	require.False(t, shouldSwitch)
+}
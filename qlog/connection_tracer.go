@@ -1,6 +1,7 @@
 package qlog
 
 import (
+	"errors"
 	"io"
 	"net"
 	"time"
@@ -169,7 +170,11 @@ func (t *connectionTracer) NegotiatedVersion(chosen logging.Version, client, ser
 }
 
 func (t *connectionTracer) ClosedConnection(e error) {
-	t.recordEvent(time.Now(), &eventConnectionClosed{e: e})
+	now := time.Now()
+	if frameErr := (wire.QlogFrameError)(nil); errors.As(e, &frameErr) {
+		t.recordEvent(now, &eventFrameParsingError{err: frameErr})
+	}
+	t.recordEvent(now, &eventConnectionClosed{e: e})
 }
 
 func (t *connectionTracer) SentTransportParameters(tp *wire.TransportParameters) {
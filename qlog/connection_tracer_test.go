@@ -12,6 +12,7 @@ import (
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/qerr"
 	"github.com/quic-go/quic-go/internal/utils"
+	"github.com/quic-go/quic-go/internal/wire"
 	"github.com/quic-go/quic-go/logging"
 
 	"github.com/stretchr/testify/require"
@@ -192,6 +193,33 @@ func TestTransportErrors(t *testing.T) {
 	require.Equal(t, "foobar", ev["reason"])
 }
 
+func TestFrameParsingErrors(t *testing.T) {
+	tracer, buf := newConnectionTracer()
+	tracer.ClosedConnection(&wire.FrameParsingError{
+		Err: &qerr.TransportError{
+			ErrorCode:    qerr.FrameEncodingError,
+			FrameType:    0x11,
+			ErrorMessage: "foobar",
+		},
+		Offset: 3,
+	})
+	tracer.Close()
+	entries := exportAndParse(t, buf)
+	require.Len(t, entries, 2)
+	require.Equal(t, "transport:frame_parsing_error", entries[0].Name)
+	ev := entries[0].Event
+	require.Len(t, ev, 3)
+	require.Equal(t, "frame_encoding_error", ev["trigger"])
+	require.Equal(t, "MAX_STREAM_DATA", ev["frame_type"])
+	require.Equal(t, float64(3), ev["frame_offset"])
+	require.Equal(t, "transport:connection_closed", entries[1].Name)
+	ev = entries[1].Event
+	require.Len(t, ev, 3)
+	require.Equal(t, "local", ev["owner"])
+	require.Equal(t, "frame_encoding_error", ev["connection_code"])
+	require.Equal(t, "foobar", ev["reason"])
+}
+
 func TestSentTransportParameters(t *testing.T) {
 	rcid := protocol.ParseConnectionID([]byte{0xde, 0xca, 0xfb, 0xad})
 	tracer, buf := newConnectionTracer()
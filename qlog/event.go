@@ -9,6 +9,7 @@ import (
 
 	"github.com/quic-go/quic-go"
 	"github.com/quic-go/quic-go/internal/protocol"
+	"github.com/quic-go/quic-go/internal/wire"
 	"github.com/quic-go/quic-go/logging"
 
 	"github.com/francoispqt/gojay"
@@ -151,6 +152,20 @@ func (e eventConnectionClosed) MarshalJSONObject(enc *gojay.Encoder) {
 	}
 }
 
+type eventFrameParsingError struct {
+	err wire.QlogFrameError
+}
+
+func (e eventFrameParsingError) Category() category { return categoryTransport }
+func (e eventFrameParsingError) Name() string       { return "frame_parsing_error" }
+func (e eventFrameParsingError) IsNil() bool        { return false }
+
+func (e eventFrameParsingError) MarshalJSONObject(enc *gojay.Encoder) {
+	enc.StringKeyOmitEmpty("trigger", e.err.QlogTrigger())
+	enc.StringKey("frame_type", e.err.FrameTypeName())
+	enc.IntKey("frame_offset", e.err.FrameOffset())
+}
+
 type eventPacketSent struct {
 	Header        gojay.MarshalerJSONObject // either a shortHeader or a packetHeader
 	Length        logging.ByteCount
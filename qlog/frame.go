@@ -18,7 +18,13 @@ var _ gojay.MarshalerJSONObject = frame{}
 var _ gojay.MarshalerJSONArray = frames{}
 
 func (f frame) MarshalJSONObject(enc *gojay.Encoder) {
-	switch frame := f.Frame.(type) {
+	fr := f.Frame
+	switch wf := fr.(type) {
+	// These are the only frames whose sensitive contents are exported.
+	case *logging.NewTokenFrame, *logging.NewConnectionIDFrame, *logging.PathChallengeFrame, *logging.PathResponseFrame:
+		fr = wire.RedactFrame(wf.(wire.Frame), wire.LogRedactionMode)
+	}
+	switch frame := fr.(type) {
 	case *logging.PingFrame:
 		marshalPingFrame(enc, frame)
 	case *logging.AckFrame:
@@ -215,7 +221,7 @@ func marshalConnectionCloseFrame(enc *gojay.Encoder, f *logging.ConnectionCloseF
 	}
 	enc.StringKey("frame_type", "connection_close")
 	enc.StringKey("error_space", errorSpace)
-	if errName := transportError(f.ErrorCode).String(); len(errName) > 0 {
+	if errName := f.ErrorCodeName(); len(errName) > 0 {
 		enc.StringKey("error_code", errName)
 	} else {
 		enc.Uint64Key("error_code", f.ErrorCode)
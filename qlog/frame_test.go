@@ -9,6 +9,7 @@ import (
 	"github.com/francoispqt/gojay"
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/internal/wire"
 	"github.com/quic-go/quic-go/logging"
 	"github.com/stretchr/testify/require"
 )
@@ -172,7 +173,7 @@ func TestNewTokenFrame(t *testing.T) {
 		},
 		map[string]interface{}{
 			"frame_type": "new_token",
-			"token":      map[string]interface{}{"data": "deadbeef"},
+			"token":      map[string]interface{}{"data": "00000000"},
 		},
 	)
 }
@@ -316,7 +317,7 @@ func TestNewConnectionIDFrame(t *testing.T) {
 			"retire_prior_to":       24,
 			"length":                4,
 			"connection_id":         "deadbeef",
-			"stateless_reset_token": "000102030405060708090a0b0c0d0e0f",
+			"stateless_reset_token": "00000000000000000000000000000000",
 		},
 	)
 }
@@ -340,7 +341,7 @@ func TestPathChallengeFrame(t *testing.T) {
 		},
 		map[string]interface{}{
 			"frame_type": "path_challenge",
-			"data":       "deadbeefcafec001",
+			"data":       "0000000000000000",
 		},
 	)
 }
@@ -352,6 +353,27 @@ func TestPathResponseFrame(t *testing.T) {
 		},
 		map[string]interface{}{
 			"frame_type": "path_response",
+			"data":       "0000000000000000",
+		},
+	)
+}
+
+func TestFramesRedactionNone(t *testing.T) {
+	orig := wire.LogRedactionMode
+	t.Cleanup(func() { wire.LogRedactionMode = orig })
+	wire.LogRedactionMode = wire.RedactionNone
+
+	check(t,
+		&logging.NewTokenFrame{Token: []byte{0xde, 0xad, 0xbe, 0xef}},
+		map[string]interface{}{
+			"frame_type": "new_token",
+			"token":      map[string]interface{}{"data": "deadbeef"},
+		},
+	)
+	check(t,
+		&logging.PathChallengeFrame{Data: [8]byte{0xde, 0xad, 0xbe, 0xef, 0xca, 0xfe, 0xc0, 0x01}},
+		map[string]interface{}{
+			"frame_type": "path_challenge",
 			"data":       "deadbeefcafec001",
 		},
 	)
@@ -378,6 +400,20 @@ func TestConnectionCloseFrame(t *testing.T) {
 				"reason":         "lorem ipsum",
 			},
 		},
+		{
+			name: "application error code colliding with a transport error code",
+			frame: &logging.ConnectionCloseFrame{
+				IsApplicationError: true,
+				ErrorCode:          uint64(qerr.InternalError),
+			},
+			expected: map[string]interface{}{
+				"frame_type":     "connection_close",
+				"error_space":    "application",
+				"error_code":     int(qerr.InternalError),
+				"raw_error_code": int(qerr.InternalError),
+				"reason":         "",
+			},
+		},
 		{
 			name: "transport error code",
 			frame: &logging.ConnectionCloseFrame{
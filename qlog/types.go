@@ -5,6 +5,7 @@ import (
 
 	"github.com/quic-go/quic-go/internal/protocol"
 	"github.com/quic-go/quic-go/internal/qerr"
+	"github.com/quic-go/quic-go/internal/wire"
 	"github.com/quic-go/quic-go/logging"
 )
 
@@ -174,44 +175,7 @@ func (t keyUpdateTrigger) String() string {
 type transportError uint64
 
 func (e transportError) String() string {
-	switch qerr.TransportErrorCode(e) {
-	case qerr.NoError:
-		return "no_error"
-	case qerr.InternalError:
-		return "internal_error"
-	case qerr.ConnectionRefused:
-		return "connection_refused"
-	case qerr.FlowControlError:
-		return "flow_control_error"
-	case qerr.StreamLimitError:
-		return "stream_limit_error"
-	case qerr.StreamStateError:
-		return "stream_state_error"
-	case qerr.FinalSizeError:
-		return "final_size_error"
-	case qerr.FrameEncodingError:
-		return "frame_encoding_error"
-	case qerr.TransportParameterError:
-		return "transport_parameter_error"
-	case qerr.ConnectionIDLimitError:
-		return "connection_id_limit_error"
-	case qerr.ProtocolViolation:
-		return "protocol_violation"
-	case qerr.InvalidToken:
-		return "invalid_token"
-	case qerr.ApplicationErrorErrorCode:
-		return "application_error"
-	case qerr.CryptoBufferExceeded:
-		return "crypto_buffer_exceeded"
-	case qerr.KeyUpdateError:
-		return "key_update_error"
-	case qerr.AEADLimitReached:
-		return "aead_limit_reached"
-	case qerr.NoViablePathError:
-		return "no_viable_path"
-	default:
-		return ""
-	}
+	return wire.TransportErrorName(qerr.TransportErrorCode(e))
 }
 
 type packetType logging.PacketType
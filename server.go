@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"sync"
 	"time"
@@ -58,6 +59,7 @@ type baseServer struct {
 
 	connIDGenerator   ConnectionIDGenerator
 	statelessResetter *statelessResetter
+	random            io.Reader
 	onClose           func()
 
 	receivedPackets chan receivedPacket
@@ -80,6 +82,7 @@ type baseServer struct {
 		protocol.ConnectionID, /* source connection ID */
 		ConnectionIDGenerator,
 		*statelessResetter,
+		io.Reader,
 		*Config,
 		*tls.Config,
 		*handshake.TokenGenerator,
@@ -236,6 +239,7 @@ func newServer(
 	tr *packetHandlerMap,
 	connIDGenerator ConnectionIDGenerator,
 	statelessResetter *statelessResetter,
+	random io.Reader,
 	connContext func(context.Context, *ClientInfo) (context.Context, error),
 	tlsConf *tls.Config,
 	config *Config,
@@ -258,6 +262,7 @@ func newServer(
 		verifySourceAddress:       verifySourceAddress,
 		connIDGenerator:           connIDGenerator,
 		statelessResetter:         statelessResetter,
+		random:                    random,
 		connQueue:                 make(chan *Conn, protocol.MaxAcceptQueueSize),
 		errorChan:                 make(chan struct{}),
 		stopAccepting:             make(chan struct{}),
@@ -708,6 +713,7 @@ func (s *baseServer) handleInitialImpl(p receivedPacket, hdr *wire.Header) error
 		connID,
 		s.connIDGenerator,
 		s.statelessResetter,
+		s.random,
 		config,
 		s.tlsConf,
 		s.tokenGenerator,
@@ -876,7 +882,7 @@ func (s *baseServer) sendError(remoteAddr net.Addr, hdr *wire.Header, sealer han
 	b := getPacketBuffer()
 	defer b.Release()
 
-	ccf := &wire.ConnectionCloseFrame{ErrorCode: uint64(errorCode)}
+	ccf := wire.NewTransportClose(errorCode, 0, "")
 
 	replyHdr := &wire.ExtendedHeader{}
 	replyHdr.Type = protocol.PacketTypeInitial
@@ -946,7 +952,11 @@ func (s *baseServer) maybeSendVersionNegotiationPacket(p receivedPacket) {
 
 	s.logger.Debugf("Client offered version %s, sending Version Negotiation", v)
 
-	data := wire.ComposeVersionNegotiation(dest, src, s.config.Versions)
+	data, err := wire.ComposeVersionNegotiationWithRand(dest, src, s.config.Versions, s.random)
+	if err != nil {
+		s.logger.Errorf("error composing Version Negotiation: %s", err)
+		return
+	}
 	if s.tracer != nil && s.tracer.SentVersionNegotiationPacket != nil {
 		s.tracer.SentVersionNegotiationPacket(p.remoteAddr, src, dest, s.config.Versions)
 	}
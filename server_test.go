@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"crypto/tls"
 	"errors"
+	"io"
 	"net"
 	"slices"
 	"testing"
@@ -45,6 +46,7 @@ type serverOpts struct {
 		protocol.ConnectionID, // source connection ID
 		ConnectionIDGenerator,
 		*statelessResetter,
+		io.Reader,
 		*Config,
 		*tls.Config,
 		*handshake.TokenGenerator,
@@ -69,6 +71,7 @@ func newTestServer(t *testing.T, serverOpts *serverOpts) *testServer {
 		(*packetHandlerMap)(tr),
 		&protocol.DefaultConnectionIDGenerator{},
 		&statelessResetter{},
+		rand.Reader,
 		func(ctx context.Context, _ *ClientInfo) (context.Context, error) { return ctx, nil },
 		&tls.Config{},
 		config,
@@ -635,6 +638,7 @@ func (r *connConstructorRecorder) NewConn(
 	srcConnID protocol.ConnectionID,
 	_ ConnectionIDGenerator,
 	_ *statelessResetter,
+	_ io.Reader,
 	config *Config,
 	_ *tls.Config,
 	_ *handshake.TokenGenerator,
@@ -873,6 +877,7 @@ func TestServerReceiveQueue(t *testing.T) {
 			_ protocol.ConnectionID,
 			_ ConnectionIDGenerator,
 			_ *statelessResetter,
+			_ io.Reader,
 			_ *Config,
 			_ *tls.Config,
 			_ *handshake.TokenGenerator,
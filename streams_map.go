@@ -179,13 +179,21 @@ func (m *streamsMap) DeleteStream(id protocol.StreamID) error {
 	panic("")
 }
 
-func (m *streamsMap) HandleMaxStreamsFrame(f *wire.MaxStreamsFrame) {
+func (m *streamsMap) HandleMaxStreamsFrame(f *wire.MaxStreamsFrame) error {
+	id, err := protocol.MaxStreamIDForCount(f.MaxStreamNum, f.Type, m.perspective)
+	if err != nil {
+		return &qerr.TransportError{
+			ErrorCode:    qerr.FrameEncodingError,
+			ErrorMessage: err.Error(),
+		}
+	}
 	switch f.Type {
 	case protocol.StreamTypeUni:
-		m.outgoingUniStreams.SetMaxStream(f.MaxStreamNum.StreamID(protocol.StreamTypeUni, m.perspective))
+		m.outgoingUniStreams.SetMaxStream(id)
 	case protocol.StreamTypeBidi:
-		m.outgoingBidiStreams.SetMaxStream(f.MaxStreamNum.StreamID(protocol.StreamTypeBidi, m.perspective))
+		m.outgoingBidiStreams.SetMaxStream(id)
 	}
+	return nil
 }
 
 type sendStreamFrameHandler interface {
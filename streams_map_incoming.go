@@ -189,10 +189,7 @@ func (m *incomingStreamsMap[T]) deleteStream(id protocol.StreamID) error {
 		// never send a value larger than the maximum value for a stream number
 		if maxStream <= protocol.MaxStreamID {
 			m.maxStream = maxStream
-			m.queueMaxStreamID(&wire.MaxStreamsFrame{
-				Type:         m.streamType,
-				MaxStreamNum: m.maxStream.StreamNum(),
-			})
+			m.queueMaxStreamID(wire.NewMaxStreamsFrameForStreamID(m.streamType, m.maxStream))
 		}
 	}
 	return nil
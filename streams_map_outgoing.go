@@ -139,14 +139,7 @@ func (m *outgoingStreamsMap[T]) maybeSendBlockedFrame() {
 		return
 	}
 
-	var streamLimit protocol.StreamNum
-	if m.maxStream != protocol.InvalidStreamID {
-		streamLimit = m.maxStream.StreamNum()
-	}
-	m.queueStreamIDBlocked(&wire.StreamsBlockedFrame{
-		Type:        m.streamType,
-		StreamLimit: streamLimit,
-	})
+	m.queueStreamIDBlocked(wire.NewStreamsBlockedFrameForStreamID(m.streamType, m.maxStream))
 	m.blockedSent = true
 }
 
@@ -242,24 +242,32 @@ func testStreamsMapStreamLimits(t *testing.T, perspective protocol.Perspective)
 	require.ErrorIs(t, err, &StreamLimitReachedError{})
 
 	// increase via MAX_STREAMS frames
-	m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{
+	require.NoError(t, m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{
 		Type:         protocol.StreamTypeBidi,
 		MaxStreamNum: 2,
-	})
+	}))
 	_, err = m.OpenStream()
 	require.NoError(t, err)
 	_, err = m.OpenStream()
 	require.ErrorIs(t, err, &StreamLimitReachedError{})
 
-	m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{
+	require.NoError(t, m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{
 		Type:         protocol.StreamTypeUni,
 		MaxStreamNum: 2,
-	})
+	}))
 	_, err = m.OpenUniStream()
 	require.NoError(t, err)
 	_, err = m.OpenUniStream()
 	require.ErrorIs(t, err, &StreamLimitReachedError{})
 
+	// invalid stream counts are rejected
+	require.ErrorIs(t,
+		m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{Type: protocol.StreamTypeBidi, MaxStreamNum: protocol.MaxStreamCount + 1}),
+		&qerr.TransportError{ErrorCode: qerr.FrameEncodingError},
+	)
+	_, err = m.OpenStream()
+	require.ErrorIs(t, err, &StreamLimitReachedError{})
+
 	// decrease via transport parameters
 	m.UpdateLimits(&wire.TransportParameters{MaxBidiStreamNum: 0})
 	_, err = m.OpenStream()
@@ -318,8 +326,8 @@ func testStreamsMapHandleReceiveStreamFrames(t *testing.T, pers protocol.Perspec
 		100,
 		pers,
 	)
-	m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{Type: protocol.StreamTypeBidi, MaxStreamNum: protocol.MaxStreamCount})
-	m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{Type: protocol.StreamTypeUni, MaxStreamNum: protocol.MaxStreamCount})
+	require.NoError(t, m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{Type: protocol.StreamTypeBidi, MaxStreamNum: protocol.MaxStreamCount}))
+	require.NoError(t, m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{Type: protocol.StreamTypeUni, MaxStreamNum: protocol.MaxStreamCount}))
 
 	var firstOutgoingUniStream, firstOutgoingBidiStream, firstIncomingUniStream, firstIncomingBidiStream protocol.StreamID
 	if pers == protocol.PerspectiveClient {
@@ -431,8 +439,8 @@ func testStreamsMapHandleSendStreamFrames(t *testing.T, pers protocol.Perspectiv
 		100,
 		pers,
 	)
-	m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{Type: protocol.StreamTypeBidi, MaxStreamNum: protocol.MaxStreamCount})
-	m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{Type: protocol.StreamTypeUni, MaxStreamNum: protocol.MaxStreamCount})
+	require.NoError(t, m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{Type: protocol.StreamTypeBidi, MaxStreamNum: protocol.MaxStreamCount}))
+	require.NoError(t, m.HandleMaxStreamsFrame(&wire.MaxStreamsFrame{Type: protocol.StreamTypeUni, MaxStreamNum: protocol.MaxStreamCount}))
 
 	var firstOutgoingUniStream, firstOutgoingBidiStream, firstIncomingUniStream, firstIncomingBidiStream protocol.StreamID
 	if pers == protocol.PerspectiveClient {
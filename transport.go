@@ -6,6 +6,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"sync"
 	"sync/atomic"
@@ -133,6 +134,14 @@ type Transport struct {
 	// Tracer.Close is called when the transport is closed.
 	Tracer *logging.Tracer
 
+	// Rand is the source of randomness used when composing packets and frames,
+	// for the server and all connections using this Transport:
+	// the data of PATH_CHALLENGE frames, the greased transport parameter,
+	// the padding of Stateless Resets and the reserved version in Version Negotiation packets.
+	// This allows deployments to supply an approved random number generator.
+	// If not set, crypto/rand.Reader is used.
+	Rand io.Reader
+
 	connMx      sync.Mutex
 	handlers    map[protocol.ConnectionID]packetHandler
 	resetTokens map[protocol.StatelessResetToken]packetHandler
@@ -147,6 +156,8 @@ type Transport struct {
 	// If no ConnectionIDGenerator is set, this is set to a default.
 	connIDGenerator   ConnectionIDGenerator
 	statelessResetter *statelessResetter
+	// Set in init to Rand, or to crypto/rand.Reader if Rand is not set.
+	random io.Reader
 
 	server *baseServer
 
@@ -218,6 +229,7 @@ func (t *Transport) createServer(tlsConf *tls.Config, conf *Config, allow0RTT bo
 		(*packetHandlerMap)(t),
 		t.connIDGenerator,
 		t.statelessResetter,
+		t.random,
 		t.ConnContext,
 		tlsConf,
 		conf,
@@ -311,6 +323,7 @@ func (t *Transport) doDial(
 		srcConnID,
 		t.connIDGenerator,
 		t.statelessResetter,
+		t.random,
 		config,
 		tlsConf,
 		initialPacketNumber,
@@ -423,6 +436,10 @@ func (t *Transport) init(allowZeroLengthConnIDs bool) error {
 			t.connIDGenerator = &protocol.DefaultConnectionIDGenerator{ConnLen: t.connIDLen}
 		}
 		t.statelessResetter = newStatelessResetter(t.StatelessResetKey)
+		t.random = t.Rand
+		if t.random == nil {
+			t.random = rand.Reader
+		}
 
 		go func() {
 			defer close(t.listening)
@@ -651,10 +668,11 @@ func (t *Transport) sendStatelessReset(p receivedPacket) {
 	}
 	token := t.statelessResetter.GetStatelessResetToken(connID)
 	t.logger.Debugf("Sending stateless reset to %s (connection ID: %s). Token: %#x", p.remoteAddr, connID, token)
-	data := make([]byte, protocol.MinStatelessResetSize-16, protocol.MinStatelessResetSize)
-	rand.Read(data)
-	data[0] = (data[0] & 0x7f) | 0x40
-	data = append(data, token[:]...)
+	data, err := wire.AppendStatelessReset(make([]byte, 0, protocol.MinStatelessResetSize), token, t.random)
+	if err != nil {
+		t.logger.Errorf("error composing Stateless Reset: %s", err)
+		return
+	}
 	if _, err := t.conn.WritePacket(data, p.remoteAddr, p.info.OOB(), 0, protocol.ECNUnsupported); err != nil {
 		t.logger.Debugf("Error sending Stateless Reset to %s: %s", p.remoteAddr, err)
 	}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"io"
 	"net"
 	"os"
 	"syscall"
@@ -485,6 +486,7 @@ func testTransportDial(t *testing.T, early bool) {
 		_ protocol.ConnectionID,
 		_ ConnectionIDGenerator,
 		_ *statelessResetter,
+		_ io.Reader,
 		_ *Config,
 		_ *tls.Config,
 		_ protocol.PacketNumber,
@@ -555,6 +557,7 @@ func TestTransportDialingVersionNegotiation(t *testing.T) {
 		_ protocol.ConnectionID,
 		_ ConnectionIDGenerator,
 		_ *statelessResetter,
+		_ io.Reader,
 		_ *Config,
 		_ *tls.Config,
 		pn protocol.PacketNumber,